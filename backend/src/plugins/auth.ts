@@ -1,8 +1,19 @@
 import fp from 'fastify-plugin';
 import jwt from '@fastify/jwt';
 import { FastifyInstance, FastifyRequest, FastifyReply } from 'fastify';
+import { createHash, timingSafeEqual } from 'crypto';
 import { loadSecret } from '../utils/secrets';
 
+// Personal access tokens start with this, which tells them apart from JWTs
+const PAT_PREFIX = 'ttp_';
+
+/**
+ * Hashes a token so tokens of any length compare in constant time
+ */
+function digest(token: string): Buffer {
+  return createHash('sha256').update(token).digest();
+}
+
 /**
  * JWT authentication plugin
  * Registers @fastify/jwt and provides authenticate decorator
@@ -22,8 +33,24 @@ export default fp(async (fastify: FastifyInstance) => {
     },
   });
 
+  // Personal access tokens for service accounts and CI, one per line or
+  // comma-separated. They don't expire; remove one here to revoke it.
+  const accessTokens = (loadSecret('cli_access_tokens', { required: false }) || '')
+    .split(/[\s,]+/)
+    .filter((token) => token.startsWith(PAT_PREFIX))
+    .map(digest);
+
   // Decorate fastify instance with authenticate function
   fastify.decorate('authenticate', async (request: FastifyRequest, reply: FastifyReply) => {
+    const bearer = (request.headers.authorization || '').replace(/^Bearer\s+/i, '');
+    if (bearer.startsWith(PAT_PREFIX)) {
+      const presented = digest(bearer);
+      if (!accessTokens.some((token) => timingSafeEqual(token, presented))) {
+        reply.code(401).send({ error: 'Invalid personal access token' });
+      }
+      return;
+    }
+
     try {
       await request.jwtVerify();
     } catch (err) {
@@ -0,0 +1,58 @@
+import fp from 'fastify-plugin';
+import { createGunzip, gzip } from 'zlib';
+import { promisify } from 'util';
+import { FastifyInstance } from 'fastify';
+
+const gzipAsync = promisify(gzip);
+
+// Smaller responses aren't worth the CPU
+const MIN_COMPRESS_SIZE = 1024;
+
+/**
+ * Compression plugin
+ * Gzips responses for clients that accept it and decodes gzipped request
+ * bodies, answering 415 for any other encoding. Every response advertises
+ * Accept-Encoding: gzip, so clients such as the CLI know they may compress
+ * large uploads like batch imports. Registered after the ETag plugin, so
+ * ETags are computed on the uncompressed body.
+ */
+export default fp(async (fastify: FastifyInstance) => {
+  fastify.addHook('preParsing', async (request, reply, payload) => {
+    const encoding = request.headers['content-encoding']?.trim().toLowerCase();
+    if (!encoding || encoding === 'identity') {
+      return payload;
+    }
+    if (encoding !== 'gzip') {
+      throw Object.assign(new Error(`Unsupported Content-Encoding: ${encoding}`), { statusCode: 415 });
+    }
+
+    // Fastify checks the content length against the compressed bytes read
+    const decoded = payload.pipe(createGunzip()) as typeof payload & { receivedEncodedLength: number };
+    decoded.receivedEncodedLength = 0;
+    payload.on('data', (chunk: Buffer) => {
+      decoded.receivedEncodedLength += chunk.length;
+    });
+    return decoded;
+  });
+
+  fastify.addHook('onSend', async (request, reply, payload) => {
+    reply.header('accept-encoding', 'gzip');
+
+    // Streams (exports, sync progress) are sent as they are
+    if (typeof payload !== 'string' && !Buffer.isBuffer(payload)) {
+      return payload;
+    }
+    if (Buffer.byteLength(payload) < MIN_COMPRESS_SIZE || reply.getHeader('content-encoding')) {
+      return payload;
+    }
+    const vary = reply.getHeader('vary');
+    reply.header('vary', vary ? `${vary}, accept-encoding` : 'accept-encoding');
+    if (!/\bgzip\b/i.test(String(request.headers['accept-encoding'] ?? ''))) {
+      return payload;
+    }
+
+    reply.header('content-encoding', 'gzip');
+    reply.removeHeader('content-length');
+    return gzipAsync(payload);
+  });
+});
@@ -0,0 +1,33 @@
+import fp from 'fastify-plugin';
+import { createHash } from 'crypto';
+import { FastifyInstance } from 'fastify';
+
+/**
+ * ETag plugin
+ * Tags successful GET responses with a hash of their body and answers
+ * requests whose If-None-Match still matches with an empty 304, so clients
+ * that poll (like the CLI's shell prompt integration) can reuse what they
+ * already have
+ */
+export default fp(async (fastify: FastifyInstance) => {
+  fastify.addHook('onSend', async (request, reply, payload) => {
+    if (request.method !== 'GET' || reply.statusCode !== 200) {
+      return payload;
+    }
+    // Streams (exports, sync progress) are sent as they are
+    if (typeof payload !== 'string' && !Buffer.isBuffer(payload)) {
+      return payload;
+    }
+
+    const etag = `"${createHash('sha256').update(payload).digest('base64url').slice(0, 27)}"`;
+    reply.header('etag', etag);
+    reply.header('cache-control', 'private, no-cache');
+
+    const ifNoneMatch = request.headers['if-none-match'];
+    if (ifNoneMatch && ifNoneMatch.split(',').some((tag) => tag.trim() === etag)) {
+      reply.code(304);
+      return '';
+    }
+    return payload;
+  });
+});
@@ -0,0 +1,31 @@
+import fp from 'fastify-plugin';
+import { randomUUID } from 'crypto';
+import { IncomingMessage } from 'http';
+import { FastifyInstance } from 'fastify';
+
+// IDs clients may choose; anything else could garble the logs
+const CLIENT_REQUEST_ID = /^[\w.-]{1,64}$/;
+
+/**
+ * Picks a request's ID: the X-Request-ID its client sent, such as the CLI's
+ * ID for the run, if it is a plausible one, or else a new UUID. Passed to
+ * Fastify as genReqId, so the ID is on every log line of the request.
+ */
+export function requestId(req: IncomingMessage): string {
+  const header = req.headers['x-request-id'];
+  if (typeof header === 'string' && CLIENT_REQUEST_ID.test(header)) {
+    return header;
+  }
+  return randomUUID();
+}
+
+/**
+ * Request ID plugin
+ * Returns each request's ID as X-Request-ID, so a failure a user reports
+ * can be found in the logs
+ */
+export default fp(async (fastify: FastifyInstance) => {
+  fastify.addHook('onRequest', async (request, reply) => {
+    reply.header('x-request-id', request.id);
+  });
+});
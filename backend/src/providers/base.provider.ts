@@ -1,9 +1,11 @@
 import fs from 'fs/promises';
 import path from 'path';
 import { PrismaClient } from '@prisma/client';
-import { TimeProvider, SyncOptions, SyncResult, RawTimeEntry } from './provider.interface';
+import { TimeProvider, SyncOptions, SyncResult, SyncPreview, RawTimeEntry } from './provider.interface';
 
 const CACHE_DURATION_MS = 10 * 60 * 1000; // 10 minutes
+const PREVIEW_SAMPLE_SIZE = 10;
+const PROGRESS_EVERY = 25; // entries between progress reports while upserting
 
 export abstract class BaseTimeProvider implements TimeProvider {
   protected cacheFile: string;
@@ -57,9 +59,13 @@ export abstract class BaseTimeProvider implements TimeProvider {
   }
 
   /**
-   * Shared upsert logic for time entries
+   * Shared upsert logic for time entries. onProgress, if given, is told how
+   * many entries were upserted every few entries.
    */
-  protected async upsertEntries(entries: RawTimeEntry[]): Promise<number> {
+  protected async upsertEntries(
+    entries: RawTimeEntry[],
+    onProgress?: SyncOptions['onProgress']
+  ): Promise<number> {
     let count = 0;
     console.log(`[${this.providerName} DB] Processing ${entries.length} entries...`);
 
@@ -87,12 +93,55 @@ export abstract class BaseTimeProvider implements TimeProvider {
         }
       });
       count++;
+      if (count % PROGRESS_EVERY === 0) {
+        onProgress?.({ fetched: entries.length, imported: count });
+      }
     }
 
     console.log(`[${this.providerName} DB] Upserted ${count} entries.`);
     return count;
   }
 
+  /**
+   * Counts what upsertEntries would import, update and skip, without
+   * writing anything
+   */
+  protected async previewEntries(entries: RawTimeEntry[]): Promise<SyncPreview> {
+    const existing = await this.prisma.timeEntry.findMany({
+      where: {
+        source: this.providerName,
+        externalId: { in: entries.map((entry) => entry.externalId) },
+      },
+    });
+    const byId = new Map(existing.map((entry) => [entry.externalId, entry]));
+
+    const preview: SyncPreview = { wouldImport: 0, wouldUpdate: 0, wouldSkip: 0, sample: [] };
+    for (const entry of entries) {
+      const current = byId.get(entry.externalId);
+      if (!current) {
+        preview.wouldImport++;
+        if (preview.sample.length < PREVIEW_SAMPLE_SIZE) {
+          preview.sample.push({
+            date: entry.date.toISOString().split('T')[0],
+            project: entry.project || null,
+            duration: entry.duration,
+            description: entry.description || null,
+          });
+        }
+      } else if (
+        current.duration !== entry.duration ||
+        current.project !== (entry.project || null) ||
+        current.description !== (entry.description || null) ||
+        current.date.getTime() !== entry.date.getTime()
+      ) {
+        preview.wouldUpdate++;
+      } else {
+        preview.wouldSkip++;
+      }
+    }
+    return preview;
+  }
+
   /**
    * Calculate date range for sync
    */
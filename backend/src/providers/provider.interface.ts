@@ -11,6 +11,15 @@ export interface SyncOptions {
   forceRefresh?: boolean;
   customStart?: string;
   customEnd?: string;
+  dryRun?: boolean; // fetch and compare, but don't write anything
+  onProgress?: (progress: { fetched: number; imported: number }) => void;
+}
+
+export interface SyncPreview {
+  wouldImport: number; // entries not in the database yet
+  wouldUpdate: number; // entries that changed since the last sync
+  wouldSkip: number; // entries that are unchanged
+  sample: { date: string; project: string | null; duration: number; description: string | null }[];
 }
 
 export interface TimeProvider {
@@ -13,7 +13,7 @@ export class TempoProvider extends BaseTimeProvider {
   }
 
   async sync(options: SyncOptions = {}): Promise<SyncResult> {
-    const { forceRefresh = false, customStart, customEnd } = options;
+    const { forceRefresh = false, customStart, customEnd, dryRun = false, onProgress } = options;
 
     let rawEntries: any[] = [];
     let usedCache = false;
@@ -54,7 +54,18 @@ export class TempoProvider extends BaseTimeProvider {
 
     // Transform and upsert entries
     const transformedEntries = rawEntries.map(entry => this.transformEntry(entry));
-    const count = await this.upsertEntries(transformedEntries);
+    onProgress?.({ fetched: transformedEntries.length, imported: 0 });
+
+    if (dryRun) {
+      return {
+        count: 0,
+        cached: usedCache,
+        message: 'Preview',
+        preview: await this.previewEntries(transformedEntries),
+      };
+    }
+
+    const count = await this.upsertEntries(transformedEntries, onProgress);
 
     return {
       count,
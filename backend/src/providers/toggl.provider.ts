@@ -12,7 +12,7 @@ export class TogglProvider extends BaseTimeProvider {
   }
 
   async sync(options: SyncOptions = {}): Promise<SyncResult> {
-    const { forceRefresh = false, customStart, customEnd } = options;
+    const { forceRefresh = false, customStart, customEnd, dryRun = false, onProgress } = options;
 
     console.log(`[Toggl Service] Request: Force=${forceRefresh}, Start=${customStart}, End=${customEnd}`);
 
@@ -56,7 +56,18 @@ export class TogglProvider extends BaseTimeProvider {
       .filter(entry => entry.duration >= 0) // Skip running timers
       .map(entry => this.transformEntry(entry));
 
-    const count = await this.upsertEntries(transformedEntries);
+    onProgress?.({ fetched: transformedEntries.length, imported: 0 });
+
+    if (dryRun) {
+      return {
+        count: 0,
+        cached: usedCache,
+        message: 'Preview',
+        preview: await this.previewEntries(transformedEntries),
+      };
+    }
+
+    const count = await this.upsertEntries(transformedEntries, onProgress);
 
     return {
       count,
@@ -1,6 +1,7 @@
 import { FastifyInstance, FastifyRequest, FastifyReply } from 'fastify';
 import bcrypt from 'bcrypt';
 import { loadSecret } from '../utils/secrets';
+import { redeemRecoveryCode, verifyTotp } from '../utils/totp';
 
 // Extend session data interface
 declare module '@fastify/secure-session' {
@@ -182,7 +183,7 @@ export default async function (fastify: FastifyInstance) {
    * (NOT in cookies, since CLI tools can't use HttpOnly cookies)
    */
   fastify.post<{
-    Body: { username: string; password: string };
+    Body: { username: string; password: string; otp?: string };
   }>(
     '/api/auth/cli-login',
     {
@@ -226,6 +227,22 @@ export default async function (fastify: FastifyInstance) {
         return reply.code(401).send({ error: 'Invalid credentials' });
       }
 
+      // With two-factor authentication enabled, the password alone isn't
+      // enough. otpRequired tells the CLI to ask for a code and retry.
+      const totpSecret = loadSecret('admin_totp_secret', { required: false });
+      if (totpSecret) {
+        const otp = (request.body.otp || '').trim();
+        if (!otp) {
+          return reply.code(401).send({ error: 'TOTP required', otpRequired: true });
+        }
+        const recoveryCodes = (loadSecret('admin_recovery_codes', { required: false }) || '')
+          .split(/[\s,]+/)
+          .filter(Boolean);
+        if (!verifyTotp(totpSecret, otp) && !redeemRecoveryCode(recoveryCodes, otp)) {
+          return reply.code(401).send({ error: 'Invalid or expired one-time code', otpRequired: true });
+        }
+      }
+
       // Generate access token (short-lived: 15 minutes)
       const accessToken = fastify.jwt.sign(
         { userId: 1, role: 'admin' },
@@ -0,0 +1,242 @@
+import { FastifyInstance } from 'fastify';
+import { createHash, randomBytes } from 'crypto';
+import axios from 'axios';
+import { loadSecret } from '../utils/secrets';
+
+/**
+ * A CLI login waiting for the identity provider to send the user back
+ */
+interface PendingLogin {
+  redirectUri: string;
+  state: string;
+  codeChallenge: string;
+  expiresAt: number;
+}
+
+/**
+ * A completed login the CLI hasn't redeemed yet
+ */
+interface IssuedCode {
+  redirectUri: string;
+  codeChallenge: string;
+  expiresAt: number;
+}
+
+// Both steps must finish within a few minutes, like the CLI's own timeout
+const LOGIN_TTL_MS = 10 * 60 * 1000;
+const CODE_TTL_MS = 5 * 60 * 1000;
+
+// Redirect URI of CLIs that can't listen locally; the code is shown instead
+export const OOB_REDIRECT_URI = 'urn:ietf:wg:oauth:2.0:oob';
+
+const pendingLogins = new Map<string, PendingLogin>();
+const issuedCodes = new Map<string, IssuedCode>();
+
+function prune() {
+  const now = Date.now();
+  for (const [key, login] of pendingLogins) {
+    if (login.expiresAt < now) pendingLogins.delete(key);
+  }
+  for (const [key, code] of issuedCodes) {
+    if (code.expiresAt < now) issuedCodes.delete(key);
+  }
+}
+
+/**
+ * Only loopback listeners and the copy-paste flow may receive codes, so a
+ * crafted link can't send one to another host
+ */
+function allowedRedirect(uri: string): boolean {
+  if (uri === OOB_REDIRECT_URI) return true;
+  try {
+    const url = new URL(uri);
+    return url.protocol === 'http:' && (url.hostname === '127.0.0.1' || url.hostname === 'localhost');
+  } catch {
+    return false;
+  }
+}
+
+function base64url(buffer: Buffer): string {
+  return buffer.toString('base64').replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+function oidcConfig() {
+  const authorizeUrl = process.env.OIDC_AUTHORIZE_URL;
+  const tokenUrl = process.env.OIDC_TOKEN_URL;
+  const clientId = process.env.OIDC_CLIENT_ID;
+  const publicUrl = process.env.PUBLIC_URL;
+  if (!authorizeUrl || !tokenUrl || !clientId || !publicUrl) {
+    return null;
+  }
+  return { authorizeUrl, tokenUrl, clientId, callbackUrl: `${publicUrl}/api/auth/cli-sso/callback` };
+}
+
+/**
+ * Browser-based CLI login through the OIDC provider.
+ *
+ * The CLI opens /api/auth/cli-sso/authorize with a PKCE challenge, the
+ * server sends the user to the identity provider and, once they are back,
+ * hands a one-time code to the CLI's redirect URI (or shows it for pasting).
+ * The CLI redeems the code and its PKCE verifier at /api/auth/cli-sso/token
+ * for the same tokens /api/auth/cli-login returns.
+ */
+export default async function (fastify: FastifyInstance) {
+  /**
+   * GET /api/auth/cli-sso
+   * Tells the CLI whether SSO login is available
+   */
+  fastify.get('/api/auth/cli-sso', async () => {
+    return { enabled: oidcConfig() !== null };
+  });
+
+  /**
+   * GET /api/auth/cli-sso/authorize
+   * Starts a login and redirects to the identity provider
+   */
+  fastify.get<{
+    Querystring: { redirect_uri?: string; state?: string; code_challenge?: string; code_challenge_method?: string };
+  }>('/api/auth/cli-sso/authorize', async (request, reply) => {
+    const oidc = oidcConfig();
+    if (!oidc) {
+      return reply.code(404).send({ error: 'SSO login is not configured' });
+    }
+
+    const { redirect_uri, state, code_challenge, code_challenge_method } = request.query;
+    if (!redirect_uri || !allowedRedirect(redirect_uri)) {
+      return reply.code(400).send({ error: 'redirect_uri must be a localhost URL or ' + OOB_REDIRECT_URI });
+    }
+    if (!state || !code_challenge || code_challenge_method !== 'S256') {
+      return reply.code(400).send({ error: 'state and an S256 code_challenge are required' });
+    }
+
+    prune();
+    const nonce = base64url(randomBytes(24));
+    pendingLogins.set(nonce, {
+      redirectUri: redirect_uri,
+      state,
+      codeChallenge: code_challenge,
+      expiresAt: Date.now() + LOGIN_TTL_MS,
+    });
+
+    const target = new URL(oidc.authorizeUrl);
+    target.searchParams.set('response_type', 'code');
+    target.searchParams.set('client_id', oidc.clientId);
+    target.searchParams.set('redirect_uri', oidc.callbackUrl);
+    target.searchParams.set('scope', 'openid email');
+    target.searchParams.set('state', nonce);
+    return reply.redirect(target.toString());
+  });
+
+  /**
+   * GET /api/auth/cli-sso/callback
+   * Completes the login with the identity provider and hands a one-time
+   * code to the CLI
+   */
+  fastify.get<{
+    Querystring: { code?: string; state?: string; error?: string };
+  }>('/api/auth/cli-sso/callback', async (request, reply) => {
+    const oidc = oidcConfig();
+    const login = request.query.state ? pendingLogins.get(request.query.state) : undefined;
+    if (!oidc || !login || login.expiresAt < Date.now()) {
+      return reply.code(400).send({ error: 'Login expired; run timetracker login --sso again' });
+    }
+    pendingLogins.delete(request.query.state!);
+
+    if (request.query.error || !request.query.code) {
+      return reply.code(401).send({ error: `Identity provider refused the login: ${request.query.error || 'no code'}` });
+    }
+
+    let idToken: string | undefined;
+    try {
+      const response = await axios.post(
+        oidc.tokenUrl,
+        new URLSearchParams({
+          grant_type: 'authorization_code',
+          code: request.query.code,
+          redirect_uri: oidc.callbackUrl,
+          client_id: oidc.clientId,
+          client_secret: loadSecret('oidc_client_secret', { required: false }) || '',
+        }).toString(),
+        { headers: { 'Content-Type': 'application/x-www-form-urlencoded' } }
+      );
+      idToken = response.data.id_token;
+    } catch (err) {
+      fastify.log.error({ err }, 'OIDC code exchange failed');
+      return reply.code(502).send({ error: 'Could not complete the login with the identity provider' });
+    }
+
+    // Only the configured account may use this single-user server. The ID
+    // token came straight from the provider's token endpoint, so its claims
+    // can be read without verifying the signature.
+    const allowedEmail = process.env.OIDC_ALLOWED_EMAIL;
+    if (allowedEmail) {
+      let email: string | undefined;
+      try {
+        email = JSON.parse(Buffer.from((idToken || '').split('.')[1], 'base64url').toString()).email;
+      } catch {
+        email = undefined;
+      }
+      if (email?.toLowerCase() !== allowedEmail.toLowerCase()) {
+        return reply.code(403).send({ error: 'This account may not use TimeTracker' });
+      }
+    }
+
+    const code = base64url(randomBytes(24));
+    issuedCodes.set(code, {
+      redirectUri: login.redirectUri,
+      codeChallenge: login.codeChallenge,
+      expiresAt: Date.now() + CODE_TTL_MS,
+    });
+
+    if (login.redirectUri === OOB_REDIRECT_URI) {
+      return reply
+        .type('text/html')
+        .send(`<p>Paste this code into the terminal to finish logging in:</p><pre>${code}</pre>`);
+    }
+
+    const target = new URL(login.redirectUri);
+    target.searchParams.set('code', code);
+    target.searchParams.set('state', login.state);
+    return reply.redirect(target.toString());
+  });
+
+  /**
+   * POST /api/auth/cli-sso/token
+   * Redeems a one-time code for CLI tokens
+   */
+  fastify.post<{
+    Body: { code: string; codeVerifier: string; redirectUri: string };
+  }>(
+    '/api/auth/cli-sso/token',
+    {
+      config: {
+        rateLimit: {
+          max: 10,
+          timeWindow: '15 minutes',
+        },
+      },
+    },
+    async (request, reply) => {
+      const { code, codeVerifier, redirectUri } = request.body || ({} as any);
+      const issued = code ? issuedCodes.get(code) : undefined;
+      if (issued) issuedCodes.delete(code);
+
+      if (!issued || issued.expiresAt < Date.now() || issued.redirectUri !== redirectUri) {
+        return reply.code(401).send({ error: 'Invalid or expired login code' });
+      }
+      const challenge = base64url(createHash('sha256').update(codeVerifier || '').digest());
+      if (challenge !== issued.codeChallenge) {
+        return reply.code(401).send({ error: 'Code verifier does not match' });
+      }
+
+      const accessToken = fastify.jwt.sign({ userId: 1, role: 'admin' }, { expiresIn: '15m' });
+      const refreshToken = fastify.jwt.sign({ userId: 1, type: 'refresh' }, { expiresIn: '7d' });
+
+      return {
+        accessToken,
+        refreshToken,
+        expiresIn: 900, // 15 minutes in seconds
+      };
+    }
+  );
+}
@@ -1,23 +1,270 @@
-import { FastifyPluginAsync } from 'fastify';
+import { FastifyBaseLogger, FastifyPluginAsync } from 'fastify';
 import { PrismaClient } from '@prisma/client';
-import { startOfDay, endOfDay, startOfWeek, endOfWeek, format, eachDayOfInterval } from 'date-fns';
+import { startOfDay, endOfDay, startOfWeek, endOfWeek, format, eachDayOfInterval, differenceInCalendarDays, isValid, parseISO } from 'date-fns';
+import { randomUUID } from 'crypto';
+import { fromZonedTime, toZonedTime } from 'date-fns-tz';
 import { ProviderFactory } from '../providers/provider.factory';
+import { TimeProvider } from '../providers/provider.interface';
 
 const prisma = new PrismaClient();
 
+// Longest date range a single sync may backfill, in days
+const syncMaxRangeDays = parseInt(process.env.SYNC_MAX_RANGE_DAYS || '366', 10);
+
+// How long finished background syncs can still be looked up
+const SYNC_JOB_RETENTION_MS = 60 * 60 * 1000; // 1 hour
+
+interface SyncJobProvider {
+  provider: string;
+  status: 'pending' | 'running' | 'done' | 'failed';
+  fetched?: number;
+  imported?: number;
+  error?: string;
+}
+
+interface SyncJob {
+  id: string;
+  status: 'running' | 'done' | 'failed';
+  startedAt: string;
+  finishedAt?: string;
+  providers: SyncJobProvider[];
+  result?: Awaited<ReturnType<typeof runSync>>;
+  error?: string;
+}
+
+const syncJobs = new Map<string, SyncJob>();
+
+interface SyncQuery {
+  force?: string;
+  providers?: string;
+  from?: string;
+  to?: string;
+  dryRun?: string;
+}
+
+/**
+ * Validates a sync request's query and picks the requested providers, or
+ * all of them
+ */
+function parseSyncQuery(query: SyncQuery):
+  | { providers: TimeProvider[]; options: { force: boolean; dryRun: boolean; from?: string; to?: string } }
+  | { error: string } {
+  const { from, to } = query;
+
+  // Validate the backfill range, if any
+  if (from || to) {
+    const start = parseISO(from || '');
+    const end = parseISO(to || '');
+    if (!isValid(start) || !isValid(end)) {
+      return { error: 'from and to must both be YYYY-MM-DD dates' };
+    }
+    if (end < start) {
+      return { error: 'to is before from' };
+    }
+    if (end > endOfDay(new Date())) {
+      return { error: 'to is in the future' };
+    }
+    if (differenceInCalendarDays(end, start) + 1 > syncMaxRangeDays) {
+      return { error: `range is longer than ${syncMaxRangeDays} days` };
+    }
+  }
+
+  const requested = (query.providers || '')
+    .split(',')
+    .map((name) => name.trim().toLowerCase())
+    .filter((name) => name !== '');
+  const providers = ProviderFactory.getAllProviders(prisma).filter(
+    (provider) => requested.length === 0 || requested.includes(provider.getName().toLowerCase())
+  );
+
+  return {
+    providers,
+    options: { force: query.force === 'true', dryRun: query.dryRun === 'true', from, to },
+  };
+}
+
+/**
+ * Forgets background syncs that finished more than an hour ago
+ */
+function pruneSyncJobs() {
+  const cutoff = Date.now() - SYNC_JOB_RETENTION_MS;
+  for (const [id, job] of syncJobs) {
+    if (job.finishedAt && Date.parse(job.finishedAt) < cutoff) {
+      syncJobs.delete(id);
+    }
+  }
+}
+
+/**
+ * Syncs from each provider and summarizes the results. onProgress, if given,
+ * is told whenever a provider starts or finishes.
+ */
+async function runSync(
+  providers: TimeProvider[],
+  options: { force: boolean; dryRun: boolean; from?: string; to?: string },
+  log: FastifyBaseLogger,
+  onProgress?: (progress: SyncJobProvider) => void
+) {
+  const { force, dryRun, from, to } = options;
+
+  // Sync from each provider
+  const results = await Promise.allSettled(
+    providers.map(async (provider) => {
+      const name = provider.getName();
+
+      // Check if provider is configured
+      const isValid = await provider.validate();
+      if (!isValid) {
+        onProgress?.({ provider: name, status: 'failed', error: 'Provider not configured' });
+        return {
+          provider: name,
+          success: false,
+          error: 'Provider not configured',
+        };
+      }
+
+      onProgress?.({ provider: name, status: 'running' });
+      try {
+        let fetched = 0;
+        const result = await provider.sync({
+          forceRefresh: force,
+          customStart: from,
+          customEnd: to,
+          dryRun,
+          onProgress: (progress) => {
+            fetched = progress.fetched;
+            onProgress?.({ provider: name, status: 'running', ...progress });
+          },
+        });
+        onProgress?.({ provider: name, status: 'done', fetched, imported: dryRun ? 0 : result.count });
+        if (dryRun) {
+          return {
+            provider: name,
+            success: true,
+            ...result.preview,
+          };
+        }
+        return {
+          provider: name,
+          success: true,
+          imported: result.count, // Providers return 'count', not 'imported'
+          skipped: 0, // Providers don't track skipped separately
+        };
+      } catch (error) {
+        log.error({ provider: name, error }, 'Sync failed');
+        onProgress?.({ provider: name, status: 'failed', error: (error as Error).message });
+        return {
+          provider: name,
+          success: false,
+          error: (error as Error).message,
+        };
+      }
+    })
+  );
+
+  // Format results
+  const syncResults = results.map((result) => {
+    if (result.status === 'fulfilled') {
+      return result.value;
+    } else {
+      return {
+        provider: 'unknown',
+        success: false,
+        error: result.reason?.message || 'Unknown error',
+      };
+    }
+  });
+
+  // Calculate summary
+  const totalImported = syncResults.reduce(
+    (sum, r) => sum + (r.success && 'imported' in r ? r.imported || 0 : 0),
+    0
+  );
+  const totalSkipped = syncResults.reduce(
+    (sum, r) => sum + (r.success && 'skipped' in r ? r.skipped || 0 : 0),
+    0
+  );
+  const failedCount = syncResults.filter((r) => !r.success).length;
+
+  return {
+    success: failedCount === 0,
+    totalImported,
+    totalSkipped,
+    results: syncResults,
+    ...(from && to ? { from, to } : {}),
+    ...(dryRun ? { dryRun } : {}),
+  };
+}
+
 /**
  * Summary routes for CLI consumption
  * Provides aggregated time data for today, week, and sync operations
  */
+/**
+ * Converts between instants and wall-clock times in the timezone a summary
+ * request asks for (?tz=Europe/Berlin), so "today" and week boundaries
+ * follow the user rather than the server. Without tz the server's own zone
+ * is used. Returns null for unknown zones.
+ */
+function requestZone(query: unknown) {
+  const tz = (query as { tz?: string })?.tz;
+  if (!tz) {
+    return { toLocal: (date: Date) => date, fromLocal: (date: Date) => date };
+  }
+  try {
+    new Intl.DateTimeFormat('en-US', { timeZone: tz });
+  } catch {
+    return null;
+  }
+  return {
+    toLocal: (date: Date) => toZonedTime(date, tz),
+    fromLocal: (date: Date) => fromZonedTime(date, tz),
+  };
+}
+
+const KNOWN_SOURCES = ['TOGGL', 'TEMPO', 'MANUAL'];
+
+/**
+ * Reads the sources a summary request is limited to (?source=tempo,manual,
+ * or repeated), as stored in the database. Returns undefined without a
+ * filter and null for unknown sources.
+ */
+function requestSources(query: unknown): string[] | undefined | null {
+  const raw = (query as { source?: string | string[] })?.source;
+  if (!raw) {
+    return undefined;
+  }
+  const sources = [...new Set(
+    ([] as string[])
+      .concat(raw)
+      .flatMap((value) => value.split(','))
+      .map((value) => value.trim().toUpperCase())
+      .filter((value) => value !== '')
+  )];
+  if (sources.some((source) => !KNOWN_SOURCES.includes(source))) {
+    return null;
+  }
+  return sources.length > 0 ? sources : undefined;
+}
+
 const summaryRoutes: FastifyPluginAsync = async (fastify) => {
   /**
    * GET /api/entries/summary/today
-   * Returns today's total hours and breakdown by source
+   * Returns today's total hours and breakdown by source and project,
+   * optionally limited to some sources
    */
   fastify.get('/entries/summary/today', async (request, reply) => {
-    const now = new Date();
-    const dayStart = startOfDay(now);
-    const dayEnd = endOfDay(now);
+    const zone = requestZone(request.query);
+    if (!zone) {
+      return reply.code(400).send({ error: 'Unknown timezone' });
+    }
+    const sources = requestSources(request.query);
+    if (sources === null) {
+      return reply.code(400).send({ error: 'Unknown source (use toggl, tempo or manual)' });
+    }
+    const now = zone.toLocal(new Date());
+    const dayStart = zone.fromLocal(startOfDay(now));
+    const dayEnd = zone.fromLocal(endOfDay(now));
 
     // Get all entries for today
     const entries = await prisma.timeEntry.findMany({
@@ -26,36 +273,52 @@ const summaryRoutes: FastifyPluginAsync = async (fastify) => {
           gte: dayStart,
           lte: dayEnd,
         },
+        ...(sources && { source: { in: sources } }),
       },
       select: {
         duration: true,
         source: true,
+        project: true,
       },
     });
 
     // Calculate total hours
     const totalHours = entries.reduce((sum, entry) => sum + entry.duration, 0);
 
-    // Calculate breakdown by source
+    // Calculate breakdown by source and by project
     const bySource: Record<string, number> = {};
+    const byProject: Record<string, number> = {};
     entries.forEach((entry) => {
       bySource[entry.source] = (bySource[entry.source] || 0) + entry.duration;
+      const project = entry.project || '(no project)';
+      byProject[project] = (byProject[project] || 0) + entry.duration;
     });
 
     return {
       date: format(now, 'yyyy-MM-dd'),
       totalHours: Math.round(totalHours * 100) / 100, // Round to 2 decimals
       bySource,
+      byProject,
       entryCount: entries.length,
+      ...(sources && { sources: sources.map((source) => source.toLowerCase()) }),
     };
   });
 
   /**
    * GET /api/entries/summary/week
-   * Returns weekly totals with daily and source breakdown
+   * Returns weekly totals with daily, source and project breakdown,
+   * optionally limited to some sources
    */
   fastify.get('/entries/summary/week', async (request, reply) => {
-    const now = new Date();
+    const zone = requestZone(request.query);
+    if (!zone) {
+      return reply.code(400).send({ error: 'Unknown timezone' });
+    }
+    const sources = requestSources(request.query);
+    if (sources === null) {
+      return reply.code(400).send({ error: 'Unknown source (use toggl, tempo or manual)' });
+    }
+    const now = zone.toLocal(new Date());
     const weekStart = startOfWeek(now, { weekStartsOn: 1 }); // Monday
     const weekEnd = endOfWeek(now, { weekStartsOn: 1 }); // Sunday
 
@@ -63,14 +326,16 @@ const summaryRoutes: FastifyPluginAsync = async (fastify) => {
     const entries = await prisma.timeEntry.findMany({
       where: {
         date: {
-          gte: weekStart,
-          lte: weekEnd,
+          gte: zone.fromLocal(weekStart),
+          lte: zone.fromLocal(weekEnd),
         },
+        ...(sources && { source: { in: sources } }),
       },
       select: {
         date: true,
         duration: true,
         source: true,
+        project: true,
       },
       orderBy: {
         date: 'asc',
@@ -89,14 +354,17 @@ const summaryRoutes: FastifyPluginAsync = async (fastify) => {
     });
 
     entries.forEach((entry) => {
-      const dateKey = format(entry.date, 'yyyy-MM-dd');
+      const dateKey = format(zone.toLocal(entry.date), 'yyyy-MM-dd');
       byDay[dateKey] = (byDay[dateKey] || 0) + entry.duration;
     });
 
-    // Calculate breakdown by source
+    // Calculate breakdown by source and by project
     const bySource: Record<string, number> = {};
+    const byProject: Record<string, number> = {};
     entries.forEach((entry) => {
       bySource[entry.source] = (bySource[entry.source] || 0) + entry.duration;
+      const project = entry.project || '(no project)';
+      byProject[project] = (byProject[project] || 0) + entry.duration;
     });
 
     // Format daily breakdown as array for easier CLI consumption
@@ -115,84 +383,110 @@ const summaryRoutes: FastifyPluginAsync = async (fastify) => {
       totalHours: Math.round(totalHours * 100) / 100,
       daily,
       bySource,
+      byProject,
       entryCount: entries.length,
+      ...(sources && { sources: sources.map((source) => source.toLowerCase()) }),
     };
   });
 
+  /**
+   * GET /api/sync/settings
+   * Returns the limits of sync requests and whether they can be previewed
+   * or run in the background
+   */
+  fastify.get('/sync/settings', async (request, reply) => {
+    return { maxRangeDays: syncMaxRangeDays, dryRun: true, async: true };
+  });
+
   /**
    * POST /api/sync
-   * Triggers sync from all configured providers
+   * Triggers sync from all configured providers, or only those named in
+   * ?providers=toggl,tempo. With ?from=YYYY-MM-DD&to=YYYY-MM-DD only that
+   * range is fetched. With ?dryRun=true nothing is written; each result
+   * previews what would be imported, updated and skipped instead. With
+   * ?async=true the sync runs in the background and its job is returned.
    */
-  fastify.post<{ Querystring: { force?: string } }>('/sync', async (request, reply) => {
-    const force = request.query.force === 'true';
+  fastify.post<{ Querystring: SyncQuery & { async?: string } }>('/sync', async (request, reply) => {
+    const parsed = parseSyncQuery(request.query);
+    if ('error' in parsed) {
+      return reply.code(400).send({ error: parsed.error });
+    }
+    const { providers, options } = parsed;
 
-    // Get all providers
-    const providers = ProviderFactory.getAllProviders(prisma);
+    // Run in the background and return the job, for syncs that outlast
+    // proxy timeouts
+    if (request.query.async === 'true') {
+      pruneSyncJobs();
+      const job: SyncJob = {
+        id: randomUUID(),
+        status: 'running',
+        startedAt: new Date().toISOString(),
+        providers: providers.map((provider) => ({ provider: provider.getName(), status: 'pending' })),
+      };
+      syncJobs.set(job.id, job);
 
-    // Sync from each provider
-    const results = await Promise.allSettled(
-      providers.map(async (provider) => {
-        const name = provider.getName();
+      runSync(providers, options, fastify.log, (progress) => {
+        job.providers = job.providers.map((entry) => (entry.provider === progress.provider ? progress : entry));
+      })
+        .then((result) => {
+          job.status = 'done';
+          job.result = result;
+        })
+        .catch((error) => {
+          job.status = 'failed';
+          job.error = (error as Error).message;
+        })
+        .finally(() => {
+          job.finishedAt = new Date().toISOString();
+        });
 
-        // Check if provider is configured
-        const isValid = await provider.validate();
-        if (!isValid) {
-          return {
-            provider: name,
-            success: false,
-            error: 'Provider not configured',
-          };
-        }
+      return reply.code(202).send(job);
+    }
 
-        try {
-          const result = await provider.sync({ forceRefresh: force });
-          return {
-            provider: name,
-            success: true,
-            imported: result.count, // Providers return 'count', not 'imported'
-            skipped: 0, // Providers don't track skipped separately
-          };
-        } catch (error) {
-          fastify.log.error({ provider: name, error }, 'Sync failed');
-          return {
-            provider: name,
-            success: false,
-            error: (error as Error).message,
-          };
-        }
-      })
-    );
+    return runSync(providers, options, fastify.log);
+  });
 
-    // Format results
-    const syncResults = results.map((result) => {
-      if (result.status === 'fulfilled') {
-        return result.value;
-      } else {
-        return {
-          provider: 'unknown',
-          success: false,
-          error: result.reason?.message || 'Unknown error',
-        };
-      }
-    });
+  /**
+   * POST /api/sync/stream
+   * Syncs like POST /api/sync, but streams each provider's progress as
+   * newline-delimited JSON: {"type":"progress","progress":{...}} lines,
+   * then {"type":"result","result":{...}} or {"type":"error","error":"..."}
+   */
+  fastify.post<{ Querystring: SyncQuery }>('/sync/stream', async (request, reply) => {
+    const parsed = parseSyncQuery(request.query);
+    if ('error' in parsed) {
+      return reply.code(400).send({ error: parsed.error });
+    }
+    const { providers, options } = parsed;
 
-    // Calculate summary
-    const totalImported = syncResults.reduce(
-      (sum, r) => sum + (r.success && 'imported' in r ? r.imported || 0 : 0),
-      0
-    );
-    const totalSkipped = syncResults.reduce(
-      (sum, r) => sum + (r.success && 'skipped' in r ? r.skipped || 0 : 0),
-      0
+    reply.hijack();
+    reply.raw.writeHead(200, { 'Content-Type': 'application/x-ndjson', 'Cache-Control': 'no-cache' });
+    const send = (event: object) => reply.raw.write(JSON.stringify(event) + '\n');
+
+    providers.forEach((provider) =>
+      send({ type: 'progress', progress: { provider: provider.getName(), status: 'pending' } })
     );
-    const failedCount = syncResults.filter((r) => !r.success).length;
+    try {
+      const result = await runSync(providers, options, fastify.log, (progress) =>
+        send({ type: 'progress', progress })
+      );
+      send({ type: 'result', result });
+    } catch (error) {
+      send({ type: 'error', error: (error as Error).message });
+    }
+    reply.raw.end();
+  });
 
-    return {
-      success: failedCount === 0,
-      totalImported,
-      totalSkipped,
-      results: syncResults,
-    };
+  /**
+   * GET /api/sync/jobs/:id
+   * Returns a background sync's progress, and its result once done
+   */
+  fastify.get<{ Params: { id: string } }>('/sync/jobs/:id', async (request, reply) => {
+    const job = syncJobs.get(request.params.id);
+    if (!job) {
+      return reply.code(404).send({ error: 'Sync job not found' });
+    }
+    return job;
   });
 };
 
@@ -1,6 +1,6 @@
 import 'dotenv/config';
 import { ProviderFactory } from './providers/provider.factory';
-import Fastify from 'fastify';
+import Fastify, { FastifyReply, FastifyRequest } from 'fastify';
 import multipart from '@fastify/multipart';
 import cors from '@fastify/cors';
 import { PrismaClient } from '@prisma/client';
@@ -9,7 +9,11 @@ import { TempoCsvAdapter } from './adapters/tempo-csv.adapter';
 import authPlugin from './plugins/auth';
 import sessionPlugin from './plugins/session';
 import securityPlugin from './plugins/security';
+import etagPlugin from './plugins/etag';
+import compressionPlugin from './plugins/compression';
+import requestIdPlugin, { requestId } from './plugins/request-id';
 import authRoutes from './routes/auth.routes';
+import ssoRoutes from './routes/sso.routes';
 import exportRoutes from './routes/export.routes';
 import summaryRoutes from './routes/summary.routes';
 import { estimateRoutes } from './routes/estimate.routes';
@@ -18,7 +22,7 @@ import { createTimeEntrySchema, calculateDuration, generateManualExternalId } fr
 import { fromZonedTime } from 'date-fns-tz';
 
 const prisma = new PrismaClient();
-const app = Fastify({ logger: true });
+const app = Fastify({ logger: true, requestIdHeader: false, genReqId: requestId });
 
 // Register plugins
 app.register(cors, {
@@ -31,21 +35,29 @@ app.register(multipart);
 app.register(securityPlugin);
 app.register(authPlugin);
 app.register(sessionPlugin);
+app.register(etagPlugin);
+app.register(compressionPlugin);
+app.register(requestIdPlugin);
 
 // --- Routes ---
 
-// Health check endpoint (public, for Docker health checks)
-app.get('/health', async (request, reply) => {
+// Health check endpoints (public): /health for Docker health checks,
+// /api/health for clients, which only know the API's base URL
+const serverVersion = process.env.APP_VERSION || process.env.npm_package_version || 'unknown';
+const healthHandler = async (request: FastifyRequest, reply: FastifyReply) => {
   try {
     await prisma.$queryRaw`SELECT 1`;
-    return { status: 'healthy', timestamp: new Date().toISOString() };
+    return { status: 'healthy', timestamp: new Date().toISOString(), version: serverVersion };
   } catch (error) {
-    reply.code(503).send({ status: 'unhealthy', error: 'Database connection failed' });
+    reply.code(503).send({ status: 'unhealthy', error: 'Database connection failed', version: serverVersion });
   }
-});
+};
+app.get('/health', healthHandler);
+app.get('/api/health', healthHandler);
 
 // Auth routes (public - no authentication required)
 app.register(authRoutes);
+app.register(ssoRoutes);
 
 // Protected API routes (require authentication)
 app.register(async (protectedRoutes) => {
@@ -0,0 +1,89 @@
+import { createHash, createHmac, timingSafeEqual } from 'crypto';
+
+const BASE32_ALPHABET = 'ABCDEFGHIJKLMNOPQRSTUVWXYZ234567';
+
+// Time step and accepted clock drift, in steps either side of now
+const STEP_SECONDS = 30;
+const DRIFT_STEPS = 1;
+
+function base32Decode(input: string): Buffer {
+  const clean = input.replace(/[\s=-]/g, '').toUpperCase();
+  let bits = 0;
+  let value = 0;
+  const bytes: number[] = [];
+  for (const char of clean) {
+    const index = BASE32_ALPHABET.indexOf(char);
+    if (index === -1) {
+      throw new Error('TOTP secret is not valid base32');
+    }
+    value = (value << 5) | index;
+    bits += 5;
+    if (bits >= 8) {
+      bytes.push((value >>> (bits - 8)) & 0xff);
+      bits -= 8;
+    }
+  }
+  return Buffer.from(bytes);
+}
+
+/**
+ * Computes the 6-digit code for a time step (RFC 6238, HMAC-SHA1)
+ */
+export function totpCode(secret: string, step: number): string {
+  const counter = Buffer.alloc(8);
+  counter.writeBigUInt64BE(BigInt(step));
+  const hmac = createHmac('sha1', base32Decode(secret)).update(counter).digest();
+  const offset = hmac[hmac.length - 1] & 0x0f;
+  const binary = hmac.readUInt32BE(offset) & 0x7fffffff;
+  return (binary % 1_000_000).toString().padStart(6, '0');
+}
+
+/**
+ * Checks a code against the current time step and its neighbours
+ */
+export function verifyTotp(secret: string, code: string, now = Date.now()): boolean {
+  if (!/^\d{6}$/.test(code)) {
+    return false;
+  }
+  const current = Math.floor(now / 1000 / STEP_SECONDS);
+  for (let drift = -DRIFT_STEPS; drift <= DRIFT_STEPS; drift++) {
+    const expected = Buffer.from(totpCode(secret, current + drift));
+    if (timingSafeEqual(expected, Buffer.from(code))) {
+      return true;
+    }
+  }
+  return false;
+}
+
+/**
+ * Recovery codes are single-use; the ones spent since the server started are
+ * remembered by their hash
+ */
+const usedRecoveryCodes = new Set<string>();
+
+function normalizeRecoveryCode(code: string): string {
+  return code.replace(/[\s-]/g, '').toLowerCase();
+}
+
+/**
+ * Accepts a recovery code from the configured list once
+ */
+export function redeemRecoveryCode(configured: string[], code: string): boolean {
+  const presented = normalizeRecoveryCode(code);
+  if (!presented) {
+    return false;
+  }
+  const digest = createHash('sha256').update(presented).digest('hex');
+  if (usedRecoveryCodes.has(digest)) {
+    return false;
+  }
+  const match = configured.some((candidate) => {
+    const a = createHash('sha256').update(normalizeRecoveryCode(candidate)).digest();
+    const b = createHash('sha256').update(presented).digest();
+    return timingSafeEqual(a, b);
+  });
+  if (match) {
+    usedRecoveryCodes.add(digest);
+  }
+  return match;
+}
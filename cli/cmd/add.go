@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	addDate        string
+	addStart       string
+	addEnd         string
+	addProject     string
+	addDescription string
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a manual time entry",
+	Long: `Create a manual time entry from a start and end time.
+
+Examples:
+  timetracker add --start 09:00 --end 10:30 --project Internal -d "Weekly planning"
+  timetracker add --date yesterday --start 14:00 --end 17:15 --project WEKA-199`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDateFlag(addDate)
+		if err != nil {
+			return err
+		}
+
+		hours, err := clockDuration(addStart, addEnd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		project := resolveProject(addProject)
+		hours, err = addEntry(client, api.CreateEntryRequest{
+			Date:        date,
+			StartTime:   addStart,
+			EndTime:     addEnd,
+			Project:     project,
+			Description: addDescription,
+		}, hours)
+		if err != nil {
+			return fmt.Errorf("failed to add entry: %w", err)
+		}
+
+		display.Printf("✓ Added %s on %s (%s-%s)", display.FormatDuration(hours), date, addStart, addEnd)
+		if project != "" {
+			display.Printf(" to %s", project)
+		}
+		display.Println()
+
+		return nil
+	},
+}
+
+// addEntry creates a manual entry and records it as pending until the
+// summaries include it. It returns the entry's hours as stored, or hours if
+// the server doesn't say.
+func addEntry(client *api.Client, request api.CreateEntryRequest, hours float64) (float64, error) {
+	changes := newEntryChanges(client)
+	defer changes.record()
+
+	request.Timezone = dates.LocalZone()
+	entry, err := changes.create(request)
+	if err != nil {
+		return 0, err
+	}
+
+	if entry.Duration > 0 {
+		hours = entry.Duration
+	}
+	return hours, nil
+}
+
+// clockDuration returns the hours between two HH:mm times on the same day
+func clockDuration(start, end string) (float64, error) {
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time %q (expected HH:mm)", start)
+	}
+
+	endTime, err := time.Parse("15:04", end)
+	if err != nil {
+		return 0, fmt.Errorf("invalid end time %q (expected HH:mm)", end)
+	}
+
+	if !endTime.After(startTime) {
+		return 0, fmt.Errorf("end time must be after start time")
+	}
+
+	return endTime.Sub(startTime).Hours(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().StringVar(&addDate, "date", "today", "Date of the entry (YYYY-MM-DD, today or yesterday)")
+	addCmd.Flags().StringVar(&addStart, "start", "", "Start time (HH:mm)")
+	addCmd.Flags().StringVar(&addEnd, "end", "", "End time (HH:mm)")
+	addCmd.Flags().StringVarP(&addProject, "project", "p", "", "Project name or alias")
+	addCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Entry description")
+
+	addCmd.MarkFlagRequired("start")
+	addCmd.MarkFlagRequired("end")
+}
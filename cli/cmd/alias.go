@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+// aliasUsageFile records when each alias was last resolved. It is kept out
+// of the config file so using an alias doesn't rewrite the config.
+const aliasUsageFile = "alias-usage.json"
+
+// aliasCmd represents the alias command
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage short names for projects",
+	Long: `Manage project aliases. Wherever a project name is accepted (add --project,
+split --into, batch files), an alias is replaced by the project it stands
+for. Names that aren't aliases are used as they are.
+
+Aliases are stored in the config file under "aliases" and are not case
+sensitive.
+
+Examples:
+  timetracker alias set cli INTPROJ-ClientName-Retainer-2024
+  timetracker add --start 09:00 --end 12:00 --project cli
+  timetracker alias list
+  timetracker alias remove cli`,
+}
+
+// aliasSetCmd represents the alias set command
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <alias> <project>",
+	Short: "Create or change an alias",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias := strings.ToLower(strings.TrimSpace(args[0]))
+		project := strings.TrimSpace(args[1])
+		if alias == "" || project == "" {
+			return fmt.Errorf("alias and project must not be empty")
+		}
+		if strings.ContainsAny(alias, ". ") {
+			return fmt.Errorf("invalid alias %q: must not contain dots or spaces", args[0])
+		}
+
+		aliases := projectAliases()
+		aliases[alias] = project
+		if err := config.Set("aliases", aliases); err != nil {
+			return fmt.Errorf("failed to save alias: %w", err)
+		}
+
+		display.Printf("✓ %s → %s\n", alias, project)
+		return nil
+	},
+}
+
+// aliasRemoveCmd represents the alias remove command
+var aliasRemoveCmd = &cobra.Command{
+	Use:   "remove <alias>",
+	Short: "Delete an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alias := strings.ToLower(strings.TrimSpace(args[0]))
+
+		aliases := projectAliases()
+		if _, ok := aliases[alias]; !ok {
+			return fmt.Errorf("no alias named %q", args[0])
+		}
+		delete(aliases, alias)
+		if err := config.Set("aliases", aliases); err != nil {
+			return fmt.Errorf("failed to save aliases: %w", err)
+		}
+
+		if usage, err := openAliasUsage(); err == nil {
+			usage.Delete(alias)
+			usage.Save()
+		}
+
+		display.Printf("✓ Removed alias %s\n", alias)
+		return nil
+	},
+}
+
+// aliasListCmd represents the alias list command
+var aliasListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "Show aliases and when they were last used",
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases := projectAliases()
+		if len(aliases) == 0 {
+			display.Println("\nNo aliases defined. Add one with 'timetracker alias set <alias> <project>'.")
+			display.Println()
+			return nil
+		}
+
+		usage, _ := openAliasUsage()
+
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		display.Println()
+		table := display.NewTable("Alias", "Project", "Last Used")
+		for _, name := range names {
+			lastUsed := "never"
+			if usage != nil {
+				if used, ok := usage.Get(name); ok {
+					if t, err := time.Parse(time.RFC3339, used); err == nil {
+						lastUsed = t.Local().Format("2006-01-02 15:04")
+					}
+				}
+			}
+			table.AddRow(name, aliases[name], lastUsed)
+		}
+		table.Print()
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	localdata.Register(aliasUsageFile)
+}
+
+// projectAliases returns the configured aliases, keyed by lowercase alias
+func projectAliases() map[string]string {
+	aliases := map[string]string{}
+	for alias, project := range viper.GetStringMapString("aliases") {
+		aliases[strings.ToLower(alias)] = project
+	}
+	return aliases
+}
+
+// resolveProject returns the project an alias stands for, or name itself
+// if it isn't an alias. Resolving an alias records when it was last used.
+func resolveProject(name string) string {
+	alias := strings.ToLower(strings.TrimSpace(name))
+	project, ok := projectAliases()[alias]
+	if !ok || alias == "" {
+		return name
+	}
+
+	// Usage tracking is informational; failures must not block the command
+	if usage, err := openAliasUsage(); err == nil {
+		usage.Set(alias, time.Now().UTC().Format(time.RFC3339))
+		usage.Save()
+	}
+
+	return project
+}
+
+// openAliasUsage opens the alias usage store in the config directory
+func openAliasUsage() (*localdata.Store[string], error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return localdata.Open[string](filepath.Join(dir, aliasUsageFile))
+}
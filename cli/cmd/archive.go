@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/archive"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	archiveBefore string
+	archiveFrom   string
+	archiveOut    string
+	archiveDelete bool
+	archiveYes    bool
+)
+
+// archiveCmd represents the archive command
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Save old entries to a local archive file",
+	Long: `Write every entry dated before --before to a gzipped JSON Lines archive,
+to keep history the server no longer retains. The archive starts with a
+manifest recording the range, the number of entries and a SHA-256 hash of
+them.
+
+With --delete, the archive is read back and verified, and then the archived
+entries are deleted from the server after you confirm. Locked entries are
+archived but not deleted.
+
+Examples:
+  timetracker archive --before 2023-01-01 --out archive-2022.json.gz
+  timetracker archive --from 2022-01-01 --before 2023-01-01 --out archive-2022.json.gz --delete
+  timetracker archive inspect archive-2022.json.gz`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		before, err := dates.Parse(archiveBefore, time.Now())
+		if err != nil {
+			return err
+		}
+		to := dates.Format(before.AddDate(0, 0, -1))
+		from, err := parseDateFlag(archiveFrom)
+		if err != nil {
+			return err
+		}
+		if from != "" && to < from {
+			return fmt.Errorf("--from must be before --before")
+		}
+		if _, err := os.Stat(archiveOut); err == nil {
+			return fmt.Errorf("%s already exists", archiveOut)
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		if len(entries) == 0 {
+			display.Printf("No entries before %s to archive.\n", dates.Format(before))
+			return nil
+		}
+
+		written, err := writeArchive(archiveOut, entries, from, to)
+		if err != nil {
+			return err
+		}
+		display.Printf("✓ Archived %d entries (%s, %s to %s) to %s\n", written.Count, display.FormatDuration(written.Hours), written.First, written.Last, archiveOut)
+		display.Printf("  sha256 %s\n", written.SHA256)
+
+		if !archiveDelete {
+			return nil
+		}
+
+		// Only delete what the file on disk provably contains
+		file, err := os.Open(archiveOut)
+		if err != nil {
+			return fmt.Errorf("failed to verify archive: %w", err)
+		}
+		verified, archived, err := archive.Read(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("archive failed verification, nothing deleted: %w", err)
+		}
+		if verified.SHA256 != written.SHA256 {
+			return fmt.Errorf("archive on disk doesn't match what was written, nothing deleted")
+		}
+		display.Println("✓ Verified archive")
+
+		var deletable, locked []api.Entry
+		for _, entry := range archived {
+			if entry.Locked {
+				locked = append(locked, entry)
+			} else {
+				deletable = append(deletable, entry)
+			}
+		}
+		if len(locked) > 0 {
+			display.Printf("🔒 %d locked entries stay on the server\n", len(locked))
+		}
+		if len(deletable) == 0 {
+			return nil
+		}
+
+		if !archiveYes {
+			fmt.Printf("\nDelete the %d archived entries from the server? This can't be undone. Type %d to delete them: ", len(deletable), len(deletable))
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(answer) != strconv.Itoa(len(deletable)) {
+				display.Println("Cancelled. The archive was kept.")
+				return nil
+			}
+		}
+
+		changes := newEntryChanges(client)
+		defer changes.record()
+
+		showProgress := display.AnimationEnabled(os.Stderr)
+		var failed []string
+		for i, entry := range deletable {
+			if err := changes.delete(entry); err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%s): %v", entry.ID, entry.Day(), err))
+			}
+			if showProgress && ((i+1)%purgeBatchSize == 0 || i+1 == len(deletable)) {
+				fmt.Fprintf(os.Stderr, "\r%s", display.ProgressBar(i+1, len(deletable)))
+			}
+		}
+		if showProgress {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+
+		display.Printf("✓ Deleted %d entries from the server", len(deletable)-len(failed))
+		if len(failed) > 0 {
+			display.Printf(", %d failed", len(failed))
+		}
+		display.Println()
+		for _, failure := range failed {
+			display.Printf("  ✗ %s\n", failure)
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d deletions failed", len(failed), len(deletable))
+		}
+		return nil
+	},
+}
+
+// archiveInspectCmd represents the archive inspect command
+var archiveInspectCmd = &cobra.Command{
+	Use:          "inspect <file>",
+	Short:        "Summarize and verify an archive file",
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer file.Close()
+
+		manifest, entries, err := archive.Read(file)
+		if manifest.Format == "" {
+			return err
+		}
+
+		display.Printf("\n📦 %s\n\n", args[0])
+		display.Printf("  Created:  %s by %s\n", manifest.CreatedAt.Local().Format("2006-01-02 15:04"), manifest.CreatedBy)
+		display.Printf("  Range:    %s to %s\n", rangeEnd(manifest.From, "the beginning"), rangeEnd(manifest.To, "the end"))
+		display.Printf("  Entries:  %d (%s), %s to %s\n", manifest.Count, display.FormatDuration(manifest.Hours), manifest.First, manifest.Last)
+		display.Printf("  SHA-256:  %s\n", manifest.SHA256)
+		if err != nil {
+			display.Printf("  Verified: %s\n\n", display.Red("✗ "+err.Error()))
+			return errors.New("archive is damaged")
+		}
+		display.Printf("  Verified: %s\n\n", display.Green("✓"))
+
+		byMonth := map[string][]float64{}
+		bySource := map[string]float64{}
+		for _, entry := range entries {
+			month := entry.Day()
+			if len(month) >= 7 {
+				month = month[:7]
+			}
+			if byMonth[month] == nil {
+				byMonth[month] = make([]float64, 2)
+			}
+			byMonth[month][0]++
+			byMonth[month][1] += entry.Duration
+			bySource[entry.Source] += entry.Duration
+		}
+
+		months := make([]string, 0, len(byMonth))
+		for month := range byMonth {
+			months = append(months, month)
+		}
+		sort.Strings(months)
+		table := display.NewTable("Month", "Entries", "Hours")
+		for _, month := range months {
+			table.AddRow(month, fmt.Sprintf("%.0f", byMonth[month][0]), display.FormatHours(byMonth[month][1]))
+		}
+		table.Print()
+
+		if len(bySource) > 0 {
+			display.Println("\nBy source:")
+			for _, source := range sortedByHours(bySource) {
+				display.Printf("  • %-8s %s\n", source+":", display.FormatDuration(bySource[source]))
+			}
+		}
+		display.Println()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveInspectCmd)
+
+	archiveCmd.Flags().StringVar(&archiveBefore, "before", "", "Archive entries dated before this day (YYYY-MM-DD)")
+	archiveCmd.Flags().StringVar(&archiveFrom, "from", "", "Only archive entries on or after this day (default: all)")
+	archiveCmd.Flags().StringVar(&archiveOut, "out", "", "Archive file to write, e.g. archive-2022.json.gz")
+	archiveCmd.Flags().BoolVar(&archiveDelete, "delete", false, "Delete the entries from the server after verifying the archive")
+	archiveCmd.Flags().BoolVarP(&archiveYes, "yes", "y", false, "Delete without asking for confirmation")
+
+	archiveCmd.MarkFlagRequired("before")
+	archiveCmd.MarkFlagRequired("out")
+}
+
+// writeArchive writes the archive atomically, so a failed run never leaves
+// a partial file behind
+func writeArchive(path string, entries []api.Entry, from, to string) (archive.Manifest, error) {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return archive.Manifest{}, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	manifest, err := archive.Write(file, entries, from, to, time.Now())
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return archive.Manifest{}, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// rangeEnd names one end of a range, which may be open
+func rangeEnd(date, open string) string {
+	if date == "" {
+		return open
+	}
+	return date
+}
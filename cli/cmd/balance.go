@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/flex"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+	"github.com/vmiller/timetracker-cli/internal/settings"
+)
+
+// vacationFile stores the vacation weeks, keyed by ISO week
+const vacationFile = "vacation.json"
+
+var (
+	balanceFrom     string
+	balanceWeeks    int
+	vacationRemove  bool
+	vacationComment string
+)
+
+// balanceCmd represents the balance command
+var balanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Show your overtime (flex-time) balance",
+	Long: `Walk the weeks since balance_start, subtract the contract hours from
+each week's logged hours, and show the running flex balance.
+
+Weeks begin on the week_start day (Monday by default, or --week-start) and
+are named after the ISO week most of their days fall in, which is how
+vacation weeks are matched. Each workday owes its hours from
+the schedule ('timetracker schedule'), or an even share of contract_hours
+over work_days without one, so a partial first week and the current week
+only owe the days counted so far. Holidays ('timetracker holidays') owe
+nothing, and neither do weeks marked as vacation.
+
+Configure in ~/.timetracker/config.yaml:
+  contract_hours: 40
+  balance_start: 2024-01-01
+
+With --output json, csv or tsv, the weeks shown are written without the
+summary lines.
+
+Examples:
+  timetracker balance
+  timetracker balance --from 2024-06-01 --weeks 4
+  timetracker balance vacation 2024-W32`,
+	Annotations: outputFormats(display.FormatJSON, display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sources := settingSources(cmd)
+
+		start := balanceFrom
+		if start == "" {
+			startSetting, _ := settings.Lookup("balance_start")
+			start = settings.Resolve(startSetting, sources).Value
+		}
+		if start == "" {
+			return fmt.Errorf("no start date: set balance_start in the config file or pass --from")
+		}
+
+		now := time.Now()
+		from, err := dates.Parse(start, now)
+		if err != nil {
+			return err
+		}
+		to := dates.StartOfDay(now)
+		if from.After(to) {
+			return fmt.Errorf("start date %s is in the future", dates.Format(from))
+		}
+
+		cal, err := workCalendar(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(dates.Format(from), dates.Format(to))
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		hours := map[string]float64{}
+		for _, entry := range entries {
+			hours[entry.Day()] += entry.Duration
+		}
+
+		firstDay, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+		weeks := flex.Accumulate(from, to, hours, cal, firstDay)
+
+		display.Printf("\n⚖️  Flex balance since %s (%s/week)\n\n", dates.Format(from), display.FormatDuration(cal.WeeklyHours()))
+
+		shown := weeks
+		if balanceWeeks > 0 && len(shown) > balanceWeeks {
+			shown = shown[len(shown)-balanceWeeks:]
+		}
+
+		table := display.NewTable("Week", "From", "Hours", "Expected", "Delta", "Balance")
+		for _, week := range shown {
+			expected := display.FormatHours(week.Expected)
+			if week.Vacation {
+				expected = "vacation"
+			} else if week.Holidays == 1 {
+				expected += " (1 holiday)"
+			} else if week.Holidays > 1 {
+				expected += fmt.Sprintf(" (%d holidays)", week.Holidays)
+			}
+			table.AddCells(display.Text(week.ISOWeek), display.Text(dates.Format(week.Start)),
+				display.Hours(week.Hours), display.Cell{Text: expected, Value: week.Expected},
+				display.Cell{Text: formatDelta(week.Delta), Value: week.Delta},
+				display.Cell{Text: display.FormatSignedHours(week.Balance), Value: week.Balance})
+		}
+		table.Print()
+
+		if len(shown) < len(weeks) {
+			display.Printf("(showing the last %d of %d weeks)\n", len(shown), len(weeks))
+		}
+
+		var balance float64
+		if len(weeks) > 0 {
+			balance = weeks[len(weeks)-1].Balance
+		}
+		display.Printf("\n⏱️  Balance: %sh\n\n", formatDelta(balance))
+
+		return display.Result(table.Records())
+	},
+}
+
+// balanceVacationCmd represents the balance vacation command
+var balanceVacationCmd = &cobra.Command{
+	Use:   "vacation [week...]",
+	Short: "Mark ISO weeks as vacation, or list them",
+	Long: `Mark weeks as vacation so the balance doesn't expect contract hours for
+them. Weeks are ISO weeks such as 2024-W32. Without arguments, the recorded
+vacation weeks are listed.
+
+Examples:
+  timetracker balance vacation 2024-W32 2024-W33 --note "Summer holiday"
+  timetracker balance vacation 2024-W33 --remove
+  timetracker balance vacation`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openVacationStore()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			keys := store.Keys()
+			if len(keys) == 0 {
+				display.Println("\nNo vacation weeks recorded.")
+				display.Println()
+				return nil
+			}
+			display.Println("\n🏖️  Vacation weeks:")
+			for _, week := range keys {
+				note, _ := store.Get(week)
+				monday, _ := dates.ParseISOWeek(week, time.Local)
+				display.Printf("  • %s (from %s) %s\n", week, dates.Format(monday), note)
+			}
+			display.Println()
+			return nil
+		}
+
+		weeks := make([]string, 0, len(args))
+		for _, arg := range args {
+			monday, err := dates.ParseISOWeek(arg, time.Local)
+			if err != nil {
+				return err
+			}
+			weeks = append(weeks, dates.ISOWeek(monday))
+		}
+
+		for _, week := range weeks {
+			if vacationRemove {
+				store.Delete(week)
+			} else {
+				store.Set(week, vacationComment)
+			}
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save vacation weeks: %w", err)
+		}
+
+		for _, week := range weeks {
+			if vacationRemove {
+				display.Printf("✓ %s is no longer marked as vacation\n", week)
+			} else {
+				display.Printf("✓ %s marked as vacation\n", week)
+			}
+		}
+
+		return nil
+	},
+}
+
+// openVacationStore opens the vacation weeks store in the config directory
+func openVacationStore() (*localdata.Store[string], error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return localdata.Open[string](filepath.Join(dir, vacationFile))
+}
+
+func init() {
+	rootCmd.AddCommand(balanceCmd)
+	balanceCmd.AddCommand(balanceVacationCmd)
+	localdata.Register(vacationFile)
+
+	balanceCmd.Flags().StringVar(&balanceFrom, "from", "", "Start date (default: balance_start from the config file)")
+	balanceCmd.Flags().Float64("contract-hours", 40, "Contract hours per week (default: contract_hours from the config file)")
+	balanceCmd.Flags().IntVar(&balanceWeeks, "weeks", 12, "Number of most recent weeks to list (0 for all)")
+	addWeekStartFlag(balanceCmd)
+
+	balanceVacationCmd.Flags().BoolVar(&vacationRemove, "remove", false, "Unmark the weeks instead")
+	balanceVacationCmd.Flags().StringVar(&vacationComment, "note", "", "Note to store with the weeks")
+}
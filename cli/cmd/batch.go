@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/batch"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	batchDryRun   bool
+	batchFailFast bool
+)
+
+// batchResult is the outcome of one operation
+type batchResult struct {
+	Op      batch.Operation
+	Detail  string
+	Err     error
+	Skipped bool
+}
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch <file>",
+	Short: "Add, edit and delete entries from a file",
+	Long: `Run a file of entry operations. The file is YAML (a list of operations)
+or JSONL (one JSON object per line); .yaml and .yml files are read as YAML.
+
+Each operation has an op of add, edit or delete:
+  add     date, start and end (or duration), optional project, description
+  edit    id plus any of date, start, end, duration, project, description
+  delete  id
+
+Every operation is validated before anything is sent. A failing operation
+doesn't stop the rest unless --fail-fast is given.
+
+Example file (retainer.yaml):
+  - op: add
+    date: 2024-03-01
+    start: "09:00"
+    duration: 8h
+    project: Retainer
+  - op: edit
+    id: "1234"
+    description: Planning
+  - op: delete
+    id: "1235"
+
+Examples:
+  timetracker batch retainer.yaml --dry-run
+  timetracker batch retainer.yaml
+  timetracker batch changes.jsonl --fail-fast`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ops, err := batch.ParseFile(args[0])
+		if err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			display.Println("No operations in file.")
+			return nil
+		}
+
+		if errs := batch.Validate(ops, time.Now()); len(errs) > 0 {
+			display.Printf("\n❌ %s has %d invalid operations:\n", args[0], len(errs))
+			for _, err := range errs {
+				display.Printf("  • %v\n", err)
+			}
+			display.Println()
+			return fmt.Errorf("nothing was changed")
+		}
+
+		if batchDryRun {
+			display.Printf("\n📋 %d operations (dry run, nothing sent):\n\n", len(ops))
+			printBatchOperations(ops)
+			display.Println()
+			return nil
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		results := runBatch(client, ops, batchFailFast)
+
+		display.Println()
+		table := display.NewTable("Line", "Op", "Target", "Result")
+		var failed, skipped int
+		for _, result := range results {
+			status := display.Green("✓ " + result.Detail)
+			switch {
+			case result.Skipped:
+				status = "skipped"
+				skipped++
+			case result.Err != nil:
+				status = display.Red("✗ " + result.Err.Error())
+				failed++
+			}
+			table.AddRow(strconv.Itoa(result.Op.Line), result.Op.Op, batchTarget(result.Op), status)
+		}
+		table.Print()
+
+		display.Printf("\n%d succeeded, %d failed, %d skipped\n\n", len(results)-failed-skipped, failed, skipped)
+		if failed > 0 {
+			return fmt.Errorf("%d of %d operations failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.Flags().BoolVar(&batchDryRun, "dry-run", false, "Print the parsed operations without calling the API")
+	batchCmd.Flags().BoolVar(&batchFailFast, "fail-fast", false, "Stop at the first failed operation")
+}
+
+// runBatch executes the operations in order, drawing a progress bar on
+// stderr when it is a terminal. With failFast the operations after the first
+// failure are marked skipped.
+func runBatch(client *api.Client, ops []batch.Operation, failFast bool) []batchResult {
+	showProgress := display.AnimationEnabled(os.Stderr)
+	progress := func(done int) {
+		if showProgress {
+			fmt.Fprintf(os.Stderr, "\r%s", display.ProgressBar(done, len(ops)))
+		}
+	}
+
+	// Edits need the entry's current fields; fetch all entries once
+	var entries map[string]api.Entry
+	lookup := func(id string) (api.Entry, error) {
+		if entries == nil {
+			all, err := client.GetEntries("", "")
+			if err != nil {
+				return api.Entry{}, fmt.Errorf("failed to fetch entries: %w", err)
+			}
+			entries = make(map[string]api.Entry, len(all))
+			for _, entry := range all {
+				entries[entry.ID] = entry
+			}
+		}
+		entry, ok := entries[id]
+		if !ok {
+			return api.Entry{}, fmt.Errorf("no entry with ID %s", id)
+		}
+		return entry, nil
+	}
+
+	changes := newEntryChanges(client)
+	defer changes.record()
+
+	results := make([]batchResult, 0, len(ops))
+	stopped := false
+	progress(0)
+	for i, op := range ops {
+		result := batchResult{Op: op}
+		if stopped {
+			result.Skipped = true
+		} else {
+			result.Detail, result.Err = runBatchOperation(changes, op, lookup)
+			stopped = failFast && result.Err != nil
+		}
+		results = append(results, result)
+		progress(i + 1)
+	}
+	if showProgress {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+
+	return results
+}
+
+// runBatchOperation executes one validated operation and describes the result
+func runBatchOperation(changes *entryChanges, op batch.Operation, lookup func(string) (api.Entry, error)) (string, error) {
+	switch op.Op {
+	case batch.Add:
+		entry, err := changes.create(api.CreateEntryRequest{
+			Date:        op.Date,
+			StartTime:   op.Start,
+			EndTime:     op.End,
+			Project:     resolveProject(op.Project),
+			Description: op.Description,
+			Timezone:    dates.LocalZone(),
+		})
+		if err != nil {
+			return "", err
+		}
+		return "created " + entry.ID, nil
+
+	case batch.Edit:
+		entry, err := lookup(op.ID)
+		if err != nil {
+			return "", err
+		}
+		update, err := editUpdate(entry, op)
+		if err != nil {
+			return "", err
+		}
+		if _, err := changes.update(entry, update); err != nil {
+			return "", err
+		}
+		return "updated", nil
+
+	case batch.Delete:
+		// An entry missing from the listing is still deleted, its hours
+		// just can't be shown as pending
+		entry, err := lookup(op.ID)
+		if err != nil {
+			entry = api.Entry{ID: op.ID}
+		}
+		if err := changes.delete(entry); err != nil {
+			return "", err
+		}
+		return "deleted", nil
+	}
+
+	return "", fmt.Errorf("unknown op %q", op.Op)
+}
+
+// editUpdate applies an edit operation's fields on top of the entry
+func editUpdate(entry api.Entry, op batch.Operation) (api.UpdateEntryRequest, error) {
+	if op.Duration != "" {
+		hours, _ := strconv.ParseFloat(op.Duration, 64)
+		entry.Duration = hours
+	}
+	if op.Project != "" {
+		entry.Project = resolveProject(op.Project)
+	}
+	if op.Description != "" {
+		entry.Description = op.Description
+	}
+	if op.Start != "" {
+		entry.StartTime = op.Start
+	}
+	if op.Start != "" && op.End != "" {
+		start, _ := time.Parse("15:04", op.Start)
+		end, _ := time.Parse("15:04", op.End)
+		entry.Duration = end.Sub(start).Hours()
+	}
+
+	update, err := durationUpdate(entry, entry.Duration)
+	if err != nil {
+		return api.UpdateEntryRequest{}, err
+	}
+	if op.Date != "" {
+		update.Date = op.Date
+	}
+
+	return update, nil
+}
+
+// printBatchOperations lists parsed operations for --dry-run
+func printBatchOperations(ops []batch.Operation) {
+	table := display.NewTable("Line", "Op", "Target", "Time", "Project", "Description")
+	for _, op := range ops {
+		clock := ""
+		switch {
+		case op.Start != "":
+			clock = op.Start + "-" + op.End
+		case op.Duration != "":
+			clock = op.Duration + "h"
+		}
+		table.AddRow(strconv.Itoa(op.Line), op.Op, batchTarget(op), clock, op.Project, op.Description)
+	}
+	table.Print()
+}
+
+// batchTarget names what an operation acts on: the entry ID, or the date
+// of a new entry
+func batchTarget(op batch.Operation) string {
+	if op.ID != "" {
+		return op.ID
+	}
+	return op.Date
+}
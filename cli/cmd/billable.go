@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	billableFrom   string
+	billableTo     string
+	billableClient string
+)
+
+// billableHours holds billable and non-billable hours
+type billableHours struct {
+	Billable    float64
+	NonBillable float64
+}
+
+// Total returns the combined hours
+func (h billableHours) Total() float64 {
+	return h.Billable + h.NonBillable
+}
+
+// add counts an entry's duration on the matching side
+func (h *billableHours) add(entry api.Entry, fallback bool) {
+	if entry.IsBillable(fallback) {
+		h.Billable += entry.Duration
+	} else {
+		h.NonBillable += entry.Duration
+	}
+}
+
+// billableCmd represents the billable command
+var billableCmd = &cobra.Command{
+	Use:   "billable",
+	Short: "Show billable vs non-billable hours",
+	Long: `Show billable and non-billable hours for a date range, overall and per
+project. Defaults to the current week.
+
+Entries whose source doesn't track billability (e.g. Tempo or manual
+entries) count according to the billable_default setting.
+
+Examples:
+  timetracker billable
+  timetracker billable --from 2024-01-01 --to 2024-01-31
+  timetracker billable --client Acme`,
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseDateFlag(billableFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(billableTo)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if from == "" {
+			firstDay, err := weekStart(cmd)
+			if err != nil {
+				return err
+			}
+			from = dates.Format(dates.StartOfWeek(now, firstDay))
+		}
+		if to == "" {
+			to = dates.Format(now)
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		entries = filterByClient(entries, billableClient)
+
+		fallback := billableDefault(cmd)
+		var total billableHours
+		byProject := map[string]*billableHours{}
+		for _, entry := range entries {
+			total.add(entry, fallback)
+
+			project := entry.Project
+			if project == "" {
+				project = "(no project)"
+			}
+			if byProject[project] == nil {
+				byProject[project] = &billableHours{}
+			}
+			byProject[project].add(entry, fallback)
+		}
+
+		display.Printf("\n💰 Billable: %s to %s", from, to)
+		if billableClient != "" {
+			display.Printf(" (%s)", billableClient)
+		}
+		display.Print("\n\n")
+		display.Printf("  Billable:     %8s (%.0f%%)\n", display.FormatDuration(total.Billable), percent(total.Billable, total.Total()))
+		display.Printf("  Non-billable: %8s\n", display.FormatDuration(total.NonBillable))
+		display.Printf("  Total:        %8s\n\n", display.FormatDuration(total.Total()))
+
+		if len(byProject) == 0 {
+			return nil
+		}
+
+		projects := make([]string, 0, len(byProject))
+		for project := range byProject {
+			projects = append(projects, project)
+		}
+		sort.Slice(projects, func(i, j int) bool {
+			return byProject[projects[i]].Total() > byProject[projects[j]].Total()
+		})
+
+		table := display.NewTable("Project", "Billable", "Non-billable")
+		for _, project := range projects {
+			hours := byProject[project]
+			table.AddRow(project, display.FormatHours(hours.Billable), display.FormatHours(hours.NonBillable))
+		}
+		table.Print()
+		display.Println()
+
+		return nil
+	},
+}
+
+// addBillableFlags adds --billable and --non-billable, which limit a
+// command to billable or non-billable entries
+func addBillableFlags(cmd *cobra.Command, billable, nonBillable *bool) {
+	cmd.Flags().BoolVar(billable, "billable", false, "Only include billable entries")
+	cmd.Flags().BoolVar(nonBillable, "non-billable", false, "Only include non-billable entries")
+}
+
+// billableFilter returns true for --billable, false for --non-billable and
+// nil if neither was given
+func billableFilter(billable, nonBillable bool) (*bool, error) {
+	switch {
+	case billable && nonBillable:
+		return nil, fmt.Errorf("--billable and --non-billable can't be used together")
+	case billable || nonBillable:
+		return &billable, nil
+	}
+	return nil, nil
+}
+
+// filterByBillable keeps the entries whose billability is filter, counting
+// entries without a flag as fallback. A nil filter keeps every entry.
+func filterByBillable(entries []api.Entry, filter *bool, fallback bool) []api.Entry {
+	if filter == nil {
+		return entries
+	}
+
+	filtered := make([]api.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsBillable(fallback) == *filter {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// percent returns part as a percentage of whole, or zero for an empty whole
+func percent(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return part / whole * 100
+}
+
+func init() {
+	rootCmd.AddCommand(billableCmd)
+
+	billableCmd.Flags().StringVar(&billableFrom, "from", "", "Start date (YYYY-MM-DD, today, yesterday; default: start of this week)")
+	billableCmd.Flags().StringVar(&billableTo, "to", "", "End date (YYYY-MM-DD, today, yesterday; default: today)")
+	billableCmd.Flags().StringVar(&billableClient, "client", "", "Only count entries of this client (see 'timetracker clients')")
+	addWeekStartFlag(billableCmd)
+}
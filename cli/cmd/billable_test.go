@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+func TestFilterByBillable(t *testing.T) {
+	yes, no := true, false
+	entries := []api.Entry{
+		{ID: "billable", Billable: &yes},
+		{ID: "internal", Billable: &no},
+		{ID: "unflagged"},
+	}
+
+	ids := func(entries []api.Entry) []string {
+		var ids []string
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+	for _, test := range []struct {
+		billable, nonBillable, fallback bool
+		want                            []string
+	}{
+		{want: []string{"billable", "internal", "unflagged"}},
+		{billable: true, fallback: true, want: []string{"billable", "unflagged"}},
+		{billable: true, fallback: false, want: []string{"billable"}},
+		{nonBillable: true, fallback: false, want: []string{"internal", "unflagged"}},
+	} {
+		filter, err := billableFilter(test.billable, test.nonBillable)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(filterByBillable(entries, filter, test.fallback)); strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("billable=%v non-billable=%v fallback=%v: got %v, want %v", test.billable, test.nonBillable, test.fallback, got, test.want)
+		}
+	}
+
+	if _, err := billableFilter(true, true); err == nil {
+		t.Error("--billable with --non-billable accepted")
+	}
+}
+
+func TestUnappliedShareFilter(t *testing.T) {
+	billable := true
+	request := api.CreateShareRequest{Client: "Acme", Billable: &billable}
+
+	if flag := unappliedShareFilter(request, &api.Share{Client: "Acme"}); flag != "--billable" {
+		t.Errorf("share without billable = %q, want --billable", flag)
+	}
+	if flag := unappliedShareFilter(request, &api.Share{Billable: &billable}); flag != "--client" {
+		t.Errorf("share without client = %q, want --client", flag)
+	}
+	if flag := unappliedShareFilter(request, &api.Share{Client: "Acme", Billable: &billable}); flag != "" {
+		t.Errorf("fully filtered share = %q", flag)
+	}
+}
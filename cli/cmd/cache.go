@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of API responses",
+	Long: `Manage the API responses kept in ~/.timetracker/cache/.
+
+Responses the server tags with an ETag are cached, and later requests only
+download them again if they changed. Pass --no-cache to any command to
+bypass the cache.
+
+Examples:
+  timetracker cache clear`,
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:          "clear",
+	Short:        "Delete all cached API responses",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := responseCache()
+		if err != nil {
+			return fmt.Errorf("failed to locate cache: %w", err)
+		}
+		removed, err := cache.Clear()
+		if err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		display.Printf("✓ Removed %d cached response(s)\n", removed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/clients"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// clientsTopProjects is how many projects are named per client
+const clientsTopProjects = 3
+
+var (
+	clientsFrom string
+	clientsTo   string
+)
+
+// clientsCmd represents the clients command
+var clientsCmd = &cobra.Command{
+	Use:   "clients",
+	Short: "Show hours per client",
+	Long: `Show hours per client for a date range, with each client's largest
+projects. Defaults to the current month.
+
+Entries are grouped by the client the server reports. For entries without
+one, the "clients" mapping in the config file assigns projects to clients
+by name pattern:
+
+  clients:
+    Acme:
+      - ACME-*
+      - INTPROJ-Acme-*
+
+Examples:
+  timetracker clients
+  timetracker clients --from 2024-01-01 --to 2024-03-31`,
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseDateFlag(clientsFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(clientsTo)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if from == "" {
+			from = dates.Format(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()))
+		}
+		if to == "" {
+			to = dates.Format(now)
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		mapping := clientMapping()
+		var total float64
+		byClient := map[string]float64{}
+		projects := map[string]map[string]float64{}
+		for _, entry := range entries {
+			name := mapping.Resolve(entry.Client, entry.Project)
+			if projects[name] == nil {
+				projects[name] = map[string]float64{}
+			}
+			byClient[name] += entry.Duration
+			projects[name][projectLabel(entry.Project)] += entry.Duration
+			total += entry.Duration
+		}
+
+		display.Printf("\n🏢 Clients: %s to %s\n\n", from, to)
+		if len(byClient) == 0 {
+			display.Println("No entries in this range.")
+			display.Println()
+			return nil
+		}
+
+		names := sortedByHours(byClient)
+		table := display.NewTable("Client", "Hours", "Share", "Top Projects")
+		for _, name := range names {
+			top := sortedByHours(projects[name])
+			more := ""
+			if len(top) > clientsTopProjects {
+				more = fmt.Sprintf(" +%d more", len(top)-clientsTopProjects)
+				top = top[:clientsTopProjects]
+			}
+			table.AddRow(name,
+				display.FormatHours(byClient[name]),
+				fmt.Sprintf("%.0f%%", percent(byClient[name], total)),
+				strings.Join(top, ", ")+more,
+			)
+		}
+		table.AddRow("Total", display.FormatHours(total), "", "")
+		table.Print()
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(clientsCmd)
+
+	clientsCmd.Flags().StringVar(&clientsFrom, "from", "", "Start date (YYYY-MM-DD, today, yesterday; default: start of this month)")
+	clientsCmd.Flags().StringVar(&clientsTo, "to", "", "End date (YYYY-MM-DD, today, yesterday; default: today)")
+}
+
+// clientMapping returns the "clients" mapping from the config file. It is
+// read from the file directly since viper would lowercase the client names.
+func clientMapping() clients.Mapping {
+	mapping := clients.Mapping{}
+	if err := config.FileValue("clients", &mapping); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Ignoring client mapping: %v\n", err)
+	}
+	return mapping
+}
+
+// filterByClient keeps the entries belonging to the named client. An empty
+// filter keeps every entry.
+func filterByClient(entries []api.Entry, filter string) []api.Entry {
+	if filter == "" {
+		return entries
+	}
+
+	mapping := clientMapping()
+	filtered := make([]api.Entry, 0, len(entries))
+	for _, entry := range entries {
+		if clients.Matches(mapping.Resolve(entry.Client, entry.Project), filter) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// sortedByHours returns the keys ordered by hours, largest first
+func sortedByHours(hours map[string]float64) []string {
+	keys := make([]string, 0, len(hours))
+	for key := range hours {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if hours[keys[i]] != hours[keys[j]] {
+			return hours[keys[i]] > hours[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	compareWeek    string
+	compareAgainst string
+	compareToDate  bool
+)
+
+// weekTotals aggregates a week's entries
+type weekTotals struct {
+	Start     time.Time
+	ByDay     []float64
+	BySource  map[string]float64
+	ByProject map[string]float64
+	Total     float64
+}
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare this week with another week",
+	Long: `Compare a week's hours with another week, day by day and by source and
+project, to see whether you are ahead or behind.
+
+By default this week is compared with last week. With --to-date only the
+days up to today's weekday are compared, so a partially elapsed week is
+measured against the same part of the other week. Weeks begin on the
+week_start day (Monday by default, or --week-start).
+
+Examples:
+  timetracker compare
+  timetracker compare --to-date
+  timetracker compare --against 2024-01-08
+  timetracker compare --week 2024-02-05 --against last-week`,
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		start, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+
+		base := dates.StartOfWeek(now, start)
+		if compareWeek != "" {
+			t, err := dates.Parse(compareWeek, now)
+			if err != nil {
+				return err
+			}
+			base = dates.StartOfWeek(t, start)
+		}
+
+		var against time.Time
+		switch strings.ToLower(compareAgainst) {
+		case "", "last-week":
+			against = base.AddDate(0, 0, -7)
+		default:
+			t, err := dates.Parse(compareAgainst, now)
+			if err != nil {
+				return fmt.Errorf("invalid --against value: expected last-week or a date in the week: %w", err)
+			}
+			against = dates.StartOfWeek(t, start)
+		}
+
+		days := 7
+		if compareToDate {
+			days = int(dates.StartOfDay(now).Sub(dates.StartOfWeek(now, start)).Hours()/24) + 1
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		// Both weeks are fetched at once
+		var current, previous *weekTotals
+		group := api.NewFetchGroup(cmd.Context(), 0)
+		group.Go("entries for the week of "+dates.Format(base), func(ctx context.Context) (err error) {
+			current, err = fetchWeekTotals(ctx, client, base, days)
+			return err
+		})
+		group.Go("entries for the week of "+dates.Format(against), func(ctx context.Context) (err error) {
+			previous, err = fetchWeekTotals(ctx, client, against, days)
+			return err
+		})
+		if err := group.Wait(); err != nil {
+			return err
+		}
+
+		currentLabel := "Week of " + dates.Format(current.Start)
+		previousLabel := "Week of " + dates.Format(previous.Start)
+
+		display.Printf("\n📊 %s vs %s", currentLabel, previousLabel)
+		if days < 7 {
+			display.Printf(" (through %s)", current.Start.AddDate(0, 0, days-1).Format("Mon"))
+		}
+		display.Print("\n\n")
+
+		table := display.NewTable("Day", currentLabel, previousLabel, "Delta")
+		for i := 0; i < days; i++ {
+			table.AddRow(
+				current.Start.AddDate(0, 0, i).Format("Mon"),
+				display.FormatHours(current.ByDay[i]),
+				display.FormatHours(previous.ByDay[i]),
+				formatDelta(current.ByDay[i]-previous.ByDay[i]),
+			)
+		}
+		table.AddRow("Total",
+			display.FormatHours(current.Total),
+			display.FormatHours(previous.Total),
+			formatDelta(current.Total-previous.Total),
+		)
+		table.Print()
+
+		// csv and tsv hold the daily table only
+		if display.OutputFormat() == display.FormatTable {
+			printBreakdownComparison("Source", current.BySource, previous.BySource, currentLabel, previousLabel)
+			printBreakdownComparison("Project", current.ByProject, previous.ByProject, currentLabel, previousLabel)
+		}
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().StringVar(&compareWeek, "week", "", "A date in the week to compare (default: this week)")
+	compareCmd.Flags().StringVar(&compareAgainst, "against", "last-week", "Week to compare against: last-week or a date in that week")
+	compareCmd.Flags().BoolVar(&compareToDate, "to-date", false, "Only compare days up to today's weekday")
+	addWeekStartFlag(compareCmd)
+}
+
+// fetchWeekTotals aggregates the first days of the week starting at start.
+// Errors are the client's, for the caller to say what failed.
+func fetchWeekTotals(ctx context.Context, client *api.Client, start time.Time, days int) (*weekTotals, error) {
+	from := dates.Format(start)
+	to := dates.Format(start.AddDate(0, 0, days-1))
+
+	entries, err := client.GetEntriesContext(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &weekTotals{
+		Start:     start,
+		ByDay:     make([]float64, days),
+		BySource:  map[string]float64{},
+		ByProject: map[string]float64{},
+	}
+
+	for _, entry := range entries {
+		day, err := time.ParseInLocation(dates.Layout, entry.Day(), start.Location())
+		if err != nil {
+			continue
+		}
+		index := int(day.Sub(start).Hours()/24 + 0.5)
+		if index < 0 || index >= days {
+			continue
+		}
+
+		project := entry.Project
+		if project == "" {
+			project = "(no project)"
+		}
+
+		totals.ByDay[index] += entry.Duration
+		totals.BySource[entry.Source] += entry.Duration
+		totals.ByProject[project] += entry.Duration
+		totals.Total += entry.Duration
+	}
+
+	return totals, nil
+}
+
+// printBreakdownComparison renders a per-key comparison table, largest
+// current values first
+func printBreakdownComparison(title string, current, previous map[string]float64, currentLabel, previousLabel string) {
+	keys := map[string]bool{}
+	for key := range current {
+		keys[key] = true
+	}
+	for key := range previous {
+		keys[key] = true
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if current[sorted[i]] != current[sorted[j]] {
+			return current[sorted[i]] > current[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	display.Printf("\nBy %s:\n", title)
+	table := display.NewTable(title, currentLabel, previousLabel, "Delta")
+	for _, key := range sorted {
+		table.AddRow(key,
+			display.FormatHours(current[key]),
+			display.FormatHours(previous[key]),
+			formatDelta(current[key]-previous[key]),
+		)
+	}
+	table.Print()
+}
+
+// formatDelta renders an hour difference, green when ahead and red when behind
+func formatDelta(delta float64) string {
+	text := display.FormatSignedHours(delta)
+	switch {
+	case delta >= 0.005:
+		return display.Green(text)
+	case delta <= -0.005:
+		return display.Red(text)
+	default:
+		return display.FormatHours(0)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+	"github.com/vmiller/timetracker-cli/internal/settings"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the CLI configuration",
+}
+
+// configEffectiveCmd represents the config effective command
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Show each setting's value and where it came from",
+	Long: `Show the final value of every setting and the layer that provided it.
+
+Settings are resolved in this order, later layers winning:
+  default < server < config < env < flag < managed
+
+Server preferences are organisation-wide defaults configured by an admin.
+They are refreshed once per day and never override values you set yourself.
+Managed values come from the system-wide overlay installed by IT and are
+locked: they cannot be overridden.`,
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table := display.NewTable("Setting", "Value", "Source")
+		for _, resolved := range settings.ResolveAll(settingSources(cmd)) {
+			source := resolved.Layer.String()
+			if resolved.Layer == settings.LayerManaged {
+				source += " (locked)"
+			}
+			value := resolved.Value
+			if resolved.Key == "proxy_url" {
+				value = logging.RedactURL(value) // may hold a password
+			}
+			table.AddRow(resolved.Key, value, source)
+		}
+
+		display.Println()
+		table.Print()
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configEffectiveCmd)
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+// continuePickWindow is how many days back `continue N` looks for entries
+const continuePickWindow = 14
+
+// timerFile holds the timer started by continue until stop logs it
+const timerFile = "timer.yaml"
+
+// runningTimerKey is the key of the running timer in the timer store
+const runningTimerKey = "running"
+
+var continueStart string
+
+// runningTimer is a task being worked on since Start. The server has no
+// timers, so it is kept locally and becomes a manual entry on stop.
+type runningTimer struct {
+	Project     string `json:"project,omitempty" yaml:"project,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Date        string `json:"date" yaml:"date"`   // YYYY-MM-DD
+	Start       string `json:"start" yaml:"start"` // HH:mm
+}
+
+// continueCmd represents the continue command
+var continueCmd = &cobra.Command{
+	Use:   "continue [N]",
+	Short: "Start a timer for the most recent entry's task",
+	Long: `Start a timer with the same project and description as your most recent
+entry from today (or yesterday, if nothing is logged today). The timer runs
+from now, or from --start to log time you already spent; 'timetracker stop'
+ends it and adds the entry.
+
+With a number, the last N entries are listed and you pick which one to resume.
+
+Examples:
+  timetracker continue
+  timetracker continue 3
+  timetracker continue --start 13:00`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count := 0
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of entries %q", args[0])
+			}
+			count = n
+		}
+
+		store, err := openTimerStore()
+		if err != nil {
+			return fmt.Errorf("failed to open the timer: %w", err)
+		}
+		if running, ok := store.Get(runningTimerKey); ok {
+			return fmt.Errorf("a timer for %s has been running since %s; run 'timetracker stop' first", timerLabel(running), running.Start)
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var entry api.Entry
+		if count == 0 {
+			recent, err := mostRecentEntry(client, dates.StartOfDay(now))
+			if err != nil {
+				return err
+			}
+			entry = *recent
+		} else {
+			picked, err := pickRecentEntry(client, dates.StartOfDay(now), count)
+			if err != nil {
+				return err
+			}
+			entry = picked
+		}
+
+		timer, err := startTimer(store, entry, continueStart, now)
+		if err != nil {
+			return err
+		}
+
+		display.Printf("▶️  Resumed: %s since %s\n", timerLabel(timer), timer.Start)
+		display.Println("   Run 'timetracker stop' when you're done to log it.")
+		return nil
+	},
+}
+
+// mostRecentEntry returns today's latest entry, or yesterday's if today is empty
+func mostRecentEntry(client *api.Client, today time.Time) (*api.Entry, error) {
+	for _, day := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		date := dates.Format(day)
+		entries, err := client.GetEntries(date, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		if len(entries) > 0 {
+			sortByRecency(entries)
+			return &entries[0], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no entries today or yesterday to continue")
+}
+
+// pickRecentEntry lists the last count entries and asks which one to resume
+func pickRecentEntry(client *api.Client, today time.Time, count int) (api.Entry, error) {
+	from := dates.Format(today.AddDate(0, 0, -continuePickWindow))
+	entries, err := client.GetEntries(from, dates.Format(today))
+	if err != nil {
+		return api.Entry{}, fmt.Errorf("failed to fetch entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return api.Entry{}, fmt.Errorf("no entries in the last %d days", continuePickWindow)
+	}
+
+	sortByRecency(entries)
+	if len(entries) > count {
+		entries = entries[:count]
+	}
+	return pickEntry(entries)
+}
+
+// startTimer starts a timer for entry's task at start (HH:mm), or now if
+// start is empty, and saves it
+func startTimer(store *localdata.Store[runningTimer], entry api.Entry, start string, now time.Time) (runningTimer, error) {
+	if start == "" {
+		start = now.Format("15:04")
+	} else if _, err := time.Parse("15:04", start); err != nil {
+		return runningTimer{}, fmt.Errorf("invalid start time %q (expected HH:mm)", start)
+	} else if start > now.Format("15:04") {
+		return runningTimer{}, fmt.Errorf("start time %s is in the future", start)
+	}
+
+	timer := runningTimer{
+		Project:     entry.Project,
+		Description: entry.Description,
+		Date:        dates.Format(now),
+		Start:       start,
+	}
+	store.Set(runningTimerKey, timer)
+	if err := store.Save(); err != nil {
+		return runningTimer{}, fmt.Errorf("failed to save the timer: %w", err)
+	}
+	return timer, nil
+}
+
+// openTimerStore opens the store of the running timer
+func openTimerStore() (*localdata.Store[runningTimer], error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return localdata.Open[runningTimer](filepath.Join(dir, timerFile))
+}
+
+// timerLabel describes a timer's task, e.g. "ACME — Code review"
+func timerLabel(timer runningTimer) string {
+	label := timer.Project
+	if label == "" {
+		label = "(no project)"
+	}
+	if timer.Description != "" {
+		label += " — " + timer.Description
+	}
+	return label
+}
+
+// sortByRecency orders entries newest first. Entries on the same date are
+// ordered by their start and then end times.
+func sortByRecency(entries []api.Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Date != b.Date {
+			return a.Date > b.Date
+		}
+		if a.StartTime != b.StartTime {
+			return a.StartTime > b.StartTime
+		}
+		return a.EndTime > b.EndTime
+	})
+}
+
+// pickEntry lists entries and asks which one to resume
+func pickEntry(entries []api.Entry) (api.Entry, error) {
+	table := display.NewTable("#", "Date", "Project", "Description", "Hours")
+	for i, entry := range entries {
+		table.AddRow(strconv.Itoa(i+1), entry.Day(), entry.Project, entry.Description, display.FormatHours(entry.Duration))
+	}
+	fmt.Println()
+	fmt.Print(table.Render())
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("Resume which entry? [1-%d]: ", len(entries))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return api.Entry{}, fmt.Errorf("no entry selected")
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(line))
+		if err == nil && choice >= 1 && choice <= len(entries) {
+			return entries[choice-1], nil
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(continueCmd)
+	localdata.Register(timerFile)
+
+	continueCmd.Flags().StringVar(&continueStart, "start", "", "Start the timer earlier today (HH:mm; default: now)")
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+func TestSortByRecency(t *testing.T) {
+	entries := []api.Entry{
+		{ID: "morning", Date: "2024-01-10", StartTime: "09:00", EndTime: "10:00"},
+		{ID: "yesterday", Date: "2024-01-09", StartTime: "16:00", EndTime: "17:00"},
+		{ID: "afternoon", Date: "2024-01-10", StartTime: "13:00", EndTime: "15:00"},
+		{ID: "afternoon-short", Date: "2024-01-10", StartTime: "13:00", EndTime: "13:30"},
+	}
+	sortByRecency(entries)
+
+	want := []string{"afternoon", "afternoon-short", "morning", "yesterday"}
+	for i, id := range want {
+		if entries[i].ID != id {
+			t.Fatalf("entry %d = %s, want %s", i, entries[i].ID, id)
+		}
+	}
+}
+
+// at returns today at hh:mm
+func at(hour, minute int) time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.Local)
+}
+
+func TestContinueAfterLunch(t *testing.T) {
+	today := dates.Format(time.Now())
+	server := newFakeServer(t, api.Entry{
+		ID: "morning", Source: "MANUAL", Date: today, StartTime: "09:00", EndTime: "12:00", Duration: 3,
+		Project: "ACME", Description: "Code review",
+	})
+	client := server.client()
+	store, err := openTimerStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Back from lunch at 13:05
+	entry, err := mostRecentEntry(client, dates.StartOfDay(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	timer, err := startTimer(store, *entry, "", at(13, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timer.Start != "13:05" || timer.Project != "ACME" || timer.Description != "Code review" {
+		t.Fatalf("timer = %+v, want ACME Code review from 13:05", timer)
+	}
+	if len(server.created) != 0 {
+		t.Fatalf("continue created entries %+v before stop", server.created)
+	}
+
+	// The timer is kept locally until stop
+	if _, ok := store.Get(runningTimerKey); !ok {
+		t.Fatal("the timer was not saved")
+	}
+
+	_, end, hours, err := stopTimer(client, store, "", at(15, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end != "15:05" || hours != 2 {
+		t.Errorf("stop logged %.2fh until %s, want 2h until 15:05", hours, end)
+	}
+	if len(server.created) != 1 || server.created[0].StartTime != "13:05" || server.created[0].EndTime != "15:05" {
+		t.Fatalf("created %+v, want one entry 13:05-15:05 without the lunch break", server.created)
+	}
+	if _, ok := store.Get(runningTimerKey); ok {
+		t.Error("the timer is still running after stop")
+	}
+}
+
+func TestContinueYesterdaysEntry(t *testing.T) {
+	yesterday := dates.Format(time.Now().AddDate(0, 0, -1))
+	server := newFakeServer(t, api.Entry{
+		ID: "toggl", Source: "TOGGL", Date: yesterday, Duration: 6, Project: "Internal", Description: "Onboarding",
+	})
+	store, err := openTimerStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := mostRecentEntry(server.client(), dates.StartOfDay(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	timer, err := startTimer(store, *entry, "", at(9, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timer.Project != "Internal" || timer.Start != "09:30" || timer.Date != dates.Format(time.Now()) {
+		t.Errorf("timer = %+v, want Internal from 09:30 today", timer)
+	}
+}
+
+func TestContinueStartTimes(t *testing.T) {
+	newFakeServer(t)
+	store, err := openTimerStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := api.Entry{Project: "ACME"}
+
+	if _, err := startTimer(store, entry, "14:00", at(13, 5)); err == nil || !strings.Contains(err.Error(), "future") {
+		t.Errorf("start in the future = %v", err)
+	}
+	if _, err := startTimer(store, entry, "1pm", at(13, 5)); err == nil {
+		t.Error("invalid start accepted")
+	}
+	timer, err := startTimer(store, entry, "12:30", at(13, 5))
+	if err != nil || timer.Start != "12:30" {
+		t.Errorf("backfilled timer = %+v, %v", timer, err)
+	}
+}
+
+func TestStopRefusesPastMidnight(t *testing.T) {
+	server := newFakeServer(t)
+	store, err := openTimerStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Set(runningTimerKey, runningTimer{Project: "ACME", Date: dates.Format(time.Now().AddDate(0, 0, -1)), Start: "22:00"})
+
+	if _, _, _, err := stopTimer(server.client(), store, "", at(9, 0)); err == nil || !strings.Contains(err.Error(), "past midnight") {
+		t.Errorf("stop = %v, want a past-midnight error", err)
+	}
+	if len(server.created) != 0 {
+		t.Errorf("created %+v", server.created)
+	}
+}
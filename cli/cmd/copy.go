@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// copyDefaultStart is where copies of entries without a start time begin,
+// unless the day's timed entries end later; they follow each other back to
+// back
+const copyDefaultStart = 9 * 60
+
+var (
+	copyTo           string
+	copyToRange      string
+	copyWorkdaysOnly bool
+	copyProject      string
+	copyMerge        bool
+	copySkipExisting bool
+	copyYes          bool
+)
+
+// copyCmd represents the copy command
+var copyCmd = &cobra.Command{
+	Use:   "copy <date>",
+	Short: "Copy a day's entries to other days",
+	Long: `Duplicate the entries of one day onto another day or a range of days as
+manual entries. Entries keep their clock times; entries without one (e.g.
+duration-only imports) are placed back to back from 09:00, or after the
+day's last timed entry. The source day itself is never a target.
+
+A copy collides with an existing entry on the target day when their times
+overlap or they have the same project and description. Collisions are
+listed and nothing is created unless you pass --merge (copy them anyway)
+or --skip-existing (leave the colliding copies out).
+
+The copies are previewed and created after you confirm, or right away with
+--yes.
+
+Examples:
+  timetracker copy 2024-04-01 --to 2024-04-02
+  timetracker copy yesterday --to today --project RETAINER
+  timetracker copy 2024-04-01 --to-range 2024-04-02..2024-04-05 --workdays-only --skip-existing`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, err := parseDateFlag(args[0])
+		if err != nil {
+			return err
+		}
+		if copyMerge && copySkipExisting {
+			return fmt.Errorf("--merge and --skip-existing can't be combined")
+		}
+
+		targets, err := copyTargets(cmd, source)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(source, source)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		if copyProject != "" {
+			project := resolveProject(copyProject)
+			filtered := entries[:0]
+			for _, entry := range entries {
+				if strings.EqualFold(entry.Project, project) {
+					filtered = append(filtered, entry)
+				}
+			}
+			entries = filtered
+		}
+		if len(entries) == 0 {
+			display.Printf("No entries to copy on %s.\n", source)
+			return nil
+		}
+
+		templates, err := copyTemplates(entries)
+		if err != nil {
+			return err
+		}
+
+		existing, err := client.GetEntries(targets[0], targets[len(targets)-1])
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries on the target days: %w", err)
+		}
+		byDay := map[string][]api.Entry{}
+		for _, entry := range existing {
+			byDay[entry.Day()] = append(byDay[entry.Day()], entry)
+		}
+
+		var copies []plannedCopy
+		conflicts := 0
+		for _, target := range targets {
+			for _, template := range templates {
+				planned := plannedCopy{Date: target, copyTemplate: template}
+				planned.Conflict = copyConflict(template, byDay[target])
+				if planned.Conflict != "" {
+					conflicts++
+				}
+				copies = append(copies, planned)
+			}
+		}
+
+		days := fmt.Sprintf("%d days", len(targets))
+		if len(targets) == 1 {
+			days = targets[0]
+		}
+		display.Printf("\n📋 Copying %d entries from %s to %s\n\n", len(templates), source, days)
+		table := display.NewTable("Date", "Time", "Project", "Description", "Hours", "Status")
+		var create []plannedCopy
+		for _, planned := range copies {
+			status := display.Green("new")
+			switch {
+			case planned.Conflict != "" && copySkipExisting:
+				status = "skipped: " + planned.Conflict
+			case planned.Conflict != "" && copyMerge:
+				status = "merged: " + planned.Conflict
+			case planned.Conflict != "":
+				status = display.Red("conflict: " + planned.Conflict)
+			}
+			if planned.Conflict == "" || copyMerge {
+				create = append(create, planned)
+			}
+			table.AddRow(planned.Date, formatClock(planned.Start)+"-"+formatClock(planned.End),
+				projectLabel(planned.Project), planned.Description,
+				display.FormatHours(float64(planned.End-planned.Start)/60), status)
+		}
+		table.Print()
+		display.Println()
+
+		if conflicts > 0 && !copyMerge && !copySkipExisting {
+			return fmt.Errorf("%d copies collide with existing entries; pass --merge to copy them anyway or --skip-existing to leave them out", conflicts)
+		}
+		if len(create) == 0 {
+			display.Println("Nothing to copy.")
+			return nil
+		}
+
+		if !copyYes && !confirm(fmt.Sprintf("Create %d entries? [y/N]: ", len(create))) {
+			display.Println("Cancelled.")
+			return nil
+		}
+
+		changes := newEntryChanges(client)
+		defer changes.record()
+
+		var failed []string
+		for _, planned := range create {
+			_, err := changes.create(api.CreateEntryRequest{
+				Date:        planned.Date,
+				StartTime:   formatClock(planned.Start),
+				EndTime:     formatClock(planned.End),
+				Project:     planned.Project,
+				Description: planned.Description,
+				Timezone:    dates.LocalZone(),
+			})
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s %s: %v", planned.Date, formatClock(planned.Start), err))
+			}
+		}
+
+		display.Printf("✓ Created %d entries", len(create)-len(failed))
+		if len(failed) > 0 {
+			display.Printf(", %d failed", len(failed))
+		}
+		display.Println()
+		for _, failure := range failed {
+			display.Printf("  ✗ %s\n", failure)
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d copies failed", len(failed), len(create))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+
+	copyCmd.Flags().StringVar(&copyTo, "to", "", "Day to copy to (YYYY-MM-DD, today, yesterday)")
+	copyCmd.Flags().StringVar(&copyToRange, "to-range", "", "Days to copy to, as FROM..TO")
+	copyCmd.Flags().BoolVar(&copyWorkdaysOnly, "workdays-only", false, "Only copy to days in work_days")
+	copyCmd.Flags().StringVarP(&copyProject, "project", "p", "", "Only copy entries of this project or alias")
+	copyCmd.Flags().BoolVar(&copyMerge, "merge", false, "Copy entries even if they collide with existing ones")
+	copyCmd.Flags().BoolVar(&copySkipExisting, "skip-existing", false, "Leave out copies that collide with existing entries")
+	copyCmd.Flags().BoolVarP(&copyYes, "yes", "y", false, "Create the copies without asking for confirmation")
+}
+
+// copyTemplate is an entry to copy, with its clock times in minutes
+type copyTemplate struct {
+	Start       int
+	End         int
+	Project     string
+	Description string
+}
+
+// plannedCopy is a copy on one target day
+type plannedCopy struct {
+	copyTemplate
+	Date     string
+	Conflict string // why it collides with an existing entry, if it does
+}
+
+// copyTargets returns the days from --to or --to-range, oldest first,
+// without the source day
+func copyTargets(cmd *cobra.Command, source string) ([]string, error) {
+	if (copyTo == "") == (copyToRange == "") {
+		return nil, fmt.Errorf("pass either --to or --to-range")
+	}
+
+	if copyTo != "" {
+		target, err := parseDateFlag(copyTo)
+		if err != nil {
+			return nil, err
+		}
+		if target == source {
+			return nil, fmt.Errorf("can't copy %s onto itself", source)
+		}
+		return []string{target}, nil
+	}
+
+	fromValue, toValue, ok := strings.Cut(copyToRange, "..")
+	if !ok {
+		return nil, fmt.Errorf("invalid --to-range %q (expected FROM..TO)", copyToRange)
+	}
+	from, err := dates.Parse(fromValue, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	to, err := dates.Parse(toValue, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("--to-range ends before it starts")
+	}
+
+	var workDays [7]bool
+	if copyWorkdaysOnly {
+		schedule, err := workSchedule(cmd)
+		if err != nil {
+			return nil, err
+		}
+		workDays = schedule.Days
+	}
+
+	var targets []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if copyWorkdaysOnly && !workDays[day.Weekday()] || dates.Format(day) == source {
+			continue
+		}
+		targets = append(targets, dates.Format(day))
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("--to-range contains no days to copy to")
+	}
+	return targets, nil
+}
+
+// copyTemplates works out the clock times of each entry's copy
+func copyTemplates(entries []api.Entry) ([]copyTemplate, error) {
+	cursor := copyDefaultStart
+	for _, entry := range entries {
+		if _, end, ok := entryClock(entry); ok && end > cursor {
+			cursor = end
+		}
+	}
+
+	templates := make([]copyTemplate, 0, len(entries))
+	for _, entry := range entries {
+		start, end, ok := entryClock(entry)
+		if !ok {
+			start = cursor
+			end = start + int(math.Round(entry.Duration*60))
+			cursor = end
+		}
+		if end >= 24*60 {
+			return nil, fmt.Errorf("entry %s (%s) doesn't fit in the day when starting at %s", entry.ID, display.FormatDuration(entry.Duration), formatClock(start))
+		}
+		templates = append(templates, copyTemplate{
+			Start:       start,
+			End:         end,
+			Project:     entry.Project,
+			Description: entry.Description,
+		})
+	}
+	return templates, nil
+}
+
+// entryClock returns an entry's start and end in minutes since midnight.
+// ok is false for entries without a start time.
+func entryClock(entry api.Entry) (start, end int, ok bool) {
+	startTime, err := time.Parse("15:04", entry.StartTime)
+	if err != nil {
+		return 0, 0, false
+	}
+	start = startTime.Hour()*60 + startTime.Minute()
+
+	if endTime, err := time.Parse("15:04", entry.EndTime); err == nil {
+		end = endTime.Hour()*60 + endTime.Minute()
+	}
+	if end <= start {
+		end = start + int(math.Round(entry.Duration*60))
+	}
+	return start, end, true
+}
+
+// copyConflict describes how a copy collides with the day's existing
+// entries, or returns "" if it doesn't
+func copyConflict(template copyTemplate, existing []api.Entry) string {
+	for _, entry := range existing {
+		if strings.EqualFold(entry.Project, template.Project) && entry.Description == template.Description {
+			return "same entry exists"
+		}
+		if start, end, ok := entryClock(entry); ok && start < template.End && template.Start < end {
+			return fmt.Sprintf("overlaps %s-%s", formatClock(start), formatClock(end))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/digest"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// Exit codes of the digest command, so schedulers can tell a server problem
+// from a mail problem
+const (
+	exitDigestFetch = 3
+	exitDigestSend  = 4
+)
+
+var (
+	digestWeek   string
+	digestEmail  string
+	digestStdout bool
+)
+
+// digestCmd represents the digest command
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Email a summary of a week",
+	Long: `Build a week's summary (daily hours, total, hours by source and by
+project) and email it as plain text with an HTML table alternative.
+
+The mail server is read from the "smtp" section of the config file:
+
+  smtp:
+    host: smtp.example.com
+    port: 587                 # 465 for implicit TLS
+    user: me@example.com
+    from: me@example.com      # default: user, if it is an address
+
+The password is read from TIMETRACKER_SMTP_PASSWORD or, if that isn't set,
+from the system keyring (service "timetracker-smtp", account smtp.user).
+
+Exits with 3 if the week can't be fetched and 4 if the email can't be sent.
+
+Examples:
+  timetracker digest --week last --email me@example.com
+  timetracker digest --week 2024-W15 --email team@example.com
+  timetracker digest --stdout`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		firstDay, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+		start, err := digestStart(digestWeek, time.Now(), firstDay)
+		if err != nil {
+			return err
+		}
+		if digestEmail == "" && !digestStdout {
+			return fmt.Errorf("--email is required unless --stdout is used")
+		}
+
+		var server digest.SMTP
+		if err := config.FileValue("smtp", &server); err != nil {
+			return err
+		}
+		if !digestStdout {
+			if err := prepareSMTP(&server); err != nil {
+				return withExitCode(exitDigestSend, err)
+			}
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return withExitCode(exitDigestFetch, err)
+		}
+		totals, err := fetchWeekTotals(cmd.Context(), client, start, 7)
+		if err != nil {
+			return withExitCode(exitDigestFetch, fmt.Errorf("failed to fetch entries for the week of %s: %w", dates.Format(start), err))
+		}
+
+		week := digest.Week{
+			Start:     totals.Start,
+			Days:      totals.ByDay,
+			BySource:  totals.BySource,
+			ByProject: totals.ByProject,
+			Total:     totals.Total,
+		}
+		html, err := digest.HTML(week)
+		if err != nil {
+			return err
+		}
+		message := digest.Message{
+			From:    server.Sender(),
+			To:      digestEmail,
+			Subject: fmt.Sprintf("Time tracking digest: %s, %s", week.Title(), display.FormatDuration(week.Total)),
+			Date:    time.Now(),
+			Text:    digest.Text(week),
+			HTML:    html,
+		}
+		raw, err := message.Bytes()
+		if err != nil {
+			return err
+		}
+
+		if digestStdout {
+			os.Stdout.Write(raw)
+			return nil
+		}
+
+		if err := digest.Send(server, message.From, digestEmail, raw); err != nil {
+			return withExitCode(exitDigestSend, fmt.Errorf("failed to send digest: %w", err))
+		}
+		display.Printf("📧 Sent %s to %s\n", week.Title(), digestEmail)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().StringVar(&digestWeek, "week", "last", "Week to summarise: last, this, an ISO week (2024-W15) or a date in it")
+	digestCmd.Flags().StringVar(&digestEmail, "email", "", "Address to send the digest to")
+	digestCmd.Flags().BoolVar(&digestStdout, "stdout", false, "Print the email instead of sending it")
+	addWeekStartFlag(digestCmd)
+}
+
+// digestStart returns the first day of the week named by value, for weeks
+// beginning on firstDay
+func digestStart(value string, now time.Time, firstDay time.Weekday) (time.Time, error) {
+	thisWeek := dates.StartOfWeek(now, firstDay)
+	switch strings.ToLower(value) {
+	case "", "last":
+		return thisWeek.AddDate(0, 0, -7), nil
+	case "this":
+		return thisWeek, nil
+	}
+	if strings.Contains(strings.ToUpper(value), "-W") {
+		return dates.ParseWeek(value, firstDay, now.Location())
+	}
+	t, err := dates.Parse(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --week %q: expected last, this, YYYY-Www or a date", value)
+	}
+	return dates.StartOfWeek(t, firstDay), nil
+}
+
+// prepareSMTP checks the mail settings and looks up the password
+func prepareSMTP(server *digest.SMTP) error {
+	if server.Host == "" {
+		return fmt.Errorf("no mail server configured: set smtp.host in the config file")
+	}
+	if server.Sender() == "" {
+		return fmt.Errorf("no sender address: set smtp.from in the config file")
+	}
+	if server.User == "" {
+		return nil
+	}
+	password, err := digest.Password(server.User)
+	if err != nil {
+		return fmt.Errorf("no SMTP password: set %s or store it in the keyring: %w", digest.PasswordEnv, err)
+	}
+	server.Password = password
+	return nil
+}
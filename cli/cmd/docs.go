@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"github.com/spf13/pflag"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/settings"
+)
+
+var (
+	docsManOut      string
+	docsMarkdownOut string
+	docsDate        string
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate man pages and markdown reference docs",
+	Long: `Generate reference documentation for every command, for packaging.
+
+The output only depends on the command tree: commands and flags are sorted,
+hidden commands are left out and man pages carry the date from --date or
+SOURCE_DATE_EPOCH, so CI can regenerate the docs and diff them. Files from
+an earlier run that no longer match a command are removed.
+
+Examples:
+  timetracker docs man --out ./man --date 2024-05-01
+  timetracker docs markdown --out ./docs`,
+	Hidden: true,
+}
+
+// docsManCmd represents the docs man command
+var docsManCmd = &cobra.Command{
+	Use:          "man",
+	Short:        "Generate man pages",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		header := doc.GenManHeader{Source: "TimeTracker CLI", Manual: "TimeTracker Manual"}
+		if docsDate != "" {
+			date, err := time.Parse("2006-01-02", docsDate)
+			if err != nil {
+				return fmt.Errorf("invalid --date %q (expected YYYY-MM-DD)", docsDate)
+			}
+			header.Date = &date
+		}
+
+		count, err := writeDocs(cmd.Root(), docsManOut, ".1", func(c *cobra.Command, w *bytes.Buffer) error {
+			page := header
+			if err := doc.GenMan(c, &page, w); err != nil {
+				return err
+			}
+			insertSection(w, ".SH SEE ALSO", manEnvironment(commandEnv(c)))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		display.Printf("✓ Wrote %d man pages to %s\n", count, docsManOut)
+		return nil
+	},
+}
+
+// docsMarkdownCmd represents the docs markdown command
+var docsMarkdownCmd = &cobra.Command{
+	Use:          "markdown",
+	Short:        "Generate markdown reference docs",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, err := writeDocs(cmd.Root(), docsMarkdownOut, ".md", func(c *cobra.Command, w *bytes.Buffer) error {
+			if err := doc.GenMarkdownCustom(c, w, func(link string) string { return link }); err != nil {
+				return err
+			}
+			insertSection(w, "### SEE ALSO", markdownEnvironment(commandEnv(c)))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		display.Printf("✓ Wrote %d markdown files to %s\n", count, docsMarkdownOut)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsManCmd)
+	docsCmd.AddCommand(docsMarkdownCmd)
+
+	docsManCmd.Flags().StringVar(&docsManOut, "out", "man", "Directory to write the man pages to")
+	docsManCmd.Flags().StringVar(&docsDate, "date", "", "Date shown in the man pages, YYYY-MM-DD (default: SOURCE_DATE_EPOCH or today)")
+	docsMarkdownCmd.Flags().StringVar(&docsMarkdownOut, "out", "docs", "Directory to write the markdown files to")
+}
+
+// writeDocs generates a file per documented command into dir, replacing the
+// files of an earlier run, and returns how many it wrote
+func writeDocs(root *cobra.Command, dir, ext string, generate func(*cobra.Command, *bytes.Buffer) error) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	stale, _ := filepath.Glob(filepath.Join(dir, root.Name()+"*"+ext))
+	for _, path := range stale {
+		os.Remove(path)
+	}
+
+	separator := "-"
+	if ext == ".md" {
+		separator = "_" // cobra's markdown links use underscores
+	}
+
+	commands := documentedCommands(root)
+	for _, c := range commands {
+		var buf bytes.Buffer
+		if err := generate(c, &buf); err != nil {
+			return 0, fmt.Errorf("failed to generate docs for %s: %w", c.CommandPath(), err)
+		}
+		name := strings.ReplaceAll(c.CommandPath(), " ", separator) + ext
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0644); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return len(commands), nil
+}
+
+// documentedCommands returns root and every visible command below it, with
+// examples moved out of their long descriptions so the generators render
+// them as examples
+func documentedCommands(root *cobra.Command) []*cobra.Command {
+	root.DisableAutoGenTag = true
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	var commands []*cobra.Command
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if c != root && (!c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand()) {
+			return
+		}
+		splitExamples(c)
+		commands = append(commands, c)
+
+		children := c.Commands()
+		sort.Slice(children, func(i, j int) bool { return children[i].Name() < children[j].Name() })
+		for _, child := range children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return commands
+}
+
+// splitExamples moves a trailing "Examples:" block of the long description
+// into Example
+func splitExamples(c *cobra.Command) {
+	const marker = "\nExamples:\n"
+	i := strings.LastIndex(c.Long, marker)
+	if i < 0 || c.Example != "" {
+		return
+	}
+	c.Example = strings.TrimRight(c.Long[i+len(marker):], "\n")
+	c.Long = strings.TrimRight(c.Long[:i], "\n")
+}
+
+// envVar is an environment variable that sets a flag or config setting
+type envVar struct {
+	Name        string
+	Flag        string // empty for settings without a flag
+	Description string
+}
+
+// commandEnv lists the environment variables that set the command's flags.
+// The root command's page also lists those of settings without flags.
+func commandEnv(c *cobra.Command) []envVar {
+	byFlag := map[string]envVar{}
+	for flag, key := range boundFlags {
+		byFlag[flag] = envVar{Name: strings.ToUpper(key), Flag: flag}
+	}
+	for _, setting := range settings.Registry {
+		if setting.Env != "" && setting.Flag != "" {
+			byFlag[setting.Flag] = envVar{Name: setting.Env, Flag: setting.Flag, Description: setting.Description}
+		}
+	}
+
+	seen := map[string]bool{}
+	var vars []envVar
+	add := func(v envVar) {
+		if !seen[v.Name] {
+			seen[v.Name] = true
+			vars = append(vars, v)
+		}
+	}
+	visit := func(flag *pflag.Flag) {
+		v, ok := byFlag[flag.Name]
+		if !ok || flag.Hidden {
+			return
+		}
+		if v.Description == "" {
+			v.Description = flag.Usage
+		}
+		add(v)
+	}
+	c.NonInheritedFlags().VisitAll(visit)
+	c.InheritedFlags().VisitAll(visit)
+
+	if !c.HasParent() {
+		for _, setting := range settings.Registry {
+			if setting.Env != "" && setting.Flag == "" {
+				add(envVar{Name: setting.Env, Description: setting.Description})
+			}
+		}
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// manEnvironment renders an ENVIRONMENT section in roff
+func manEnvironment(vars []envVar) string {
+	if len(vars) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer(`\`, `\\`, "-", `\-`).Replace
+
+	var b strings.Builder
+	b.WriteString(".SH ENVIRONMENT\n")
+	for _, v := range vars {
+		fmt.Fprintf(&b, ".TP\n\\fB%s\\fP\n%s", escape(v.Name), escape(v.Description))
+		if v.Flag != "" {
+			fmt.Fprintf(&b, " (same as \\fB\\-\\-%s\\fP)", escape(v.Flag))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// markdownEnvironment renders an Environment section in markdown
+func markdownEnvironment(vars []envVar) string {
+	if len(vars) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("### Environment\n\n")
+	for _, v := range vars {
+		fmt.Fprintf(&b, "* `%s`: %s", v.Name, v.Description)
+		if v.Flag != "" {
+			fmt.Fprintf(&b, " (same as `--%s`)", v.Flag)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// insertSection inserts section before the line starting with before, or
+// appends it if there is no such line
+func insertSection(buf *bytes.Buffer, before, section string) {
+	if section == "" {
+		return
+	}
+	content := buf.String()
+	i := strings.Index(content, "\n"+before)
+	buf.Reset()
+	if i < 0 {
+		buf.WriteString(content)
+		buf.WriteString("\n" + section)
+		return
+	}
+	buf.WriteString(content[:i+1])
+	buf.WriteString(section)
+	buf.WriteString(content[i+1:])
+}
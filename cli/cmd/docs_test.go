@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+func generateMarkdown(t *testing.T, dir string) {
+	t.Helper()
+	_, err := writeDocs(rootCmd, dir, ".md", func(c *cobra.Command, w *bytes.Buffer) error {
+		if err := doc.GenMarkdownCustom(c, w, func(link string) string { return link }); err != nil {
+			return err
+		}
+		insertSection(w, "### SEE ALSO", markdownEnvironment(commandEnv(c)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDocsAreDeterministic(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	generateMarkdown(t, first)
+	os.WriteFile(filepath.Join(second, "timetracker_removed.md"), []byte("stale"), 0644)
+	generateMarkdown(t, second)
+
+	files, _ := os.ReadDir(first)
+	again, _ := os.ReadDir(second)
+	if len(files) != len(again) {
+		t.Fatalf("%d files, then %d", len(files), len(again))
+	}
+	for _, file := range files {
+		a, _ := os.ReadFile(filepath.Join(first, file.Name()))
+		b, _ := os.ReadFile(filepath.Join(second, file.Name()))
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s differs between runs", file.Name())
+		}
+		if strings.HasPrefix(file.Name(), "timetracker_docs") {
+			t.Errorf("hidden command documented: %s", file.Name())
+		}
+	}
+
+	root, _ := os.ReadFile(filepath.Join(first, "timetracker.md"))
+	for _, want := range []string{"### Environment", "`API_URL`", "`TIMETRACKER_DURATION_FORMAT`"} {
+		if !strings.Contains(string(root), want) {
+			t.Errorf("timetracker.md lacks %q", want)
+		}
+	}
+	week, _ := os.ReadFile(filepath.Join(first, "timetracker_week.md"))
+	if !strings.Contains(string(week), "`TIMETRACKER_WEEK_START`: First day of the week (same as `--week-start`)") {
+		t.Errorf("timetracker_week.md lacks TIMETRACKER_WEEK_START:\n%s", week)
+	}
+	submit, _ := os.ReadFile(filepath.Join(first, "timetracker_submit.md"))
+	if !strings.Contains(string(submit), "### Examples\n\n```\n  timetracker submit") {
+		t.Errorf("timetracker_submit.md has no examples section:\n%s", submit)
+	}
+}
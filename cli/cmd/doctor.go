@@ -0,0 +1,547 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+	"github.com/vmiller/timetracker-cli/internal/localfile"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+)
+
+const (
+	// dialTimeout bounds the reachability check
+	dialTimeout = 5 * time.Second
+
+	// skewWarn and skewFail are the clock skew thresholds. Access tokens
+	// live for 15 minutes, so a few minutes of skew already breaks auth.
+	skewWarn = 30 * time.Second
+	skewFail = 5 * time.Minute
+)
+
+// checkStatus is the outcome of a single doctor check
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+	checkSkip
+)
+
+func (s checkStatus) String() string {
+	switch s {
+	case checkPass:
+		return "PASS"
+	case checkWarn:
+		return "WARN"
+	case checkFail:
+		return "FAIL"
+	default:
+		return "SKIP"
+	}
+}
+
+// check is a single row of the doctor report
+type check struct {
+	Name   string
+	Status checkStatus
+	Detail string
+	Hint   string
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration, connectivity and authentication problems",
+	Long: `Run a series of checks and report whether the problem is the config,
+the network or the token:
+  - config file exists and is only readable by you (0600)
+  - local data files are readable by this CLI version
+  - which proxy, if any, requests go through
+  - API URL is reachable and the server reports healthy
+  - the server's TLS certificate is trusted, including by ca_cert
+  - access token is accepted and the refresh token works; checking the
+    refresh token rotates both tokens, like any refresh, and saves the
+    new ones
+  - local clock agrees with the server
+  - sync providers are configured
+
+Exits non-zero if any check fails, so it can be used in scripts.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		client := newClient(cmd.Context(), cfg)
+
+		checks := []check{checkConfigFile(), localFilesCheck()}
+
+		proxy, proxyErr := client.Proxy()
+		checks = append(checks, proxyCheck(cfg, proxy, proxyErr))
+
+		reachable := checkReachable(cfg.APIURL, proxy)
+		checks = append(checks, reachable)
+		if reachable.Status == checkFail {
+			checks = append(checks, skipped("TLS certificate"))
+		} else {
+			checks = append(checks, tlsCheck(cfg, proxy))
+		}
+
+		var health *api.HealthResponse
+		var requested, received time.Time
+		if reachable.Status == checkFail {
+			checks = append(checks, skipped("Server health"))
+		} else {
+			requested = time.Now()
+			health, err = client.Health()
+			received = time.Now()
+			checks = append(checks, healthCheck(health, err))
+		}
+
+		loggedIn := cfg.AccessToken != "" || cfg.RefreshToken != ""
+		online := health != nil
+		switch {
+		case !loggedIn:
+			checks = append(checks, check{
+				Name:   "Access token",
+				Status: checkFail,
+				Detail: "not logged in",
+				Hint:   "Run 'timetracker login'",
+			}, skipped("Refresh token"))
+		case !online:
+			checks = append(checks, skipped("Access token"), skipped("Refresh token"))
+		default:
+			checks = append(checks, tokenChecks(client, cfg)...)
+		}
+
+		if health != nil {
+			checks = append(checks, clockSkewCheck(health.Timestamp, requested, received))
+		} else {
+			checks = append(checks, skipped("Clock skew"))
+		}
+
+		if loggedIn && online {
+			checks = append(checks, providersCheck(client))
+		} else {
+			checks = append(checks, skipped("Providers"))
+		}
+
+		return printChecks(checks)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// skipped marks a check that could not run because an earlier one failed
+func skipped(name string) check {
+	return check{Name: name, Status: checkSkip, Detail: "skipped"}
+}
+
+// printChecks renders the report and returns an error if any check failed
+func printChecks(checks []check) error {
+	table := display.NewTable("Check", "Status", "Details")
+	failed := 0
+	for _, c := range checks {
+		table.AddRow(c.Name, c.Status.String(), c.Detail)
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+
+	display.Println()
+	display.Print(table.Render())
+
+	var hints []check
+	for _, c := range checks {
+		if c.Hint != "" && (c.Status == checkWarn || c.Status == checkFail) {
+			hints = append(hints, c)
+		}
+	}
+	if len(hints) > 0 {
+		display.Println("\nHow to fix:")
+		for _, c := range hints {
+			display.Printf("  💡 %s: %s\n", c.Name, c.Hint)
+		}
+	}
+	display.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// checkConfigFile verifies the config file exists and is private to the user
+func checkConfigFile() check {
+	c := check{Name: "Config file"}
+
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		dir, err := config.Dir()
+		if err != nil {
+			c.Status, c.Detail = checkFail, err.Error()
+			return c
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		c.Status, c.Detail = checkFail, fmt.Sprintf("%s not found", path)
+		c.Hint = "Run 'timetracker login' to create it"
+		return c
+	}
+
+	// Windows has no meaningful Unix permission bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		c.Status = checkFail
+		c.Detail = fmt.Sprintf("%s has permissions %04o", path, info.Mode().Perm())
+		c.Hint = fmt.Sprintf("Credentials are readable by other users; run 'chmod 600 %s'", path)
+		return c
+	}
+
+	c.Status, c.Detail = checkPass, path
+	return c
+}
+
+// localFilesCheck inventories the data files in the config directory and
+// their format versions
+func localFilesCheck() check {
+	c := check{Name: "Local files"}
+
+	dir, err := config.Dir()
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+
+	c.Status = checkPass
+	var inventory []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == "config.yaml" || !localfile.IsData(name) || localdata.OriginalName(name) != "" {
+			continue
+		}
+
+		header, err := localfile.Inspect(filepath.Join(dir, name))
+		if err != nil {
+			c.Status = checkFail
+			inventory = append(inventory, fmt.Sprintf("%s unreadable", name))
+			continue
+		}
+
+		format, known := localfile.Lookup(header.Format)
+		switch {
+		case header.Format == "":
+			inventory = append(inventory, fmt.Sprintf("%s v0", name))
+			if c.Status == checkPass {
+				c.Status = checkWarn
+				c.Hint = "Files without a version header are upgraded the next time the CLI writes them"
+			}
+		case !known:
+			inventory = append(inventory, fmt.Sprintf("%s %s v%d (unknown format)", name, header.Format, header.Version))
+			if c.Status == checkPass {
+				c.Status = checkWarn
+			}
+		case header.Version > format.Version:
+			c.Status = checkFail
+			c.Hint = "Some files were written by a newer CLI; upgrade to read them"
+			inventory = append(inventory, fmt.Sprintf("%s %s v%d by %s (newer)", name, header.Format, header.Version, header.CreatedBy))
+		default:
+			inventory = append(inventory, fmt.Sprintf("%s %s v%d", name, header.Format, header.Version))
+		}
+	}
+
+	if len(inventory) == 0 {
+		c.Detail = "none"
+	} else {
+		c.Detail = strings.Join(inventory, ", ")
+	}
+	return c
+}
+
+// proxyCheck reports the proxy requests go through and where it was
+// configured. Credentials in the proxy URL are never shown.
+func proxyCheck(cfg *config.Config, proxy *url.URL, err error) check {
+	c := check{Name: "Proxy"}
+	switch {
+	case err != nil:
+		c.Status, c.Detail = checkFail, fmt.Sprintf("invalid proxy: %v", err)
+		c.Hint = "Fix HTTPS_PROXY/HTTP_PROXY, or set proxy_url in the config file"
+	case proxy == nil:
+		c.Status, c.Detail = checkPass, "none (direct)"
+	case cfg.ProxyURL != "":
+		c.Status, c.Detail = checkPass, logging.RedactURL(proxy.String())+", from proxy_url"
+	default:
+		c.Status, c.Detail = checkPass, logging.RedactURL(proxy.String())+", from the environment"
+	}
+	return c
+}
+
+// checkReachable opens a TCP connection to the API host, or to the proxy
+// when requests go through one
+func checkReachable(apiURL string, proxy *url.URL) check {
+	c := check{Name: "API reachable"}
+
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		c.Status, c.Detail = checkFail, fmt.Sprintf("invalid API URL %q", apiURL)
+		c.Hint = "Set api_url in the config file or pass --api-url"
+		return c
+	}
+
+	target, detail := u, apiURL
+	if proxy != nil {
+		target, detail = proxy, apiURL+" via proxy "+proxy.Host
+	}
+
+	conn, err := net.DialTimeout("tcp", hostPort(target), dialTimeout)
+	if err != nil {
+		c.Status, c.Detail = checkFail, fmt.Sprintf("%s: %v", detail, err)
+		c.Hint = "Check the API URL, your network/VPN, and that the backend is running"
+		if proxy != nil {
+			c.Hint = "Check that the proxy is running and reachable from this network"
+		}
+		return c
+	}
+	conn.Close()
+
+	c.Status, c.Detail = checkPass, detail
+	return c
+}
+
+// hostPort returns the host and port to dial for a URL, defaulting the port
+// from the scheme
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	switch u.Scheme {
+	case "https":
+		port = "443"
+	case "socks5":
+		port = "1080"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// tlsCheck verifies the server's certificate against the system's CAs and
+// ca_cert, even when --insecure skips that for requests
+func tlsCheck(cfg *config.Config, proxy *url.URL) check {
+	c := check{Name: "TLS certificate"}
+
+	u, _ := url.Parse(cfg.APIURL)
+	if u.Scheme != "https" {
+		c.Status, c.Detail = checkSkip, "not using HTTPS"
+		return c
+	}
+	pool, err := cfg.CertPool()
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		c.Hint = "Point ca_cert (or --ca-cert) at a readable PEM file with your CA's certificate"
+		return c
+	}
+	if proxy != nil {
+		c.Status, c.Detail = checkSkip, "checked by the server health request, through the proxy"
+		return c
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", hostPort(u), &tls.Config{RootCAs: pool, ServerName: u.Hostname()})
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		var verify *tls.CertificateVerificationError
+		switch {
+		case !errors.As(err, &verify):
+			c.Hint = "Check that the API URL's host and port serve HTTPS"
+		case cfg.CACert != "":
+			c.Hint = fmt.Sprintf("%s doesn't include the CA that signed the server's certificate", cfg.CACert)
+		default:
+			c.Hint = "Set ca_cert to the PEM bundle of the CA that signed the server's certificate"
+		}
+		return c
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	c.Status = checkPass
+	c.Detail = fmt.Sprintf("issued by %s, expires %s", certs[0].Issuer.CommonName, certs[0].NotAfter.Local().Format("2006-01-02"))
+	if cfg.CACert != "" {
+		c.Detail += ", with ca_cert " + cfg.CACert
+	}
+	if cfg.InsecureSkipVerify {
+		c.Status = checkWarn
+		c.Detail += "; verification is disabled"
+		c.Hint = "The certificate verifies, so insecure_skip_verify (--insecure) isn't needed"
+	}
+	return c
+}
+
+// healthCheck interprets the result of the health check
+func healthCheck(health *api.HealthResponse, err error) check {
+	c := check{Name: "Server health"}
+	var verify *tls.CertificateVerificationError
+	var unreachable *api.HealthError
+	switch {
+	case errors.As(err, &verify):
+		c.Status, c.Detail = checkFail, err.Error()
+		c.Hint = "The server's certificate isn't trusted; see the TLS certificate check"
+	case errors.Is(err, api.ErrProxyAuth):
+		c.Status, c.Detail = checkFail, err.Error()
+		c.Hint = "The proxy refused the credentials; check the user and password in proxy_url or HTTPS_PROXY"
+	case errors.As(err, &unreachable):
+		c.Status, c.Detail = checkFail, err.Error()
+		c.Hint = "Set api_url in the config file or pass --api-url, and check that the backend is running"
+	case err != nil:
+		c.Status, c.Detail = checkFail, err.Error()
+		c.Hint = "The server is up but unhealthy; check its database connection and logs"
+	case health.Status != "healthy":
+		c.Status, c.Detail = checkWarn, fmt.Sprintf("status %q", health.Status)
+	default:
+		c.Status, c.Detail = checkPass, health.Status
+	}
+	if health != nil && health.Version != "" {
+		c.Detail += ", server version " + health.Version
+	}
+	return c
+}
+
+// tokenChecks verifies the access token is accepted and the refresh token
+// can mint a new one. A rejected access token is only a warning when the
+// refresh token still works, since the CLI recovers from that on its own.
+// The access token is probed with refreshing turned off, so an expired one
+// is reported rather than silently replaced. Checking the refresh token uses
+// it up: the server rotates both tokens, and the new ones are saved.
+func tokenChecks(client *api.Client, cfg *config.Config) []check {
+	access := check{Name: "Access token"}
+	refresh := check{Name: "Refresh token"}
+
+	client.DisableRefresh()
+	_, accessErr := client.GetProviderStatus()
+	if accessErr == nil {
+		access.Status, access.Detail = checkPass, "accepted, from "+cfg.CredentialSource()
+	}
+
+	// Personal access tokens are used as they are, with nothing to refresh
+	if cfg.AuthMode == config.AuthModeToken {
+		access.Name = "Access token (personal)"
+		refresh.Status, refresh.Detail = checkSkip, "not used with personal access tokens"
+		if accessErr != nil {
+			access.Status, access.Detail = checkFail, accessErr.Error()
+			access.Hint = "Generate a new token and run 'timetracker login --token'"
+		}
+		return []check{access, refresh}
+	}
+
+	if cfg.RefreshToken == "" {
+		refresh.Status, refresh.Detail = checkWarn, "no refresh token stored"
+		refresh.Hint = "Run 'timetracker login' to store one"
+	} else if err := client.RefreshToken(); err != nil {
+		refresh.Status, refresh.Detail = checkFail, err.Error()
+		refresh.Hint = "Refresh tokens expire after 7 days; run 'timetracker login'"
+	} else {
+		refresh.Status, refresh.Detail = checkPass, "accepted; new tokens issued and saved"
+		if cfg.Session {
+			refresh.Detail = "accepted; new tokens issued for this session"
+		}
+	}
+
+	if accessErr != nil {
+		access.Detail = accessErr.Error()
+		if refresh.Status == checkPass {
+			access.Status = checkWarn
+			access.Hint = "The access token had expired; checking the refresh token replaced it, as any command would have"
+		} else {
+			access.Status = checkFail
+			access.Hint = "Run 'timetracker login'"
+		}
+	}
+
+	return []check{access, refresh}
+}
+
+// clockSkewCheck compares the server timestamp with the local clock at the
+// midpoint of the request
+func clockSkewCheck(serverTime string, requested, received time.Time) check {
+	c := check{Name: "Clock skew"}
+
+	server, err := time.Parse(time.RFC3339, serverTime)
+	if err != nil {
+		c.Status, c.Detail = checkWarn, "server did not report its time"
+		return c
+	}
+
+	local := requested.Add(received.Sub(requested) / 2)
+	skew := server.Sub(local)
+	if skew < 0 {
+		skew = -skew
+	}
+	skew = skew.Round(time.Second)
+
+	switch {
+	case skew >= skewFail:
+		c.Status = checkFail
+	case skew >= skewWarn:
+		c.Status = checkWarn
+	default:
+		c.Status = checkPass
+	}
+	c.Detail = fmt.Sprintf("%s off server time", skew)
+	if c.Status != checkPass {
+		c.Hint = "Sync your system clock (e.g. enable NTP); tokens may be rejected as expired"
+	}
+	return c
+}
+
+// providersCheck reports which sync providers are not configured on the server
+func providersCheck(client *api.Client) check {
+	c := check{Name: "Providers"}
+
+	providers, err := client.GetProviderStatus()
+	if err != nil {
+		c.Status, c.Detail = checkFail, err.Error()
+		return c
+	}
+
+	var ok, missing []string
+	for _, provider := range providers {
+		if provider.Configured {
+			ok = append(ok, provider.Name)
+		} else {
+			missing = append(missing, provider.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		c.Status = checkWarn
+		c.Detail = "not configured: " + strings.Join(missing, ", ")
+		c.Hint = "Set the provider credentials in the backend's environment, then run 'timetracker sync'"
+		return c
+	}
+
+	c.Status, c.Detail = checkPass, strings.Join(ok, ", ")
+	return c
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// tokenServer accepts the access token "fresh" and the refresh token
+// "valid", and counts refreshes
+func tokenServer(t *testing.T, refreshes *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/auth/cli-refresh" {
+			*refreshes++
+			var req api.RefreshRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.RefreshToken != "valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Invalid refresh token"}`))
+				return
+			}
+			w.Write([]byte(`{"accessToken":"fresh","refreshToken":"rotated","expiresIn":900}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Token expired"}`))
+			return
+		}
+		w.Write([]byte(`{"providers":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTokenChecksReportExpiredAccessToken(t *testing.T) {
+	for _, test := range []struct {
+		name                    string
+		access, refreshToken    string
+		wantAccess, wantRefresh checkStatus
+		wantRefreshes           int
+	}{
+		{"valid", "fresh", "valid", checkPass, checkPass, 1},
+		{"expired", "expired", "valid", checkWarn, checkPass, 1},
+		{"both expired", "expired", "expired", checkFail, checkFail, 1},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var refreshes int
+			server := tokenServer(t, &refreshes)
+			cfg := &config.Config{APIURL: server.URL, AccessToken: test.access, RefreshToken: test.refreshToken, AuthMode: config.AuthModePassword, Session: true}
+
+			checks := tokenChecks(api.NewClient(cfg), cfg)
+			if checks[0].Status != test.wantAccess {
+				t.Errorf("access token: %s (%s), want %s", checks[0].Status, checks[0].Detail, test.wantAccess)
+			}
+			if checks[1].Status != test.wantRefresh {
+				t.Errorf("refresh token: %s (%s), want %s", checks[1].Status, checks[1].Detail, test.wantRefresh)
+			}
+			// Only the refresh token check refreshes, not the access token probe
+			if refreshes != test.wantRefreshes {
+				t.Errorf("refreshed %d times, want %d", refreshes, test.wantRefreshes)
+			}
+		})
+	}
+}
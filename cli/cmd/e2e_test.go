@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// e2eEnv makes the test binary run the CLI instead of the tests, so each
+// end-to-end test gets a fresh process with fresh flags and config
+const e2eEnv = "TIMETRACKER_E2E"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(e2eEnv) == "1" {
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs the CLI with args against the fixtures in
+// testdata/fixtures/<fixtures>, logged in and in UTC, and returns what it
+// printed to stdout and stderr
+func runCLI(t *testing.T, fixtures string, args ...string) (string, error) {
+	t.Helper()
+	args = append([]string{"--replay", "testdata/fixtures/" + fixtures, "--timezone", "UTC", "--api-url", "http://fixtures.invalid"}, args...)
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = []string{
+		e2eEnv + "=1",
+		"HOME=" + t.TempDir(),
+		"PATH=" + os.Getenv("PATH"),
+		"TIMETRACKER_ACCESS_TOKEN=test-token",
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// wantOutput fails t unless out contains every line of want
+func wantOutput(t *testing.T, out string, want ...string) {
+	t.Helper()
+	for _, line := range want {
+		if !strings.Contains(out, line) {
+			t.Errorf("output lacks %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestTodayE2E(t *testing.T) {
+	out, err := runCLI(t, "today", "today")
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	wantOutput(t, out,
+		"📅 2024-01-10",
+		"📝 Release day",
+		"Total Hours: 6.50",
+		"Entries: 4",
+		"• TOGGL:   4.00h",
+		"• MANUAL:  0.50h",
+	)
+}
+
+func TestWeekE2E(t *testing.T) {
+	out, err := runCLI(t, "week", "week", "--date", "2024-01-10", "--by", "both")
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	wantOutput(t, out,
+		"2024-01-08 to 2024-01-14",
+		"│ Wednesday │ 2024-01-10 │ 6.50  │",
+		"Total Hours: 38.50",
+		"Expected: 40.00 (-1.50)",
+		"• ACME:     26.50h",
+		"• Internal: 12.00h",
+	)
+}
+
+func TestSyncE2E(t *testing.T) {
+	out, err := runCLI(t, "sync", "sync")
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	wantOutput(t, out,
+		"toggl: ✓ fetched 12, imported 10",
+		"tempo: ✗ token expired",
+		"Imported: 10 entries",
+		"✗ TEMPO:   token expired",
+	)
+}
+
+func TestReplayUnmatchedRequestE2E(t *testing.T) {
+	out, err := runCLI(t, "today", "week", "--date", "2024-01-10")
+	if err == nil {
+		t.Fatalf("week replayed today's fixtures:\n%s", out)
+	}
+	wantOutput(t, out, "no recorded fixture for GET /api/entries/summary/week?weekStart=2024-01-08&tz=UTC in testdata/fixtures/today")
+}
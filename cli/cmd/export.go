@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/ical"
+)
+
+var (
+	exportFrom        string
+	exportTo          string
+	exportOut         string
+	exportClient      string
+	exportBillable    bool
+	exportNonBillable bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export entries to other formats",
+	Long: `Export time entries for use in other tools.
+
+Examples:
+  timetracker export ical --out timetracker.ics`,
+}
+
+// exportICalCmd represents the export ical command
+var exportICalCmd = &cobra.Command{
+	Use:   "ical",
+	Short: "Export entries as an iCalendar (.ics) file",
+	Long: `Export entries as iCalendar events to import into or subscribe to from a
+calendar app. Entries with start and end times become timed events; entries
+with only a duration become all-day events with the hours in the title. The
+project is the event title and the description its notes.
+
+Each event's UID is derived from the entry's ID, so importing a newer export
+updates the events instead of duplicating them. Defaults to the current
+month and writes to stdout unless --out is given.
+
+--client, --billable and --non-billable limit the export to some entries.
+Entries whose source doesn't track billability count according to the
+billable_default setting.
+
+Examples:
+  timetracker export ical --out timetracker.ics
+  timetracker export ical --from 2024-01-01 --to 2024-03-31 --out q1.ics
+  timetracker export ical --client Acme --billable --out acme.ics`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseDateFlag(exportFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(exportTo)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if from == "" {
+			from = dates.Format(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local))
+		}
+		if to == "" {
+			to = dates.Format(now)
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+		billable, err := billableFilter(exportBillable, exportNonBillable)
+		if err != nil {
+			return err
+		}
+		fallback := billableDefault(cmd)
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		// Only the events are kept, not every page of entries
+		var events []ical.Event
+		err = client.EachEntries(from, to, func(entries []api.Entry) error {
+			entries = filterByBillable(filterByClient(entries, exportClient), billable, fallback)
+			for _, entry := range entries {
+				events = append(events, entryEvent(entry))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if exportOut != "" && exportOut != "-" {
+			file, err := os.Create(exportOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", exportOut, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		if err := ical.Write(out, events, now); err != nil {
+			return fmt.Errorf("failed to write calendar: %w", err)
+		}
+
+		if out != os.Stdout {
+			display.Printf("✓ Exported %d event(s) from %s to %s to %s\n", len(events), from, to, exportOut)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportICalCmd)
+
+	exportICalCmd.Flags().StringVar(&exportFrom, "from", "", "Start date (YYYY-MM-DD, today, yesterday; default: start of this month)")
+	exportICalCmd.Flags().StringVar(&exportTo, "to", "", "End date (YYYY-MM-DD, today, yesterday; default: today)")
+	exportICalCmd.Flags().StringVar(&exportOut, "out", "", "File to write (default: stdout)")
+	exportICalCmd.Flags().StringVar(&exportClient, "client", "", "Only export entries of this client (see 'timetracker clients')")
+	addBillableFlags(exportICalCmd, &exportBillable, &exportNonBillable)
+}
+
+// entryEvent turns an entry into a calendar event, timed if the entry has
+// a start time and all-day otherwise
+func entryEvent(entry api.Entry) ical.Event {
+	event := ical.Event{
+		UID:         fmt.Sprintf("entry-%s@timetracker", entry.ID),
+		Summary:     projectLabel(entry.Project),
+		Description: entry.Description,
+	}
+
+	day := dayOf(entry.Day())
+	if start, end, ok := entryClock(entry); ok {
+		// time.Date normalizes the minutes, staying correct on DST changes
+		event.Start = time.Date(day.Year(), day.Month(), day.Day(), 0, start, 0, 0, time.Local)
+		event.End = time.Date(day.Year(), day.Month(), day.Day(), 0, end, 0, 0, time.Local)
+		return event
+	}
+
+	event.AllDay = true
+	event.Summary = fmt.Sprintf("%s (%s)", event.Summary, display.FormatDuration(entry.Duration))
+	event.Start = day
+	event.End = day.AddDate(0, 0, 1)
+	return event
+}
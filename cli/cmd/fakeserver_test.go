@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// fakeServer is an in-memory TimeTracker API for command tests: it lists,
+// creates, updates and deletes entries and summarises them
+type fakeServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	entries []api.Entry
+	created []api.CreateEntryRequest
+	nextID  int
+
+	// summarised is what the summaries report while they lag behind writes
+	summarised []api.Entry
+	lagging    bool
+}
+
+// newFakeServer starts a fake server holding entries, and points the
+// config directory at a temporary home for the test
+func newFakeServer(t *testing.T, entries ...api.Entry) *fakeServer {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	s := &fakeServer{entries: entries}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// lag freezes the summaries at the current entries, as the server's
+// summary cache does, until catchUp
+func (s *fakeServer) lag() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summarised = append([]api.Entry(nil), s.entries...)
+	s.lagging = true
+}
+
+// catchUp makes the summaries report the current entries again
+func (s *fakeServer) catchUp() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lagging = false
+}
+
+// summary returns what the summary endpoint reports for date
+func (s *fakeServer) summary(date string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.summaryHours(date)
+}
+
+// client returns an API client for the server
+func (s *fakeServer) client() *api.Client {
+	return api.NewClient(&config.Config{APIURL: s.URL, AccessToken: "test-token", Attempts: 1})
+}
+
+func (s *fakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/stats":
+		writeJSON(w, http.StatusOK, s.entries)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/api/entries/summary/today":
+		today := dates.Format(time.Now())
+		writeJSON(w, http.StatusOK, api.TodaySummaryResponse{Date: today, TotalHours: s.summaryHours(today)})
+
+	case r.Method == http.MethodGet && r.URL.Path == "/api/entries/summary/week":
+		start := dates.StartOfWeek(dates.StartOfDay(time.Now()), time.Monday)
+		week := api.WeekSummaryResponse{WeekStart: dates.Format(start), WeekEnd: dates.Format(start.AddDate(0, 0, 6))}
+		for i := 0; i < 7; i++ {
+			date := dates.Format(start.AddDate(0, 0, i))
+			week.Daily = append(week.Daily, api.DailySummary{Date: date, Hours: s.summaryHours(date)})
+			week.TotalHours += s.summaryHours(date)
+		}
+		writeJSON(w, http.StatusOK, week)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/api/entries":
+		var req api.CreateEntryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		hours, err := clockDuration(req.StartTime, req.EndTime)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		s.nextID++
+		entry := api.Entry{
+			ID: fmt.Sprintf("new-%d", s.nextID), Source: "MANUAL", Date: req.Date, Duration: hours,
+			Project: req.Project, Description: req.Description, StartTime: req.StartTime, EndTime: req.EndTime,
+		}
+		s.created = append(s.created, req)
+		s.entries = append(s.entries, entry)
+		writeJSON(w, http.StatusCreated, entry)
+
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/entries/"):
+		i := s.find(strings.TrimPrefix(r.URL.Path, "/api/entries/"))
+		if i < 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "Entry not found"})
+			return
+		}
+		var req api.UpdateEntryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		entry := &s.entries[i]
+		entry.Date, entry.Duration, entry.StartTime, entry.EndTime = req.Date, req.Duration, req.StartTime, req.EndTime
+		entry.Project, entry.Description = req.Project, req.Description
+		if req.StartTime != "" && req.EndTime != "" {
+			entry.Duration, _ = clockDuration(req.StartTime, req.EndTime)
+		}
+		writeJSON(w, http.StatusOK, entry)
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/entries/"):
+		i := s.find(strings.TrimPrefix(r.URL.Path, "/api/entries/"))
+		if i < 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "Entry not found"})
+			return
+		}
+		s.entries = append(s.entries[:i], s.entries[i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// find returns the index of the entry with id, or -1
+func (s *fakeServer) find(id string) int {
+	for i, entry := range s.entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// summaryHours is the total the summaries report for date; the caller
+// holds s.mu
+func (s *fakeServer) summaryHours(date string) float64 {
+	if s.lagging {
+		return dayHours(s.summarised, date)
+	}
+	return dayHours(s.entries, date)
+}
+
+// dayHours sums the hours of the entries on date
+func dayHours(entries []api.Entry, date string) float64 {
+	total := 0.0
+	for _, entry := range entries {
+		if entry.Day() == date {
+			total += entry.Duration
+		}
+	}
+	return total
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
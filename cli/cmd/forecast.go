@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/forecast"
+)
+
+var (
+	forecastWeeks int
+	forecastGoal  float64
+)
+
+// forecastCmd represents the forecast command
+var forecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Project this week's total at your current pace",
+	Long: `Project where this week will end: the hours logged so far plus your
+average hours per workday over the previous weeks for each workday left,
+and the hours per day you'd need to reach the goal.
+
+The goal is the week's expected hours from the schedule or contract_hours,
+minus holidays and vacation, unless --goal is given. The pace only counts
+workdays, so holidays and vacation in the previous weeks don't drag it
+down. Today counts as a remaining day.
+
+Examples:
+  timetracker forecast
+  timetracker forecast --weeks 8
+  timetracker forecast --goal 40`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if forecastWeeks < 1 {
+			return fmt.Errorf("--weeks must be at least 1")
+		}
+		if forecastGoal < 0 {
+			return fmt.Errorf("--goal can't be negative")
+		}
+
+		cal, err := workCalendar(cmd)
+		if err != nil {
+			return err
+		}
+		_, goalSource, err := weekCalendar(cmd)
+		if err != nil {
+			return err
+		}
+		firstDay, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		from := dates.StartOfWeek(now, firstDay).AddDate(0, 0, -7*forecastWeeks)
+		entries, err := client.GetEntries(dates.Format(from), dates.Format(now))
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		hours := map[string]float64{}
+		for _, entry := range entries {
+			hours[entry.Day()] += entry.Duration
+		}
+
+		f := forecast.Project(forecast.Input{
+			Today:     now,
+			WeekStart: firstDay,
+			Hours:     hours,
+			Calendar:  cal,
+			Weeks:     forecastWeeks,
+			Goal:      forecastGoal,
+		})
+
+		display.Printf("\n🔮 Forecast for %s (week of %s)\n\n", dates.WeekName(f.Start), dates.Format(f.Start))
+		display.Printf("  Logged so far: %7s\n", display.FormatDuration(f.Logged))
+		display.Printf("  Goal:          %7s\n", display.FormatDuration(f.Goal))
+		if !f.Scheduled {
+			display.Printf("  Pace:          %7s per workday\n", display.FormatDuration(f.Pace))
+		}
+		display.Printf("  Projected:     %7s", display.FormatDuration(f.Projected))
+		switch short := f.Short(); {
+		case short > 0.005:
+			display.Printf(" (%s short)\n", display.FormatDuration(short))
+		case short < -0.005:
+			display.Printf(" (%s over)\n", display.FormatDuration(-short))
+		default:
+			display.Println(" (on target)")
+		}
+
+		switch {
+		case f.Remaining == 0:
+			display.Println("\n  No workdays left this week.")
+		case f.Needed == 0:
+			display.Println("\n  ✓ Goal already reached.")
+		default:
+			display.Printf("\n  Needed: %s per day over the %s left (including today)\n", display.FormatDuration(f.Needed), countLabel(f.Remaining, "workday"))
+		}
+
+		display.Println("\nAssumptions:")
+		if f.Scheduled {
+			display.Printf("  - nothing logged in the last %s; remaining days are expected to reach their scheduled hours\n", countLabel(forecastWeeks, "week"))
+		} else {
+			display.Printf("  - pace based on the last %s (%s), excluding holidays and vacation\n",
+				countLabel(forecastWeeks, "week"), countLabel(f.PaceDays, "workday"))
+		}
+		if forecastGoal > 0 {
+			display.Println("  - goal from --goal")
+		} else {
+			display.Printf("  - goal from %s, minus holidays and vacation\n", goalSource)
+		}
+		display.Println("  - today counts as a remaining day")
+		display.Println()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(forecastCmd)
+
+	forecastCmd.Flags().IntVar(&forecastWeeks, "weeks", 4, "Number of previous weeks to average the pace over")
+	forecastCmd.Flags().Float64Var(&forecastGoal, "goal", 0, "Hours to reach this week (default: the week's expected hours)")
+	addWeekStartFlag(forecastCmd)
+}
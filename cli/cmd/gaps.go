@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/timeline"
+)
+
+// gapsTimelineWidth is the width of the timeline bars
+const gapsTimelineWidth = 48
+
+var (
+	gapsDate   string
+	gapsMinGap time.Duration
+)
+
+// gapsCmd represents the gaps command
+var gapsCmd = &cobra.Command{
+	Use:   "gaps",
+	Short: "Find overlapping entries and gaps in a day",
+	Long: `Lay out a day's entries on a timeline, flag entries that overlap (e.g.
+two providers tracking the same meeting, which counts its hours twice) and
+gaps of at least --min-gap during working hours (work_hours in the config
+file). Entries without a start time can't be placed and are listed
+separately.
+
+Examples:
+  timetracker gaps
+  timetracker gaps --date 2024-04-02 --min-gap 30m
+  timetracker gaps -o csv`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDateFlag(gapsDate)
+		if err != nil {
+			return err
+		}
+		if gapsMinGap <= 0 {
+			return fmt.Errorf("--min-gap must be positive")
+		}
+
+		schedule, err := workSchedule(cmd)
+		if err != nil {
+			return err
+		}
+		workStart, workEnd := int(schedule.Start.Minutes()), int(schedule.End.Minutes())
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(date, date)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		var placed, unplaced []api.Entry
+		var intervals []timeline.Interval
+		for _, entry := range entries {
+			start, end, ok := entryClock(entry)
+			if !ok {
+				unplaced = append(unplaced, entry)
+				continue
+			}
+			placed = append(placed, entry)
+			intervals = append(intervals, timeline.Interval{ID: entry.ID, Start: start, End: end})
+		}
+		sort.SliceStable(intervals, func(i, j int) bool { return intervals[i].Start < intervals[j].Start })
+		byID := make(map[string]api.Entry, len(placed))
+		for _, entry := range placed {
+			byID[entry.ID] = entry
+		}
+
+		overlaps := timeline.Overlaps(intervals)
+		overlapping := map[string]bool{}
+		for _, overlap := range overlaps {
+			for _, id := range overlap.IDs {
+				overlapping[id] = true
+			}
+		}
+
+		display.Printf("\n🧭 Timeline for %s\n\n", date)
+
+		if len(intervals) > 0 {
+			// Show at least the working hours, widened to whole hours around the entries
+			from, to := workStart, workEnd
+			for _, interval := range intervals {
+				if interval.Start < from {
+					from = interval.Start
+				}
+				if interval.End > to {
+					to = interval.End
+				}
+			}
+			from, to = from/60*60, (to+59)/60*60
+
+			display.Printf("  %-11s  %-*s%s\n", "", gapsTimelineWidth-5, formatClock(from), formatClock(to))
+			for _, interval := range intervals {
+				entry := byID[interval.ID]
+				marker := " "
+				if overlapping[interval.ID] {
+					marker = display.Red("!")
+				}
+				display.Printf("%s %s-%s  %s  %-6s %s %s\n", marker,
+					formatClock(interval.Start), formatClock(interval.End),
+					timelineBar(interval.Start, interval.End, from, to),
+					entry.Source, projectLabel(entry.Project), entry.Description)
+			}
+			display.Println()
+		} else {
+			display.Println("No entries with start times.")
+			display.Println()
+		}
+
+		// csv and tsv list the overlaps and gaps in one table
+		findings := display.NewTable("Kind", "Start", "End", "Minutes", "Entries")
+
+		if len(overlaps) > 0 {
+			display.Println("⚠️  Overlaps:")
+			for _, overlap := range overlaps {
+				labels := make([]string, len(overlap.IDs))
+				for i, id := range overlap.IDs {
+					entry := byID[id]
+					labels[i] = fmt.Sprintf("%s %s", entry.Source, projectLabel(entry.Project))
+				}
+				display.Printf("  %s-%s (%dm): %s\n", formatClock(overlap.Start), formatClock(overlap.End),
+					overlap.Minutes(), strings.Join(labels, " & "))
+				findings.AddCells(display.Text("overlap"), display.Text(formatClock(overlap.Start)), display.Text(formatClock(overlap.End)),
+					display.Cell{Text: strconv.Itoa(overlap.Minutes()), Value: overlap.Minutes()}, display.Text(strings.Join(labels, " & ")))
+			}
+			display.Printf("  %s counted more than once\n\n", formatIdle(time.Duration(timeline.DoubleCounted(overlaps))*time.Minute))
+		}
+
+		if schedule.Days[dayOf(date).Weekday()] {
+			gaps := timeline.Gaps(intervals, workStart, workEnd, int(gapsMinGap.Minutes()))
+			if len(gaps) > 0 {
+				display.Printf("🕳️  Gaps of %s or more during working hours:\n", formatIdle(gapsMinGap))
+				for _, gap := range gaps {
+					display.Printf("  %s-%s (%s)\n", formatClock(gap.Start), formatClock(gap.End), formatIdle(time.Duration(gap.Minutes())*time.Minute))
+					findings.AddCells(display.Text("gap"), display.Text(formatClock(gap.Start)), display.Text(formatClock(gap.End)),
+						display.Cell{Text: strconv.Itoa(gap.Minutes()), Value: gap.Minutes()}, display.Text(""))
+				}
+				display.Println()
+			}
+		}
+
+		if len(unplaced) > 0 {
+			display.Println("📌 Unplaced (no start time):")
+			for _, entry := range unplaced {
+				display.Printf("  %6s  %-6s %s %s\n", display.FormatDuration(entry.Duration), entry.Source, projectLabel(entry.Project), entry.Description)
+			}
+			display.Println()
+		}
+
+		if len(overlaps) == 0 && len(placed) > 0 {
+			display.Println("✓ No overlapping entries")
+			display.Println()
+		}
+
+		if display.OutputFormat() != display.FormatTable {
+			findings.Print()
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gapsCmd)
+
+	gapsCmd.Flags().StringVar(&gapsDate, "date", "today", "Day to check (YYYY-MM-DD, today, yesterday)")
+	gapsCmd.Flags().DurationVar(&gapsMinGap, "min-gap", 15*time.Minute, "Report gaps at least this long")
+}
+
+// timelineBar draws the span start..end on a bar covering from..to
+func timelineBar(start, end, from, to int) string {
+	scale := float64(gapsTimelineWidth) / float64(to-from)
+	first := int(float64(start-from) * scale)
+	last := int(float64(end-from)*scale + 0.5)
+	if last <= first {
+		last = first + 1
+	}
+	if last > gapsTimelineWidth {
+		last = gapsTimelineWidth
+	}
+	return strings.Repeat("·", first) + strings.Repeat("█", last-first) + strings.Repeat("·", gapsTimelineWidth-last)
+}
+
+// dayOf parses a YYYY-MM-DD date in local time
+func dayOf(date string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+	return t
+}
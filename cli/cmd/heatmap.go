@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var heatmapMonths int
+
+// heatmapCmd represents the heatmap command
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Show a calendar heatmap of logged hours",
+	Long: `Render the last few months as calendars with each day shaded by the
+hours logged, so gaps stand out. Workdays without hours are dotted; weekends
+without hours stay blank.
+
+Weeks start on the configured week_start day. With --no-color (or NO_COLOR,
+or when output is not a terminal) ASCII density characters are used instead
+of block shades.
+
+Examples:
+  timetracker heatmap
+  timetracker heatmap --months 6`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if heatmapMonths < 1 {
+			return fmt.Errorf("--months must be at least 1")
+		}
+		start, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		to := dates.StartOfDay(now)
+		from := time.Date(to.Year(), to.Month()-time.Month(heatmapMonths-1), 1, 0, 0, 0, 0, to.Location())
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(dates.Format(from), dates.Format(to))
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		hours := map[string]float64{}
+		var total float64
+		for _, entry := range entries {
+			hours[entry.Day()] += entry.Duration
+			total += entry.Duration
+		}
+
+		heatmap := display.Heatmap{
+			Hours:     hours,
+			From:      from,
+			To:        to,
+			WeekStart: start,
+			ASCII:     !display.ColorEnabled(),
+		}
+
+		display.Printf("\n🗓️  %s to %s — %s logged\n\n", dates.Format(from), dates.Format(to), display.FormatDuration(total))
+		display.Print(heatmap.Render())
+		display.Println(heatmap.Legend())
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(heatmapCmd)
+
+	heatmapCmd.Flags().IntVar(&heatmapMonths, "months", 3, "Number of months to show, including the current one")
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/help"
+)
+
+// helpCmd replaces cobra's help command to also serve the topic guides
+var helpCmd = &cobra.Command{
+	Use:   "help [command | topics | <topic>]",
+	Short: "Help about any command, or a task-oriented guide",
+	Long: `Show help for a command, or a task-oriented guide.
+
+  timetracker help topics     list the guides
+  timetracker help <topic>    read a guide
+  timetracker help <command>  show a command's help`,
+	SilenceUsage: true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := []string{"topics"}
+		for _, topic := range help.Topics {
+			names = append(names, topic.Name)
+		}
+		for _, sub := range rootCmd.Commands() {
+			if sub.IsAvailableCommand() {
+				names = append(names, sub.Name())
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return rootCmd.Help()
+		}
+
+		if len(args) == 1 {
+			if args[0] == "topics" {
+				printTopics()
+				return nil
+			}
+			if _, ok := help.Lookup(args[0]); ok {
+				text, err := help.Render(args[0], helpData(), display.Bold)
+				if err != nil {
+					return err
+				}
+				return display.Page("\n" + text + "\n")
+			}
+		}
+
+		target, _, err := rootCmd.Find(args)
+		if err == nil && target != rootCmd {
+			return target.Help()
+		}
+
+		msg := fmt.Sprintf("%q is not a command", strings.Join(args, " "))
+		suggestions := append(help.Suggest(args[0]), rootCmd.SuggestionsFor(args[0])...)
+		if len(suggestions) > 0 {
+			msg += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+		}
+		msg += "\n\nRun 'timetracker help topics' to list the guides."
+		return fmt.Errorf("%w or %s", help.ErrUnknownTopic, msg)
+	},
+}
+
+func init() {
+	rootCmd.SetHelpCommand(helpCmd)
+}
+
+// printTopics lists the available guides
+func printTopics() {
+	display.Println("\n📚 Help topics:")
+	display.Println()
+	for _, topic := range help.Topics {
+		display.Printf("  %-18s %s\n", topic.Name, topic.Summary)
+	}
+	display.Println("\nRead one with 'timetracker help <topic>'.")
+	display.Println()
+}
+
+// helpData collects the user's actual settings for the topic examples
+func helpData() help.Data {
+	data := help.Data{
+		APIURL:     viper.GetString("api_url"),
+		ConfigPath: viper.ConfigFileUsed(),
+	}
+
+	if data.ConfigPath == "" {
+		if dir, err := config.Dir(); err == nil {
+			data.ConfigPath = filepath.Join(dir, "config.yaml")
+		} else {
+			data.ConfigPath = "~/.timetracker/config.yaml"
+		}
+	}
+	data.ConfigDir = filepath.Dir(data.ConfigPath)
+
+	return data
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/help"
+)
+
+// commandRef matches an example invocation up to its first flag or argument
+// that isn't a plain word
+var commandRef = regexp.MustCompile(`timetracker((?: [a-z][a-z-]*)+)`)
+
+func TestHelpTopicsReferenceExistingCommands(t *testing.T) {
+	data := help.Data{APIURL: "http://localhost:3000", ConfigPath: "/tmp/config.yaml", ConfigDir: "/tmp"}
+
+	for _, topic := range help.Topics {
+		text, err := help.Render(topic.Name, data, func(s string) string { return s })
+		if err != nil {
+			t.Fatalf("%s: %v", topic.Name, err)
+		}
+
+		refs := commandRef.FindAllStringSubmatch(text, -1)
+		if len(refs) == 0 {
+			t.Errorf("%s: no example commands", topic.Name)
+		}
+		for _, ref := range refs {
+			args := strings.Fields(ref[1])
+			found, _, err := rootCmd.Find(args)
+			if err != nil || found == rootCmd {
+				t.Errorf("%s: %q is not a command", topic.Name, "timetracker"+ref[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/httpcache"
+	"github.com/vmiller/timetracker-cli/internal/rounding"
+)
+
+// newAuthenticatedClient loads the config and creates an API client whose
+// requests stop when ctx is done, failing early if the user has not logged
+// in yet
+func newAuthenticatedClient(ctx context.Context) (*api.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := newClient(ctx, cfg)
+	if cfg.AccessToken == "" && cfg.RefreshToken == "" {
+		return nil, fmt.Errorf("not logged in. Run 'timetracker login' first")
+	}
+	configureChunking(client)
+	configurePaging(client)
+	configureRateLimit(client)
+
+	return client, nil
+}
+
+// newClient creates an API client for cfg whose requests stop when ctx is
+// done and reuse cached responses unless --no-cache is given. In fixture
+// mode it records to or replays from the fixtures instead.
+func newClient(ctx context.Context, cfg *config.Config) *api.Client {
+	client := api.NewClient(cfg)
+	client.SetContext(ctx)
+	if viper.GetBool("preflight") {
+		client.SetPreflight()
+	}
+	if fixtures != nil {
+		// Every request reaches the fixtures, never a cached copy
+		client.SetFixtures(fixtures)
+		return client
+	}
+	if !viper.GetBool("no_cache") {
+		if cache, err := responseCache(); err == nil {
+			client.SetCache(cache)
+		}
+	}
+	if dir, err := config.Dir(); err == nil {
+		client.SetOffline(httpcache.New(filepath.Join(dir, "offline")), offlineMode())
+	}
+	return client
+}
+
+// openFixtures opens the fixtures chosen with --record, --replay or
+// TIMETRACKER_FIXTURES, if any
+func openFixtures() error {
+	var err error
+	switch dir := os.Getenv(fixturesEnv); {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("--record and --replay can't be used together")
+	case recordDir != "":
+		fixtures, err = api.RecordFixtures(recordDir)
+	case replayDir != "":
+		fixtures, err = api.ReplayFixtures(replayDir)
+	case dir != "" && api.HasFixtures(dir):
+		fixtures, err = api.ReplayFixtures(dir)
+	case dir != "":
+		fixtures, err = api.RecordFixtures(dir)
+	}
+	return err
+}
+
+// offlineMode returns the offline behavior chosen with --offline or
+// --no-offline
+func offlineMode() api.OfflineMode {
+	switch {
+	case viper.GetBool("offline"):
+		return api.OfflineOnly
+	case viper.GetBool("no_offline"):
+		return api.OfflineNever
+	}
+	return api.OfflineFallback
+}
+
+// offlineFrom returns when the oldest last-known copy the client answered
+// with was fetched, or nil if everything came from the server
+func offlineFrom(client *api.Client) *time.Time {
+	since, ok := client.OfflineSince()
+	if !ok {
+		return nil
+	}
+	return &since
+}
+
+// printOfflineBanner warns that the numbers below are a last-known copy
+func printOfflineBanner(from *time.Time) {
+	if from == nil {
+		return
+	}
+	when := from.Format("15:04")
+	if dates.Format(*from) != dates.Format(time.Now()) {
+		when = from.Format("Mon Jan 2 15:04")
+	}
+	display.Printf("\n%s\n", display.Yellow(display.Bold("📴 Offline — data from "+when)))
+}
+
+// responseCache returns the cache of API responses in the config directory
+func responseCache() (*httpcache.Cache, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return httpcache.New(filepath.Join(dir, "cache")), nil
+}
+
+// configureChunking applies the --chunk-days/--no-chunking settings and
+// reports per-chunk progress on stderr when it is a terminal
+func configureChunking(client *api.Client) {
+	days := viper.GetInt("chunk_days")
+	if viper.GetBool("no_chunking") {
+		days = 0
+	}
+
+	client.SetChunking(days, func(done, total int) {
+		if !display.AnimationEnabled(os.Stderr) {
+			return
+		}
+		if done == total {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+			return
+		}
+		fmt.Fprintf(os.Stderr, "\r📦 Fetching in %d chunks... %d/%d", total, done, total)
+	})
+}
+
+// configurePaging applies the api.max_pages setting and reports progress
+// through lists of several pages on stderr when it is a terminal
+func configurePaging(client *api.Client) {
+	client.SetPaging(viper.GetInt("api.max_pages"), func(done, total int) {
+		if !display.AnimationEnabled(os.Stderr) {
+			return
+		}
+		if done == total {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+			return
+		}
+		fmt.Fprintf(os.Stderr, "\r📄 Fetching page %d of %d...", done+1, total)
+	})
+}
+
+// configureRateLimit applies the api.rate_limit setting and, on a
+// terminal, shows when the server has asked to slow down, so batch
+// operations don't seem to hang
+func configureRateLimit(client *api.Client) {
+	client.SetRateLimit(viper.GetFloat64("api.rate_limit"), func(wait time.Duration) {
+		if !display.AnimationEnabled(os.Stderr) {
+			return
+		}
+		if wait == 0 {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+			return
+		}
+		seconds := (wait + time.Second - 1).Truncate(time.Second)
+		fmt.Fprintf(os.Stderr, "\r\033[K⏳ Rate limited, waiting %s…", seconds)
+	})
+}
+
+// entriesTable lists entries with their start time, project, description
+// and hours, sorted by start time with untimed entries last. Descriptions
+// are cut to fit the terminal.
+func entriesTable(entries []api.Entry) *display.Table {
+	sorted := append([]api.Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].StartTime, sorted[j].StartTime
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		return a < b
+	})
+
+	table := display.NewTable("Start", "Project", "Description", "Hours")
+	table.FitWidth(display.TerminalWidth(), 2)
+	for _, entry := range sorted {
+		table.AddCells(display.Text(entry.StartTime), display.Text(projectLabel(entry.Project)),
+			display.Text(strings.Join(strings.Fields(entry.Description), " ")), display.Hours(entry.Duration))
+	}
+	return table
+}
+
+// breakdownSections parses --by into which breakdowns to print
+func breakdownSections(by string) (sources, projects bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(by)) {
+	case "source":
+		return true, false, nil
+	case "project":
+		return false, true, nil
+	case "both":
+		return true, true, nil
+	}
+	return false, false, fmt.Errorf("invalid --by %q (use project, source or both)", by)
+}
+
+// knownSources are the sources entries come from
+var knownSources = []string{"toggl", "tempo", "manual"}
+
+// parseSources validates --source, returning the lowercase source names
+// without duplicates
+func parseSources(values []string) ([]string, error) {
+	var sources []string
+	for _, value := range values {
+		name := strings.ToLower(strings.TrimSpace(value))
+		known := false
+		for _, source := range knownSources {
+			known = known || name == source
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid --source %q (use toggl, tempo or manual)", value)
+		}
+		duplicate := false
+		for _, source := range sources {
+			duplicate = duplicate || name == source
+		}
+		if !duplicate {
+			sources = append(sources, name)
+		}
+	}
+	return sources, nil
+}
+
+// printSourceFilter notes in a summary's header that it only counts some
+// sources
+func printSourceFilter(sources []string) {
+	if len(sources) > 0 {
+		display.Printf("🔎 Filtered to %s entries only\n", strings.Join(sources, ", "))
+	}
+}
+
+// printBreakdown prints hours by key, most first. With top > 0, the keys
+// after the first top are summed into one "(other)" line.
+func printBreakdown(title string, hours map[string]float64, top int) {
+	keys := sortedByHours(hours)
+	var other float64
+	grouped := 0
+	if top > 0 && len(keys) > top {
+		for _, key := range keys[top:] {
+			other += hours[key]
+		}
+		grouped = len(keys) - top
+		keys = keys[:top]
+	}
+
+	width := 8
+	for _, key := range append(keys, "(other)") {
+		if w := utf8.RuneCountInString(key) + 1; w > width {
+			width = w
+		}
+	}
+
+	display.Printf("Breakdown by %s:\n", title)
+	for _, key := range keys {
+		display.Printf("  • %-*s %s\n", width, key+":", display.FormatDuration(hours[key]))
+	}
+	if grouped > 0 {
+		display.Printf("  • %-*s %s (%s)\n", width, "(other):", display.FormatDuration(other), countLabel(grouped, strings.ToLower(title)))
+	}
+}
+
+// printBreakdownVs prints a breakdown with each key's hours in the previous
+// period and the difference; keys found in either period are listed
+func printBreakdownVs(title string, hours, previous map[string]float64) {
+	keys := sortedByHours(hours)
+	for _, key := range sortedByHours(previous) {
+		if _, ok := hours[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	width := 8
+	for _, key := range keys {
+		if w := utf8.RuneCountInString(key) + 1; w > width {
+			width = w
+		}
+	}
+
+	display.Printf("Breakdown by %s:\n", title)
+	for _, key := range keys {
+		display.Printf("  • %-*s %s (prev %s, %s)\n", width, key+":", display.FormatDuration(hours[key]), display.FormatDuration(previous[key]), formatDelta(hours[key]-previous[key]))
+	}
+}
+
+// sumHours adds up hours
+func sumHours(hours map[string]float64) float64 {
+	var total float64
+	for _, value := range hours {
+		total += value
+	}
+	return total
+}
+
+// roundedEntries returns a copy of entries with their durations rounded for
+// display
+func roundedEntries(entries []api.Entry, round rounding.Display) []api.Entry {
+	if !round.Enabled() {
+		return entries
+	}
+	rounded := make([]api.Entry, len(entries))
+	for i, entry := range entries {
+		entry.Duration = round.Hours(entry.Duration)
+		rounded[i] = entry
+	}
+	return rounded
+}
+
+// printRoundingNote notes below a summary that its durations are rounded
+func printRoundingNote(round rounding.Display) {
+	if round.Enabled() {
+		display.Printf("ℹ️  Durations rounded %s for display; totals add up the rounded values\n\n", round)
+	}
+}
+
+// projectHours sums the entries' hours by project
+func projectHours(entries []api.Entry) map[string]float64 {
+	hours := map[string]float64{}
+	for _, entry := range entries {
+		hours[projectLabel(entry.Project)] += entry.Duration
+	}
+	return hours
+}
+
+// parseDateFlag normalizes a date flag value to YYYY-MM-DD.
+// An empty value stays empty so the range remains open on that side.
+func parseDateFlag(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	t, err := dates.Parse(value, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	return dates.Format(t), nil
+}
+
+// parseMonthFlag returns the first day of the month given as YYYY-MM or as
+// a month name or number in the current year
+func parseMonthFlag(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), nil
+	}
+	if t, err := time.ParseInLocation("2006-01", value, now.Location()); err == nil {
+		return t, nil
+	}
+	month, err := dates.ParseMonth(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --month %q (expected YYYY-MM or a month name)", value)
+	}
+	return time.Date(now.Year(), month, 1, 0, 0, 0, 0, now.Location()), nil
+}
+
+// isNotFound reports whether err is the API's 404 response
+func isNotFound(err error) bool {
+	return errors.Is(err, api.ErrNotFound)
+}
+
+// apiErrorHint suggests what to do about an API error, or returns "" if
+// there's nothing to suggest
+func apiErrorHint(err error) string {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	switch {
+	case apiErr.Unauthorized():
+		return "Your session is no longer valid; run 'timetracker login' to log in again."
+	case apiErr.ServerError():
+		return "The server had a problem; try again in a moment."
+	}
+	return ""
+}
+
+// durationUpdate builds the request that changes an entry's duration while
+// keeping its other fields. Entries with clock times keep their start time
+// and get a new end time, since the server derives a manual entry's duration
+// from its times, and fail if that end would fall on the next day.
+func durationUpdate(entry api.Entry, hours float64) (api.UpdateEntryRequest, error) {
+	update := api.UpdateEntryRequest{
+		Date:        entry.Date,
+		Duration:    hours,
+		Project:     entry.Project,
+		Description: entry.Description,
+		Source:      entry.Source,
+	}
+
+	start, err := time.Parse("15:04", entry.StartTime)
+	if err != nil {
+		return update, nil
+	}
+
+	end := start.Add(time.Duration(math.Round(hours*60)) * time.Minute)
+	if end.Day() != start.Day() {
+		return api.UpdateEntryRequest{}, fmt.Errorf("entry must end on the same day (%s + %s is past midnight)", entry.StartTime, display.FormatDuration(hours))
+	}
+	update.Date = entry.Day()
+	update.StartTime = entry.StartTime
+	update.EndTime = end.Format("15:04")
+	update.Timezone = dates.LocalZone()
+	return update, nil
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+// holidaysFile stores the public holidays, keyed by YYYY-MM-DD date
+const holidaysFile = "holidays.json"
+
+var (
+	holidaysYear    int
+	holidaysCountry string
+)
+
+// holidaysCmd represents the holidays command
+var holidaysCmd = &cobra.Command{
+	Use:   "holidays",
+	Short: "List, add and remove public holidays",
+	Long: `Manage the public holidays that 'missing', 'balance' and 'week' treat as
+days off. Without a subcommand, the holidays of --year (default: this year)
+are listed.
+
+Holidays are stored in ~/.timetracker/holidays.json. Holidays listed under
+"holidays" in the config file are honored too. Vacation weeks are marked
+with 'timetracker balance vacation'.
+
+Examples:
+  timetracker holidays
+  timetracker holidays add 2024-05-01 "Labour Day"
+  timetracker holidays import --country DE --year 2024
+  timetracker holidays remove 2024-05-01`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := holidaysYear
+		if year == 0 {
+			year = time.Now().Year()
+		}
+
+		holidays, err := loadHolidays()
+		if err != nil {
+			return err
+		}
+
+		prefix := fmt.Sprintf("%d-", year)
+		table := display.NewTable("Date", "Day", "Name")
+		count := 0
+		for _, date := range sortedKeys(holidays) {
+			if !strings.HasPrefix(date, prefix) {
+				continue
+			}
+			table.AddRow(date, dayOf(date).Format("Mon"), holidays[date])
+			count++
+		}
+
+		if count == 0 {
+			display.Printf("No holidays recorded for %d. Add them with 'timetracker holidays add' or 'holidays import'.\n", year)
+			return nil
+		}
+
+		display.Printf("\n🎉 Holidays in %d\n\n", year)
+		table.Print()
+		display.Println()
+		return nil
+	},
+}
+
+// holidaysAddCmd represents the holidays add command
+var holidaysAddCmd = &cobra.Command{
+	Use:   "add <date> <name>",
+	Short: "Add or rename a holiday",
+	Args:  cobra.ExactArgs(2),
+	Example: `  timetracker holidays add 2024-05-01 "Labour Day"
+  timetracker holidays add 2024-12-24 "Christmas Eve"`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDateFlag(args[0])
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSpace(args[1])
+		if name == "" {
+			return fmt.Errorf("holiday name must not be empty")
+		}
+
+		store, err := openHolidayStore()
+		if err != nil {
+			return err
+		}
+		store.Set(date, name)
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save holidays: %w", err)
+		}
+
+		display.Printf("✓ %s (%s) is %s\n", date, dayOf(date).Format("Mon"), name)
+		return nil
+	},
+}
+
+// holidaysRemoveCmd represents the holidays remove command
+var holidaysRemoveCmd = &cobra.Command{
+	Use:          "remove <date>",
+	Short:        "Remove a holiday",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDateFlag(args[0])
+		if err != nil {
+			return err
+		}
+
+		store, err := openHolidayStore()
+		if err != nil {
+			return err
+		}
+		name, ok := store.Get(date)
+		if !ok {
+			if _, inConfig := configHolidays()[date]; inConfig {
+				return fmt.Errorf("%s is set under \"holidays\" in the config file; remove it there", date)
+			}
+			return fmt.Errorf("no holiday on %s", date)
+		}
+		store.Delete(date)
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save holidays: %w", err)
+		}
+
+		display.Printf("✓ Removed %s (%s)\n", date, name)
+		return nil
+	},
+}
+
+// holidaysImportCmd represents the holidays import command
+var holidaysImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Add a country's public holidays for a year",
+	Long: fmt.Sprintf(`Add the nationwide public holidays of a country for a year. Regional
+holidays (e.g. of a German state) aren't included; add them with
+'timetracker holidays add'. Holidays you already recorded keep their names.
+
+Countries: %s
+
+Examples:
+  timetracker holidays import --country DE --year 2024
+  timetracker holidays import --country US`, strings.Join(calendar.Countries(), ", ")),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := holidaysYear
+		if year == 0 {
+			year = time.Now().Year()
+		}
+
+		generated, err := calendar.PublicHolidays(holidaysCountry, year)
+		if err != nil {
+			return err
+		}
+
+		store, err := openHolidayStore()
+		if err != nil {
+			return err
+		}
+
+		added := 0
+		for _, date := range sortedKeys(generated) {
+			if _, ok := store.Get(date); ok {
+				continue
+			}
+			store.Set(date, generated[date])
+			display.Printf("  + %s %s %s\n", date, dayOf(date).Format("Mon"), generated[date])
+			added++
+		}
+		if added == 0 {
+			display.Printf("All %d holidays of %s %d are already recorded.\n", len(generated), strings.ToUpper(holidaysCountry), year)
+			return nil
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save holidays: %w", err)
+		}
+
+		display.Printf("✓ Added %d of %d holidays for %s %d\n", added, len(generated), strings.ToUpper(holidaysCountry), year)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(holidaysCmd)
+	holidaysCmd.AddCommand(holidaysAddCmd)
+	holidaysCmd.AddCommand(holidaysRemoveCmd)
+	holidaysCmd.AddCommand(holidaysImportCmd)
+	localdata.Register(holidaysFile)
+
+	holidaysCmd.Flags().IntVar(&holidaysYear, "year", 0, "Year to list (default: this year)")
+	holidaysImportCmd.Flags().IntVar(&holidaysYear, "year", 0, "Year to add holidays for (default: this year)")
+	holidaysImportCmd.Flags().StringVar(&holidaysCountry, "country", "", "Country code ("+strings.Join(calendar.Countries(), ", ")+")")
+	holidaysImportCmd.MarkFlagRequired("country")
+}
+
+// openHolidayStore opens the holidays store in the config directory
+func openHolidayStore() (*localdata.Store[string], error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return localdata.Open[string](filepath.Join(dir, holidaysFile))
+}
+
+// loadHolidays returns all holidays, from the store and the config file,
+// as YYYY-MM-DD dates to names. The store wins where both name a date.
+func loadHolidays() (map[string]string, error) {
+	store, err := openHolidayStore()
+	if err != nil {
+		return nil, err
+	}
+
+	holidays := configHolidays()
+	for _, date := range store.Keys() {
+		holidays[date], _ = store.Get(date)
+	}
+	return holidays, nil
+}
+
+// configHolidays returns the "holidays" from the config file, a map of
+// YYYY-MM-DD dates to names
+func configHolidays() map[string]string {
+	holidays := map[string]string{}
+	if err := config.FileValue("holidays", &holidays); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Ignoring holidays in the config file: %v\n", err)
+	}
+	return holidays
+}
+
+// sortedKeys returns the keys of a string map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dayLabel describes a day off in the calendar, or returns "" for workdays
+// and weekends
+func dayLabel(cal *calendar.Calendar, date string) string {
+	switch kind, name := cal.Day(dayOf(date)); kind {
+	case calendar.Holiday:
+		return name
+	case calendar.Vacation:
+		return strings.TrimSpace("vacation " + name)
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/importer"
+)
+
+var importDryRun bool
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import time entries from provider CSV exports",
+	Long: `Import time entries from CSV files exported by a provider.
+
+Rows are parsed locally and compared against the entries already on the
+server by content (date, project, description and duration), so importing
+the same file twice does not create duplicates.`,
+}
+
+// importTogglCmd represents the import toggl-csv command
+var importTogglCmd = &cobra.Command{
+	Use:   "toggl-csv <file>",
+	Short: "Import a Toggl detailed report CSV export",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(cmd.Context(), importer.Toggl{}, args[0])
+	},
+}
+
+// importTempoCmd represents the import tempo-csv command
+var importTempoCmd = &cobra.Command{
+	Use:   "tempo-csv <file>",
+	Short: "Import a Tempo worklog CSV export",
+	Long: `Import a Tempo worklog CSV export.
+
+The file must contain the "Issue Key", "Hours" and "Work date" columns;
+"Work Description" is used as the entry description when present.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(cmd.Context(), importer.Tempo{}, args[0])
+	},
+}
+
+// runImport parses a CSV file with the given adapter, deduplicates the rows
+// against the server and uploads the rest
+func runImport(ctx context.Context, adapter importer.Adapter, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	rows, rejected, err := importer.Parse(file, adapter)
+	if err != nil {
+		return fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	if len(rows) == 0 {
+		display.Println("No importable entries found in file.")
+		printRejected(rejected)
+		return nil
+	}
+
+	client, err := newAuthenticatedClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to := importer.DateRange(rows)
+	existing, err := client.GetEntries(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to fetch existing entries: %w", err)
+	}
+
+	fresh, skipped := importer.Dedupe(rows, existing)
+
+	if importDryRun {
+		display.Printf("\n🔎 Dry run: %d entries would be imported, %d skipped as duplicates\n\n", len(fresh), len(skipped))
+		if len(fresh) > 0 {
+			table := display.NewTable("Date", "Start", "Project", "Description", "Hours")
+			for _, row := range fresh {
+				table.AddRow(row.Date, row.StartTime, row.Project, row.Description, display.FormatHours(row.Hours))
+			}
+			table.Print()
+			display.Println()
+		}
+		printRejected(rejected)
+		return nil
+	}
+
+	showProgress := display.AnimationEnabled(os.Stdout)
+	result, err := importer.Upload(client, adapter.Source(), fresh, func(done, total int) {
+		if showProgress {
+			display.Printf("\r⬆️  Uploading %d/%d entries...", done, total)
+		}
+	})
+	if showProgress && len(fresh) > 0 {
+		display.Print("\r\033[K") // Clear progress line
+	}
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	failed := result.Failed + len(rejected)
+	if failed == 0 {
+		display.Print("✓ Import completed successfully!\n\n")
+	} else {
+		display.Print("⚠️  Import completed with errors\n\n")
+	}
+
+	display.Printf("📥 Imported: %d entries\n", result.Imported)
+	display.Printf("⏭️  Skipped: %d entries\n", result.Skipped+len(skipped))
+	display.Printf("✗ Failed: %d entries\n\n", failed)
+
+	printRejected(rejected)
+
+	return nil
+}
+
+// printRejected lists the rows that could not be parsed
+func printRejected(rejected []importer.RowError) {
+	if len(rejected) == 0 {
+		return
+	}
+
+	display.Printf("Rows not imported (%d):\n", len(rejected))
+	for _, rowErr := range rejected {
+		display.Printf("  ✗ %s\n", rowErr.Error())
+	}
+	display.Println()
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importTogglCmd)
+	importCmd.AddCommand(importTempoCmd)
+
+	importCmd.PersistentFlags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without uploading")
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/lint"
+)
+
+var (
+	lintFrom  string
+	lintTo    string
+	lintRules bool
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check entries for problems before invoicing",
+	Long: `Check the entries of a date range (default: this month) for problems such
+as missing descriptions, implausibly long or short entries and
+descriptions that are just issue numbers. Findings are grouped by rule.
+
+The command exits with an error when an error-severity rule fires, so it
+can gate an invoicing script. Warnings are only reported.
+
+Rules can be disabled in the config file:
+
+  lint:
+    disabled:
+      - tiny-duration
+
+Examples:
+  timetracker lint
+  timetracker lint --from 2024-03-01 --to 2024-03-31
+  timetracker lint --rules`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		disabled, err := disabledLintRules()
+		if err != nil {
+			return err
+		}
+
+		if lintRules {
+			table := display.NewTable("Rule", "Severity", "Flags", "Enabled")
+			for _, rule := range lint.Rules {
+				enabled := "yes"
+				if disabled[rule.Name] {
+					enabled = "no"
+				}
+				table.AddRow(rule.Name, string(rule.Severity), rule.Description, enabled)
+			}
+			table.Print()
+			return nil
+		}
+
+		from, err := parseDateFlag(lintFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(lintTo)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if from == "" {
+			from = dates.Format(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local))
+		}
+		if to == "" {
+			to = dates.Format(now)
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		findings := lint.Run(entries, disabled)
+
+		display.Printf("\n🧹 Linting %d entries from %s to %s\n\n", len(entries), from, to)
+		if len(findings) == 0 {
+			display.Println("✓ No problems found")
+			display.Println()
+			return nil
+		}
+
+		// Scripts get every finding in one table rather than one per rule
+		delimited := display.OutputFormat() != display.FormatTable
+		all := display.NewTable("Rule", "Severity", "ID", "Date", "Source", "Project", "Hours", "Problem")
+
+		errors, warnings := 0, 0
+		for start := 0; start < len(findings); {
+			rule := findings[start].Rule
+			end := start
+			for end < len(findings) && findings[end].Rule.Name == rule.Name {
+				end++
+			}
+			group := findings[start:end]
+
+			label := display.Yellow("warning")
+			if rule.Severity == lint.Error {
+				label = display.Red("error")
+				errors += len(group)
+			} else {
+				warnings += len(group)
+			}
+			display.Printf("%s %s: %s (%d)\n", label, rule.Name, rule.Description, len(group))
+			table := display.NewTable("ID", "Date", "Source", "Project", "Hours", "Problem")
+			for _, finding := range group {
+				entry := finding.Entry
+				table.AddRow(entry.ID, entry.Day(), entry.Source, projectLabel(entry.Project),
+					display.FormatHours(entry.Duration), finding.Message)
+				all.AddCells(display.Text(rule.Name), display.Text(string(rule.Severity)), display.Text(entry.ID),
+					display.Text(entry.Day()), display.Text(entry.Source), display.Text(projectLabel(entry.Project)),
+					display.Hours(entry.Duration), display.Text(finding.Message))
+			}
+			if !delimited {
+				table.Print()
+			}
+			display.Println()
+
+			start = end
+		}
+
+		if delimited {
+			all.Print()
+		}
+		display.Printf("%s, %s\n\n", countLabel(errors, "error"), countLabel(warnings, "warning"))
+
+		if lint.HasErrors(findings) {
+			return fmt.Errorf("%s found", countLabel(errors, "error"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVar(&lintFrom, "from", "", "Start date (YYYY-MM-DD, today, yesterday; default: start of this month)")
+	lintCmd.Flags().StringVar(&lintTo, "to", "", "End date (YYYY-MM-DD, today, yesterday; default: today)")
+	lintCmd.Flags().BoolVar(&lintRules, "rules", false, "List the rules and whether they're enabled")
+}
+
+// disabledLintRules returns the rules disabled under "lint" in the config
+// file
+func disabledLintRules() (map[string]bool, error) {
+	var settings struct {
+		Disabled []string `yaml:"disabled"`
+	}
+	if err := config.FileValue("lint", &settings); err != nil {
+		return nil, err
+	}
+
+	disabled := map[string]bool{}
+	for _, name := range settings.Disabled {
+		if _, ok := lint.Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown lint rule %q in config file (see 'timetracker lint --rules')", name)
+		}
+		disabled[name] = true
+	}
+	return disabled, nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+var mergeStrategy string
+
+// localdataCmd represents the localdata command
+var localdataCmd = &cobra.Command{
+	Use:   "localdata",
+	Short: "Maintain the data the CLI stores locally",
+	Long: `Maintain the data the CLI keeps in ~/.timetracker.
+
+When the directory is synced between machines (Dropbox, Syncthing), editing
+on both sides produces conflicted copies. Use 'localdata merge' to combine
+them item by item.`,
+}
+
+// localdataMergeCmd represents the localdata merge command
+var localdataMergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Merge conflicted copies of local data files",
+	Long: `Merge conflicted copies left by file-sync tools back into the original files.
+
+Items that are identical in every copy are kept. For items that differ, the
+field-level changes are shown and you pick which version to keep, or use
+--strategy newest to keep the most recently modified one automatically.
+
+Merged copies are moved to ~/.timetracker/conflicts/ rather than deleted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var strategy localdata.Strategy
+		switch mergeStrategy {
+		case "newest":
+			strategy = localdata.Newest
+		case "interactive":
+			strategy = promptVersion(bufio.NewReader(os.Stdin))
+		default:
+			return fmt.Errorf("unknown strategy %q (expected interactive or newest)", mergeStrategy)
+		}
+
+		dir, err := config.Dir()
+		if err != nil {
+			return err
+		}
+
+		conflicts, err := localdata.FindConflicts(dir)
+		if err != nil {
+			return err
+		}
+
+		if len(conflicts) == 0 {
+			display.Println("✓ No conflicted copies found.")
+			return nil
+		}
+
+		for _, conflict := range conflicts {
+			name := filepath.Base(conflict.Original)
+			if !conflict.Mergeable() {
+				display.Printf("⚠️  %s: %d conflicted copies, not a mergeable store — resolve manually\n", name, len(conflict.Copies))
+				continue
+			}
+
+			if err := mergeConflict(conflict, strategy, filepath.Join(dir, "conflicts")); err != nil {
+				return fmt.Errorf("failed to merge %s: %w", name, err)
+			}
+			display.Printf("✓ %s: merged %d conflicted copies\n", name, len(conflict.Copies))
+		}
+
+		return nil
+	},
+}
+
+// mergeConflict merges a store's conflicted copies into it and archives them
+func mergeConflict(conflict localdata.Conflict, strategy localdata.Strategy, archiveDir string) error {
+	stores := make([]*localdata.Store[any], 0, len(conflict.Copies)+1)
+	for _, path := range append([]string{conflict.Original}, conflict.Copies...) {
+		store, err := localdata.Open[any](path)
+		if err != nil {
+			return err
+		}
+		stores = append(stores, store)
+	}
+
+	if err := localdata.Merge(stores, strategy); err != nil {
+		return err
+	}
+
+	if err := stores[0].Save(); err != nil {
+		return err
+	}
+
+	return conflict.Archive(archiveDir, time.Now())
+}
+
+// promptVersion returns a strategy that asks the user to pick each differing item
+func promptVersion(reader *bufio.Reader) localdata.Strategy {
+	return func(d localdata.Difference) (int, error) {
+		fmt.Printf("\n%s\n", d.Key)
+		for _, change := range localdata.FieldChanges(d) {
+			fmt.Printf("  %s\n", change)
+		}
+		for i, version := range d.Versions {
+			modified := "missing"
+			if version.Present {
+				modified = "modified " + version.Item.ModifiedAt.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  [%d] %s (%s)\n", i+1, version.File, modified)
+		}
+
+		for {
+			fmt.Printf("Keep which version? [1-%d]: ", len(d.Versions))
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return 0, fmt.Errorf("no version chosen for %q", d.Key)
+			}
+
+			choice, err := strconv.Atoi(strings.TrimSpace(line))
+			if err == nil && choice >= 1 && choice <= len(d.Versions) {
+				return choice - 1, nil
+			}
+		}
+	}
+}
+
+// warnLocalConflicts prints a warning when conflicted copies exist in the
+// config directory. It never fails the command it runs before.
+func warnLocalConflicts(cmd *cobra.Command, args []string) {
+	if cmd == localdataMergeCmd {
+		return
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return
+	}
+
+	conflicts, err := localdata.FindConflicts(dir)
+	if err != nil || len(conflicts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  %d local data file(s) have sync conflicts. Run 'timetracker localdata merge' to resolve them.\n", len(conflicts))
+}
+
+func init() {
+	rootCmd.AddCommand(localdataCmd)
+	localdataCmd.AddCommand(localdataMergeCmd)
+
+	localdataMergeCmd.Flags().StringVar(&mergeStrategy, "strategy", "interactive", "How to resolve differing items: interactive or newest")
+}
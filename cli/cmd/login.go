@@ -1,21 +1,56 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"syscall"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vmiller/timetracker-cli/internal/api"
 	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/logging"
 	"golang.org/x/term"
 )
 
 var (
-	username string
-	password string
+	username           string
+	password           string
+	loginPasswordStdin bool
+	loginToken         bool
+	loginTokenStdin    bool
+	loginSSO           bool
+	loginNoBrowser     bool
+	loginOTP           string
+	loginNoSave        bool
+	loginExport        bool
 )
 
+// loginPrompts is where prompts go: stdout, or stderr when stdout is
+// captured for --export
+var loginPrompts io.Writer = os.Stdout
+
+// loginInput buffers stdin for all of login's prompts, so text read ahead
+// for one prompt isn't lost to the next
+var loginInput = bufio.NewReader(os.Stdin)
+
+// passwordEnv names the environment variable a password is read from
+const passwordEnv = "TIMETRACKER_PASSWORD"
+
+// tokenEnv names the environment variable login --token reads a personal
+// access token from
+const tokenEnv = "TIMETRACKER_TOKEN"
+
+// otpEnv names the environment variable a one-time code is read from
+const otpEnv = "TIMETRACKER_OTP"
+
+// otpAttempts is how many codes login asks for before giving up
+const otpAttempts = 3
+
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
 	Use:   "login",
@@ -25,29 +60,107 @@ var loginCmd = &cobra.Command{
 The credentials are stored in ~/.timetracker/config.yaml with 0600 permissions
 (readable only by the current user).
 
-You can provide credentials via flags or be prompted interactively.`,
+With --api-url, the server logged in to is saved as well, so later commands
+use it without the flag. Other commands never save --api-url; it only
+applies to that run.
+
+You can provide credentials via flags or be prompted interactively. For
+scripts, pipe the password in with --password-stdin or set
+TIMETRACKER_PASSWORD; --password leaves it in your shell history.
+
+Accounts with two-factor authentication are asked for the 6-digit code from
+their authenticator app, or one of their recovery codes, up to three times.
+Scripts can pass it with --otp or TIMETRACKER_OTP instead.
+
+With --token, log in with a personal access token generated in the
+dashboard instead. The token is read from --token-stdin, the
+TIMETRACKER_TOKEN environment variable or a prompt, in that order. Unlike
+password logins it is never refreshed, which suits CI jobs and servers.
+
+With --sso, log in through your organisation's identity provider in the
+browser. Where no browser can be opened, e.g. over SSH, or with
+--no-browser, the CLI prints the login URL and asks for the code shown
+after logging in. The login times out after 5 minutes.
+
+With --no-save, the tokens are kept out of the config file, e.g. on shared
+machines. Add --export to print them as shell exports instead; later
+commands use TIMETRACKER_ACCESS_TOKEN and TIMETRACKER_REFRESH_TOKEN from
+the environment in place of the config file.
+
+Examples:
+  timetracker login
+  timetracker login --api-url https://tt.example.com
+  eval $(timetracker login --no-save --export)
+  timetracker login --sso
+  timetracker login --token
+  echo "$PAT" | timetracker login --token --token-stdin`,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if loginTokenStdin && !loginToken {
+			return fmt.Errorf("--token-stdin requires --token")
+		}
+		if loginPasswordStdin && password != "" {
+			return fmt.Errorf("--password and --password-stdin can't be used together")
+		}
+		if loginPasswordStdin && username == "" {
+			return fmt.Errorf("--password-stdin requires --username")
+		}
+		passwordGiven := password != "" || loginPasswordStdin
+		if loginToken && (username != "" || passwordGiven) {
+			return fmt.Errorf("--token can't be combined with --username or --password")
+		}
+		if loginSSO && (loginToken || username != "" || passwordGiven) {
+			return fmt.Errorf("--sso can't be combined with --token, --username or --password")
+		}
+		if loginOTP != "" && (loginToken || loginSSO) {
+			return fmt.Errorf("--otp only applies to password logins")
+		}
+		if loginNoBrowser && !loginSSO {
+			return fmt.Errorf("--no-browser requires --sso")
+		}
+
+		if loginExport {
+			// Only the exports may reach stdout
+			loginPrompts = os.Stderr
+			logging.SetLevel(logging.Quiet)
+		}
+
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
+		// Logging in is where a new server is saved
+		cfg.SaveAPIURL = cmd.Flags().Changed("api-url")
+
+		// A wrong API URL is reported before asking for credentials
+		if err := newClient(cmd.Context(), cfg).Preflight(); err != nil {
+			return err
+		}
+
+		if loginToken {
+			return loginWithToken(cmd.Context(), cfg)
+		}
+		if loginSSO {
+			return loginWithSSO(cmd.Context(), cfg, loginNoBrowser)
+		}
 
 		// Prompt for username if not provided
 		if username == "" {
-			fmt.Print("Username: ")
-			fmt.Scanln(&username)
+			fmt.Fprint(loginPrompts, "Username: ")
+			line, err := readInputLine()
+			if err != nil {
+				return fmt.Errorf("failed to read username: %w", err)
+			}
+			username = strings.TrimSpace(line)
 		}
 
-		// Prompt for password if not provided (with masking)
+		// Read the password from stdin, the environment or a prompt
 		if password == "" {
-			fmt.Print("Password: ")
-			bytepw, err := term.ReadPassword(int(syscall.Stdin))
+			password, err = readPassword()
 			if err != nil {
-				return fmt.Errorf("failed to read password: %w", err)
+				return err
 			}
-			password = string(bytepw)
-			fmt.Println() // Add newline after password input
 		}
 
 		// Validate inputs
@@ -56,25 +169,191 @@ You can provide credentials via flags or be prompted interactively.`,
 		}
 
 		// Create API client
-		client := api.NewClient(cfg)
+		client := newLoginClient(cmd.Context(), cfg)
 
 		// Attempt login
-		fmt.Printf("Logging in as %s...\n", username)
-		if err := client.Login(username, password); err != nil {
+		display.Printf("Logging in as %s...\n", username)
+		otp := loginOTP
+		if otp == "" {
+			otp = os.Getenv(otpEnv)
+		}
+		if err := passwordLogin(client, username, password, strings.TrimSpace(otp)); err != nil {
 			return fmt.Errorf("login failed: %w", err)
 		}
 
-		fmt.Println("✓ Login successful!")
-		fmt.Printf("Config saved to: %s/.timetracker/config.yaml\n", os.Getenv("HOME"))
-
+		finishLogin(cfg, "✓ Login successful!")
 		return nil
 	},
 }
 
+// newLoginClient returns a client to log in with. The new tokens are saved
+// unless --no-save is given, even if the current ones came from the
+// environment.
+func newLoginClient(ctx context.Context, cfg *config.Config) *api.Client {
+	client := newClient(ctx, cfg)
+	cfg.Session = loginNoSave
+	return client
+}
+
+// finishLogin reports a successful login and prints the tokens as shell
+// exports with --export
+func finishLogin(cfg *config.Config, message string) {
+	display.Println(message)
+	if !loginNoSave {
+		if cfg.SaveAPIURL {
+			display.Printf("API URL saved: %s\n", cfg.APIURL)
+		}
+		display.Printf("Config saved to: %s/.timetracker/config.yaml\n", os.Getenv("HOME"))
+	} else if !loginExport {
+		display.Println("The tokens were not saved and end with this command; add --export to keep them in your shell")
+	}
+
+	if loginExport {
+		fmt.Printf("export %s=%s\n", config.AccessTokenEnv, shellQuote(cfg.AccessToken))
+		fmt.Printf("export %s=%s\n", config.RefreshTokenEnv, shellQuote(cfg.RefreshToken))
+		fmt.Printf("export %s=%s\n", config.AuthModeEnv, shellQuote(cfg.AuthMode))
+	}
+}
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// passwordLogin logs in with a password, asking for a one-time code when
+// the server requires one. Codes given up front are not re-prompted.
+func passwordLogin(client *api.Client, username, password, otp string) error {
+	err := client.Login(username, password, otp)
+	if !errors.Is(err, api.ErrOTPRequired) {
+		return err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("this account uses two-factor authentication; pass the code with --otp or %s", otpEnv)
+	}
+
+	for attempt := 1; ; attempt++ {
+		fmt.Fprint(loginPrompts, "Authentication code (or recovery code): ")
+		code, readErr := readInputLine()
+		if readErr != nil {
+			return fmt.Errorf("failed to read code: %w", readErr)
+		}
+		err = client.Login(username, password, strings.TrimSpace(code))
+		if !errors.Is(err, api.ErrOTPInvalid) || attempt == otpAttempts {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "✗ That code didn't work, try again.")
+	}
+}
+
+// loginWithToken stores a personal access token once the server accepts it
+func loginWithToken(ctx context.Context, cfg *config.Config) error {
+	token, err := readToken()
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("no token given")
+	}
+
+	client := newLoginClient(ctx, cfg)
+	display.Println("Checking token...")
+	if err := client.LoginWithToken(token); err != nil {
+		if errors.Is(err, api.ErrTokenRejected) {
+			return err
+		}
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	finishLogin(cfg, "✓ Logged in with a personal access token")
+	return nil
+}
+
+// readToken reads a personal access token from stdin, the environment or
+// a masked prompt
+func readToken() (string, error) {
+	if loginTokenStdin {
+		line, err := readInputLine()
+		if err != nil {
+			return "", fmt.Errorf("failed to read token: %w", err)
+		}
+		return strings.TrimSpace(line), nil
+	}
+	if token := strings.TrimSpace(os.Getenv(tokenEnv)); token != "" {
+		return token, nil
+	}
+
+	fmt.Fprint(loginPrompts, "Personal access token: ")
+	token, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(loginPrompts)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token: %w", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// readPassword reads the password from stdin with --password-stdin, from
+// TIMETRACKER_PASSWORD or from a masked prompt. Without a terminal to mask
+// input on, the prompt reads a plain line.
+func readPassword() (string, error) {
+	if loginPasswordStdin {
+		data, err := io.ReadAll(loginInput)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return trimNewline(string(data)), nil
+	}
+	if password := os.Getenv(passwordEnv); password != "" {
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "⚠️  stdin is not a terminal; reading the password without masking (use --password-stdin or %s in scripts)\n", passwordEnv)
+		fmt.Fprint(loginPrompts, "Password: ")
+		line, err := readInputLine()
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return line, nil
+	}
+
+	fmt.Fprint(loginPrompts, "Password: ")
+	bytepw, err := term.ReadPassword(fd)
+	fmt.Fprintln(loginPrompts) // Add newline after password input
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(bytepw), nil
+}
+
+// readInputLine reads a line from stdin without its line ending. Input that
+// ends without a newline counts as the last line.
+func readInputLine() (string, error) {
+	line, err := loginInput.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return trimNewline(line), nil
+}
+
+// trimNewline removes one trailing line ending
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
 func init() {
 	rootCmd.AddCommand(loginCmd)
 
 	// Flags for non-interactive login
 	loginCmd.Flags().StringVarP(&username, "username", "u", "", "Username for authentication")
 	loginCmd.Flags().StringVarP(&password, "password", "p", "", "Password for authentication (not recommended, use interactive prompt)")
+	loginCmd.Flags().BoolVar(&loginPasswordStdin, "password-stdin", false, "Read the password from stdin (requires --username)")
+	loginCmd.Flags().StringVar(&loginOTP, "otp", "", "One-time or recovery code for accounts with two-factor authentication")
+	loginCmd.Flags().BoolVar(&loginToken, "token", false, "Log in with a personal access token instead of a password")
+	loginCmd.Flags().BoolVar(&loginSSO, "sso", false, "Log in through the server's identity provider in a browser")
+	loginCmd.Flags().BoolVar(&loginNoBrowser, "no-browser", false, "With --sso, print the login URL and prompt for the code instead of opening a browser")
+	loginCmd.Flags().BoolVar(&loginNoSave, "no-save", false, "Keep the tokens out of the config file")
+	loginCmd.Flags().BoolVar(&loginExport, "export", false, "Print the tokens as shell export commands, for eval")
+	loginCmd.Flags().BoolVar(&loginTokenStdin, "token-stdin", false, "Read the personal access token from stdin (with --token)")
 }
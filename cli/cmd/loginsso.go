@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/browser"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/sso"
+)
+
+// ssoTimeout bounds the whole browser login, from opening the page to
+// redeeming the code
+const ssoTimeout = 5 * time.Minute
+
+// loginWithSSO logs in through the server's identity provider in the
+// browser. The code arrives on a localhost listener, or is pasted by the user
+// when no browser can be opened here.
+func loginWithSSO(ctx context.Context, cfg *config.Config, noBrowser bool) error {
+	client := newLoginClient(ctx, cfg)
+
+	settings, err := client.GetSSOSettings()
+	if isNotFound(err) || (err == nil && !settings.Enabled) {
+		return fmt.Errorf("the server at %s doesn't offer SSO login; use 'timetracker login' with a password or --token", cfg.APIURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check SSO login: %w", err)
+	}
+
+	pkce, err := sso.NewPKCE()
+	if err != nil {
+		return err
+	}
+	state, err := sso.NewState()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ssoTimeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	code, redirectURI, err := awaitSSOCode(ctx, client, pkce, state, noBrowser)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("SSO login timed out after %s; run 'timetracker login --sso' again", ssoTimeout)
+	}
+	if errors.Is(err, context.Canceled) {
+		return errors.New("SSO login cancelled")
+	}
+	if err != nil {
+		return fmt.Errorf("SSO login failed: %w", err)
+	}
+
+	if err := client.LoginWithSSOCode(ctx, code, pkce.Verifier, redirectURI); err != nil {
+		return fmt.Errorf("SSO login failed: %w", err)
+	}
+
+	finishLogin(cfg, "✓ Login successful!")
+	return nil
+}
+
+// awaitSSOCode sends the user to the login page and returns the code and
+// the redirect URI it was issued for
+func awaitSSOCode(ctx context.Context, client *api.Client, pkce sso.PKCE, state string, noBrowser bool) (string, string, error) {
+	if !noBrowser {
+		listener, err := sso.Listen(state)
+		if err != nil {
+			return "", "", err
+		}
+		defer listener.Close()
+
+		authorizeURL := client.SSOAuthorizeURL(listener.RedirectURI, state, pkce.Challenge)
+		err = browser.Default().Open(authorizeURL)
+		if err == nil {
+			fmt.Fprintln(loginPrompts, "Opened your browser to log in. Waiting for you to finish there...")
+			fmt.Fprintf(loginPrompts, "If it didn't open, visit:\n\n  %s\n\n", authorizeURL)
+			code, err := listener.Wait(ctx)
+			return code, listener.RedirectURI, err
+		}
+		if !errors.Is(err, browser.ErrNoBrowser) {
+			return "", "", err
+		}
+		// A browser elsewhere can't reach this machine's localhost, so
+		// fall back to pasting the code
+	}
+
+	authorizeURL := client.SSOAuthorizeURL(sso.OOBRedirectURI, state, pkce.Challenge)
+	fmt.Fprintf(loginPrompts, "Open this URL in a browser and log in:\n\n  %s\n\n", authorizeURL)
+	code, err := promptLine(ctx, "Paste the code shown after logging in: ")
+	if err == nil && code == "" {
+		err = errors.New("no code given")
+	}
+	return code, sso.OOBRedirectURI, err
+}
+
+// promptLine reads a line from stdin, giving up when ctx is done
+func promptLine(ctx context.Context, prompt string) (string, error) {
+	fmt.Fprint(loginPrompts, prompt)
+
+	lines := make(chan string, 1)
+	errs := make(chan error, 1)
+	go func() {
+		line, err := readInputLine()
+		if err != nil {
+			errs <- fmt.Errorf("failed to read code: %w", err)
+			return
+		}
+		lines <- strings.TrimSpace(line)
+	}()
+
+	select {
+	case line := <-lines:
+		return line, nil
+	case err := <-errs:
+		return "", err
+	case <-ctx.Done():
+		fmt.Fprintln(loginPrompts)
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/managed"
+)
+
+// managedOverlay is the organisation-managed config overlay, nil if none is installed
+var managedOverlay *managed.Overlay
+
+// applyManagedConfig loads the managed overlay and pins its keys in viper so
+// they win over the config file, environment and flags. A tampered or
+// unsigned overlay (when signing is required) stops the CLI.
+func applyManagedConfig() {
+	overlay, err := managed.Load(managed.DefaultPath(), managed.PublicKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "The managed configuration at %s could not be verified. Contact your IT department.\n", managed.DefaultPath())
+		os.Exit(1)
+	}
+	if overlay == nil {
+		return
+	}
+	managedOverlay = overlay
+
+	// Tell the user when something they set is being ignored
+	attempts := map[string]string{}
+	for _, key := range managed.LockedKeys {
+		if viper.InConfig(key) {
+			attempts[key] = viper.GetString(key)
+		}
+		for _, env := range []string{strings.ToUpper(key), "TIMETRACKER_" + strings.ToUpper(key)} {
+			if value, ok := os.LookupEnv(env); ok {
+				attempts[key] = value
+			}
+		}
+		if flag := rootCmd.PersistentFlags().Lookup(flagForKey(key)); flag != nil && flag.Changed {
+			attempts[key] = flag.Value.String()
+		}
+	}
+	for _, key := range overlay.Overridden(attempts) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s is managed by your organisation; ignoring your value\n", key)
+	}
+
+	for key, value := range overlay.Values {
+		viper.Set(key, value)
+	}
+}
+
+// flagForKey returns the global flag that sets a viper key, e.g. insecure
+// for insecure_skip_verify
+func flagForKey(key string) string {
+	for flag, bound := range boundFlags {
+		if bound == key {
+			return flag
+		}
+	}
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// managedValues returns the managed overlay's values, if any
+func managedValues() map[string]string {
+	if managedOverlay == nil {
+		return nil
+	}
+	return managedOverlay.Values
+}
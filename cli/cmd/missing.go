@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	missingMonth string
+	missingMin   string
+)
+
+// missingCmd represents the missing command
+var missingCmd = &cobra.Command{
+	Use:   "missing",
+	Short: "List workdays with missing or too few hours",
+	Long: `List the workdays of a month, up to today, that have fewer hours than
+expected, with each day's total. Days off in the schedule (weekends by
+default), holidays ('timetracker holidays') and vacation weeks are skipped.
+
+A day is expected to have its hours from 'timetracker schedule', or the
+daily share of contract_hours over work_days without one (8h for 40h over
+Monday to Friday). --min replaces that with one threshold for every day.
+
+Vacation weeks are those marked with 'timetracker balance vacation'.
+
+The command exits with an error when any day is missing time, so it can
+gate a timesheet submission script.
+
+Examples:
+  timetracker missing
+  timetracker missing --month 2024-03 --min 6h`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		first, err := parseMonthFlag(missingMonth, now)
+		if err != nil {
+			return err
+		}
+		last := first.AddDate(0, 1, -1)
+		if today := dates.StartOfDay(now); last.After(today) {
+			last = today
+		}
+		if first.After(last) {
+			return fmt.Errorf("%s hasn't started yet", first.Format("January 2006"))
+		}
+
+		cal, err := workCalendar(cmd)
+		if err != nil {
+			return err
+		}
+
+		var min float64
+		if missingMin != "" {
+			if min, err = dates.ParseHours(missingMin); err != nil {
+				return fmt.Errorf("invalid --min: %w", err)
+			}
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(dates.Format(first), dates.Format(last))
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		hours := map[string]float64{}
+		for _, entry := range entries {
+			hours[entry.Day()] += entry.Duration
+		}
+
+		threshold := "expected hours"
+		if missingMin != "" {
+			threshold = display.FormatDuration(min)
+		}
+		display.Printf("\n🔍 Workdays under %s: %s to %s\n\n", threshold, dates.Format(first), dates.Format(last))
+
+		table := display.NewTable("Date", "Day", "Hours", "Expected", "Missing")
+		var workdays, missing, holidayCount, vacationDays int
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			date := dates.Format(day)
+			switch kind, _ := cal.Day(day); kind {
+			case calendar.DayOff:
+				continue
+			case calendar.Holiday:
+				holidayCount++
+				continue
+			case calendar.Vacation:
+				vacationDays++
+				continue
+			}
+
+			workdays++
+			expected := cal.Expected(day)
+			if missingMin != "" {
+				expected = min
+			}
+			if hours[date] < expected {
+				missing++
+				table.AddRow(date, day.Format("Mon"), display.FormatHours(hours[date]),
+					display.FormatHours(expected), display.Red(display.FormatHours(expected-hours[date])))
+			}
+		}
+
+		if missing == 0 {
+			display.Printf("✓ All %d workdays have at least %s\n", workdays, threshold)
+		} else {
+			table.Print()
+			display.Printf("\n%d of %d workdays are under %s\n", missing, workdays, threshold)
+		}
+		if holidayCount > 0 || vacationDays > 0 {
+			display.Printf("Skipped %s and %s\n", countLabel(holidayCount, "holiday"), countLabel(vacationDays, "vacation day"))
+		}
+		display.Println()
+
+		if missing > 0 {
+			return fmt.Errorf("%d workdays are missing time", missing)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(missingCmd)
+
+	missingCmd.Flags().StringVar(&missingMonth, "month", "", "Month to check (YYYY-MM or a month name; default: this month)")
+	missingCmd.Flags().StringVar(&missingMin, "min", "", "Minimum hours per workday, e.g. 6h or 7.5 (default: each day's hours from the schedule)")
+}
+
+// countLabel formats a count with a singular or plural noun
+func countLabel(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+// notesFile stores day notes, keyed by date, when the server has no notes
+// endpoint
+const notesFile = "notes.json"
+
+var (
+	noteDelete bool
+	noteMonth  string
+)
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note <date> [text]",
+	Short: "Attach a note to a day",
+	Long: `Attach a note such as "half day - dentist" to a day, show a day's note,
+or remove it with --delete. 'week' and 'today' show the notes of the days
+they cover.
+
+Notes are stored on the server, or in ~/.timetracker/notes.json when the
+server doesn't support them.
+
+Examples:
+  timetracker note 2024-04-03 "half day - dentist"
+  timetracker note today
+  timetracker note 2024-04-03 --delete
+  timetracker note list --month 2024-04`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDateFlag(args[0])
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+		notes := openNotebook(client)
+
+		switch {
+		case noteDelete:
+			if len(args) > 1 {
+				return fmt.Errorf("--delete doesn't take a note text")
+			}
+			if err := notes.Delete(date); err != nil {
+				return fmt.Errorf("failed to delete note: %w", err)
+			}
+			display.Printf("✓ Removed the note for %s\n", date)
+
+		case len(args) == 2:
+			text := strings.TrimSpace(args[1])
+			if text == "" {
+				return fmt.Errorf("the note is empty (use --delete to remove a note)")
+			}
+			if err := notes.Set(date, text); err != nil {
+				return fmt.Errorf("failed to save note: %w", err)
+			}
+			display.Printf("✓ Noted for %s: %s\n", date, text)
+
+		default:
+			found, err := notes.Between(date, date)
+			if err != nil {
+				return fmt.Errorf("failed to fetch notes: %w", err)
+			}
+			text, ok := found[date]
+			if !ok {
+				display.Printf("No note for %s.\n", date)
+				return nil
+			}
+			display.Printf("📝 %s: %s\n", date, text)
+		}
+
+		if notes.local != nil {
+			display.Println("   (stored locally; the server doesn't support notes)")
+		}
+		return nil
+	},
+}
+
+// noteListCmd represents the note list command
+var noteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the notes of a month",
+	Long: `List the day notes of a month, the current month by default.
+
+Examples:
+  timetracker note list
+  timetracker note list --month 2024-04
+  timetracker note list --month april`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		first, err := parseMonthFlag(noteMonth, time.Now())
+		if err != nil {
+			return err
+		}
+		from, to := dates.Format(first), dates.Format(first.AddDate(0, 1, -1))
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		found, err := openNotebook(client).Between(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes: %w", err)
+		}
+
+		display.Printf("\n📝 Notes for %s\n\n", first.Format("January 2006"))
+		if len(found) == 0 {
+			display.Println("No notes.")
+			display.Println()
+			return nil
+		}
+
+		days := make([]string, 0, len(found))
+		for date := range found {
+			days = append(days, date)
+		}
+		sort.Strings(days)
+
+		table := display.NewTable("Date", "Day", "Note")
+		for _, date := range days {
+			day, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+			table.AddRow(date, day.Format("Mon"), found[date])
+		}
+		table.Print()
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+	noteCmd.AddCommand(noteListCmd)
+	localdata.Register(notesFile)
+
+	noteCmd.Flags().BoolVar(&noteDelete, "delete", false, "Remove the day's note")
+	noteListCmd.Flags().StringVar(&noteMonth, "month", "", "Month to list (YYYY-MM or a month name; default: this month)")
+}
+
+// notebook reads and writes day notes on the server, or in the local notes
+// file when the server has no notes endpoint
+type notebook struct {
+	client *api.Client
+	local  *localdata.Store[string] // set once the server turned out not to support notes
+}
+
+// openNotebook returns a notebook that finds out on first use where notes
+// are kept
+func openNotebook(client *api.Client) *notebook {
+	return &notebook{client: client}
+}
+
+// Between returns the notes of the days from..to (inclusive), keyed by date
+func (n *notebook) Between(from, to string) (map[string]string, error) {
+	if n.local == nil {
+		notes, err := n.client.GetNotes(from, to)
+		if err == nil {
+			found := make(map[string]string, len(notes))
+			for _, note := range notes {
+				found[note.Date] = note.Text
+			}
+			return found, nil
+		}
+		if !isNotFound(err) {
+			return nil, err
+		}
+		if err := n.useLocal(); err != nil {
+			return nil, err
+		}
+	}
+
+	found := map[string]string{}
+	for _, date := range n.local.Keys() {
+		if date >= from && date <= to {
+			found[date], _ = n.local.Get(date)
+		}
+	}
+	return found, nil
+}
+
+// Set stores the note for a day
+func (n *notebook) Set(date, text string) error {
+	if n.local == nil {
+		err := n.client.SetNote(date, text)
+		if !isNotFound(err) {
+			return err
+		}
+		if err := n.useLocal(); err != nil {
+			return err
+		}
+	}
+	n.local.Set(date, text)
+	return n.local.Save()
+}
+
+// Delete removes the note for a day
+func (n *notebook) Delete(date string) error {
+	if n.local == nil {
+		// A 404 may mean either no endpoint or no note, so ask the list
+		if _, err := n.Between(date, date); err != nil {
+			return err
+		}
+	}
+	if n.local == nil {
+		err := n.client.DeleteNote(date)
+		if isNotFound(err) {
+			return fmt.Errorf("no note for %s", date)
+		}
+		return err
+	}
+
+	if _, ok := n.local.Get(date); !ok {
+		return fmt.Errorf("no note for %s", date)
+	}
+	n.local.Delete(date)
+	return n.local.Save()
+}
+
+func (n *notebook) useLocal() error {
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	n.local, err = localdata.Open[string](filepath.Join(dir, notesFile))
+	return err
+}
+
+// dayNotes returns the notes of the days from..to for display next to
+// summaries. Failures are reported as a warning, since notes are secondary.
+func dayNotes(client *api.Client, from, to string) map[string]string {
+	notes, err := openNotebook(client).Between(from, to)
+	if _, offline := client.OfflineSince(); err != nil && offline {
+		// The offline banner already says the server is out of reach
+		return nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not load day notes: %v\n", err)
+		return nil
+	}
+	return notes
+}
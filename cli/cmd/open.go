@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/browser"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var openDate string
+
+// dashboardViews are the dashboard pages `open` can jump to
+var dashboardViews = []string{"today", "week", "month", "estimates", "utilities", "settings"}
+
+// openCmd represents the open command
+var openCmd = &cobra.Command{
+	Use:   "open [today|week|month|estimates|utilities|settings]",
+	Short: "Open the web dashboard in your browser",
+	Long: `Open the TimeTracker web dashboard in the default browser.
+
+Without arguments the dashboard's default view is opened. A view name or
+--date jumps straight to that page or day. When no browser can be launched
+(for example over SSH), the URL is printed instead.
+
+The dashboard is expected at http://localhost:5173, where the frontend's
+development server runs; set dashboard_url in the config file if it is
+served elsewhere (e.g. http://localhost:8088 for the Docker setup).`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: dashboardViews,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		base := cfg.DashboardURL
+
+		view := ""
+		if len(args) == 1 {
+			view = args[0]
+		}
+
+		firstDay, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+		target, err := dashboardURL(base, view, openDate, time.Now(), firstDay)
+		if err != nil {
+			return err
+		}
+
+		if err := browser.Default().Open(target); err != nil {
+			if !errors.Is(err, browser.ErrNoBrowser) {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+			fmt.Printf("Open this URL in your browser:\n  %s\n", target)
+			return nil
+		}
+
+		display.Printf("🌐 Opened %s\n", target)
+		return nil
+	},
+}
+
+// dashboardURL maps a view and/or date onto the query parameters the
+// dashboard reads on load (view, from and to). The week view begins on
+// firstDay.
+func dashboardURL(base, view, date string, now time.Time, firstDay time.Weekday) (string, error) {
+	u, err := url.Parse(strings.TrimRight(base, "/") + "/")
+	if err != nil {
+		return "", fmt.Errorf("invalid dashboard URL %q: %w", base, err)
+	}
+
+	query := url.Values{}
+	setRange := func(from, to time.Time) {
+		query.Set("view", "dashboard")
+		query.Set("from", dates.Format(from))
+		query.Set("to", dates.Format(to))
+	}
+
+	today := dates.StartOfDay(now)
+	switch view {
+	case "":
+	case "today":
+		setRange(today, today)
+	case "week":
+		start := dates.StartOfWeek(today, firstDay)
+		setRange(start, start.AddDate(0, 0, 6))
+	case "month":
+		start := today.AddDate(0, 0, 1-today.Day())
+		setRange(start, start.AddDate(0, 1, -1))
+	case "estimates", "utilities", "settings":
+		query.Set("view", view)
+	default:
+		return "", fmt.Errorf("unknown view %q (expected one of: %s)", view, strings.Join(dashboardViews, ", "))
+	}
+
+	if date != "" {
+		day, err := dates.Parse(date, now)
+		if err != nil {
+			return "", err
+		}
+		setRange(day, day)
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().StringVar(&openDate, "date", "", "Open the dashboard on a specific day (YYYY-MM-DD)")
+	addWeekStartFlag(openCmd)
+}
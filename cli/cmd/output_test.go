@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// tableOnlyCommands print tables meant only for people, such as previews of
+// changes and reports of what a run did, which csv and tsv have no use for
+var tableOnlyCommands = map[string]string{
+	"batchCmd":       "reports the outcome of each operation",
+	"continueCmd":    "lists recent entries to pick from",
+	"copyCmd":        "previews the copies before creating them",
+	"doctorCmd":      "renders its checks for people",
+	"importTogglCmd": "reports the import",
+	"importTempoCmd": "reports the import",
+	"purgeCmd":       "previews the entries before deleting them",
+	"roundCmd":       "previews the changes before applying them",
+	"splitCmd":       "previews the parts before creating them",
+	"submitCmd":      "previews the timesheet before submitting it",
+	"syncCmd":        "reports the sync it runs",
+	"syncUndoCmd":    "previews the entries before removing them",
+}
+
+// TestTableCommandsSupportDelimitedOutput finds the commands whose RunE can
+// reach display.NewTable and checks that they accept --output csv and tsv
+func TestTableCommandsSupportDelimitedOutput(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := map[string][]string{} // function or command → functions it calls
+	tables := map[string]bool{}    // functions and commands creating a table
+	delimited := map[string]bool{} // commands annotated with csv and tsv
+	var commands []string
+
+	inspect := func(name string, body ast.Node) {
+		ast.Inspect(body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch fn := call.Fun.(type) {
+			case *ast.Ident:
+				calls[name] = append(calls[name], fn.Name)
+			case *ast.SelectorExpr:
+				if pkg, ok := fn.X.(*ast.Ident); ok && pkg.Name == "display" && fn.Sel.Name == "NewTable" {
+					tables[name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for _, file := range pkgs["cmd"].Files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Body != nil {
+					inspect(decl.Name.Name, decl.Body)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					value, ok := spec.(*ast.ValueSpec)
+					if !ok || len(value.Values) != 1 {
+						continue
+					}
+					literal := commandLiteral(value.Values[0])
+					if literal == nil {
+						continue
+					}
+					name := value.Names[0].Name
+					commands = append(commands, name)
+					for _, elt := range literal.Elts {
+						field, ok := elt.(*ast.KeyValueExpr)
+						if !ok {
+							continue
+						}
+						switch key := field.Key.(*ast.Ident); key.Name {
+						case "RunE", "Run":
+							inspect(name, field.Value)
+						case "Annotations":
+							delimited[name] = mentions(field.Value, "FormatCSV") && mentions(field.Value, "FormatTSV")
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var reaches func(name string, seen map[string]bool) bool
+	reaches = func(name string, seen map[string]bool) bool {
+		if tables[name] {
+			return true
+		}
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+		for _, callee := range calls[name] {
+			if reaches(callee, seen) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range commands {
+		_, exempt := tableOnlyCommands[name]
+		table := reaches(name, map[string]bool{})
+		if exempt && !table {
+			t.Errorf("%s is listed in tableOnlyCommands but prints no table", name)
+		}
+		if table && !exempt && !delimited[name] {
+			t.Errorf("%s prints a table but doesn't accept --output csv and tsv; add them with outputFormats or list it in tableOnlyCommands", name)
+		}
+	}
+}
+
+// commandLiteral returns the cobra.Command literal expr takes the address
+// of, if it does
+func commandLiteral(expr ast.Expr) *ast.CompositeLit {
+	unary, ok := expr.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return nil
+	}
+	literal, ok := unary.X.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	if typ, ok := literal.Type.(*ast.SelectorExpr); !ok || typ.Sel.Name != "Command" {
+		return nil
+	}
+	return literal
+}
+
+// mentions reports whether node refers to an identifier called name
+func mentions(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/pending"
+)
+
+// entryChanges creates, updates and deletes entries, and records what each
+// change does to its date's total as pending until the summaries show it.
+// The summaries are read once, before the first change; record then saves
+// one delta per date.
+type entryChanges struct {
+	client    *api.Client
+	baselines map[string]map[string]float64 // date → view → total, nil until read
+	hours     map[string]float64
+}
+
+func newEntryChanges(client *api.Client) *entryChanges {
+	return &entryChanges{client: client, hours: map[string]float64{}}
+}
+
+// create creates an entry and records its hours
+func (c *entryChanges) create(request api.CreateEntryRequest) (*api.Entry, error) {
+	c.readBaselines()
+	entry, err := c.client.CreateEntry(request)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := entry.Duration
+	if hours <= 0 {
+		hours, _ = clockDuration(request.StartTime, request.EndTime)
+	}
+	c.hours[request.Date] += hours
+	return entry, nil
+}
+
+// update changes entry and records the difference, on both dates if the
+// entry moved
+func (c *entryChanges) update(entry api.Entry, request api.UpdateEntryRequest) (*api.Entry, error) {
+	c.readBaselines()
+	updated, err := c.client.UpdateEntry(entry.ID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := updated.Duration
+	if hours <= 0 {
+		hours = request.Duration
+		if request.StartTime != "" && request.EndTime != "" {
+			hours, _ = clockDuration(request.StartTime, request.EndTime)
+		}
+	}
+	date := request.Date
+	if date == "" {
+		date = entry.Day()
+	}
+	c.hours[entry.Day()] -= entry.Duration
+	c.hours[date] += hours
+	return updated, nil
+}
+
+// delete deletes entry and records its hours as removed
+func (c *entryChanges) delete(entry api.Entry) error {
+	c.readBaselines()
+	if err := c.client.DeleteEntry(entry.ID); err != nil {
+		return err
+	}
+	c.hours[entry.Day()] -= entry.Duration
+	return nil
+}
+
+// readBaselines captures what each summary view reports before the first
+// change, so later reads can tell whether the server reflects the changes.
+// Views that fail to load are left out.
+func (c *entryChanges) readBaselines() {
+	if c.baselines != nil {
+		return
+	}
+	c.baselines = map[string]map[string]float64{}
+	set := func(date, view string, hours float64) {
+		if c.baselines[date] == nil {
+			c.baselines[date] = map[string]float64{}
+		}
+		c.baselines[date][view] = hours
+	}
+
+	var today api.TodaySummaryResponse
+	if err := c.client.Get("/api/entries/summary/today", &today); err == nil {
+		set(today.Date, pending.ViewToday, today.TotalHours)
+	}
+
+	var week api.WeekSummaryResponse
+	if err := c.client.Get("/api/entries/summary/week", &week); err == nil {
+		for _, day := range week.Daily {
+			set(day.Date, pending.ViewWeek, day.Hours)
+		}
+	}
+}
+
+// record stores the changes made so far as pending. Dates no summary covers
+// are left out. Failures are ignored: the annotation is a convenience and
+// must never fail the changes themselves.
+func (c *entryChanges) record() {
+	if len(c.hours) == 0 {
+		return
+	}
+	store := loadPending()
+	if store == nil {
+		return
+	}
+
+	now := time.Now()
+	for date, hours := range c.hours {
+		store.Add(pending.Delta{
+			Date:      date,
+			Hours:     hours,
+			Baselines: c.baselines[date],
+			CreatedAt: now,
+		})
+	}
+	store.Save()
+	c.hours = map[string]float64{}
+}
+
+// reconcilePending returns the hours a view is still missing for each date,
+// given the totals the server reported. Dates with nothing pending are omitted.
+func reconcilePending(view string, observed map[string]float64) map[string]float64 {
+	result := map[string]float64{}
+
+	store := loadPending()
+	if store == nil {
+		return result
+	}
+
+	now := time.Now()
+	for date, hours := range observed {
+		if pendingHours := store.Reconcile(view, date, hours, now); pendingHours != 0 {
+			result[date] = pendingHours
+		}
+	}
+	store.Save()
+
+	return result
+}
+
+// loadPending opens the pending store, returning nil if it is unavailable
+func loadPending() *pending.Store {
+	path, err := pending.DefaultPath()
+	if err != nil {
+		return nil
+	}
+
+	store, err := pending.Load(path)
+	if err != nil {
+		return nil
+	}
+
+	return store
+}
+
+// formatPending renders a pending adjustment, e.g. "+0.75h pending server refresh"
+func formatPending(hours float64) string {
+	return fmt.Sprintf("%s pending server refresh", display.FormatSignedDuration(hours))
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/batch"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/pending"
+)
+
+// pendingToday reconciles both views against what the server reports for today
+func pendingToday(t *testing.T, server *fakeServer) (today, week float64) {
+	t.Helper()
+	date := dates.Format(time.Now())
+	observed := map[string]float64{date: server.summary(date)}
+	return reconcilePending(pending.ViewToday, observed)[date], reconcilePending(pending.ViewWeek, observed)[date]
+}
+
+func TestBatchRecordsPendingChanges(t *testing.T) {
+	today := dates.Format(time.Now())
+	server := newFakeServer(t,
+		api.Entry{ID: "review", Source: "MANUAL", Date: today, StartTime: "09:00", EndTime: "11:00", Duration: 2, Project: "ACME"},
+		api.Entry{ID: "standup", Source: "MANUAL", Date: today, StartTime: "11:00", EndTime: "11:30", Duration: 0.5, Project: "ACME"},
+	)
+	server.lag()
+
+	results := runBatch(server.client(), []batch.Operation{
+		{Op: batch.Add, Date: today, Start: "13:00", End: "14:00", Project: "ACME"},
+		{Op: batch.Edit, ID: "review", Duration: "3"},
+		{Op: batch.Delete, ID: "standup"},
+	}, true)
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatalf("%s: %v", result.Op.Op, result.Err)
+		}
+	}
+
+	// +1h added, +1h edited, -0.5h deleted, none of it in the summaries yet
+	if todayPending, weekPending := pendingToday(t, server); math.Abs(todayPending-1.5) > 0.001 || math.Abs(weekPending-1.5) > 0.001 {
+		t.Errorf("pending = %.2fh today, %.2fh week; want 1.5h", todayPending, weekPending)
+	}
+
+	server.catchUp()
+	if todayPending, weekPending := pendingToday(t, server); todayPending != 0 || weekPending != 0 {
+		t.Errorf("pending after the summaries caught up = %.2fh today, %.2fh week", todayPending, weekPending)
+	}
+}
+
+func TestDeletesRecordNegativePending(t *testing.T) {
+	today := dates.Format(time.Now())
+	entries := []api.Entry{
+		{ID: "a", Source: "MANUAL", Date: today, StartTime: "09:00", EndTime: "10:00", Duration: 1},
+		{ID: "b", Source: "MANUAL", Date: today, StartTime: "10:00", EndTime: "12:00", Duration: 2},
+	}
+	server := newFakeServer(t, entries...)
+	server.lag()
+
+	// As purge and archive delete
+	changes := newEntryChanges(server.client())
+	for _, entry := range entries {
+		if err := changes.delete(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changes.record()
+
+	if todayPending, _ := pendingToday(t, server); math.Abs(todayPending+3) > 0.001 {
+		t.Errorf("pending = %.2fh, want -3h", todayPending)
+	}
+}
+
+func TestSplitRollbackLeavesNothingPending(t *testing.T) {
+	today := dates.Format(time.Now())
+	entry := api.Entry{ID: "long", Source: "MANUAL", Date: today, StartTime: "09:00", EndTime: "12:00", Duration: 3}
+	server := newFakeServer(t, entry)
+	server.lag()
+
+	// The second part runs past midnight, so the first part is rolled back
+	parts := []splitPart{
+		{Project: "ACME", Start: "09:00", End: "10:00", Minutes: 60},
+		{Project: "Internal", Start: "10:00", End: "09:00", Minutes: 60},
+	}
+	if err := applySplit(server.client(), &entry, parts, 60); err == nil {
+		t.Fatal("applySplit succeeded, want a failure")
+	}
+
+	if todayPending, weekPending := pendingToday(t, server); todayPending != 0 || weekPending != 0 {
+		t.Errorf("pending after rollback = %.2fh today, %.2fh week", todayPending, weekPending)
+	}
+}
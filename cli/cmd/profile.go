@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	profileAPIURL string
+	profileUse    bool
+	profileYes    bool
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles for several servers or accounts",
+	Long: `Keep several servers or accounts side by side. Each profile has its own
+API URL and login; settings in a profile's section of the config file
+override the top-level ones. The top-level settings are the "default"
+profile.
+
+Examples:
+  timetracker profile list
+  timetracker profile create work --api-url https://tt.company.com --use
+  timetracker login
+  timetracker profile use default
+  timetracker profile delete work`,
+}
+
+// profileListCmd represents the profile list command
+var profileListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List profiles",
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := config.Profiles()
+		if err != nil {
+			return err
+		}
+
+		table := display.NewTable("", "Profile", "API URL", "Logged In")
+		for _, name := range names {
+			values, err := config.ProfileValues(name)
+			if err != nil {
+				return err
+			}
+			marker := ""
+			if name == config.ActiveProfile() {
+				marker = "*"
+			}
+			apiURL, _ := values["api_url"].(string)
+			if apiURL == "" {
+				apiURL = "http://localhost:3000"
+			}
+			loggedIn := "no"
+			if token, _ := values["refresh_token"].(string); token != "" {
+				loggedIn = "yes"
+			} else if token, _ := values["access_token"].(string); token != "" {
+				loggedIn = "yes"
+			}
+			if mode, _ := values["auth_mode"].(string); loggedIn == "yes" && mode == config.AuthModeToken {
+				loggedIn = "yes (token)"
+			}
+			table.AddRow(marker, name, apiURL, loggedIn)
+		}
+
+		display.Println()
+		table.Print()
+		display.Println()
+		return nil
+	},
+}
+
+// profileCreateCmd represents the profile create command
+var profileCreateCmd = &cobra.Command{
+	Use:          "create <name>",
+	Short:        "Add a profile for a server",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		parsed, err := url.Parse(profileAPIURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid --api-url %q (expected e.g. https://tt.company.com)", profileAPIURL)
+		}
+
+		if err := config.CreateProfile(name, profileAPIURL); err != nil {
+			return err
+		}
+		display.Printf("✓ Created profile %s (%s)\n", name, profileAPIURL)
+
+		if profileUse {
+			if err := config.UseProfile(name); err != nil {
+				return err
+			}
+			display.Printf("✓ Now using %s. Run 'timetracker login' to sign in.\n", name)
+		} else {
+			display.Printf("Switch to it with 'timetracker profile use %s', then run 'timetracker login'.\n", name)
+		}
+		return nil
+	},
+}
+
+// profileUseCmd represents the profile use command
+var profileUseCmd = &cobra.Command{
+	Use:          "use <name>",
+	Short:        "Switch the active profile",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.UseProfile(args[0]); err != nil {
+			return err
+		}
+		display.Printf("✓ Now using profile %s\n", args[0])
+		return nil
+	},
+}
+
+// profileDeleteCmd represents the profile delete command
+var profileDeleteCmd = &cobra.Command{
+	Use:          "delete <name>",
+	Short:        "Delete a profile and its login",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		wasActive := name == config.ActiveProfile()
+		if wasActive && !profileYes {
+			if !confirm(fmt.Sprintf("%s is the active profile. Delete it and switch to the default profile? [y/N]: ", name)) {
+				display.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		if err := config.DeleteProfile(name); err != nil {
+			return err
+		}
+		display.Printf("✓ Deleted profile %s\n", name)
+		if wasActive {
+			display.Printf("Now using profile %s\n", config.DefaultProfile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+
+	profileCreateCmd.Flags().StringVar(&profileAPIURL, "api-url", "", "API base URL of the profile's server")
+	profileCreateCmd.Flags().BoolVar(&profileUse, "use", false, "Switch to the new profile")
+	profileCreateCmd.MarkFlagRequired("api-url")
+	profileDeleteCmd.Flags().BoolVarP(&profileYes, "yes", "y", false, "Delete the active profile without asking for confirmation")
+}
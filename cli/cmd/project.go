@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+const (
+	// projectDefaultWeeks is how many weeks the report covers by default
+	projectDefaultWeeks = 12
+
+	// projectTrendWidth is the width of the longest bar in the weekly trend
+	projectTrendWidth = 30
+)
+
+var (
+	projectFrom string
+	projectTo   string
+	projectTop  int
+)
+
+// projectReport is everything the project command shows, also its --json
+// output
+type projectReport struct {
+	Project       string               `json:"project"`
+	From          string               `json:"from"`
+	To            string               `json:"to"`
+	TotalHours    float64              `json:"totalHours"`
+	Entries       int                  `json:"entries"`
+	FirstActivity string               `json:"firstActivity,omitempty"`
+	LastActivity  string               `json:"lastActivity,omitempty"`
+	Weeks         []projectWeek        `json:"weeks"`
+	Descriptions  []projectDescription `json:"descriptions"`
+	// Billable figures are only reported when the project's sources track
+	// billability
+	BillableHours *float64 `json:"billableHours,omitempty"`
+	BillableShare *float64 `json:"billableShare,omitempty"`
+}
+
+// projectWeek is one week of the trend
+type projectWeek struct {
+	Start string  `json:"start"`
+	Hours float64 `json:"hours"`
+}
+
+// projectDescription is the time spent under one description
+type projectDescription struct {
+	Description string  `json:"description"`
+	Hours       float64 `json:"hours"`
+	Entries     int     `json:"entries"`
+}
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project <name>",
+	Short: "Show a deep-dive into one project",
+	Long: `Show one project's total hours, weekly trend, top descriptions by time,
+first and last activity and billable share for a date range. Defaults to
+the last 12 weeks.
+
+The name may be an alias and is matched case-insensitively; if it is part of
+several project names you pick one (or, when not in a terminal, the
+candidates are listed).
+
+Examples:
+  timetracker project WEKA
+  timetracker project weka --from 2024-01-01 --to 2024-03-31
+  timetracker project retainer --json | jq .totalHours`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	Annotations:  outputFormats(display.FormatJSON, display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseDateFlag(projectFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(projectTo)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		start, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+		if from == "" {
+			from = dates.Format(dates.StartOfWeek(now, start).AddDate(0, 0, -7*(projectDefaultWeeks-1)))
+		}
+		if to == "" {
+			to = dates.Format(now)
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		project, err := matchProject(resolveProject(args[0]), entries)
+		if err != nil {
+			return err
+		}
+
+		var matching []api.Entry
+		for _, entry := range entries {
+			if entry.Project == project {
+				matching = append(matching, entry)
+			}
+		}
+
+		report := buildProjectReport(project, from, to, matching, start, billableDefault(cmd))
+
+		if display.JSON() {
+			return display.Result(report)
+		}
+		printProjectReport(report)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+
+	projectCmd.Flags().StringVar(&projectFrom, "from", "", "Start date (YYYY-MM-DD, today, yesterday; default: 12 weeks ago)")
+	projectCmd.Flags().StringVar(&projectTo, "to", "", "End date (YYYY-MM-DD, today, yesterday; default: today)")
+	projectCmd.Flags().IntVar(&projectTop, "top", 5, "Number of descriptions to list")
+}
+
+// matchProject finds the project name meant by name among the entries'
+// projects: an exact case-insensitive match, else the only project
+// containing name, else the one picked from a prompt
+func matchProject(name string, entries []api.Entry) (string, error) {
+	seen := map[string]bool{}
+	var candidates []string
+	needle := strings.ToLower(name)
+	for _, entry := range entries {
+		if seen[entry.Project] || entry.Project == "" {
+			continue
+		}
+		seen[entry.Project] = true
+		if strings.EqualFold(entry.Project, name) {
+			return entry.Project, nil
+		}
+		if strings.Contains(strings.ToLower(entry.Project), needle) {
+			candidates = append(candidates, entry.Project)
+		}
+	}
+	sort.Strings(candidates)
+
+	switch {
+	case len(candidates) == 0:
+		return "", fmt.Errorf("no project matching %q has entries in this range", name)
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case !term.IsTerminal(int(os.Stdin.Fd())):
+		return "", fmt.Errorf("%q matches several projects: %s", name, strings.Join(candidates, ", "))
+	}
+
+	// Prompt on stderr so --json output stays clean
+	fmt.Fprintf(os.Stderr, "%q matches several projects:\n", name)
+	for i, candidate := range candidates {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, candidate)
+	}
+	fmt.Fprintf(os.Stderr, "Which one? [1-%d]: ", len(candidates))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	choice, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return "", fmt.Errorf("no project selected")
+	}
+	return candidates[choice-1], nil
+}
+
+// buildProjectReport aggregates one project's entries
+func buildProjectReport(project, from, to string, entries []api.Entry, start time.Weekday, billableFallback bool) projectReport {
+	report := projectReport{Project: project, From: from, To: to, Entries: len(entries)}
+
+	weekly := map[string]float64{}
+	descriptions := map[string]*projectDescription{}
+	var billable billableHours
+	tracked := false
+	for _, entry := range entries {
+		day := entry.Day()
+		report.TotalHours += entry.Duration
+		if report.FirstActivity == "" || day < report.FirstActivity {
+			report.FirstActivity = day
+		}
+		if day > report.LastActivity {
+			report.LastActivity = day
+		}
+
+		if t, err := time.ParseInLocation("2006-01-02", day, time.Local); err == nil {
+			weekly[dates.Format(dates.StartOfWeek(t, start))] += entry.Duration
+		}
+
+		description := strings.TrimSpace(entry.Description)
+		if description == "" {
+			description = "(no description)"
+		}
+		if descriptions[description] == nil {
+			descriptions[description] = &projectDescription{Description: description}
+		}
+		descriptions[description].Hours += entry.Duration
+		descriptions[description].Entries++
+
+		billable.add(entry, billableFallback)
+		tracked = tracked || entry.Billable != nil
+	}
+
+	// Every week of the range appears in the trend, even without hours
+	first, _ := time.ParseInLocation("2006-01-02", from, time.Local)
+	last, _ := time.ParseInLocation("2006-01-02", to, time.Local)
+	for week := dates.StartOfWeek(first, start); !week.After(last); week = week.AddDate(0, 0, 7) {
+		key := dates.Format(week)
+		report.Weeks = append(report.Weeks, projectWeek{Start: key, Hours: weekly[key]})
+	}
+
+	report.Descriptions = make([]projectDescription, 0, len(descriptions))
+	for _, description := range descriptions {
+		report.Descriptions = append(report.Descriptions, *description)
+	}
+	sort.Slice(report.Descriptions, func(i, j int) bool {
+		if report.Descriptions[i].Hours != report.Descriptions[j].Hours {
+			return report.Descriptions[i].Hours > report.Descriptions[j].Hours
+		}
+		return report.Descriptions[i].Description < report.Descriptions[j].Description
+	})
+
+	if tracked {
+		share := percent(billable.Billable, billable.Total())
+		report.BillableHours, report.BillableShare = &billable.Billable, &share
+	}
+
+	return report
+}
+
+// printProjectReport shows the report as text
+func printProjectReport(report projectReport) {
+	display.Printf("\n📁 %s: %s to %s\n\n", report.Project, report.From, report.To)
+	display.Printf("  Total:          %s in %d entries\n", display.FormatDuration(report.TotalHours), report.Entries)
+	if report.Entries > 0 {
+		display.Printf("  First activity: %s\n", report.FirstActivity)
+		display.Printf("  Last activity:  %s\n", report.LastActivity)
+	}
+	if report.BillableHours != nil {
+		display.Printf("  Billable:       %s (%.0f%%)\n", display.FormatDuration(*report.BillableHours), *report.BillableShare)
+	}
+
+	var max float64
+	for _, week := range report.Weeks {
+		if week.Hours > max {
+			max = week.Hours
+		}
+	}
+	display.Println("\n📈 Weekly trend:")
+	display.Println()
+	for _, week := range report.Weeks {
+		bar := ""
+		if max > 0 {
+			bar = strings.Repeat("█", int(week.Hours/max*projectTrendWidth+0.5))
+		}
+		display.Println(strings.TrimRight(fmt.Sprintf("  %s  %6s  %s", week.Start, display.FormatHours(week.Hours), bar), " "))
+	}
+
+	if len(report.Descriptions) > 0 {
+		display.Println("\n📝 Top descriptions:")
+		display.Println()
+		table := display.NewTable("Description", "Hours", "Entries", "Share")
+		for i, description := range report.Descriptions {
+			if i == projectTop {
+				break
+			}
+			table.AddRow(description.Description,
+				display.FormatHours(description.Hours),
+				strconv.Itoa(description.Entries),
+				fmt.Sprintf("%.0f%%", percent(description.Hours, report.TotalHours)))
+		}
+		table.Print()
+		if len(report.Descriptions) > projectTop {
+			display.Printf("... and %d more\n", len(report.Descriptions)-projectTop)
+		}
+	}
+	display.Println()
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+const (
+	// purgeBatchSize is how many deletions are reported as one progress step
+	purgeBatchSize = 25
+
+	// entryListLimit is how many entries are listed before deleting them
+	entryListLimit = 20
+)
+
+var (
+	purgeFrom    string
+	purgeTo      string
+	purgeSource  string
+	purgeProject string
+	purgeYes     bool
+)
+
+// purgeCmd represents the purge command
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete all entries in a date range",
+	Long: `Delete every entry in a date range, optionally only those of one source
+or project. The matching entries are listed first, and you confirm by typing
+how many there are (or pass --yes in scripts).
+
+Entries that are locked because their week was submitted are skipped.
+
+Examples:
+  timetracker purge --from 2024-03-01 --to 2024-03-01 --source manual
+  timetracker purge --from 2024-03-01 --to 2024-03-31 --project TEST
+  timetracker purge --from 2024-03-01 --to 2024-03-31 --source toggl --yes`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseDateFlag(purgeFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(purgeTo)
+		if err != nil {
+			return err
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		project := ""
+		if purgeProject != "" {
+			project = resolveProject(purgeProject)
+		}
+
+		var matches, locked []api.Entry
+		var hours float64
+		for _, entry := range entries {
+			if purgeSource != "" && !strings.EqualFold(entry.Source, purgeSource) {
+				continue
+			}
+			if project != "" && !strings.EqualFold(entry.Project, project) {
+				continue
+			}
+			if entry.Locked {
+				locked = append(locked, entry)
+				continue
+			}
+			matches = append(matches, entry)
+			hours += entry.Duration
+		}
+
+		display.Printf("\n🗑️  %d entries (%s) from %s to %s match\n", len(matches), display.FormatDuration(hours), from, to)
+		if len(locked) > 0 {
+			display.Printf("🔒 %d locked entries will be skipped\n", len(locked))
+		}
+		if len(matches) == 0 {
+			display.Println()
+			return nil
+		}
+
+		display.Println()
+		printEntryList(matches)
+		display.Println()
+
+		if !purgeYes {
+			fmt.Printf("This can't be undone. Type %d to delete them: ", len(matches))
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(answer) != strconv.Itoa(len(matches)) {
+				display.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		changes := newEntryChanges(client)
+		defer changes.record()
+
+		showProgress := display.AnimationEnabled(os.Stderr)
+		var deleted int
+		var failed []string
+		for start := 0; start < len(matches); start += purgeBatchSize {
+			end := start + purgeBatchSize
+			if end > len(matches) {
+				end = len(matches)
+			}
+			for _, entry := range matches[start:end] {
+				if err := changes.delete(entry); err != nil {
+					failed = append(failed, fmt.Sprintf("%s (%s): %v", entry.ID, entry.Day(), err))
+					continue
+				}
+				deleted++
+			}
+			if showProgress {
+				fmt.Fprintf(os.Stderr, "\r%s", display.ProgressBar(end, len(matches)))
+			}
+		}
+		if showProgress {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+
+		display.Printf("✓ Deleted %d entries", deleted)
+		if len(locked) > 0 {
+			display.Printf(", skipped %d locked", len(locked))
+		}
+		if len(failed) > 0 {
+			display.Printf(", %d failed", len(failed))
+		}
+		display.Println()
+
+		for _, entry := range locked {
+			display.Printf("  🔒 %s %s %s (locked)\n", entry.ID, entry.Day(), projectLabel(entry.Project))
+		}
+		for _, failure := range failed {
+			display.Printf("  ✗ %s\n", failure)
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d deletions failed", len(failed), len(matches))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().StringVar(&purgeFrom, "from", "", "First day to purge (YYYY-MM-DD, today, yesterday)")
+	purgeCmd.Flags().StringVar(&purgeTo, "to", "", "Last day to purge (YYYY-MM-DD, today, yesterday)")
+	purgeCmd.Flags().StringVar(&purgeSource, "source", "", "Only purge entries of this source (toggl, tempo, manual)")
+	purgeCmd.Flags().StringVarP(&purgeProject, "project", "p", "", "Only purge entries of this project or alias")
+	purgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "Delete without asking for confirmation")
+
+	purgeCmd.MarkFlagRequired("from")
+	purgeCmd.MarkFlagRequired("to")
+}
+
+// printEntryList lists the first entries about to be deleted
+func printEntryList(entries []api.Entry) {
+	table := display.NewTable("ID", "Date", "Source", "Project", "Description", "Hours")
+	for i, entry := range entries {
+		if i == entryListLimit {
+			break
+		}
+		table.AddRow(entry.ID, entry.Day(), entry.Source, entry.Project, entry.Description, display.FormatHours(entry.Duration))
+	}
+	table.Print()
+
+	if len(entries) > entryListLimit {
+		display.Printf("... and %d more\n", len(entries)-entryListLimit)
+	}
+}
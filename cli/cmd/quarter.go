@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	quarterNumber int
+	quarterYear   int
+)
+
+// quarterCmd represents the quarter command
+var quarterCmd = &cobra.Command{
+	Use:   "quarter",
+	Short: "Show a quarterly time tracking summary",
+	Long: `Display a quarter's logged hours per month and per project, compared
+with the previous quarter. Defaults to the current quarter.
+
+Quarters follow the fiscal_year_start setting (default: january). Fiscal
+years are named after the calendar year they start in, so with
+fiscal_year_start: april, Q4 FY2023 is January to March 2024.
+
+Examples:
+  timetracker quarter
+  timetracker quarter --q 2
+  timetracker quarter --q 4 --year 2023`,
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fiscalStart, err := fiscalYearStart(cmd)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		quarter := dates.QuarterOf(now, fiscalStart)
+		if quarterYear != 0 {
+			quarter.Year = quarterYear
+		}
+		if quarterNumber != 0 {
+			if quarterNumber < 1 || quarterNumber > 4 {
+				return fmt.Errorf("--q must be between 1 and 4")
+			}
+			quarter.Number = quarterNumber
+		}
+
+		start, end := quarter.Start(time.Local), quarter.End(time.Local)
+		if start.After(now) {
+			return fmt.Errorf("%s has not started yet", quarter)
+		}
+		previous := quarter.Previous()
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		summary, err := client.SummarizeRange(start, end)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", quarter, err)
+		}
+		before, err := client.SummarizeRange(previous.Start(time.Local), previous.End(time.Local))
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", previous, err)
+		}
+
+		display.Printf("\n📅 %s (%s to %s)\n\n", quarter, dates.Format(start), dates.Format(end))
+
+		table := display.NewTable("Month", "Hours", "Entries")
+		for _, month := range summary.Monthly {
+			table.AddRow(monthName(month.Month), display.FormatHours(month.Hours), fmt.Sprint(month.EntryCount))
+		}
+		table.AddRow("Total", display.FormatHours(summary.TotalHours), fmt.Sprint(summary.EntryCount))
+		table.Print()
+
+		display.Printf("\n⏱️  Total Hours: %s\n", display.FormatHours(summary.TotalHours))
+		delta := summary.TotalHours - before.TotalHours
+		display.Printf("↔️  vs %s: %s (%s", previous, display.FormatDuration(before.TotalHours), formatDelta(delta))
+		if before.TotalHours > 0 {
+			display.Printf(", %+.1f%%", percent(delta, before.TotalHours))
+		}
+		display.Print(")")
+		if !end.Before(dates.StartOfDay(now)) {
+			display.Print(" — quarter in progress")
+		}
+		display.Print("\n\n")
+
+		printHourBreakdown("Project", summary.ByProject)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quarterCmd)
+
+	quarterCmd.Flags().IntVar(&quarterNumber, "q", 0, "Quarter number 1-4 (default: current quarter)")
+	quarterCmd.Flags().IntVar(&quarterYear, "year", 0, "Fiscal year (default: current fiscal year)")
+}
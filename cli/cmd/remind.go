@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/daemon"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/notify"
+	"github.com/vmiller/timetracker-cli/internal/remind"
+)
+
+var (
+	remindThreshold time.Duration
+	remindInterval  time.Duration
+	remindDaemon    bool
+)
+
+// remindCmd represents the remind command
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Remind you when no time has been logged for a while",
+	Long: `Check today's total every --interval and remind you when nothing new has
+been logged for longer than --threshold, e.g. after a meeting you forgot to
+track. Reminders are desktop notifications where available and a terminal
+bell otherwise, and repeat every --threshold until time is logged.
+
+Reminders are only sent during working time, set with work_hours (default
+09:00-17:00) and work_days (default mon-fri) in the config file. Failed
+checks are retried with increasing delays, so the reminder keeps running
+through network or server outages.
+
+It runs in the foreground until interrupted; --daemon starts it in the
+background with its output in ~/.timetracker/remind.log.
+
+Examples:
+  timetracker remind
+  timetracker remind --threshold 90m --interval 10m
+  timetracker remind --daemon`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if remindThreshold <= 0 {
+			return fmt.Errorf("--threshold must be positive")
+		}
+		if remindInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		schedule, err := workSchedule(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if remindDaemon {
+			return startRemindDaemon()
+		}
+
+		display.Printf("🔔 Reminding after %s without new time, %s-%s on %s. Press Ctrl+C to stop.\n",
+			formatIdle(remindThreshold), formatClock(int(schedule.Start.Minutes())), formatClock(int(schedule.End.Minutes())), workDaysLabel(schedule))
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(stop)
+
+		watcher := &remind.Watcher{Threshold: remindThreshold, Schedule: schedule}
+		notifier := notify.Default()
+		failures := 0
+		for {
+			wait := remindInterval
+
+			var summary api.TodaySummaryResponse
+			err := client.Get("/api/entries/summary/today", &summary)
+			switch {
+			case errors.Is(err, api.ErrNotFound):
+				return fmt.Errorf("the server does not provide today's summary")
+			case err != nil:
+				failures++
+				wait = remind.Backoff(failures)
+				display.Printf("⚠️  [%s] Check failed, retrying in %s: %v\n", time.Now().Format("15:04"), wait, err)
+			default:
+				failures = 0
+				if ok, idle := watcher.Observe(time.Now(), summary.TotalHours); ok {
+					sendReminder(notifier, idle, summary.TotalHours)
+				}
+			}
+
+			select {
+			case <-stop:
+				display.Println("\nStopped.")
+				return nil
+			case <-time.After(wait):
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+
+	remindCmd.Flags().DurationVar(&remindThreshold, "threshold", 2*time.Hour, "Remind after this long without new time")
+	remindCmd.Flags().DurationVar(&remindInterval, "interval", 15*time.Minute, "How often to check today's total")
+	remindCmd.Flags().BoolVar(&remindDaemon, "daemon", false, "Run in the background")
+}
+
+// sendReminder shows a desktop notification, falling back to the terminal
+// bell, and logs the reminder
+func sendReminder(notifier notify.Notifier, idle time.Duration, total float64) {
+	message := fmt.Sprintf("Nothing logged for %s (%s today). What have you been working on?", formatIdle(idle), display.FormatDuration(total))
+
+	bell := ""
+	if err := notifier.Notify("TimeTracker", message); err != nil {
+		bell = "\a"
+	}
+	display.Printf("%s🔔 [%s] %s\n", bell, time.Now().Format("15:04"), message)
+}
+
+// startRemindDaemon re-runs the command in the background without --daemon
+func startRemindDaemon() error {
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	logPath := filepath.Join(dir, "remind.log")
+
+	var args []string
+	for _, arg := range os.Args[1:] {
+		if arg != "--daemon" && !strings.HasPrefix(arg, "--daemon=") {
+			args = append(args, arg)
+		}
+	}
+
+	pid, err := daemon.Start(args, logPath)
+	if err != nil {
+		return err
+	}
+
+	display.Printf("🔔 Reminder running in the background (PID %d)\n", pid)
+	display.Printf("   Log: %s\n", logPath)
+	display.Printf("   Stop it with: kill %d\n", pid)
+	return nil
+}
+
+// formatIdle renders a duration like "2h05m" or "45m"
+func formatIdle(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// workDaysLabel lists the working days, e.g. "Mon Tue Wed Thu Fri"
+func workDaysLabel(schedule remind.Schedule) string {
+	var days []string
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		if schedule.Days[day] {
+			days = append(days, day.String()[:3])
+		}
+	}
+	return strings.Join(days, " ")
+}
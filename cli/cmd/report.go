@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	shareWeek    string
+	shareFrom    string
+	shareTo      string
+	shareExpires string
+	shareLabel   string
+	shareQR      bool
+	shareSources []string
+	shareClient  string
+
+	shareBillable    bool
+	shareNonBillable bool
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Share reports with others",
+	Long: `Share reports with people who don't use the CLI.
+
+Examples:
+  timetracker report share --week 2024-W15 --expires 7d
+  timetracker report share list
+  timetracker report share revoke <id>`,
+}
+
+// reportShareCmd represents the report share command
+var reportShareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Create a read-only link to a report",
+	Long: `Ask the server for a signed, read-only link to the report of a week or
+date range, and print it. Anyone with the link can view the report until it
+expires or is revoked; they can't change anything.
+
+--expires takes days ("7d"), weeks ("2w") or hours ("12h"). The server may
+cap how long links live, and administrators can turn sharing off.
+
+With --source, the report only counts entries of that source (toggl, tempo
+or manual); repeat it for several sources. --client limits it to a client's
+entries, and --billable or --non-billable to billable or non-billable ones.
+The server applies these filters; if it doesn't support one, the link is
+revoked again rather than left sharing everything.
+
+Examples:
+  timetracker report share                         # this week, for 7 days
+  timetracker report share --week 2024-W15 --expires 7d
+  timetracker report share --source tempo
+  timetracker report share --client Acme --billable --expires 30d
+  timetracker report share --from 2024-04-01 --to 2024-04-30 --label "April" --qr`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+		from, to, err := shareRange(start)
+		if err != nil {
+			return err
+		}
+		lifetime, err := parseExpiry(shareExpires)
+		if err != nil {
+			return err
+		}
+		sources, err := parseSources(shareSources)
+		if err != nil {
+			return err
+		}
+		billable, err := billableFilter(shareBillable, shareNonBillable)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		settings, err := client.GetShareSettings()
+		if isNotFound(err) {
+			return fmt.Errorf("the server does not support share links")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check share settings: %w", err)
+		}
+		if !settings.Enabled {
+			return fmt.Errorf("sharing is disabled on this server")
+		}
+		if settings.MaxExpiryDays > 0 && lifetime > time.Duration(settings.MaxExpiryDays)*24*time.Hour {
+			return fmt.Errorf("--expires %s is longer than the server allows (%dd)", shareExpires, settings.MaxExpiryDays)
+		}
+
+		request := api.CreateShareRequest{
+			From:      from,
+			To:        to,
+			Label:     shareLabel,
+			ExpiresAt: time.Now().Add(lifetime).UTC().Format(time.RFC3339),
+			Sources:   sources,
+			Client:    shareClient,
+			Billable:  billable,
+		}
+		share, err := client.CreateShare(request)
+		if err != nil {
+			return fmt.Errorf("failed to create share link: %w", err)
+		}
+		if flag := unappliedShareFilter(request, share); flag != "" {
+			// Older servers ignore filters they don't know and share
+			// everything; don't leave that link around
+			if err := client.RevokeShare(share.ID); err != nil {
+				return fmt.Errorf("the server does not support %s for share links, and revoking the unfiltered link %s failed: %w", flag, share.ID, err)
+			}
+			return fmt.Errorf("the server does not support %s for share links", flag)
+		}
+
+		display.Printf("\n🔗 %s\n\n", share.URL)
+		if shareQR {
+			code, err := qrcode.New(share.URL, qrcode.Medium)
+			if err != nil {
+				return fmt.Errorf("failed to encode the QR code: %w", err)
+			}
+			// Dark modules are left blank, so the code reads correctly on
+			// the dark background most terminals use
+			display.Println(code.ToSmallString(false))
+		}
+		display.Printf("  Report:  %s to %s\n", share.From, share.To)
+		if len(share.Sources) > 0 {
+			display.Printf("  Sources: %s only\n", strings.Join(share.Sources, ", "))
+		}
+		if share.Client != "" {
+			display.Printf("  Client:  %s only\n", share.Client)
+		}
+		if share.Billable != nil {
+			display.Printf("  Entries: %s only\n", billableLabel(*share.Billable))
+		}
+		display.Printf("  Expires: %s\n", formatTimestamp(share.ExpiresAt))
+		display.Printf("  Revoke with: timetracker report share revoke %s\n\n", share.ID)
+		return nil
+	},
+}
+
+// reportShareListCmd represents the report share list command
+var reportShareListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List active share links",
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		shares, err := client.GetShares()
+		if isNotFound(err) {
+			return fmt.Errorf("the server does not support share links")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list share links: %w", err)
+		}
+		if len(shares) == 0 {
+			display.Println("No active share links.")
+			return nil
+		}
+
+		table := display.NewTable("ID", "Range", "Label", "Expires", "Views", "URL")
+		for _, share := range shares {
+			table.AddRow(share.ID, share.From+" to "+share.To, share.Label,
+				formatTimestamp(share.ExpiresAt), strconv.Itoa(share.Views), share.URL)
+		}
+		display.Println()
+		table.Print()
+		display.Println()
+		return nil
+	},
+}
+
+// reportShareRevokeCmd represents the report share revoke command
+var reportShareRevokeCmd = &cobra.Command{
+	Use:          "revoke <id>",
+	Short:        "Invalidate a share link",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		err = client.RevokeShare(args[0])
+		if isNotFound(err) {
+			return fmt.Errorf("no share link %s", args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to revoke share link: %w", err)
+		}
+
+		display.Printf("✓ Revoked share link %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportShareCmd)
+	reportShareCmd.AddCommand(reportShareListCmd)
+	reportShareCmd.AddCommand(reportShareRevokeCmd)
+
+	reportShareCmd.Flags().StringVar(&shareWeek, "week", "", "ISO week to share, e.g. 2024-W15 (default: this week)")
+	reportShareCmd.Flags().StringVar(&shareFrom, "from", "", "Start date (YYYY-MM-DD), instead of --week")
+	reportShareCmd.Flags().StringVar(&shareTo, "to", "", "End date (YYYY-MM-DD), instead of --week")
+	reportShareCmd.Flags().StringVar(&shareExpires, "expires", "7d", "How long the link works, e.g. 7d, 2w or 12h")
+	reportShareCmd.Flags().StringVar(&shareLabel, "label", "", "Title shown on the shared report")
+	reportShareCmd.Flags().BoolVar(&shareQR, "qr", false, "Also print the link as a QR code")
+	addSourceFlag(reportShareCmd, &shareSources)
+	reportShareCmd.Flags().StringVar(&shareClient, "client", "", "Only report entries of this client (see 'timetracker clients')")
+	addBillableFlags(reportShareCmd, &shareBillable, &shareNonBillable)
+	addWeekStartFlag(reportShareCmd)
+}
+
+// unappliedShareFilter returns the flag of a filter that was requested but
+// that the created share doesn't report, or "" if the server applied them all
+func unappliedShareFilter(request api.CreateShareRequest, share *api.Share) string {
+	switch {
+	case len(request.Sources) > 0 && len(share.Sources) == 0:
+		return "--source"
+	case request.Client != "" && share.Client == "":
+		return "--client"
+	case request.Billable != nil && share.Billable == nil:
+		if *request.Billable {
+			return "--billable"
+		}
+		return "--non-billable"
+	}
+	return ""
+}
+
+// billableLabel names entries of the given billability
+func billableLabel(billable bool) string {
+	if billable {
+		return "billable"
+	}
+	return "non-billable"
+}
+
+// shareRange returns the dates the shared report covers: --from/--to,
+// --week, or this week, for weeks beginning on start
+func shareRange(start time.Weekday) (string, string, error) {
+	if shareFrom != "" || shareTo != "" {
+		if shareWeek != "" {
+			return "", "", fmt.Errorf("use either --week or --from/--to")
+		}
+		if shareFrom == "" || shareTo == "" {
+			return "", "", fmt.Errorf("--from and --to must be used together")
+		}
+		from, err := parseDateFlag(shareFrom)
+		if err != nil {
+			return "", "", err
+		}
+		to, err := parseDateFlag(shareTo)
+		if err != nil {
+			return "", "", err
+		}
+		if to < from {
+			return "", "", fmt.Errorf("--to is before --from")
+		}
+		return from, to, nil
+	}
+
+	first := dates.StartOfWeek(time.Now(), start)
+	if shareWeek != "" {
+		var err error
+		if first, err = dates.ParseWeek(shareWeek, start, time.Local); err != nil {
+			return "", "", err
+		}
+	}
+	return dates.Format(first), dates.Format(first.AddDate(0, 0, 6)), nil
+}
+
+// parseExpiry parses a link lifetime such as "7d", "2w" or "12h"
+func parseExpiry(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var lifetime time.Duration
+	if unit := strings.TrimLeft(value, "0123456789"); unit == "d" || unit == "w" {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, unit))
+		if err != nil {
+			return 0, fmt.Errorf("invalid --expires %q (use e.g. 7d, 2w or 12h)", value)
+		}
+		lifetime = time.Duration(n) * 24 * time.Hour
+		if unit == "w" {
+			lifetime *= 7
+		}
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --expires %q (use e.g. 7d, 2w or 12h)", value)
+		}
+		lifetime = d
+	}
+	if lifetime <= 0 {
+		return 0, fmt.Errorf("--expires must be positive")
+	}
+	return lifetime, nil
+}
@@ -1,14 +1,58 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+	"github.com/vmiller/timetracker-cli/internal/version"
 )
 
-var cfgFile string
+var (
+	cfgFile      string
+	profileFlag  string
+	jsonOutput   bool
+	outputFormat string
+
+	// profileErr is the failure to select the profile asked for with
+	// --profile or TIMETRACKER_PROFILE, reported once the command runs
+	profileErr error
+
+	// recordDir and replayDir are the fixture directories of --record and
+	// --replay
+	recordDir string
+	replayDir string
+
+	// fixtures are what API clients record to or replay from, nil outside
+	// fixture mode
+	fixtures *api.Fixtures
+)
+
+// profileEnv selects a profile for one run, like --profile
+const profileEnv = "TIMETRACKER_PROFILE"
+
+// fixturesEnv names a fixture directory, replayed if it holds fixtures and
+// recorded to otherwise
+const fixturesEnv = "TIMETRACKER_FIXTURES"
+
+// exitInterrupted is the exit code after Ctrl-C, as for other programs
+// killed by SIGINT
+const exitInterrupted = 130
+
+// outputAnnotation lists the output formats a command supports besides
+// table, comma-separated
+const outputAnnotation = "output"
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -19,26 +63,160 @@ from the terminal without needing to open a browser.
 
 You can check today's hours, view weekly summaries, and sync data from
 external providers like Toggl and Tempo.`,
+	Version:           version.Version,
+	PersistentPreRunE: beforeCommand,
+	SilenceErrors:     true, // Execute reports errors, as JSON with --output json
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Ctrl-C or SIGTERM cancels the context commands get from cmd.Context(),
+// which stops their requests; a second Ctrl-C kills the process.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
-		os.Exit(1)
+		code := 1
+		var exit *exitError
+		if errors.As(err, &exit) {
+			code = exit.code
+			if exit.err == nil {
+				os.Exit(code)
+			}
+		} else if ctx.Err() != nil {
+			// Whatever failed did so because the command was interrupted
+			display.ClearLine(os.Stderr)
+			display.ClearLine(os.Stdout)
+			err, code = errors.New("interrupted"), exitInterrupted
+		} else {
+			err = withRequestID(err)
+		}
+		if format, formatErr := selectedFormat(); formatErr == nil {
+			display.SetFormat(format)
+		}
+		display.Error(err, code)
+		if !display.JSON() && strings.HasPrefix(err.Error(), "unknown command") {
+			fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", rootCmd.Name())
+		}
+		if hint := apiErrorHint(err); hint != "" && !display.JSON() {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+		os.Exit(code)
 	}
 }
 
+// exitError makes the process exit with a specific code, for commands whose
+// callers need to tell kinds of failures apart
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode wraps err so the process exits with code
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// withRequestID adds the run's request ID to err once requests were sent,
+// so the failure can be found in the server's logs, and the server's own ID
+// for the failed request if it chose another
+func withRequestID(err error) error {
+	if !api.RequestIDSent() {
+		return err
+	}
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) && apiErr.RequestID != "" && apiErr.RequestID != api.RequestID() {
+		return fmt.Errorf("%w (request id: %s, server request id: %s)", err, api.RequestID(), apiErr.RequestID)
+	}
+	return fmt.Errorf("%w (request id: %s)", err, api.RequestID())
+}
+
+// silentExit makes the process exit with code without printing an error,
+// for commands whose exit code is the answer
+func silentExit(code int) error {
+	return &exitError{code: code}
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.timetracker/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Config profile to use for this command only (default: the active profile)")
 	rootCmd.PersistentFlags().String("api-url", "http://localhost:3000", "API base URL")
+	rootCmd.PersistentFlags().Duration("timeout", api.DefaultTimeout, "How long to wait for the server on each request (0 waits indefinitely)")
+	rootCmd.PersistentFlags().Int("chunk-days", api.DefaultChunkDays, "Split long date ranges into requests of at most this many days")
+	rootCmd.PersistentFlags().Bool("no-chunking", false, "Fetch long date ranges in a single request")
+	rootCmd.PersistentFlags().Bool("offline", false, "Show the last-known summaries without contacting the server")
+	rootCmd.PersistentFlags().Bool("no-offline", false, "Fail when the server can't be reached instead of showing the last-known summaries")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Download every response again instead of reusing unchanged cached ones")
+	rootCmd.PersistentFlags().Bool("preflight", false, "Check the server's health before the first request, to tell a wrong API URL or a server that is down from other failures")
+	rootCmd.PersistentFlags().String("ca-cert", "", "PEM bundle of certificate authorities to trust besides the system's, e.g. an internal CA")
+	rootCmd.PersistentFlags().Bool("insecure", false, "Skip TLS certificate verification (unsafe, for debugging only)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colors and other terminal styling")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Print details such as the active profile and API requests to stderr")
+	rootCmd.PersistentFlags().Int("debug", 0, "Log API requests with their headers to stderr, and with --debug=2 their bodies; secrets are redacted")
+	rootCmd.PersistentFlags().Lookup("debug").NoOptDefVal = "1"
+	rootCmd.PersistentFlags().String("timezone", "", "IANA timezone for today, weeks and other dates, e.g. Europe/Berlin (default: the system's)")
+	rootCmd.PersistentFlags().Bool("quiet", false, "Print only errors and machine output, e.g. for cron jobs")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(display.FormatTable), "Output format: table, json, csv or tsv (for commands that support it)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Same as --output json")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record every API request and response to YAML fixtures in this directory (for development)")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Answer API requests from the fixtures in this directory instead of the server (for development)")
+	rootCmd.PersistentFlags().MarkHidden("record")
+	rootCmd.PersistentFlags().MarkHidden("replay")
 
 	// Bind flags to viper
-	viper.BindPFlag("api_url", rootCmd.PersistentFlags().Lookup("api-url"))
+	for flag, key := range boundFlags {
+		viper.BindPFlag(key, rootCmd.PersistentFlags().Lookup(flag))
+	}
+	viper.BindEnv("timezone", "TIMETRACKER_TIMEZONE")
+	viper.BindEnv("debug", "TIMETRACKER_DEBUG")
+	viper.BindEnv("timeout", "TIMETRACKER_TIMEOUT")
+	viper.BindEnv("api.attempts", "TIMETRACKER_API_ATTEMPTS")
+	viper.BindEnv("api.retry_delay", "TIMETRACKER_API_RETRY_DELAY")
+	viper.BindEnv("api.max_pages", "TIMETRACKER_API_MAX_PAGES")
+	viper.BindEnv("api.rate_limit", "TIMETRACKER_API_RATE_LIMIT")
+	viper.BindEnv("proxy_url", "TIMETRACKER_PROXY_URL")
+	viper.BindEnv("ca_cert", "TIMETRACKER_CA_CERT")
+	viper.BindEnv("insecure_skip_verify", "TIMETRACKER_INSECURE_SKIP_VERIFY")
+}
+
+// boundFlags maps global flags to their viper keys. With AutomaticEnv, each
+// key can also be set by its upper-cased environment variable.
+var boundFlags = map[string]string{
+	"api-url":     "api_url",
+	"chunk-days":  "chunk_days",
+	"no-chunking": "no_chunking",
+	"no-cache":    "no_cache",
+	"preflight":   "preflight",
+	"offline":     "offline",
+	"no-offline":  "no_offline",
+	"ca-cert":     "ca_cert",
+	"insecure":    "insecure_skip_verify",
+	"no-color":    "no_color",
+	"verbose":     "verbose",
+	"debug":       "debug",
+	"quiet":       "quiet",
+	"timezone":    "timezone",
+	"timeout":     "timeout",
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -68,4 +246,120 @@ func initConfig() {
 		// Config file found and successfully read
 		// Silently continue - we don't need to log this
 	}
+
+	// Overlay the active profile's server, tokens and defaults. A profile
+	// asked for explicitly must exist, so commands never silently run
+	// against another server.
+	override := profileFlag
+	if override == "" {
+		override = os.Getenv(profileEnv)
+	}
+	if err := config.ApplyProfile(override); err != nil {
+		if override != "" {
+			profileErr = err
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	}
+
+	// Organisation-managed keys take precedence over everything above
+	applyManagedConfig()
+
+	if viper.GetBool("no_color") {
+		display.DisableColor()
+	}
+	switch debug := viper.GetInt("debug"); {
+	case viper.GetBool("quiet"):
+		logging.SetLevel(logging.Quiet)
+	case debug >= 2:
+		logging.SetLevel(logging.DebugBodies)
+	case debug == 1:
+		logging.SetLevel(logging.Debug)
+	case viper.GetBool("verbose"):
+		logging.SetLevel(logging.Verbose)
+	}
+}
+
+// beforeCommand runs before every command
+func beforeCommand(cmd *cobra.Command, args []string) error {
+	format, err := selectedFormat()
+	if err == nil && format != display.FormatTable && !supportsFormat(cmd, format) {
+		err = fmt.Errorf("'%s' does not support --output %s", cmd.CommandPath(), format)
+	}
+	if err == nil {
+		err = profileErr
+	}
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if format != display.FormatTable {
+		display.SetFormat(format)
+		cmd.SilenceUsage = true
+	}
+	if viper.GetBool("quiet") && viper.GetBool("verbose") {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("--quiet and --verbose can't be used together")
+	}
+	if viper.GetBool("quiet") && viper.GetInt("debug") > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("--quiet and --debug can't be used together")
+	}
+	if viper.GetBool("offline") && viper.GetBool("no_offline") {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("--offline and --no-offline can't be used together")
+	}
+	if err := dates.UseZone(viper.GetString("timezone")); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if err := openFixtures(); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	duration, err := durationFormat(cmd)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	display.SetDurationFormat(duration)
+	if viper.GetBool("insecure_skip_verify") {
+		// Printed on every run, even with --quiet, so it's never left on unnoticed
+		fmt.Fprintln(os.Stderr, display.Red(display.Bold("⚠️  WARNING: TLS certificate verification is disabled (--insecure). Anyone on the network can read and change your requests, tokens included.")))
+	}
+	logging.Verbosef("👤 Profile: %s", config.ActiveProfile())
+	logging.Verbosef("🔖 Request ID: %s", api.RequestID())
+	warnLocalConflicts(cmd, args)
+	return nil
+}
+
+// selectedFormat returns the format chosen with --output or --json
+func selectedFormat() (display.Format, error) {
+	if jsonOutput {
+		if outputFormat != string(display.FormatTable) && !strings.EqualFold(outputFormat, string(display.FormatJSON)) {
+			return "", fmt.Errorf("--json conflicts with --output %s", outputFormat)
+		}
+		return display.FormatJSON, nil
+	}
+	return display.ParseFormat(outputFormat)
+}
+
+// outputFormats returns the annotations of a command supporting the given
+// formats besides table
+func outputFormats(formats ...display.Format) map[string]string {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+	return map[string]string{outputAnnotation: strings.Join(names, ",")}
+}
+
+// supportsFormat reports whether cmd can write format
+func supportsFormat(cmd *cobra.Command, format display.Format) bool {
+	for _, name := range strings.Split(cmd.Annotations[outputAnnotation], ",") {
+		if name == string(format) {
+			return true
+		}
+	}
+	return false
 }
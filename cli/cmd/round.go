@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/rounding"
+)
+
+var (
+	roundFrom        string
+	roundTo          string
+	roundGranularity time.Duration
+	roundMode        string
+	roundPerDay      bool
+	roundApply       bool
+)
+
+// roundChange is the rounding of one entry, or of a day's entries on one
+// project with --per-day. The delta is applied to Target.
+type roundChange struct {
+	Day      string
+	Project  string
+	Entries  []api.Entry
+	Target   api.Entry
+	Old, New float64
+	Billable bool
+}
+
+// Delta returns the hours the rounding adds (or removes)
+func (c roundChange) Delta() float64 {
+	return c.New - c.Old
+}
+
+// roundCmd represents the round command
+var roundCmd = &cobra.Command{
+	Use:   "round",
+	Short: "Round entry durations to billing increments",
+	Long: `Preview how rounding each entry's duration to a billing increment would
+change it, and apply the changes with --apply. Defaults to the current week.
+
+With --per-day the daily total of each project is rounded instead of every
+entry, so many short entries aren't each inflated; the difference is applied
+to the day's longest entry on that project.
+
+Entries with clock times keep their start time and get a new end time.
+
+Examples:
+  timetracker round
+  timetracker round --from 2024-01-01 --to 2024-01-31 --granularity 15m --mode up
+  timetracker round --per-day --mode nearest --apply`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mode, err := rounding.ParseMode(roundMode)
+		if err != nil {
+			return err
+		}
+		if roundGranularity < time.Minute || roundGranularity%time.Minute != 0 {
+			return fmt.Errorf("--granularity must be a whole number of minutes")
+		}
+
+		from, err := parseDateFlag(roundFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(roundTo)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if from == "" {
+			firstDay, err := weekStart(cmd)
+			if err != nil {
+				return err
+			}
+			from = dates.Format(dates.StartOfWeek(now, firstDay))
+		}
+		if to == "" {
+			to = dates.Format(now)
+		}
+		if to < from {
+			return fmt.Errorf("--to must not be before --from")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		fallback := billableDefault(cmd)
+		var changes []roundChange
+		if roundPerDay {
+			changes = roundPerProjectDay(entries, roundGranularity, mode, fallback)
+		} else {
+			changes = roundEntries(entries, roundGranularity, mode, fallback)
+		}
+
+		display.Printf("\n🧮 Rounding %s to %s %s to %d-minute increments\n\n", from, to, mode, int(roundGranularity.Minutes()))
+
+		var changed []roundChange
+		var delta, billableDelta float64
+		for _, change := range changes {
+			if math.Abs(change.Delta()) < 1e-9 {
+				continue
+			}
+			changed = append(changed, change)
+			delta += change.Delta()
+			if change.Billable {
+				billableDelta += change.Delta()
+			}
+		}
+
+		if len(changed) == 0 {
+			display.Println("Nothing to round.")
+			display.Println()
+			return nil
+		}
+
+		var table *display.Table
+		if roundPerDay {
+			table = display.NewTable("Date", "Project", "Entries", "Old", "New", "Delta")
+		} else {
+			table = display.NewTable("Date", "Project", "Description", "Old", "New", "Delta")
+		}
+		for _, change := range changed {
+			third := change.Target.Description
+			if roundPerDay {
+				third = strconv.Itoa(len(change.Entries))
+			}
+			table.AddRow(change.Day, change.Project, third,
+				display.FormatHours(change.Old),
+				display.FormatHours(change.New),
+				formatDelta(change.Delta()),
+			)
+		}
+		table.Print()
+
+		unit := "entries"
+		if roundPerDay {
+			unit = "project days"
+		}
+		display.Printf("\n%d of %d %s change\n", len(changed), len(changes), unit)
+		display.Printf("Total delta:    %sh\n", formatDelta(delta))
+		display.Printf("Billable delta: %sh\n\n", formatDelta(billableDelta))
+
+		if !roundApply {
+			display.Println("Preview only. Run again with --apply to update the entries.")
+			return nil
+		}
+
+		updates := newEntryChanges(client)
+		defer updates.record()
+
+		failed := 0
+		for _, change := range changed {
+			hours := change.Target.Duration + change.Delta()
+			if hours <= 0 {
+				display.Printf("⚠️  Skipped %s %s: the longest entry can't absorb %s\n", change.Day, change.Project, display.FormatDuration(change.Delta()))
+				failed++
+				continue
+			}
+			update, err := durationUpdate(change.Target, hours)
+			if err != nil {
+				display.Printf("⚠️  Skipped %s %s: %v\n", change.Day, change.Project, err)
+				failed++
+				continue
+			}
+			if _, err := updates.update(change.Target, update); err != nil {
+				display.Printf("❌ %s %s: %v\n", change.Day, change.Project, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d changes failed", failed, len(changed))
+		}
+		display.Printf("✓ Updated %d entries\n", len(changed))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(roundCmd)
+
+	roundCmd.Flags().StringVar(&roundFrom, "from", "", "Start date (YYYY-MM-DD, today, yesterday; default: start of this week)")
+	roundCmd.Flags().StringVar(&roundTo, "to", "", "End date (YYYY-MM-DD, today, yesterday; default: today)")
+	roundCmd.Flags().DurationVar(&roundGranularity, "granularity", 15*time.Minute, "Billing increment, e.g. 6m, 15m, 30m")
+	roundCmd.Flags().StringVar(&roundMode, "mode", string(rounding.Up), "Rounding mode: up, nearest or down")
+	roundCmd.Flags().BoolVar(&roundPerDay, "per-day", false, "Round each project's daily total instead of each entry")
+	roundCmd.Flags().BoolVar(&roundApply, "apply", false, "Update the entries instead of only previewing")
+	addWeekStartFlag(roundCmd)
+}
+
+// roundEntries rounds every entry on its own
+func roundEntries(entries []api.Entry, granularity time.Duration, mode rounding.Mode, fallback bool) []roundChange {
+	changes := make([]roundChange, 0, len(entries))
+	for _, entry := range entries {
+		changes = append(changes, roundChange{
+			Day:      entry.Day(),
+			Project:  projectLabel(entry.Project),
+			Entries:  []api.Entry{entry},
+			Target:   entry,
+			Old:      entry.Duration,
+			New:      rounding.Hours(entry.Duration, granularity, mode),
+			Billable: entry.IsBillable(fallback),
+		})
+	}
+	sortRoundChanges(changes)
+	return changes
+}
+
+// roundPerProjectDay rounds each project's total per day. The delta goes to
+// the day's longest entry on the project, which also decides billability.
+func roundPerProjectDay(entries []api.Entry, granularity time.Duration, mode rounding.Mode, fallback bool) []roundChange {
+	groups := map[[2]string]*roundChange{}
+	for _, entry := range entries {
+		key := [2]string{entry.Day(), projectLabel(entry.Project)}
+		group, ok := groups[key]
+		if !ok {
+			group = &roundChange{Day: key[0], Project: key[1], Target: entry}
+			groups[key] = group
+		}
+		group.Entries = append(group.Entries, entry)
+		group.Old += entry.Duration
+		if entry.Duration > group.Target.Duration {
+			group.Target = entry
+		}
+	}
+
+	changes := make([]roundChange, 0, len(groups))
+	for _, group := range groups {
+		group.New = rounding.Hours(group.Old, granularity, mode)
+		group.Billable = group.Target.IsBillable(fallback)
+		changes = append(changes, *group)
+	}
+	sortRoundChanges(changes)
+	return changes
+}
+
+// sortRoundChanges orders changes by date, then project
+func sortRoundChanges(changes []roundChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Day != changes[j].Day {
+			return changes[i].Day < changes[j].Day
+		}
+		return changes[i].Project < changes[j].Project
+	})
+}
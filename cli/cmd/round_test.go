@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+func TestDurationUpdate(t *testing.T) {
+	entry := api.Entry{Date: "2024-01-10", Source: "MANUAL", StartTime: "22:10", EndTime: "22:50", Duration: 40.0 / 60}
+
+	update, err := durationUpdate(entry, 0.75)
+	if err != nil || update.StartTime != "22:10" || update.EndTime != "22:55" || update.Date != "2024-01-10" {
+		t.Errorf("durationUpdate(0.75h) = %+v, %v; want 22:10-22:55", update, err)
+	}
+
+	// Rounded up to 2h the entry would end at 00:10 the next day
+	if _, err := durationUpdate(entry, 2); err == nil || !strings.Contains(err.Error(), "same day") {
+		t.Errorf("durationUpdate(2h) = %v, want a same-day error", err)
+	}
+
+	// Entries without clock times only change their duration
+	entry.StartTime, entry.EndTime = "", ""
+	update, err = durationUpdate(entry, 5)
+	if err != nil || update.Duration != 5 || update.EndTime != "" {
+		t.Errorf("durationUpdate without times = %+v, %v", update, err)
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/settings"
+)
+
+// scheduleCmd represents the schedule command
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Show or set the hours expected per weekday",
+	Long: `Manage the weekly schedule: how many hours each weekday is expected to
+have. 'week', 'balance' and 'missing' compute expected hours from it, so a
+part-time week (e.g. 32h with Wednesdays off) is measured correctly.
+
+Without a schedule, contract_hours is spread evenly over work_days.
+
+The schedule is stored in the config file:
+
+  schedule:
+    mon: 8
+    tue: 8
+    thu: 8
+    fri: 8
+
+Examples:
+  timetracker schedule show
+  timetracker schedule set mon=8 tue=8 wed=0 thu=8 fri=8
+  timetracker schedule set fri=6
+  timetracker schedule reset`,
+}
+
+// scheduleShowCmd represents the schedule show command
+var scheduleShowCmd = &cobra.Command{
+	Use:          "show",
+	Short:        "Show the expected hours per weekday",
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cal, source, err := weekCalendar(cmd)
+		if err != nil {
+			return err
+		}
+
+		display.Printf("\n🗓️  Weekly schedule (%s)\n\n", source)
+		table := display.NewTable("Day", "Hours")
+		for i := 1; i <= 7; i++ {
+			day := time.Weekday(i % 7)
+			hours := "-"
+			if cal.WorkDays[day] {
+				hours = display.FormatHours(cal.Hours[day])
+			}
+			table.AddRow(day.String(), hours)
+		}
+		table.Print()
+		display.Printf("\n⏱️  %s per week\n\n", display.FormatDuration(cal.WeeklyHours()))
+		return nil
+	},
+}
+
+// scheduleSetCmd represents the schedule set command
+var scheduleSetCmd = &cobra.Command{
+	Use:   "set <day=hours>...",
+	Short: "Set the expected hours of weekdays",
+	Long: `Set the expected hours of one or more weekdays. Days not mentioned keep
+their hours; set a day to 0 to make it a day off. When no schedule exists
+yet, it starts from the current one (contract_hours over work_days).
+
+Examples:
+  timetracker schedule set mon=8 tue=8 wed=0 thu=8 fri=8
+  timetracker schedule set fri=6h30m`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cal, _, err := weekCalendar(cmd)
+		if err != nil {
+			return err
+		}
+		hours := cal.Hours
+		for day, work := range cal.WorkDays {
+			if !work {
+				hours[day] = 0
+			}
+		}
+
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("invalid %q (expected day=hours, e.g. mon=8)", arg)
+			}
+			day, err := dates.ParseWeekday(name)
+			if err != nil {
+				return err
+			}
+			h, err := dates.ParseHours(value)
+			if err != nil {
+				return err
+			}
+			if h < 0 || h > 24 {
+				return fmt.Errorf("invalid hours for %s: %v", day, h)
+			}
+			hours[day] = h
+		}
+
+		schedule := map[string]float64{}
+		for day, h := range hours {
+			if h > 0 {
+				schedule[scheduleKey(time.Weekday(day))] = h
+			}
+		}
+		if len(schedule) == 0 {
+			return fmt.Errorf("the schedule must have at least one working day")
+		}
+		if err := config.Set("schedule", schedule); err != nil {
+			return fmt.Errorf("failed to save schedule: %w", err)
+		}
+
+		display.Printf("✓ Schedule saved: %s per week\n", display.FormatDuration(calendar.FromSchedule(hours).WeeklyHours()))
+		return nil
+	},
+}
+
+// scheduleResetCmd represents the schedule reset command
+var scheduleResetCmd = &cobra.Command{
+	Use:          "reset",
+	Short:        "Remove the schedule and go back to contract_hours over work_days",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.Set("schedule", nil); err != nil {
+			return fmt.Errorf("failed to remove schedule: %w", err)
+		}
+		display.Println("✓ Schedule removed; expected hours follow contract_hours and work_days")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleShowCmd)
+	scheduleCmd.AddCommand(scheduleSetCmd)
+	scheduleCmd.AddCommand(scheduleResetCmd)
+}
+
+// scheduleKey returns the config key of a weekday, e.g. "mon"
+func scheduleKey(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
+
+// weekCalendar returns the working week without holidays or vacation: the
+// schedule from the config file, or contract_hours spread over work_days.
+// An explicit --contract-hours flag overrides the schedule. The second
+// result names where the hours came from.
+func weekCalendar(cmd *cobra.Command) (*calendar.Calendar, string, error) {
+	sources := settingSources(cmd)
+	contractSetting, _ := settings.Lookup("contract_hours")
+	contract := settings.Resolve(contractSetting, sources)
+
+	var schedule map[string]float64
+	if err := config.FileValue("schedule", &schedule); err != nil {
+		return nil, "", err
+	}
+	if len(schedule) > 0 && contract.Layer != settings.LayerFlag {
+		hours, err := calendar.ParseSchedule(schedule)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid schedule in config file: %w", err)
+		}
+		return calendar.FromSchedule(hours), "schedule", nil
+	}
+
+	weekly, err := strconv.ParseFloat(contract.Value, 64)
+	if err != nil || weekly < 0 {
+		return nil, "", fmt.Errorf("invalid contract_hours: %q", contract.Value)
+	}
+	work, err := workSchedule(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+	return calendar.FromContract(weekly, work.Days), "contract_hours over work_days", nil
+}
+
+// workCalendar combines the working week, the holidays and the vacation
+// weeks into the calendar deciding which days owe how many hours
+func workCalendar(cmd *cobra.Command) (*calendar.Calendar, error) {
+	cal, _, err := weekCalendar(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cal.Holidays, err = loadHolidays()
+	if err != nil {
+		return nil, err
+	}
+
+	vacation, err := openVacationStore()
+	if err != nil {
+		return nil, err
+	}
+	cal.Vacation = map[string]string{}
+	for _, week := range vacation.Keys() {
+		cal.Vacation[week], _ = vacation.Get(week)
+	}
+
+	return cal, nil
+}
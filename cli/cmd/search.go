@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	searchFrom  string
+	searchTo    string
+	searchLimit int
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search time entries by description",
+	Long: `Search time entry descriptions for the given text (case-insensitive).
+
+The query may contain qualifiers to narrow the results:
+  project:<name>   only entries whose project contains <name>
+  source:<name>    only entries from the given source (toggl, tempo, manual)
+
+Examples:
+  timetracker search migration
+  timetracker search "database migration" project:WEKA --from 2024-01-01`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchLimit < 1 {
+			return fmt.Errorf("--limit must be at least 1")
+		}
+
+		from, err := parseDateFlag(searchFrom)
+		if err != nil {
+			return err
+		}
+		to, err := parseDateFlag(searchTo)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		rawQuery := strings.Join(args, " ")
+		query := parseSearchQuery(rawQuery)
+
+		// Prefer the server-side search, fall back to filtering locally
+		var results []api.Entry
+		var total int
+		resp, err := client.SearchEntries(rawQuery, from, to, searchLimit)
+		switch {
+		case err == nil:
+			results, total = resp.Results, resp.Total
+		case isNotFound(err):
+			entries, err := client.GetEntries(from, to)
+			if err != nil {
+				return fmt.Errorf("failed to fetch entries: %w", err)
+			}
+			results = query.filter(entries)
+			total = len(results)
+		default:
+			return fmt.Errorf("search failed: %w", err)
+		}
+
+		if total < len(results) {
+			total = len(results)
+		}
+		if len(results) > searchLimit {
+			results = results[:searchLimit]
+		}
+
+		if len(results) == 0 {
+			display.Printf("\nNo entries found matching %q.\n\n", rawQuery)
+			return nil
+		}
+
+		display.Printf("\n🔍 %d match(es) for %q\n\n", total, rawQuery)
+		for _, entry := range results {
+			display.Printf("%s  %7s  %s  [%s]\n", entry.Day(), display.FormatDuration(entry.Duration), entry.Project, entry.Source)
+			display.Printf("    %s\n", display.Highlight(entry.Description, query.text))
+		}
+
+		if total > len(results) {
+			display.Printf("\nShowing %d of %d matches. Use --limit to see more.\n", len(results), total)
+		}
+
+		display.Println()
+
+		return nil
+	},
+}
+
+// searchQuery is a parsed search query with optional qualifiers
+type searchQuery struct {
+	text    string
+	project string
+	source  string
+}
+
+// parseSearchQuery splits project:/source: qualifiers from the free text
+func parseSearchQuery(raw string) searchQuery {
+	var query searchQuery
+	var words []string
+
+	for _, word := range strings.Fields(raw) {
+		lower := strings.ToLower(word)
+		switch {
+		case strings.HasPrefix(lower, "project:"):
+			query.project = word[len("project:"):]
+		case strings.HasPrefix(lower, "source:"):
+			query.source = word[len("source:"):]
+		default:
+			words = append(words, word)
+		}
+	}
+
+	query.text = strings.Join(words, " ")
+	return query
+}
+
+// matches reports whether an entry satisfies the query
+func (q searchQuery) matches(entry api.Entry) bool {
+	if q.source != "" && !strings.EqualFold(entry.Source, q.source) {
+		return false
+	}
+	if q.project != "" && !strings.Contains(strings.ToLower(entry.Project), strings.ToLower(q.project)) {
+		return false
+	}
+	return strings.Contains(strings.ToLower(entry.Description), strings.ToLower(q.text))
+}
+
+// filter returns the matching entries, newest first
+func (q searchQuery) filter(entries []api.Entry) []api.Entry {
+	var results []api.Entry
+	for _, entry := range entries {
+		if q.matches(entry) {
+			results = append(results, entry)
+		}
+	}
+
+	sortByRecency(results)
+	return results
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringVar(&searchFrom, "from", "", "Only search entries on or after this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchTo, "to", "", "Only search entries on or before this date (YYYY-MM-DD)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum number of matches to show")
+}
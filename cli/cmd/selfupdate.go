@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/selfupdate"
+	"github.com/vmiller/timetracker-cli/internal/settings"
+	"github.com/vmiller/timetracker-cli/internal/version"
+)
+
+var (
+	selfUpdateCheck bool
+	selfUpdateForce bool
+)
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update the CLI to the latest release",
+	Long: `Check GitHub for a newer release and, if there is one, download the binary
+for this platform, verify it against the release's published SHA-256
+checksums and replace the running executable with it.
+
+Installs managed by an organisation can turn this off with
+"self_update: false" in the config file or the managed configuration.
+
+Examples:
+  timetracker self-update --check
+  timetracker self-update`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, _ := settings.Lookup("self_update")
+		resolved := settings.Resolve(setting, settingSources(cmd))
+		if enabled, err := strconv.ParseBool(resolved.Value); err == nil && !enabled && !selfUpdateCheck {
+			return fmt.Errorf("self-update is turned off (self_update: false, from %s); ask whoever manages this install for updates", resolved.Layer)
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the executable: %w", err)
+		}
+		if resolvedPath, err := filepath.EvalSymlinks(executable); err == nil {
+			executable = resolvedPath
+		}
+		selfupdate.Cleanup(executable)
+
+		updater := &selfupdate.Updater{}
+		release, err := updater.Latest()
+		if err != nil {
+			return err
+		}
+
+		current := version.Version
+		newer := selfupdate.Newer(current, release.TagName)
+		switch {
+		case newer:
+			display.Printf("⬆️  %s is available (you have %s)\n", release.TagName, current)
+		case current == release.TagName:
+			display.Printf("✓ %s is the latest version\n", current)
+			return nil
+		case !selfUpdateForce:
+			display.Printf("Latest release is %s; this build is %s.\n", release.TagName, current)
+			if !selfUpdateCheck {
+				display.Println("Use --force to install the release anyway.")
+			}
+			return nil
+		}
+		if selfUpdateCheck {
+			display.Printf("   %s\n", release.HTMLURL)
+			return nil
+		}
+
+		asset := selfupdate.AssetName(runtime.GOOS, runtime.GOARCH)
+		display.Printf("Downloading %s...\n", asset)
+		binary, err := updater.Download(release, asset)
+		if err != nil {
+			return err
+		}
+		display.Println("✓ Checksum verified")
+
+		if err := selfupdate.Replace(executable, binary); err != nil {
+			return err
+		}
+		display.Printf("✓ Updated %s to %s\n", executable, release.TagName)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Only report whether an update is available")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Install the latest release even if this build isn't older, e.g. a dev build")
+}
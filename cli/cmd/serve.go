@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/browser"
+	"github.com/vmiller/timetracker-cli/internal/dashboard"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	servePort    int
+	serveHost    string
+	serveRefresh time.Duration
+	serveOpen    bool
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local read-only dashboard",
+	Long: `Serve a single-page dashboard with today's, this week's and this month's
+hours and a chart of the month's days, e.g. to show on a call. Summaries are
+fetched every --refresh and cached; the page updates itself.
+
+The dashboard only listens on localhost unless --host says otherwise, and
+can't change anything. Press Ctrl+C to stop it.
+
+Examples:
+  timetracker serve
+  timetracker serve --port 8787 --refresh 30s --open`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveRefresh < 5*time.Second {
+			return fmt.Errorf("--refresh must be at least 5s")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		board := dashboard.New(func(ctx context.Context) (*dashboard.Snapshot, error) {
+			return fetchSnapshot(client)
+		}, serveRefresh)
+		if err := board.Refresh(ctx); err != nil {
+			return fmt.Errorf("failed to fetch summaries: %w", err)
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort(serveHost, strconv.Itoa(servePort)))
+		if err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+		server := &http.Server{Handler: board.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+		url := "http://" + listener.Addr().String()
+		if ip := net.ParseIP(serveHost); serveHost != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			display.Println("⚠️  Listening beyond localhost: anyone who can reach this machine can see your hours.")
+		}
+		display.Printf("📊 Dashboard at %s (refreshing every %s). Press Ctrl+C to stop.\n", url, serveRefresh)
+		if serveOpen {
+			if err := browser.Default().Open(url); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Couldn't open a browser: %v\n", err)
+			}
+		}
+
+		go board.Run(ctx, func(err error) {
+			display.Printf("⚠️  [%s] Refresh failed, showing the last data: %v\n", time.Now().Format("15:04"), err)
+		})
+
+		served := make(chan error, 1)
+		go func() { served <- server.Serve(listener) }()
+
+		select {
+		case err := <-served:
+			return fmt.Errorf("dashboard stopped: %w", err)
+		case <-ctx.Done():
+		}
+
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to stop the dashboard: %w", err)
+		}
+		display.Println("\nStopped.")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().IntVar(&servePort, "port", 8787, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Address to listen on")
+	serveCmd.Flags().DurationVar(&serveRefresh, "refresh", time.Minute, "How often to fetch new summaries")
+	serveCmd.Flags().BoolVar(&serveOpen, "open", false, "Open the dashboard in the browser")
+}
+
+// fetchSnapshot loads today's and this week's summaries and the month's
+// entries for the dashboard
+func fetchSnapshot(client *api.Client) (*dashboard.Snapshot, error) {
+	var today api.TodaySummaryResponse
+	if err := client.Get("/api/entries/summary/today", &today); err != nil {
+		return nil, fmt.Errorf("today: %w", err)
+	}
+	var week api.WeekSummaryResponse
+	if err := client.Get("/api/entries/summary/week", &week); err != nil {
+		return nil, fmt.Errorf("week: %w", err)
+	}
+
+	now := time.Now()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	entries, err := client.GetEntries(dates.Format(first), dates.Format(now))
+	if err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+
+	snapshot := &dashboard.Snapshot{
+		Today:     today.TotalHours,
+		Week:      week.TotalHours,
+		MonthName: now.Format("January"),
+		BySource:  map[string]float64{},
+		Updated:   now,
+	}
+	daily := map[string]float64{}
+	for _, entry := range entries {
+		daily[entry.Day()] += entry.Duration
+		snapshot.Month += entry.Duration
+		snapshot.BySource[entry.Source] += entry.Duration
+	}
+	for day := first; !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := dates.Format(day)
+		snapshot.Days = append(snapshot.Days, dashboard.Day{Date: date, Hours: daily[date]})
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/remind"
+	"github.com/vmiller/timetracker-cli/internal/rounding"
+	"github.com/vmiller/timetracker-cli/internal/settings"
+)
+
+// settingSources collects the value layers for the command being run
+func settingSources(cmd *cobra.Command) settings.Sources {
+	// Read the config file on its own so flag and env values bound to the
+	// global viper instance don't masquerade as config file values
+	fileConfig := viper.New()
+	if path := viper.ConfigFileUsed(); path != "" {
+		fileConfig.SetConfigFile(path)
+		fileConfig.ReadInConfig()
+	}
+	profile, _ := config.ProfileValues(config.ActiveProfile())
+
+	return settings.Sources{
+		Managed: managedValues(),
+		Server:  serverPreferences(cmd.Context()),
+		Config: func(key string) (string, bool) {
+			if config.ActiveProfile() != config.DefaultProfile {
+				if value, ok := profile[key]; ok {
+					return fmt.Sprint(value), true
+				}
+				if config.IsProfileKey(key) {
+					return "", false
+				}
+			}
+			if !fileConfig.IsSet(key) {
+				return "", false
+			}
+			return fileConfig.GetString(key), true
+		},
+		Env: settings.EnvSource,
+		Flag: func(name string) (string, bool) {
+			flag := cmd.Flag(name)
+			if flag == nil || !flag.Changed {
+				return "", false
+			}
+			return flag.Value.String(), true
+		},
+	}
+}
+
+// billableDefault reports whether entries without a billable flag count as billable
+func billableDefault(cmd *cobra.Command) bool {
+	setting, _ := settings.Lookup("billable_default")
+	billable, err := strconv.ParseBool(settings.Resolve(setting, settingSources(cmd)).Value)
+	if err != nil {
+		return true
+	}
+	return billable
+}
+
+// weekStart returns the configured first day of the week
+func weekStart(cmd *cobra.Command) (time.Weekday, error) {
+	setting, _ := settings.Lookup("week_start")
+	day, err := dates.ParseWeekday(settings.Resolve(setting, settingSources(cmd)).Value)
+	if err != nil {
+		return time.Monday, fmt.Errorf("invalid week_start: %w", err)
+	}
+	return day, nil
+}
+
+// durationFormat returns how hours are shown (duration_format)
+func durationFormat(cmd *cobra.Command) (display.DurationFormat, error) {
+	setting, _ := settings.Lookup("duration_format")
+	f, err := display.ParseDurationFormat(settings.Resolve(setting, settingSources(cmd)).Value)
+	if err != nil {
+		return display.DurationDecimal, fmt.Errorf("invalid duration_format: %w", err)
+	}
+	return f, nil
+}
+
+// breakdownProjects returns how many projects breakdowns list before the
+// rest are grouped as "(other)"
+func breakdownProjects(cmd *cobra.Command) (int, error) {
+	setting, _ := settings.Lookup("breakdown_projects")
+	value := settings.Resolve(setting, settingSources(cmd)).Value
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of projects %q (--top or breakdown_projects): must be at least 1", value)
+	}
+	return n, nil
+}
+
+// syncRetries returns how often sync is retried after transient failures
+func syncRetries(cmd *cobra.Command) (int, error) {
+	setting, _ := settings.Lookup("sync.retries")
+	value := settings.Resolve(setting, settingSources(cmd)).Value
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number of retries %q (--retry or sync.retries): must be 0 or more", value)
+	}
+	return n, nil
+}
+
+// displayRounding returns how today and week round the durations they show:
+// --round or display.rounding, or else the rounding policy
+func displayRounding(cmd *cobra.Command) (rounding.Display, error) {
+	sources := settingSources(cmd)
+	setting, _ := settings.Lookup("display.rounding")
+	resolved := settings.Resolve(setting, sources)
+	if resolved.Layer == settings.LayerDefault {
+		setting, _ = settings.Lookup("rounding")
+		resolved = settings.Resolve(setting, sources)
+	}
+	round, err := rounding.ParseDisplay(resolved.Value)
+	if err != nil {
+		return rounding.Display{}, fmt.Errorf("invalid %s: %w", setting.Key, err)
+	}
+	return round, nil
+}
+
+// addRoundFlag adds --round, which rounds the durations shown
+func addRoundFlag(cmd *cobra.Command) {
+	cmd.Flags().String("round", "", "Round the durations shown, e.g. 15m or 15m:up; entries are not changed (default: display.rounding from the config file)")
+}
+
+// addBreakdownFlags adds --by and --top, which choose the breakdowns printed
+func addBreakdownFlags(cmd *cobra.Command, by *string) {
+	cmd.Flags().StringVar(by, "by", "source", "Breakdowns to print: project, source or both")
+	cmd.Flags().Int("top", 8, "Projects to list before grouping the rest as (other) (default: breakdown_projects from the config file)")
+}
+
+// addSourceFlag adds --source, which limits a summary to some sources
+func addSourceFlag(cmd *cobra.Command, sources *[]string) {
+	cmd.Flags().StringSliceVar(sources, "source", nil, "Only count entries of this source: toggl, tempo or manual (repeatable)")
+}
+
+// addWeekStartFlag adds --week-start, which overrides the week_start setting
+func addWeekStartFlag(cmd *cobra.Command) {
+	cmd.Flags().String("week-start", "monday", "First day of the week, e.g. sunday (default: week_start from the config file)")
+}
+
+// fiscalYearStart returns the configured first month of the fiscal year
+func fiscalYearStart(cmd *cobra.Command) (time.Month, error) {
+	setting, _ := settings.Lookup("fiscal_year_start")
+	month, err := dates.ParseMonth(settings.Resolve(setting, settingSources(cmd)).Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fiscal_year_start: %w", err)
+	}
+	return month, nil
+}
+
+// workSchedule returns the configured working hours and days
+func workSchedule(cmd *cobra.Command) (remind.Schedule, error) {
+	sources := settingSources(cmd)
+	hours, _ := settings.Lookup("work_hours")
+	days, _ := settings.Lookup("work_days")
+	return remind.ParseSchedule(settings.Resolve(hours, sources).Value, settings.Resolve(days, sources).Value)
+}
+
+// preferenceTimeout bounds fetching the server's preferences, which runs
+// before commands that don't otherwise talk to the server
+const preferenceTimeout = 5 * time.Second
+
+var (
+	serverPreferencesOnce   sync.Once
+	serverPreferencesValues map[string]string
+)
+
+// serverPreferences returns the server's preferences, fetching them at most
+// once per process and once per day. Any failure leaves the server layer
+// empty or stale rather than failing the command.
+func serverPreferences(ctx context.Context) map[string]string {
+	serverPreferencesOnce.Do(func() {
+		serverPreferencesValues = fetchServerPreferences(ctx)
+	})
+	return serverPreferencesValues
+}
+
+// fetchServerPreferences reads the preference cache, refreshing it when it
+// is stale. A failed fetch isn't retried for settings.PreferenceRetryAfter,
+// and --offline never fetches.
+func fetchServerPreferences(ctx context.Context) map[string]string {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil
+	}
+
+	cache := settings.LoadPreferenceCache(filepath.Join(dir, "preferences.json"))
+	now := time.Now()
+	if !cache.Stale(now) || offlineMode() == api.OfflineOnly {
+		return cache.Values
+	}
+
+	cfg, err := config.Load()
+	if err != nil || (cfg.AccessToken == "" && cfg.RefreshToken == "") {
+		return cache.Values
+	}
+
+	// One quick attempt: the preferences only refine the defaults
+	client := newClient(api.WithTimeout(ctx, preferenceTimeout), cfg)
+	client.SetRetries(1, 0)
+	values, err := client.GetPreferences()
+	switch {
+	case err == nil:
+		cache.Update(values, true, now)
+	case isNotFound(err):
+		// The server doesn't offer preferences; don't ask again today
+		cache.Update(nil, false, now)
+	default:
+		cache.Fail(now)
+	}
+	cache.Save()
+
+	return cache.Values
+}
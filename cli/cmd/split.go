@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// splitDefaultStart is where the parts begin when the entry has no start time
+const splitDefaultStart = "09:00"
+
+var (
+	splitInto           []string
+	splitAllowRemainder bool
+)
+
+// splitPart is one piece of a split entry
+type splitPart struct {
+	Project     string
+	Description string
+	Minutes     int
+	Start, End  string
+}
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split <entry-id>",
+	Short: "Divide one entry into several",
+	Long: `Split an entry into parts on other projects. Each --into is
+PROJECT=DURATION with an optional :DESCRIPTION, where DURATION is like 5h,
+90m, 1h30m or 2.5. Descriptions default to the original entry's.
+
+The parts must add up to the entry's duration. With --allow-remainder they
+may add up to less, and the original entry keeps the difference; otherwise
+the original is deleted. The parts are created as manual entries back to
+back from the entry's start time (09:00 if it has none). If any part can't
+be created, the parts created so far are deleted again and the original is
+left untouched.
+
+Examples:
+  timetracker split 42 --into "ProjectA=5h" --into "ProjectB=3h"
+  timetracker split 42 --into "ProjectA=5h:code review" --into "ProjectB=3h"
+  timetracker split 42 --into "Internal=1h" --allow-remainder`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(splitInto) == 0 {
+			return fmt.Errorf("at least one --into part is required")
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entry, err := client.FindEntry(args[0])
+		if isNotFound(err) {
+			return fmt.Errorf("no entry with ID %s", args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch entry: %w", err)
+		}
+
+		parts := make([]splitPart, 0, len(splitInto))
+		for _, spec := range splitInto {
+			part, err := parseSplitPart(spec, entry.Description)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+		}
+
+		remainder, err := layoutSplit(entry, parts, splitAllowRemainder)
+		if err != nil {
+			return err
+		}
+
+		display.Printf("\n✂️  Splitting %s on %s (%s)\n\n", display.FormatDuration(entry.Duration), entry.Day(), projectLabel(entry.Project))
+		table := display.NewTable("Project", "Description", "Time", "Hours")
+		for _, part := range parts {
+			table.AddRow(part.Project, part.Description, part.Start+"-"+part.End, display.FormatHours(float64(part.Minutes)/60))
+		}
+		if remainder > 0 {
+			table.AddRow(projectLabel(entry.Project)+" (kept)", entry.Description, "", display.FormatHours(float64(remainder)/60))
+		}
+		table.Print()
+		display.Println()
+
+		if err := applySplit(client, entry, parts, remainder); err != nil {
+			return err
+		}
+
+		display.Printf("✓ Split entry %s into %d parts\n", entry.ID, len(parts))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().StringArrayVar(&splitInto, "into", nil, "Part as PROJECT=DURATION[:DESCRIPTION] (repeatable)")
+	splitCmd.Flags().BoolVar(&splitAllowRemainder, "allow-remainder", false, "Keep the unassigned difference on the original entry")
+}
+
+// parseSplitPart parses PROJECT=DURATION[:DESCRIPTION]
+func parseSplitPart(spec, defaultDescription string) (splitPart, error) {
+	project, rest, ok := strings.Cut(spec, "=")
+	project = strings.TrimSpace(project)
+	if !ok || project == "" {
+		return splitPart{}, fmt.Errorf("invalid part %q (expected PROJECT=DURATION[:DESCRIPTION])", spec)
+	}
+
+	duration, description, hasDescription := strings.Cut(rest, ":")
+	if !hasDescription {
+		description = defaultDescription
+	}
+
+	hours, err := dates.ParseHours(duration)
+	if err != nil {
+		return splitPart{}, fmt.Errorf("invalid part %q: %w", spec, err)
+	}
+
+	minutes := int(math.Round(hours * 60))
+	if minutes <= 0 {
+		return splitPart{}, fmt.Errorf("invalid part %q: duration must be at least one minute", spec)
+	}
+
+	return splitPart{
+		Project:     resolveProject(project),
+		Description: strings.TrimSpace(description),
+		Minutes:     minutes,
+	}, nil
+}
+
+// layoutSplit checks the parts against the entry and assigns their clock
+// times. The original keeps the first remainder minutes, the parts follow
+// back to back. It returns the remainder in minutes.
+func layoutSplit(entry *api.Entry, parts []splitPart, allowRemainder bool) (int, error) {
+	total := int(math.Round(entry.Duration * 60))
+	assigned := 0
+	for _, part := range parts {
+		assigned += part.Minutes
+	}
+
+	remainder := total - assigned
+	switch {
+	case remainder < 0:
+		return 0, fmt.Errorf("parts add up to %s, more than the entry's %s", display.FormatDuration(float64(assigned)/60), display.FormatDuration(entry.Duration))
+	case remainder > 0 && !allowRemainder:
+		return 0, fmt.Errorf("parts add up to %s but the entry has %s (use --allow-remainder to keep the difference on the original)",
+			display.FormatDuration(float64(assigned)/60), display.FormatDuration(entry.Duration))
+	}
+
+	start := entry.StartTime
+	if start == "" {
+		start = splitDefaultStart
+	}
+	clock, err := time.Parse("15:04", start)
+	if err != nil {
+		return 0, fmt.Errorf("entry has an invalid start time %q", start)
+	}
+
+	cursor := clock.Hour()*60 + clock.Minute() + remainder
+	for i := range parts {
+		parts[i].Start = formatClock(cursor)
+		cursor += parts[i].Minutes
+		if cursor >= 24*60 {
+			return 0, fmt.Errorf("the parts don't fit in the day when starting at %s", start)
+		}
+		parts[i].End = formatClock(cursor)
+	}
+
+	return remainder, nil
+}
+
+// applySplit creates the parts and then deletes the original, or shrinks it
+// to the remainder. Parts already created are deleted again if a later step
+// fails, so the entry is either fully split or left as it was.
+func applySplit(client *api.Client, entry *api.Entry, parts []splitPart, remainder int) error {
+	changes := newEntryChanges(client)
+	defer changes.record()
+
+	var created []api.Entry
+	rollback := func(cause error) error {
+		var failed []string
+		for _, part := range created {
+			if err := changes.delete(part); err != nil {
+				failed = append(failed, part.ID)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%w; rollback failed, delete entries %s manually", cause, strings.Join(failed, ", "))
+		}
+		return fmt.Errorf("%w; no changes were made", cause)
+	}
+
+	for _, part := range parts {
+		newEntry, err := changes.create(api.CreateEntryRequest{
+			Date:        entry.Day(),
+			StartTime:   part.Start,
+			EndTime:     part.End,
+			Project:     part.Project,
+			Description: part.Description,
+			Timezone:    dates.LocalZone(),
+		})
+		if err != nil {
+			return rollback(fmt.Errorf("failed to create part for %s: %w", part.Project, err))
+		}
+		created = append(created, *newEntry)
+	}
+
+	if remainder == 0 {
+		if err := changes.delete(*entry); err != nil {
+			return rollback(fmt.Errorf("failed to delete original entry: %w", err))
+		}
+		return nil
+	}
+
+	update, err := durationUpdate(*entry, float64(remainder)/60)
+	if err != nil {
+		return rollback(err)
+	}
+	if _, err := changes.update(*entry, update); err != nil {
+		return rollback(fmt.Errorf("failed to update original entry: %w", err))
+	}
+
+	return nil
+}
+
+// formatClock renders minutes since midnight as HH:mm
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// projectLabel names an entry's project, which may be empty
+func projectLabel(project string) string {
+	if project == "" {
+		return "(no project)"
+	}
+	return project
+}
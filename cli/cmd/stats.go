@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/stats"
+)
+
+// statsTopProjects is how many of this month's projects are listed
+const statsTopProjects = 5
+
+var statsDays int
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show working-pattern statistics",
+	Long: `Show how you work over the last days: when your day usually starts and
+ends, average hours per weekday, your longest and current streaks of tracked
+days, and this month's most-worked projects.
+
+Entries without a start time (e.g. duration-only imports) count towards
+hours and streaks but not towards the start and end times. Weekends without
+entries don't break a streak.
+
+Examples:
+  timetracker stats
+  timetracker stats --days 30`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statsDays < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+
+		now := time.Now()
+		to := dates.StartOfDay(now)
+		from := to.AddDate(0, 0, -(statsDays - 1))
+
+		// This month's projects are shown even when the window is shorter
+		fetchFrom := from
+		if monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()); monthStart.Before(from) {
+			fetchFrom = monthStart
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.GetEntries(dates.Format(fetchFrom), dates.Format(to))
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		report := stats.Compute(entries, from, to, now)
+
+		display.Printf("\n📈 Stats: %s to %s (%d days)\n\n", dates.Format(from), dates.Format(to), statsDays)
+		display.Printf("  Hours:         %s in %d entries\n", display.FormatHours(report.TotalHours), report.Entries)
+		display.Printf("  Tracked days:  %d\n", report.TrackedDays)
+		if report.TrackedDays > 0 {
+			display.Printf("  Per day:       %s on tracked days\n", display.FormatDuration(report.TotalHours/float64(report.TrackedDays)))
+		}
+
+		display.Println("\n🕘 Time of day:")
+		if report.TimedDays == 0 {
+			display.Println("  No entries with start times in this range.")
+		} else {
+			display.Printf("  Average start: %s\n", formatClock(int(report.AverageStart.Minutes())))
+			display.Printf("  Average end:   %s\n", formatClock(int(report.AverageEnd.Minutes())))
+			if report.TimedDays == 1 {
+				display.Print("  Based on 1 day")
+			} else {
+				display.Printf("  Based on %d days", report.TimedDays)
+			}
+			if report.UntimedEntries > 0 {
+				display.Printf("; %d entries without start times left out", report.UntimedEntries)
+			}
+			display.Println()
+		}
+
+		display.Println("\n📅 Weekdays:")
+		display.Println()
+		table := display.NewTable("Day", "Hours", "Tracked", "Average")
+		for _, weekday := range report.Weekdays {
+			table.AddRow(weekday.Day.String(),
+				display.FormatHours(weekday.Hours),
+				fmt.Sprintf("%d of %d", weekday.TrackedDays, weekday.Occurrences),
+				display.FormatHours(weekday.Average),
+			)
+		}
+		table.Print()
+
+		display.Println("\n🔥 Streaks:")
+		display.Printf("  Longest:       %s\n", formatStreak(report.LongestStreak))
+		display.Printf("  Current:       %s\n", formatStreak(report.CurrentStreak))
+
+		display.Printf("\n🏆 Projects in %s:\n", now.Format("January"))
+		if len(report.Projects) == 0 {
+			display.Println("  No entries this month.")
+		} else if display.OutputFormat() == display.FormatTable {
+			// csv and tsv hold the weekday table only
+			display.Println()
+			table := display.NewTable("#", "Project", "Hours")
+			for i, project := range report.Projects {
+				if i == statsTopProjects {
+					break
+				}
+				table.AddRow(strconv.Itoa(i+1), projectLabel(project.Name), display.FormatHours(project.Hours))
+			}
+			table.Print()
+		}
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().IntVar(&statsDays, "days", 90, "Number of days to analyse, including today")
+}
+
+// formatStreak describes a streak like "12 days (2024-03-01 to 2024-03-18)"
+func formatStreak(streak stats.Streak) string {
+	switch streak.Days {
+	case 0:
+		return "none"
+	case 1:
+		return "1 day (" + dates.Format(streak.Start) + ")"
+	}
+	return fmt.Sprintf("%d days (%s to %s)", streak.Days, dates.Format(streak.Start), dates.Format(streak.End))
+}
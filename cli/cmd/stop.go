@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+var (
+	stopEnd     string
+	stopDiscard bool
+)
+
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the timer started by continue and log it",
+	Long: `Stop the timer started by 'timetracker continue' and add it as a manual
+entry from its start until now, or until --end.
+
+A timer can't run past midnight; log such time with 'timetracker add' and
+drop the timer with --discard.
+
+Examples:
+  timetracker stop
+  timetracker stop --end 17:30
+  timetracker stop --discard`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openTimerStore()
+		if err != nil {
+			return fmt.Errorf("failed to open the timer: %w", err)
+		}
+
+		if stopDiscard {
+			timer, ok := store.Get(runningTimerKey)
+			if !ok {
+				return fmt.Errorf("no timer is running")
+			}
+			store.Delete(runningTimerKey)
+			if err := store.Save(); err != nil {
+				return fmt.Errorf("failed to save the timer: %w", err)
+			}
+			display.Printf("🗑️  Discarded the timer for %s since %s\n", timerLabel(timer), timer.Start)
+			return nil
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		timer, end, hours, err := stopTimer(client, store, stopEnd, time.Now())
+		if err != nil {
+			return err
+		}
+
+		display.Printf("⏹️  Logged %s on %s (%s-%s) to %s\n", display.FormatDuration(hours), timer.Date, timer.Start, end, timerLabel(timer))
+		return nil
+	},
+}
+
+// stopTimer adds the running timer as an entry ending at end (HH:mm), or
+// now if end is empty, and removes it. It returns the timer, the end time
+// and the hours logged.
+func stopTimer(client *api.Client, store *localdata.Store[runningTimer], end string, now time.Time) (runningTimer, string, float64, error) {
+	timer, ok := store.Get(runningTimerKey)
+	if !ok {
+		return runningTimer{}, "", 0, fmt.Errorf("no timer is running; start one with 'timetracker continue'")
+	}
+	if timer.Date != dates.Format(now) {
+		return runningTimer{}, "", 0, fmt.Errorf("the timer started on %s at %s and can't run past midnight; log the time with 'timetracker add' and run 'timetracker stop --discard'", timer.Date, timer.Start)
+	}
+
+	if end == "" {
+		end = now.Format("15:04")
+	}
+	hours, err := clockDuration(timer.Start, end)
+	if err != nil {
+		return runningTimer{}, "", 0, err
+	}
+
+	hours, err = addEntry(client, api.CreateEntryRequest{
+		Date:        timer.Date,
+		StartTime:   timer.Start,
+		EndTime:     end,
+		Project:     timer.Project,
+		Description: timer.Description,
+	}, hours)
+	if err != nil {
+		return runningTimer{}, "", 0, fmt.Errorf("failed to add entry: %w", err)
+	}
+
+	store.Delete(runningTimerKey)
+	if err := store.Save(); err != nil {
+		return runningTimer{}, "", 0, fmt.Errorf("the entry was added, but the timer couldn't be removed: %w", err)
+	}
+	return timer, end, hours, nil
+}
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+
+	stopCmd.Flags().StringVar(&stopEnd, "end", "", "End time (HH:mm; default: now)")
+	stopCmd.Flags().BoolVar(&stopDiscard, "discard", false, "Drop the timer without logging it")
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+const (
+	// maxDayHours and maxWeekHours bound what a submitted week may contain
+	maxDayHours  = 24
+	maxWeekHours = 80
+
+	// submissionStatusLimit is how many submissions --status lists
+	submissionStatusLimit = 10
+)
+
+var (
+	submitWeek      string
+	submitAllowGaps bool
+	submitYes       bool
+	submitStatus    bool
+)
+
+// submitCmd represents the submit command
+var submitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Submit a week's timesheet for approval",
+	Long: `Validate a week, show its summary for confirmation, and submit it. The
+server locks the week's entries once submitted.
+
+Timesheets are ISO weeks (Monday to Sunday) whatever week_start is set to,
+since that is how the server files and locks them.
+
+A week is rejected if a workday (Mon-Fri) has no hours (unless --allow-gaps),
+if any day has more than 24 hours, or if the week has no hours or more than 80.
+
+Examples:
+  timetracker submit                    # this week
+  timetracker submit --week 2024-W14
+  timetracker submit --week 2024-W14 --allow-gaps --yes
+  timetracker submit --status`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if submitStatus {
+			return printSubmissions(client)
+		}
+
+		// Submissions are keyed by ISO week, so week_start doesn't apply
+		monday := dates.StartOfWeek(time.Now(), time.Monday)
+		if submitWeek != "" {
+			monday, err = dates.ParseISOWeek(submitWeek, time.Local)
+			if err != nil {
+				return err
+			}
+		}
+		week := dates.ISOWeek(monday)
+		from, to := dates.Format(monday), dates.Format(monday.AddDate(0, 0, 6))
+
+		entries, err := client.GetEntries(from, to)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		var daily [7]float64
+		var total float64
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			day, err := time.ParseInLocation(dates.Layout, entry.Day(), time.Local)
+			if err != nil {
+				continue
+			}
+			index := int(day.Sub(monday).Hours()/24 + 0.5)
+			if index < 0 || index > 6 {
+				continue
+			}
+			daily[index] += entry.Duration
+			total += entry.Duration
+			ids = append(ids, entry.ID)
+		}
+
+		display.Printf("\n📋 Timesheet %s (%s to %s)\n\n", week, from, to)
+		table := display.NewTable("Day", "Date", "Hours")
+		for i, hours := range daily {
+			day := monday.AddDate(0, 0, i)
+			table.AddRow(day.Format("Mon"), dates.Format(day), display.FormatHours(hours))
+		}
+		table.AddRow("Total", "", display.FormatHours(total))
+		table.Print()
+		display.Println()
+
+		if problems := validateTimesheet(monday, daily, total, submitAllowGaps); len(problems) > 0 {
+			display.Println("❌ The week can't be submitted:")
+			for _, problem := range problems {
+				display.Printf("  • %s\n", problem)
+			}
+			display.Println()
+			return fmt.Errorf("%s failed validation", week)
+		}
+
+		if !submitYes && !confirm(fmt.Sprintf("Submit %s (%s)? The entries will be locked. [y/N]: ", week, display.FormatDuration(total))) {
+			display.Println("Cancelled.")
+			return nil
+		}
+
+		submission, err := client.SubmitTimesheet(api.SubmitTimesheetRequest{
+			Week:       week,
+			From:       from,
+			To:         to,
+			TotalHours: total,
+			EntryIDs:   ids,
+		})
+		if isNotFound(err) {
+			return fmt.Errorf("the server does not support timesheet submissions")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to submit %s: %w", week, err)
+		}
+
+		display.Printf("\n✓ %s submitted (status: %s)\n\n", submission.Week, strings.ToLower(submission.Status))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(submitCmd)
+
+	submitCmd.Flags().StringVar(&submitWeek, "week", "", "ISO week to submit, e.g. 2024-W14 (default: this week)")
+	submitCmd.Flags().BoolVar(&submitAllowGaps, "allow-gaps", false, "Allow workdays without hours")
+	submitCmd.Flags().BoolVarP(&submitYes, "yes", "y", false, "Submit without asking for confirmation")
+	submitCmd.Flags().BoolVar(&submitStatus, "status", false, "List recent submissions and their approval state")
+}
+
+// validateTimesheet returns the reasons a week can't be submitted
+func validateTimesheet(monday time.Time, daily [7]float64, total float64, allowGaps bool) []string {
+	var problems []string
+
+	for i, hours := range daily {
+		day := monday.AddDate(0, 0, i)
+		weekend := day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+		switch {
+		case hours <= 0 && !weekend && !allowGaps:
+			problems = append(problems, fmt.Sprintf("%s %s has no hours (use --allow-gaps if intended)", day.Format("Mon"), dates.Format(day)))
+		case hours > maxDayHours:
+			problems = append(problems, fmt.Sprintf("%s %s has %s, more than %dh", day.Format("Mon"), dates.Format(day), display.FormatDuration(hours), maxDayHours))
+		}
+	}
+
+	if total <= 0 {
+		problems = append(problems, "the week has no hours")
+	} else if total > maxWeekHours {
+		problems = append(problems, fmt.Sprintf("the week has %s, more than %dh", display.FormatDuration(total), maxWeekHours))
+	}
+
+	return problems
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// printSubmissions lists the most recent submissions
+func printSubmissions(client *api.Client) error {
+	submissions, err := client.GetTimesheetSubmissions(submissionStatusLimit)
+	if isNotFound(err) {
+		return fmt.Errorf("the server does not support timesheet submissions")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch submissions: %w", err)
+	}
+
+	if len(submissions) == 0 {
+		display.Println("\nNo timesheets submitted yet.")
+		display.Println()
+		return nil
+	}
+
+	display.Println("\n📋 Recent submissions:")
+	display.Println()
+	table := display.NewTable("Week", "Hours", "Status", "Submitted", "Comment")
+	for _, s := range submissions {
+		submitted := s.SubmittedAt
+		if t, err := time.Parse(time.RFC3339, s.SubmittedAt); err == nil {
+			submitted = t.Local().Format("2006-01-02 15:04")
+		}
+		table.AddRow(s.Week, display.FormatHours(s.TotalHours), strings.ToLower(s.Status), submitted, s.Comment)
+	}
+	table.Print()
+	display.Println()
+
+	return nil
+}
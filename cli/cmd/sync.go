@@ -1,15 +1,52 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vmiller/timetracker-cli/internal/api"
 	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/logging"
 )
 
-var forceSync bool
+var (
+	forceSync         bool
+	syncProviderNames []string
+	syncFrom          string
+	syncTo            string
+	syncSince         string
+	syncDryRun        bool
+	syncTimeout       time.Duration
+	syncAsync         bool
+	syncWait          bool
+)
+
+// Exit codes of sync. With --quiet, a sync where some providers failed exits
+// with exitSyncPartial and any other failure with exitSyncFailed.
+const (
+	exitSyncPartial     = 1
+	exitSyncFailed      = 2
+	exitSyncInterrupted = exitInterrupted
+)
+
+// Backoff between sync attempts with --retry: syncRetryMin before the first
+// retry, then twice as long each time up to syncRetryMax, less up to half
+// as jitter so cron jobs on many machines don't retry in step
+var (
+	syncRetryMin = 2 * time.Second
+	syncRetryMax = 30 * time.Second
+)
 
 // syncCmd represents the sync command
 var syncCmd = &cobra.Command{
@@ -19,24 +56,363 @@ var syncCmd = &cobra.Command{
   - Toggl (if configured)
   - Tempo (if configured)
 
-Use --force to force a full refresh instead of incremental sync.`,
+Use --force to force a full refresh instead of incremental sync. With --output
+json, the sync result is written as JSON and no spinner is shown.
+
+Use --provider to only sync some providers; it can be repeated. The names are
+checked against the server's providers before syncing, and --force then only
+refreshes the named providers.
+
+Use --from and --to to backfill a date range, for example after a provider's
+token had expired, without the full re-import of --force. --to defaults to
+today; neither may be in the future, and the server caps how many days one
+sync may cover. --since backfills a window up to today instead, e.g. --since
+72h or --since 3d from the day 72 hours or 3 days ago, in the configured
+timezone.
+
+Use --dry-run to preview a sync without changing anything: each provider's
+entries are fetched and compared, and the counts of entries that would be
+imported, updated or skipped are shown with a sample of the new ones. The
+preview exits with 1 if any provider failed.
+
+With --quiet, for cron jobs, nothing but a single line is printed on success:
+  synced imported=12 skipped=40 providers=toggl,tempo duration=3.2s
+Errors go to stderr, and the exit code is 0 if every provider synced, 1 if
+some failed and 2 if the sync failed altogether. The spinner is only shown
+when stdout is a terminal.
+
+Where the server streams progress, a line per provider shows what it has
+fetched and imported so far. Otherwise, servers that can run syncs in the
+background get the sync as a job, whose per-provider progress is shown until
+it finishes, so long syncs don't run into proxy timeouts. --async starts the
+job, prints its ID and exits instead; check on it with
+'timetracker sync status <job-id>'.
+
+The CLI gives up waiting for the server after --timeout (default 5m, 0 waits
+forever); Ctrl+C stops waiting right away. Either way the server may still
+finish the sync.
+
+With --retry N (or sync.retries in the config file), a sync that fails with
+a network error or a 502, 503 or 504 response, e.g. while the server
+restarts, is tried up to N more times, waiting longer between attempts.
+Other errors, such as 400 or 401 responses, fail right away.
+
+Examples:
+  timetracker sync --force --dry-run
+  timetracker sync --force --async
+  timetracker sync --provider toggl
+  timetracker sync --from 2024-02-05 --to 2024-02-18 --provider toggl
+  timetracker sync --since 3d
+  timetracker sync --provider toggl --provider tempo --force
+  timetracker sync --quiet --retry 3`,
+	Annotations:  outputFormats(display.FormatJSON),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load config
-		cfg, err := config.Load()
+		err := runSync(cmd)
+		var exit *exitError
+		if err != nil && logging.IsQuiet() && !errors.As(err, &exit) {
+			return withExitCode(exitSyncFailed, err)
+		}
+		return err
+	},
+}
+
+// runSync triggers a sync and prints its results
+func runSync(cmd *cobra.Command) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create API client
+	client := newClient(cmd.Context(), cfg)
+
+	// Check if logged in
+	if cfg.AccessToken == "" && cfg.RefreshToken == "" {
+		return fmt.Errorf("not logged in. Run 'timetracker login' first")
+	}
+
+	if syncTimeout < 0 {
+		return fmt.Errorf("--timeout can't be negative")
+	}
+	if syncAsync && cmd.Flags().Changed("wait") && syncWait {
+		return fmt.Errorf("use either --async or --wait")
+	}
+	if syncSince != "" && (syncFrom != "" || syncTo != "") {
+		return fmt.Errorf("--since can't be combined with --from or --to; use either a window or a date range")
+	}
+	if syncSince != "" && forceSync {
+		return fmt.Errorf("--since can't be combined with --force; --force re-imports everything, --since only the window")
+	}
+	detach := syncAsync || !syncWait
+	retries, err := syncRetries(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C or the timeout stops waiting for the server
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	interrupted := ctx
+
+	var settings *api.SyncSettings
+	var providers []string
+	err = retryTransient(interrupted, retries, func() error {
+		var err error
+		if settings, err = syncSettings(client); err != nil {
+			return err
+		}
+		providers, err = syncProviders(client, syncProviderNames)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	from, to, err := syncRange(settings, time.Now())
+	if err != nil {
+		return err
+	}
+	// Older servers would ignore dryRun and async and sync right away
+	if syncDryRun && (settings == nil || !settings.DryRun) {
+		return fmt.Errorf("the server does not support previewing a sync")
+	}
+	background := settings != nil && settings.Async
+	if detach && !background {
+		return fmt.Errorf("the server can't run syncs in the background")
+	}
+	opts := api.SyncOptions{
+		Force:     forceSync,
+		Providers: providers,
+		From:      from,
+		To:        to,
+		DryRun:    syncDryRun,
+	}
+
+	if syncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, syncTimeout)
+		defer cancel()
+	}
+	// Syncing takes as long as it takes; --timeout bounds it, not the
+	// client's timeout for single requests
+	ctx = api.WithTimeout(ctx, 0)
+
+	if detach {
+		var job *api.SyncJob
+		err := retryTransient(ctx, retries, func() error {
+			var err error
+			job, err = client.StartSync(ctx, opts)
+			return err
+		})
 		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+			return fmt.Errorf("failed to start sync: %w", err)
+		}
+		if logging.IsQuiet() && !display.JSON() {
+			fmt.Println(job.ID)
 		}
+		display.Printf("🚀 Sync started in the background as job %s\n", job.ID)
+		display.Printf("Check on it with 'timetracker sync status %s'.\n", job.ID)
+		return display.Result(job)
+	}
 
-		// Check if logged in
-		if cfg.AccessToken == "" && cfg.RefreshToken == "" {
-			return fmt.Errorf("not logged in. Run 'timetracker login' first")
+	// Trigger sync, following the server's progress where it streams it
+	started := time.Now()
+	var syncResp *api.SyncResponse
+	var jobID string
+	err = retryTransient(ctx, retries, func() error {
+		var err error
+		syncResp, err = streamSync(ctx, client, opts)
+		if isNotFound(err) {
+			syncResp, jobID, err = waitForSync(ctx, client, opts, background)
 		}
+		if err != nil && jobID != "" {
+			// The job runs on; a retry would start another
+			return finalAttempt{err}
+		}
+		return err
+	})
+
+	cancelled := interrupted.Err() != nil
+	stop()
 
-		// Create API client
-		client := api.NewClient(cfg)
+	switch {
+	case err != nil && jobID != "" && ctx.Err() != nil:
+		err = fmt.Errorf("stopped waiting for sync job %s; it continues on the server, check on it with 'timetracker sync status %s'", jobID, jobID)
+		if cancelled {
+			return withExitCode(exitSyncInterrupted, err)
+		}
+		return err
+	case err != nil && cancelled:
+		return withExitCode(exitSyncInterrupted,
+			errors.New("sync cancelled; the CLI stopped waiting, but the server may still complete it"))
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("sync timed out after %s; the server may still complete it (use --timeout to wait longer)", syncTimeout)
+	case err != nil:
+		return fmt.Errorf("sync failed: %w", err)
+	}
 
-		// Show spinner (simple text-based animation)
-		done := make(chan bool)
+	// Older servers sync every provider; only show those asked for
+	if len(providers) > 0 {
+		syncResp.Results = requestedResults(syncResp.Results, providers)
+	}
+
+	if syncResp.From != "" {
+		from, to = syncResp.From, syncResp.To
+	}
+	if syncDryRun {
+		return printSyncPreview(syncResp, from, to)
+	}
+	if logging.IsQuiet() {
+		return printSyncLine(syncResp, time.Since(started))
+	}
+
+	// Display results
+	if syncResp.Success {
+		display.Print("✓ Sync completed successfully!\n\n")
+	} else {
+		display.Print("⚠️  Sync completed with errors\n\n")
+	}
+
+	if from != "" {
+		display.Printf("📅 Range: %s to %s\n", from, to)
+	}
+
+	display.Printf("📥 Imported: %d entries\n", syncResp.TotalImported)
+	display.Printf("⏭️  Skipped: %d entries\n\n", syncResp.TotalSkipped)
+
+	// Show per-provider results
+	display.Println("Provider Results:")
+	for _, result := range syncResp.Results {
+		if result.Success {
+			display.Printf("  ✓ %-8s imported: %d, skipped: %d\n",
+				result.Provider+":",
+				result.Imported,
+				result.Skipped)
+		} else {
+			display.Printf("  ✗ %-8s %s\n",
+				result.Provider+":",
+				result.Error)
+		}
+	}
+
+	display.Println()
+
+	return display.Result(syncResp)
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	// Add force flag
+	syncCmd.Flags().BoolVarP(&forceSync, "force", "f", false, "Force a full refresh (ignores last sync time)")
+	syncCmd.Flags().StringSliceVar(&syncProviderNames, "provider", nil, "Only sync this provider, e.g. toggl (repeatable)")
+	syncCmd.Flags().StringVar(&syncFrom, "from", "", "Only fetch entries from this date on (YYYY-MM-DD)")
+	syncCmd.Flags().StringVar(&syncTo, "to", "", "Only fetch entries up to this date (default: today)")
+	syncCmd.Flags().StringVar(&syncSince, "since", "", "Only fetch entries from this long ago up to today, e.g. 72h or 3d")
+	syncCmd.Flags().DurationVar(&syncTimeout, "timeout", 5*time.Minute, "How long to wait for the server (0 waits forever)")
+	syncCmd.Flags().BoolVar(&syncAsync, "async", false, "Start the sync in the background, print its job ID and exit")
+	syncCmd.Flags().BoolVar(&syncWait, "wait", true, "Wait for a background sync to finish, showing its progress")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Preview what a sync would import without changing anything")
+	syncCmd.Flags().Int("retry", 0, "Retry up to N times after network errors or 502, 503 or 504 responses (default: sync.retries from the config file)")
+}
+
+// finalAttempt marks an error that must not be retried even if it looks
+// transient
+type finalAttempt struct{ err error }
+
+func (f finalAttempt) Error() string { return f.err.Error() }
+func (f finalAttempt) Unwrap() error { return f.err }
+
+// retryTransient calls fn until it succeeds, fails with an error a retry
+// can't fix, or has been retried retries times, backing off in between. A
+// final transient error tells how many attempts were made.
+func retryTransient(ctx context.Context, retries int, fn func() error) error {
+	delay := syncRetryMin
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		var final finalAttempt
+		if errors.As(err, &final) {
+			return final.err
+		}
+		if !api.IsTransient(err) {
+			return err
+		}
+		if attempt > retries {
+			if attempt > 1 {
+				return fmt.Errorf("%w (gave up after %d attempts)", err, attempt)
+			}
+			return err
+		}
+
+		wait := delay - time.Duration(rand.Int63n(int64(delay/2)+1))
+		logging.Verbosef("↻ Attempt %d of %d failed: %v; retrying in %s", attempt, retries+1, err, wait.Round(100*time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		if delay *= 2; delay > syncRetryMax {
+			delay = syncRetryMax
+		}
+	}
+}
+
+// streamSync syncs while showing a progress line per provider, redrawn in
+// place on terminals. Returns ErrNotFound if the server can't stream
+// progress.
+func streamSync(ctx context.Context, client *api.Client, opts api.SyncOptions) (*api.SyncResponse, error) {
+	var lines *display.StatusLines
+	if !display.JSON() && display.AnimationEnabled(os.Stdout) {
+		lines = display.NewStatusLines()
+		defer lines.Clear()
+	}
+
+	statuses := map[string]string{}
+	return client.SyncStream(ctx, opts, func(progress api.SyncJobProvider) {
+		if lines != nil {
+			lines.Set(progress.Provider, streamLine(progress))
+		} else if statuses[progress.Provider] != progress.Status {
+			// Without redrawing, only show when a provider starts or ends
+			statuses[progress.Provider] = progress.Status
+			display.Printf("  %s\n", streamLine(progress))
+		}
+	})
+}
+
+// streamLine describes a provider's progress, e.g.
+// "toggl: fetched 240, imported 31…"
+func streamLine(progress api.SyncJobProvider) string {
+	name := strings.ToLower(progress.Provider)
+	switch progress.Status {
+	case api.SyncJobPending:
+		return name + ": waiting"
+	case api.SyncJobDone:
+		return fmt.Sprintf("%s: ✓ fetched %d, imported %d", name, progress.Fetched, progress.Imported)
+	case api.SyncJobFailed:
+		return fmt.Sprintf("%s: ✗ %s", name, progress.Error)
+	}
+	if progress.Fetched == 0 {
+		return name + ": fetching…"
+	}
+	return fmt.Sprintf("%s: fetched %d, imported %d…", name, progress.Fetched, progress.Imported)
+}
+
+// waitForSync syncs behind a spinner, as a background job where the server
+// supports it so long syncs don't run into proxy timeouts. It returns the
+// job's ID, if any, with the result.
+func waitForSync(ctx context.Context, client *api.Client, opts api.SyncOptions, background bool) (*api.SyncResponse, string, error) {
+	what := "providers"
+	if len(opts.Providers) > 0 {
+		what = strings.Join(opts.Providers, ", ")
+	}
+	var label atomic.Value
+	label.Store("Syncing from " + what + "...")
+
+	// Show spinner (simple text-based animation) when stdout is a terminal
+	spin := !display.JSON() && display.AnimationEnabled(os.Stdout)
+	done := make(chan bool)
+	if spin {
 		go func() {
 			spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 			i := 0
@@ -45,64 +421,282 @@ Use --force to force a full refresh instead of incremental sync.`,
 				case <-done:
 					return
 				default:
-					fmt.Printf("\r%s Syncing from providers...", spinner[i%len(spinner)])
+					display.Printf("\r\033[K%s %s", spinner[i%len(spinner)], label.Load())
 					i++
 					time.Sleep(100 * time.Millisecond)
 				}
 			}
 		}()
+	}
 
-		// Trigger sync
-		endpoint := "/api/sync"
-		if forceSync {
-			endpoint += "?force=true"
+	var resp *api.SyncResponse
+	var jobID string
+	var err error
+	if background {
+		var job *api.SyncJob
+		job, err = client.StartSync(ctx, opts)
+		if err == nil {
+			jobID = job.ID
+			last := ""
+			resp, err = client.WaitForSyncJob(ctx, job, func(job *api.SyncJob) {
+				progress := syncJobProgress(job.Providers)
+				if progress == "" || progress == last {
+					return
+				}
+				last = progress
+				if spin {
+					label.Store("Syncing: " + progress)
+				} else {
+					display.Printf("⏳ %s\n", progress)
+				}
+			})
 		}
+	} else {
+		resp, err = client.Sync(ctx, opts)
+	}
 
-		var syncResp api.SyncResponse
-		err = client.Post(endpoint, nil, &syncResp)
-
-		// Stop spinner
+	// Stop spinner
+	if spin {
 		done <- true
-		fmt.Print("\r") // Clear spinner line
+		display.Print("\r\033[K") // Clear spinner line
+	}
+	return resp, jobID, err
+}
 
-		if err != nil {
-			return fmt.Errorf("sync failed: %w", err)
+// printSyncLine prints a finished sync as one line for logs, and its failed
+// providers on stderr
+func printSyncLine(resp *api.SyncResponse, elapsed time.Duration) error {
+	var synced, failed []string
+	for _, result := range resp.Results {
+		provider := strings.ToLower(result.Provider)
+		if result.Success {
+			synced = append(synced, provider)
+		} else {
+			failed = append(failed, provider)
+			fmt.Fprintf(os.Stderr, "Error: sync failed for %s: %s\n", provider, result.Error)
 		}
+	}
+	total := len(failed) > 0 && len(synced) == 0
 
-		// Display results
-		if syncResp.Success {
-			fmt.Println("✓ Sync completed successfully!\n")
-		} else {
-			fmt.Println("⚠️  Sync completed with errors\n")
-		}
-
-		fmt.Printf("📥 Imported: %d entries\n", syncResp.TotalImported)
-		fmt.Printf("⏭️  Skipped: %d entries\n\n", syncResp.TotalSkipped)
-
-		// Show per-provider results
-		fmt.Println("Provider Results:")
-		for _, result := range syncResp.Results {
-			if result.Success {
-				fmt.Printf("  ✓ %-8s imported: %d, skipped: %d\n",
-					result.Provider+":",
-					result.Imported,
-					result.Skipped)
-			} else {
-				fmt.Printf("  ✗ %-8s %s\n",
-					result.Provider+":",
-					result.Error)
+	if !total && !display.JSON() {
+		fmt.Printf("synced imported=%d skipped=%d providers=%s duration=%.1fs\n",
+			resp.TotalImported, resp.TotalSkipped, strings.Join(synced, ","), elapsed.Seconds())
+	}
+	if err := display.Result(resp); err != nil {
+		return err
+	}
+	switch {
+	case total:
+		return silentExit(exitSyncFailed)
+	case len(failed) > 0:
+		return silentExit(exitSyncPartial)
+	}
+	return nil
+}
+
+// printSyncPreview shows what a dry-run sync would change and fails if any
+// provider couldn't be previewed
+func printSyncPreview(resp *api.SyncResponse, from, to string) error {
+	display.Print("🔍 Sync preview - nothing was imported\n\n")
+	if from != "" {
+		display.Printf("📅 Range: %s to %s\n\n", from, to)
+	}
+
+	var failed []string
+	var sample []api.SyncResult
+	display.Println("Provider Results:")
+	for _, result := range resp.Results {
+		if !result.Success {
+			failed = append(failed, result.Provider)
+			display.Printf("  ✗ %-8s %s\n", result.Provider+":", result.Error)
+			continue
+		}
+		display.Printf("  ✓ %-8s would import: %d, update: %d, skip: %d\n",
+			result.Provider+":", result.WouldImport, result.WouldUpdate, result.WouldSkip)
+		if len(result.Sample) > 0 {
+			sample = append(sample, result)
+		}
+	}
+	display.Println()
+
+	if len(sample) > 0 {
+		table := display.NewTable("Date", "Project", "Description", "Hours")
+		table.FitWidth(display.TerminalWidth(), 2)
+		for _, result := range sample {
+			title := fmt.Sprintf("%s: new entries", result.Provider)
+			if len(result.Sample) < result.WouldImport {
+				title = fmt.Sprintf("%s: %d of %d new entries", result.Provider, len(result.Sample), result.WouldImport)
+			}
+			table.AddSection(title)
+			for _, entry := range result.Sample {
+				table.AddCells(display.Text(entry.Date), display.Text(projectLabel(entry.Project)),
+					display.Text(strings.Join(strings.Fields(entry.Description), " ")), display.Hours(entry.Duration))
 			}
 		}
+		table.Print()
+		display.Println()
+	}
 
-		fmt.Println()
+	if err := display.Result(resp); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		err := fmt.Errorf("preview failed for %s", strings.Join(failed, ", "))
+		if len(failed) < len(resp.Results) {
+			return withExitCode(exitSyncPartial, err)
+		}
+		return err
+	}
+	return nil
+}
 
-		return nil
-	},
+// syncSettings returns what sync requests the server supports, or nil for
+// servers that only sync everything right away
+func syncSettings(client *api.Client) (*api.SyncSettings, error) {
+	settings, err := client.GetSyncSettings()
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check sync settings: %w", err)
+	}
+	return settings, nil
 }
 
-func init() {
-	rootCmd.AddCommand(syncCmd)
+// syncJobProgress condenses a background sync's providers to e.g.
+// "TOGGL ✓ 4 imported, TEMPO running"
+func syncJobProgress(providers []api.SyncJobProvider) string {
+	parts := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		switch provider.Status {
+		case api.SyncJobDone:
+			parts = append(parts, fmt.Sprintf("%s ✓ %d imported", provider.Provider, provider.Imported))
+		case api.SyncJobFailed:
+			parts = append(parts, provider.Provider+" ✗")
+		case api.SyncJobPending:
+			parts = append(parts, provider.Provider+" waiting")
+		default:
+			parts = append(parts, provider.Provider+" "+provider.Status)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
 
-	// Add force flag
-	syncCmd.Flags().BoolVarP(&forceSync, "force", "f", false, "Force a full refresh (ignores last sync time)")
+// syncRange validates --from and --to, or --since, against today and the
+// server's maximum range, and returns them as YYYY-MM-DD, or empty strings
+// without either
+func syncRange(settings *api.SyncSettings, now time.Time) (string, string, error) {
+	var from time.Time
+	to := dates.StartOfDay(now)
+	switch {
+	case syncSince != "":
+		window, err := dates.ParseDuration(syncSince)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid --since: %w", err)
+		}
+		if window <= 0 {
+			return "", "", fmt.Errorf("--since must be positive")
+		}
+		from = dates.StartOfDay(now.Add(-window))
+	case syncFrom == "":
+		if syncTo != "" {
+			return "", "", fmt.Errorf("--to needs --from")
+		}
+		return "", "", nil
+	default:
+		var err error
+		if from, err = dates.Parse(syncFrom, now); err != nil {
+			return "", "", err
+		}
+		if syncTo != "" {
+			if to, err = dates.Parse(syncTo, now); err != nil {
+				return "", "", err
+			}
+		}
+	}
+	if to.Before(from) {
+		return "", "", fmt.Errorf("--to is before --from")
+	}
+	if to.After(now) {
+		return "", "", fmt.Errorf("can't sync %s, it's in the future", dates.Format(to))
+	}
+
+	if settings == nil {
+		return "", "", fmt.Errorf("the server does not support syncing a date range")
+	}
+	days := int(to.Sub(from).Hours()/24+0.5) + 1
+	if settings.MaxRangeDays > 0 && days > settings.MaxRangeDays {
+		given := fmt.Sprintf("--from %s --to %s", dates.Format(from), dates.Format(to))
+		if syncSince != "" {
+			given = fmt.Sprintf("--since %s (%s to %s)", syncSince, dates.Format(from), dates.Format(to))
+		}
+		return "", "", fmt.Errorf("%s covers %d days, the server allows at most %d", given, days, settings.MaxRangeDays)
+	}
+	return dates.Format(from), dates.Format(to), nil
+}
+
+// syncProviders checks the --provider names against the server's providers
+// and returns them lowercased, without duplicates. Servers without a provider
+// list get the names unchecked.
+func syncProviders(client *api.Client, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var selected []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return nil, fmt.Errorf("--provider needs a provider name")
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, name)
+		}
+	}
+
+	statuses, err := client.GetProviderStatus()
+	if isNotFound(err) {
+		return selected, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch providers: %w", err)
+	}
+
+	configured := map[string]bool{}
+	var available []string
+	for _, status := range statuses {
+		if strings.EqualFold(status.Name, "MANUAL") {
+			continue // manual entries aren't synced
+		}
+		configured[strings.ToLower(status.Name)] = status.Configured
+		available = append(available, strings.ToLower(status.Name))
+	}
+	sort.Strings(available)
+
+	for _, name := range selected {
+		ok, known := configured[name]
+		if !known {
+			return nil, fmt.Errorf("unknown provider %q (available: %s)", name, strings.Join(available, ", "))
+		}
+		if !ok {
+			return nil, fmt.Errorf("provider %q is not configured on the server", name)
+		}
+	}
+	return selected, nil
+}
+
+// requestedResults keeps the results of the given lowercase providers
+func requestedResults(results []api.SyncResult, providers []string) []api.SyncResult {
+	var kept []api.SyncResult
+	for _, result := range results {
+		for _, name := range providers {
+			if strings.EqualFold(result.Provider, name) {
+				kept = append(kept, result)
+				break
+			}
+		}
+	}
+	return kept
 }
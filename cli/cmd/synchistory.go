@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var (
+	syncHistoryLast     int
+	syncHistoryProvider string
+)
+
+// syncHistoryCmd represents the sync history command
+var syncHistoryCmd = &cobra.Command{
+	Use:   "history [id]",
+	Short: "List recent sync runs, or show one in detail",
+	Long: `List recent sync runs with when they ran, what triggered them (manual,
+cron or CLI), how long they took and what each provider imported. Pass a
+run's ID to see it in detail, including the date range it covered.
+
+Examples:
+  timetracker sync history
+  timetracker sync history --last 5 --provider tempo
+  timetracker sync history 42`,
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			run, err := client.GetSyncRun(args[0])
+			if isNotFound(err) {
+				return fmt.Errorf("no sync run %s (or the server doesn't record sync history)", args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to fetch sync run: %w", err)
+			}
+			printSyncRun(run)
+			return nil
+		}
+
+		provider := strings.ToUpper(syncHistoryProvider)
+		runs, err := client.GetSyncHistory(syncHistoryLast, provider)
+		if isNotFound(err) {
+			return fmt.Errorf("the server does not record sync history")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch sync history: %w", err)
+		}
+
+		// The server may ignore the filters, so apply them again locally
+		if provider != "" {
+			runs = filterSyncRuns(runs, provider)
+		}
+		if syncHistoryLast > 0 && len(runs) > syncHistoryLast {
+			runs = runs[:syncHistoryLast]
+		}
+
+		if len(runs) == 0 {
+			display.Println("\nNo sync runs recorded.")
+			display.Println()
+			return nil
+		}
+
+		display.Println("\n🔄 Recent syncs:")
+		display.Println()
+		table := display.NewTable("ID", "Started", "Trigger", "Duration", "Imported", "Skipped", "Providers")
+		for _, run := range runs {
+			table.AddRow(run.ID,
+				formatTimestamp(run.StartedAt),
+				strings.ToLower(run.Trigger),
+				formatMillis(run.DurationMs),
+				strconv.Itoa(run.TotalImported),
+				strconv.Itoa(run.TotalSkipped),
+				providerSummary(run.Results),
+			)
+		}
+		table.Print()
+		display.Println("\nShow a run with 'timetracker sync history <id>'.")
+		display.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncHistoryCmd)
+
+	syncHistoryCmd.Flags().IntVarP(&syncHistoryLast, "last", "n", 10, "Number of runs to list")
+	syncHistoryCmd.Flags().StringVar(&syncHistoryProvider, "provider", "", "Only list runs involving this provider (toggl, tempo)")
+}
+
+// filterSyncRuns keeps the runs involving provider, reduced to its result
+func filterSyncRuns(runs []api.SyncRun, provider string) []api.SyncRun {
+	filtered := make([]api.SyncRun, 0, len(runs))
+	for _, run := range runs {
+		for _, result := range run.Results {
+			if strings.EqualFold(result.Provider, provider) {
+				run.Results = []api.SyncResult{result}
+				filtered = append(filtered, run)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// providerSummary condenses per-provider results to e.g. "TOGGL 8/2, TEMPO ✗"
+func providerSummary(results []api.SyncResult) string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Success {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", result.Provider, result.Imported, result.Skipped))
+		} else {
+			parts = append(parts, display.Red(result.Provider+" ✗"))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printSyncRun shows one sync run in detail
+func printSyncRun(run *api.SyncRun) {
+	status := "✓ succeeded"
+	if !run.Success {
+		status = "⚠️  completed with errors"
+	}
+
+	display.Printf("\n🔄 Sync run %s: %s\n\n", run.ID, status)
+	display.Printf("  Started:   %s\n", formatTimestamp(run.StartedAt))
+	if run.FinishedAt != "" {
+		display.Printf("  Finished:  %s\n", formatTimestamp(run.FinishedAt))
+	}
+	display.Printf("  Duration:  %s\n", formatMillis(run.DurationMs))
+	trigger := strings.ToLower(run.Trigger)
+	if run.Force {
+		trigger += " (full refresh)"
+	}
+	display.Printf("  Trigger:   %s\n", trigger)
+	switch {
+	case run.RangeStart != "" && run.RangeEnd != "":
+		display.Printf("  Covered:   %s to %s\n", run.RangeStart, run.RangeEnd)
+	case run.RangeStart != "":
+		display.Printf("  Covered:   since %s\n", run.RangeStart)
+	default:
+		display.Printf("  Covered:   not recorded\n")
+	}
+	display.Printf("  Imported:  %d entries\n", run.TotalImported)
+	display.Printf("  Skipped:   %d entries\n\n", run.TotalSkipped)
+
+	if len(run.Results) == 0 {
+		return
+	}
+	table := display.NewTable("Provider", "Status", "Imported", "Skipped", "Error")
+	for _, result := range run.Results {
+		state := display.Green("ok")
+		if !result.Success {
+			state = display.Red("failed")
+		}
+		table.AddRow(result.Provider, state, strconv.Itoa(result.Imported), strconv.Itoa(result.Skipped), result.Error)
+	}
+	table.Print()
+	display.Println()
+}
+
+// formatTimestamp renders an RFC 3339 timestamp in local time, or returns
+// it unchanged if it doesn't parse
+func formatTimestamp(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// formatMillis renders a duration in milliseconds like "1.2s" or "2m5s"
+func formatMillis(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
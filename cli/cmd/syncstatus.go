@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// syncStatusCmd represents the sync status command
+var syncStatusCmd = &cobra.Command{
+	Use:   "status <job-id>",
+	Short: "Show the progress of a background sync",
+	Long: `Show whether a sync started with 'timetracker sync --async' is still
+running, how far each provider has got and, once it is done, what it
+imported. The server keeps finished jobs for an hour.
+
+Examples:
+  timetracker sync status 3f2b9c1e-8d4a-4f7e-9a61-0c2d5e7b8a90`,
+	Annotations:  outputFormats(display.FormatJSON, display.FormatCSV, display.FormatTSV),
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		job, err := client.GetSyncJob(cmd.Context(), args[0])
+		if isNotFound(err) {
+			return fmt.Errorf("no sync job %s (it may have expired, or the server can't run syncs in the background)", args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch sync job: %w", err)
+		}
+
+		printSyncJob(job)
+		return display.Result(job)
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncStatusCmd)
+}
+
+// printSyncJob shows a background sync and its providers' progress
+func printSyncJob(job *api.SyncJob) {
+	status := "⏳ running"
+	switch job.Status {
+	case api.SyncJobDone:
+		status = "✓ done"
+		if job.Result != nil && !job.Result.Success {
+			status = "⚠️  done with errors"
+		}
+	case api.SyncJobFailed:
+		status = "✗ failed"
+	}
+
+	display.Printf("\n🔄 Sync job %s: %s\n\n", job.ID, status)
+	display.Printf("  Started:   %s\n", formatTimestamp(job.StartedAt))
+	if job.FinishedAt != "" {
+		display.Printf("  Finished:  %s\n", formatTimestamp(job.FinishedAt))
+	}
+	if job.Error != "" {
+		display.Printf("  Error:     %s\n", job.Error)
+	}
+	if job.Result != nil {
+		display.Printf("  Imported:  %d entries\n", job.Result.TotalImported)
+		display.Printf("  Skipped:   %d entries\n", job.Result.TotalSkipped)
+	}
+	display.Println()
+
+	if len(job.Providers) == 0 {
+		return
+	}
+	table := display.NewTable("Provider", "Status", "Imported", "Error")
+	for _, provider := range job.Providers {
+		state := provider.Status
+		switch provider.Status {
+		case api.SyncJobDone:
+			state = display.Green(state)
+		case api.SyncJobFailed:
+			state = display.Red(state)
+		}
+		table.AddRow(provider.Provider, state, strconv.Itoa(provider.Imported), provider.Error)
+	}
+	table.Print()
+	display.Println()
+}
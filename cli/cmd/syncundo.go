@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var syncUndoYes bool
+
+// syncUndoCmd represents the sync undo command
+var syncUndoCmd = &cobra.Command{
+	Use:   "undo [run-id]",
+	Short: "Delete the entries a sync imported",
+	Long: `Roll back a sync run by deleting the entries it imported, e.g. after a
+misconfigured provider imported junk. Without an ID the most recent run is
+undone; IDs are listed by 'timetracker sync history'.
+
+The entries to delete are listed first and you confirm before anything is
+removed. Imported entries that were changed after the sync are kept and
+listed separately.
+
+Examples:
+  timetracker sync undo
+  timetracker sync undo 42
+  timetracker sync undo 42 --yes`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var run *api.SyncRun
+		if len(args) == 1 {
+			run, err = client.GetSyncRun(args[0])
+			if isNotFound(err) {
+				return fmt.Errorf("no sync run %s (or the server doesn't record sync history)", args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to fetch sync run: %w", err)
+			}
+		} else {
+			runs, err := client.GetSyncHistory(1, "")
+			if isNotFound(err) {
+				return fmt.Errorf("the server does not record sync history")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to fetch sync history: %w", err)
+			}
+			if len(runs) == 0 {
+				display.Println("No sync runs recorded.")
+				return nil
+			}
+			run = &runs[0]
+		}
+
+		preview, err := client.UndoSyncRun(run.ID, true)
+		if isNotFound(err) {
+			return fmt.Errorf("the server can't undo sync run %s", run.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to preview undo: %w", err)
+		}
+
+		display.Printf("\n↩️  Sync run %s: started %s (%s), imported %d entries\n\n",
+			run.ID, formatTimestamp(run.StartedAt), strings.ToLower(run.Trigger), run.TotalImported)
+
+		if len(preview.Modified) > 0 {
+			display.Printf("⚠️  %d entries were changed after the import and will be kept:\n\n", len(preview.Modified))
+			printEntryList(preview.Modified)
+			display.Println()
+		}
+
+		if len(preview.Entries) == 0 {
+			display.Println("Nothing to undo: none of the imported entries are left to delete.")
+			display.Println()
+			return nil
+		}
+
+		var hours float64
+		for _, entry := range preview.Entries {
+			hours += entry.Duration
+		}
+		display.Printf("🗑️  %d entries (%s) will be deleted:\n\n", len(preview.Entries), display.FormatDuration(hours))
+		printEntryList(preview.Entries)
+		display.Println()
+
+		if !syncUndoYes && !confirm(fmt.Sprintf("Delete %d entries? [y/N]: ", len(preview.Entries))) {
+			display.Println("Cancelled.")
+			return nil
+		}
+
+		result, err := client.UndoSyncRun(run.ID, false)
+		if err != nil {
+			return fmt.Errorf("failed to undo sync run: %w", err)
+		}
+
+		display.Printf("✓ Deleted %d entries imported by sync run %s", result.Deleted, run.ID)
+		if len(result.Modified) > 0 {
+			display.Printf(", kept %d changed since", len(result.Modified))
+		}
+		display.Println()
+
+		// Entries may have been edited between the preview and the undo
+		if kept := len(result.Modified) - len(preview.Modified); kept > 0 {
+			display.Printf("⚠️  %d more entries were changed since the preview and were kept\n", kept)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncUndoCmd)
+
+	syncUndoCmd.Flags().BoolVarP(&syncUndoYes, "yes", "y", false, "Delete without asking for confirmation")
+}
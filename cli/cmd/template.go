@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/localdata"
+)
+
+// templatesFile holds the saved entry templates, keyed by name
+const templatesFile = "templates.yaml"
+
+var (
+	templateProject     string
+	templateDescription string
+	templateDuration    string
+	templateStart       string
+	templateTags        []string
+	templateDate        string
+	templateAll         string
+)
+
+// entryTemplate is a saved entry to create again and again
+type entryTemplate struct {
+	Project     string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Duration    string   `json:"duration" yaml:"duration"`
+	Start       string   `json:"start,omitempty" yaml:"start,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Save and apply entry templates",
+	Long: `Save entries you create often as named templates and create them again
+with one command. Templates are stored in ~/.timetracker/templates.yaml.
+
+A template without a start time is placed after the day's last timed
+entry, or at 09:00 on an empty day. Templates applied together follow each
+other back to back.
+
+Examples:
+  timetracker template save standup --project Internal -d "Weekly planning" --duration 30m --tag monday
+  timetracker template list
+  timetracker template apply standup --date today
+  timetracker template apply --all monday`,
+}
+
+// templateSaveCmd represents the template save command
+var templateSaveCmd = &cobra.Command{
+	Use:          "save <name>",
+	Short:        "Create or replace a template",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.ToLower(strings.TrimSpace(args[0]))
+		if name == "" || strings.ContainsAny(name, " ") {
+			return fmt.Errorf("invalid template name %q: must not be empty or contain spaces", args[0])
+		}
+
+		template := entryTemplate{
+			Project:     templateProject,
+			Description: templateDescription,
+			Duration:    templateDuration,
+			Start:       templateStart,
+		}
+		for _, tag := range templateTags {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				template.Tags = append(template.Tags, tag)
+			}
+		}
+		if _, err := templateMinutes(template); err != nil {
+			return err
+		}
+
+		store, err := openTemplateStore()
+		if err != nil {
+			return err
+		}
+		_, replaced := store.Get(name)
+		store.Set(name, template)
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save template: %w", err)
+		}
+
+		verb := "Saved"
+		if replaced {
+			verb = "Updated"
+		}
+		display.Printf("✓ %s template %s: %s\n", verb, name, describeTemplate(template))
+		return nil
+	},
+}
+
+// templateListCmd represents the template list command
+var templateListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List saved templates",
+	Annotations:  outputFormats(display.FormatCSV, display.FormatTSV),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openTemplateStore()
+		if err != nil {
+			return err
+		}
+
+		names := store.Keys()
+		if len(names) == 0 {
+			display.Println("No templates saved. Create one with 'timetracker template save'.")
+			return nil
+		}
+
+		table := display.NewTable("Name", "Start", "Duration", "Project", "Description", "Tags")
+		for _, name := range names {
+			template, _ := store.Get(name)
+			start := template.Start
+			if start == "" {
+				start = "-"
+			}
+			table.AddRow(name, start, template.Duration, projectLabel(template.Project),
+				template.Description, strings.Join(template.Tags, ", "))
+		}
+		table.Print()
+		return nil
+	},
+}
+
+// templateRemoveCmd represents the template remove command
+var templateRemoveCmd = &cobra.Command{
+	Use:          "remove <name>",
+	Short:        "Delete a template",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.ToLower(strings.TrimSpace(args[0]))
+
+		store, err := openTemplateStore()
+		if err != nil {
+			return err
+		}
+		if _, ok := store.Get(name); !ok {
+			return fmt.Errorf("no template named %q", args[0])
+		}
+		store.Delete(name)
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save templates: %w", err)
+		}
+
+		display.Printf("✓ Removed template %s\n", name)
+		return nil
+	},
+}
+
+// templateApplyCmd represents the template apply command
+var templateApplyCmd = &cobra.Command{
+	Use:   "apply [name...]",
+	Short: "Create entries from templates",
+	Long: `Create entries from one or more templates, or from every template with a
+tag using --all. --project, --description, --duration and --start override
+the templates' values; --start only works with a single template.
+
+Examples:
+  timetracker template apply standup
+  timetracker template apply standup review --date yesterday
+  timetracker template apply --all monday
+  timetracker template apply standup --duration 45m -d "Planning + retro"`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := parseDateFlag(templateDate)
+		if err != nil {
+			return err
+		}
+		if (len(args) == 0) == (templateAll == "") {
+			return fmt.Errorf("pass template names or --all <tag>")
+		}
+
+		store, err := openTemplateStore()
+		if err != nil {
+			return err
+		}
+
+		names, err := selectTemplates(store, args, strings.ToLower(templateAll))
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("start") && len(names) > 1 {
+			return fmt.Errorf("--start can only override a single template")
+		}
+
+		templates := make([]entryTemplate, len(names))
+		for i, name := range names {
+			template, _ := store.Get(name)
+			if cmd.Flags().Changed("project") {
+				template.Project = templateProject
+			}
+			if cmd.Flags().Changed("description") {
+				template.Description = templateDescription
+			}
+			if cmd.Flags().Changed("duration") {
+				template.Duration = templateDuration
+			}
+			if cmd.Flags().Changed("start") {
+				template.Start = templateStart
+			}
+			templates[i] = template
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		existing, err := client.GetEntries(date, date)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+
+		planned, err := placeTemplates(templates, existing)
+		if err != nil {
+			return err
+		}
+
+		var failed []string
+		total := 0.0
+		for i, entry := range planned {
+			hours, err := addEntry(client, api.CreateEntryRequest{
+				Date:        date,
+				StartTime:   formatClock(entry.Start),
+				EndTime:     formatClock(entry.End),
+				Project:     resolveProject(entry.Project),
+				Description: entry.Description,
+			}, float64(entry.End-entry.Start)/60)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", names[i], err))
+				continue
+			}
+			total += hours
+			display.Printf("✓ %s: %s-%s %s\n", names[i], formatClock(entry.Start), formatClock(entry.End),
+				strings.TrimSpace(projectLabel(entry.Project)+" "+entry.Description))
+		}
+
+		for _, failure := range failed {
+			display.Printf("  ✗ %s\n", failure)
+		}
+		if len(planned) > len(failed) {
+			display.Printf("\nAdded %s on %s\n", display.FormatDuration(total), date)
+		}
+
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d templates failed", len(failed), len(planned))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateSaveCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateRemoveCmd)
+	templateCmd.AddCommand(templateApplyCmd)
+	localdata.Register(templatesFile)
+
+	for _, c := range []*cobra.Command{templateSaveCmd, templateApplyCmd} {
+		c.Flags().StringVarP(&templateProject, "project", "p", "", "Project name or alias")
+		c.Flags().StringVarP(&templateDescription, "description", "d", "", "Entry description")
+		c.Flags().StringVar(&templateDuration, "duration", "", "Duration (e.g. 30m, 1h30m or 1.5)")
+		c.Flags().StringVar(&templateStart, "start", "", "Start time (HH:mm; default: after the day's last entry)")
+	}
+	templateSaveCmd.Flags().StringSliceVar(&templateTags, "tag", nil, "Tag for applying templates together with apply --all (repeatable)")
+	templateSaveCmd.MarkFlagRequired("duration")
+
+	templateApplyCmd.Flags().StringVar(&templateDate, "date", "today", "Date of the entries (YYYY-MM-DD, today or yesterday)")
+	templateApplyCmd.Flags().StringVar(&templateAll, "all", "", "Apply every template with this tag")
+}
+
+// openTemplateStore opens the saved templates
+func openTemplateStore() (*localdata.Store[entryTemplate], error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return localdata.Open[entryTemplate](filepath.Join(dir, templatesFile))
+}
+
+// selectTemplates returns the named templates, or those tagged tag, in
+// order
+func selectTemplates(store *localdata.Store[entryTemplate], names []string, tag string) ([]string, error) {
+	if tag == "" {
+		selected := make([]string, len(names))
+		for i, name := range names {
+			selected[i] = strings.ToLower(name)
+			if _, ok := store.Get(selected[i]); !ok {
+				return nil, fmt.Errorf("no template named %q", name)
+			}
+		}
+		return selected, nil
+	}
+
+	var selected []string
+	for _, name := range store.Keys() {
+		template, _ := store.Get(name)
+		for _, t := range template.Tags {
+			if t == tag {
+				selected = append(selected, name)
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no templates tagged %q", tag)
+	}
+	return selected, nil
+}
+
+// templateMinutes validates a template and returns its length in minutes
+func templateMinutes(template entryTemplate) (int, error) {
+	hours, err := dates.ParseHours(template.Duration)
+	if err != nil {
+		return 0, err
+	}
+	minutes := int(math.Round(hours * 60))
+	if minutes <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	if template.Start != "" {
+		if _, err := time.Parse("15:04", template.Start); err != nil {
+			return 0, fmt.Errorf("invalid start time %q (expected HH:mm)", template.Start)
+		}
+	}
+	return minutes, nil
+}
+
+// placeTemplates works out the clock times of the templates' entries on a
+// day with the existing entries. Templates without a start follow the
+// day's last timed entry, or each other, from copyDefaultStart.
+func placeTemplates(templates []entryTemplate, existing []api.Entry) ([]copyTemplate, error) {
+	cursor := copyDefaultStart
+	for _, entry := range existing {
+		if _, end, ok := entryClock(entry); ok && end > cursor {
+			cursor = end
+		}
+	}
+	// Fixed templates are placed first so the others can follow them
+	for _, template := range templates {
+		if start, err := time.Parse("15:04", template.Start); err == nil {
+			minutes, err := templateMinutes(template)
+			if err != nil {
+				return nil, err
+			}
+			if end := start.Hour()*60 + start.Minute() + minutes; end > cursor {
+				cursor = end
+			}
+		}
+	}
+
+	placed := make([]copyTemplate, 0, len(templates))
+	for _, template := range templates {
+		minutes, err := templateMinutes(template)
+		if err != nil {
+			return nil, err
+		}
+
+		start := cursor
+		if clock, err := time.Parse("15:04", template.Start); err == nil {
+			start = clock.Hour()*60 + clock.Minute()
+		} else {
+			cursor += minutes
+		}
+		if start+minutes >= 24*60 {
+			return nil, fmt.Errorf("a %s entry starting at %s doesn't fit in the day", template.Duration, formatClock(start))
+		}
+
+		placed = append(placed, copyTemplate{
+			Start:       start,
+			End:         start + minutes,
+			Project:     template.Project,
+			Description: template.Description,
+		})
+	}
+	return placed, nil
+}
+
+// describeTemplate summarizes a template in one line
+func describeTemplate(template entryTemplate) string {
+	parts := []string{template.Duration}
+	if template.Start != "" {
+		parts = append(parts, "at "+template.Start)
+	}
+	parts = append(parts, projectLabel(template.Project))
+	if template.Description != "" {
+		parts = append(parts, fmt.Sprintf("%q", template.Description))
+	}
+	if len(template.Tags) > 0 {
+		tags := append([]string(nil), template.Tags...)
+		sort.Strings(tags)
+		parts = append(parts, "["+strings.Join(tags, ", ")+"]")
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,11 +1,31 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vmiller/timetracker-cli/internal/api"
 	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/pending"
+)
+
+// Exit codes of today with --min-hours or --max-hours, for shell prompts
+const (
+	exitTodayOutside = 1
+	exitTodayError   = 2
+)
+
+var (
+	todayEntries  bool
+	todayBy       string
+	todayMinHours float64
+	todayMaxHours float64
+	todaySources  []string
 )
 
 // todayCmd represents the today command
@@ -14,49 +34,211 @@ var todayCmd = &cobra.Command{
 	Short: "Show today's time tracking summary",
 	Long: `Display a summary of today's logged hours including:
   - Total hours worked today
-  - Breakdown by source (Toggl, Tempo, Manual)
-  - Number of entries`,
+  - Breakdown by source (Toggl, Tempo, Manual) or project (--by)
+  - Number of entries
+
+Project breakdowns list the projects with the most hours first; beyond
+--top projects (breakdown_projects in the config file, default 8) the rest
+are grouped as "(other)".
+
+With --entries, today's entries are listed below the summary, by start time.
+
+With --round, durations are shown rounded, e.g. to 15 minutes with
+--round 15m (or 15m:up, 15m:down), and the total adds up the rounded
+values. Entries are not changed; use 'timetracker round' for that. The
+default comes from display.rounding in the config file.
+
+With --source, only entries of that source (toggl, tempo or manual) are
+counted; repeat it for several sources.
+
+With --output json, the summary is written as JSON instead.
+
+With --min-hours or --max-hours, the exit code tells whether today's total
+is within them, for shell prompts and scripts:
+  0  the total meets --min-hours and doesn't exceed --max-hours
+  1  the total is below --min-hours or above --max-hours
+  2  the summary couldn't be fetched
+Add --quiet to print nothing at all.
+
+Examples:
+  timetracker today --by both --entries
+  timetracker today --min-hours 6 --quiet
+  timetracker today --max-hours 10 --quiet || echo "Time to stop"`,
+	Annotations:  outputFormats(display.FormatJSON, display.FormatCSV, display.FormatTSV),
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load config
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+		err := runToday(cmd)
+		var exit *exitError
+		if err != nil && todayThresholds(cmd) && !errors.As(err, &exit) {
+			return withExitCode(exitTodayError, err)
 		}
+		return err
+	},
+}
+
+// runToday prints today's summary
+func runToday(cmd *cobra.Command) error {
+	if cmd.Flags().Changed("min-hours") && cmd.Flags().Changed("max-hours") && todayMinHours > todayMaxHours {
+		return fmt.Errorf("--min-hours is above --max-hours")
+	}
+	showSources, showProjects, err := breakdownSections(todayBy)
+	if err != nil {
+		return err
+	}
+	top, err := breakdownProjects(cmd)
+	if err != nil {
+		return err
+	}
+	sources, err := parseSources(todaySources)
+	if err != nil {
+		return err
+	}
+	round, err := displayRounding(cmd)
+	if err != nil {
+		return err
+	}
 
-		// Check if logged in
-		if cfg.AccessToken == "" && cfg.RefreshToken == "" {
-			return fmt.Errorf("not logged in. Run 'timetracker login' first")
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create API client
+	client := newClient(cmd.Context(), cfg)
+
+	// Check if logged in
+	if cfg.AccessToken == "" && cfg.RefreshToken == "" {
+		return fmt.Errorf("not logged in. Run 'timetracker login' first")
+	}
+
+	// Fetch today's summary
+	fetched, err := client.GetTodaySummary(sources)
+	if isNotFound(err) {
+		return fmt.Errorf("no data for today (%s)", dates.Format(time.Now()))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch today's summary: %w", err)
+	}
+	summary := *fetched
+	summary.OfflineFrom = offlineFrom(client)
+	hours := summary.TotalHours
+	if round.Enabled() {
+		summary.BySource = round.Map(summary.BySource)
+		summary.ByProject = round.Map(summary.ByProject)
+		summary.TotalHours = sumHours(summary.BySource)
+		if summary.BillableHours != nil {
+			billable := round.Hours(*summary.BillableHours)
+			summary.BillableHours = &billable
 		}
+	}
+
+	// Display results
+	printOfflineBanner(summary.OfflineFrom)
+	display.Printf("\n📅 %s\n", summary.Date)
+	printSourceFilter(sources)
+	if note, ok := dayNotes(client, summary.Date, summary.Date)[summary.Date]; ok {
+		display.Printf("📝 %s\n", note)
+	}
+	display.Println()
+	display.Printf("⏱️  Total Hours: %s\n", display.FormatHours(summary.TotalHours))
+	// Pending changes are tracked against the unfiltered totals
+	pendingHours := map[string]float64{}
+	if len(sources) == 0 {
+		pendingHours = reconcilePending(pending.ViewToday, map[string]float64{summary.Date: hours})
+	}
+	if hours, ok := pendingHours[summary.Date]; ok {
+		display.Printf("    %s\n", formatPending(hours))
+	}
+	// Thresholds apply to the hours logged, not the rounded ones
+	total := hours + pendingHours[summary.Date]
+	outside := todayOutside(cmd, total)
+	if outside != "" {
+		display.Printf("⚠️  %s\n", outside)
+	}
+	display.Printf("📊 Entries: %d\n\n", summary.EntryCount)
 
-		// Create API client
-		client := api.NewClient(cfg)
+	// csv and tsv list the entries, as --entries does
+	listEntries := todayEntries || display.OutputFormat() == display.FormatCSV || display.OutputFormat() == display.FormatTSV
 
-		// Fetch today's summary
-		var summary api.TodaySummaryResponse
-		if err := client.Get("/api/entries/summary/today", &summary); err != nil {
-			return fmt.Errorf("failed to fetch today's summary: %w", err)
+	// Older servers don't report projects; add them up from the entries
+	var entries []api.Entry
+	if listEntries || (showProjects && summary.ByProject == nil) {
+		entries, err = client.GetEntries(summary.Date, summary.Date)
+		if err != nil {
+			return fmt.Errorf("failed to fetch entries: %w", err)
+		}
+		entries = api.FilterSources(entries, sources)
+		if summary.ByProject == nil {
+			summary.ByProject = round.Map(projectHours(entries))
 		}
+		entries = roundedEntries(entries, round)
+	}
 
-		// Display results
-		fmt.Printf("\n📅 %s\n\n", summary.Date)
-		fmt.Printf("⏱️  Total Hours: %.2f\n", summary.TotalHours)
-		fmt.Printf("📊 Entries: %d\n\n", summary.EntryCount)
-
-		if len(summary.BySource) > 0 {
-			fmt.Println("Breakdown by Source:")
-			for source, hours := range summary.BySource {
-				fmt.Printf("  • %-8s %.2fh\n", source+":", hours)
-			}
-		} else {
-			fmt.Println("No time entries logged today.")
+	if len(summary.BySource) == 0 && len(summary.ByProject) == 0 {
+		display.Println("No time entries logged today.")
+	} else {
+		if showSources {
+			printBreakdown("Source", summary.BySource, 0)
+		}
+		if showSources && showProjects {
+			display.Println()
+		}
+		if showProjects {
+			printBreakdown("Project", summary.ByProject, top)
 		}
+	}
 
-		fmt.Println()
+	display.Println()
 
-		return nil
-	},
+	if listEntries && len(entries) > 0 {
+		entriesTable(entries).Print()
+		display.Println()
+	}
+	var result interface{} = summary
+	if todayEntries {
+		result = struct {
+			api.TodaySummaryResponse
+			Entries []api.Entry `json:"entries"`
+		}{summary, entries}
+	}
+	printRoundingNote(round)
+	if err := display.Result(result); err != nil {
+		return err
+	}
+
+	if outside != "" {
+		return silentExit(exitTodayOutside)
+	}
+	return nil
+}
+
+// todayThresholds reports whether --min-hours or --max-hours was given
+func todayThresholds(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("min-hours") || cmd.Flags().Changed("max-hours")
+}
+
+// todayOutside describes how total misses --min-hours or --max-hours, or
+// returns "" if it doesn't
+func todayOutside(cmd *cobra.Command, total float64) string {
+	// Compare in hundredths, as the hours are shown
+	hundredths := func(hours float64) int64 { return int64(math.Round(hours * 100)) }
+	if cmd.Flags().Changed("min-hours") && hundredths(total) < hundredths(todayMinHours) {
+		return fmt.Sprintf("Below the %s minimum by %s", display.FormatDuration(todayMinHours), display.FormatDuration(todayMinHours-total))
+	}
+	if cmd.Flags().Changed("max-hours") && hundredths(total) > hundredths(todayMaxHours) {
+		return fmt.Sprintf("Above the %s maximum by %s", display.FormatDuration(todayMaxHours), display.FormatDuration(total-todayMaxHours))
+	}
+	return ""
 }
 
 func init() {
 	rootCmd.AddCommand(todayCmd)
+
+	todayCmd.Flags().BoolVar(&todayEntries, "entries", false, "Also list today's entries")
+	addBreakdownFlags(todayCmd, &todayBy)
+	addSourceFlag(todayCmd, &todaySources)
+	addRoundFlag(todayCmd)
+	todayCmd.Flags().Float64Var(&todayMinHours, "min-hours", 0, "Exit with 1 if today's total is below this")
+	todayCmd.Flags().Float64Var(&todayMaxHours, "max-hours", 0, "Exit with 1 if today's total is above this")
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/tui"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse your time interactively",
+	Long: `Open a full-screen view with today's summary, the current week and the
+entries of the last 30 days.
+
+Keys:
+  j/k, arrows     move through the entries
+  PgUp/PgDn       scroll a page
+  /               filter entries by typing (enter keeps it, esc clears it)
+  s               sync from all providers
+  r               reload
+  q, ctrl+c       quit
+
+Needs an interactive terminal; in scripts use today, week or search.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		// Chunk progress on stderr would draw over the screen
+		client.SetChunking(0, nil)
+
+		return tui.Run(client, tui.Options{Highlight: display.ColorEnabled()})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
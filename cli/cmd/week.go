@@ -2,69 +2,605 @@ package cmd
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vmiller/timetracker-cli/internal/api"
 	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
 	"github.com/vmiller/timetracker-cli/internal/display"
+	"github.com/vmiller/timetracker-cli/internal/pending"
+	"github.com/vmiller/timetracker-cli/internal/rounding"
 )
 
+var (
+	weekBillableSplit bool
+	weekDetailed      bool
+	weekBy            string
+	weekLast          int
+	weekDate          string
+	weekISO           string
+	weekCompare       bool
+	weekChart         bool
+	weekSources       []string
+)
+
+// weekChartWidth is the width of the longest bar of --chart, unless the
+// terminal is too narrow for it
+const weekChartWidth = 30
+
+// weekSelectionHelp lists the ways to pick a week, for error messages
+const weekSelectionHelp = `Accepted formats:
+  --last            the previous week
+  --last 3          three weeks back
+  --date 2024-03-14 the week containing that day (or today, yesterday)
+  --iso 2024-W11    an ISO 8601 week`
+
 // weekCmd represents the week command
 var weekCmd = &cobra.Command{
 	Use:   "week",
 	Short: "Show this week's time tracking summary",
 	Long: `Display a summary of this week's logged hours including:
-  - Daily breakdown (Monday-Sunday)
+  - Daily breakdown, from the week_start day (Monday by default, or
+    --week-start)
   - Total hours for the week
-  - Breakdown by source (Toggl, Tempo, Manual)`,
+  - Breakdown by source (Toggl, Tempo, Manual) or project (--by)
+
+Project breakdowns list the projects with the most hours first; beyond
+--top projects (breakdown_projects in the config file, default 8) the rest
+are grouped as "(other)".
+
+With --detailed, each day's entries are listed under it.
+
+With --source, only entries of that source (toggl, tempo or manual) are
+counted; repeat it for several sources.
+
+With --compare, the previous week is shown alongside: each day gains the
+same weekday's hours from the week before and the difference, and the total
+and source breakdown are compared as well.
+
+With --round, durations are shown rounded, e.g. to 15 minutes with
+--round 15m, and totals add up the rounded days; see 'timetracker today
+--help'.
+
+With --chart, a bar next to each day's hours shows the shape of the week,
+scaled to the longest day. The bars are drawn with "#" when color is off.
+
+Pick another week with --last (the previous week), --last N (N weeks back),
+--date (the week containing that day) or --iso (an ISO week such as
+2024-W11).
+
+With --output json, the summary is written as JSON instead; csv and tsv
+write the daily breakdown.`,
+	Args:         weekArgs,
+	SilenceUsage: true,
+	Annotations:  outputFormats(display.FormatJSON, display.FormatCSV, display.FormatTSV),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if weekChart && weekDetailed {
+			return fmt.Errorf("--chart and --detailed can't be combined")
+		}
+		showSources, showProjects, err := breakdownSections(weekBy)
+		if err != nil {
+			return err
+		}
+		top, err := breakdownProjects(cmd)
+		if err != nil {
+			return err
+		}
+		sources, err := parseSources(weekSources)
+		if err != nil {
+			return err
+		}
+		round, err := displayRounding(cmd)
+		if err != nil {
+			return err
+		}
+
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		// Create API client
+		client := newClient(cmd.Context(), cfg)
+
 		// Check if logged in
 		if cfg.AccessToken == "" && cfg.RefreshToken == "" {
 			return fmt.Errorf("not logged in. Run 'timetracker login' first")
 		}
 
-		// Create API client
-		client := api.NewClient(cfg)
+		now := time.Now()
+		firstDay, err := weekStart(cmd)
+		if err != nil {
+			return err
+		}
+		start, err := selectedWeek(cmd, now, firstDay)
+		if err != nil {
+			return err
+		}
 
-		// Fetch week's summary
-		var summary api.WeekSummaryResponse
-		if err := client.Get("/api/entries/summary/week", &summary); err != nil {
+		// Fetch week's summary, and the previous one alongside for --compare
+		var fetched, previous *api.WeekSummaryResponse
+		if weekCompare {
+			fetched, previous, err = fetchWeekAndPrevious(client, start, now, firstDay, sources)
+		} else {
+			fetched, err = client.GetWeekSummary(start, sources)
+		}
+		if isNotFound(err) {
+			week := start
+			if week.IsZero() {
+				week = dates.StartOfWeek(now, firstDay)
+			}
+			return fmt.Errorf("no data for the week of %s", dates.Format(week))
+		}
+		if err != nil {
 			return fmt.Errorf("failed to fetch week's summary: %w", err)
 		}
+		summary := *fetched
+		summary.OfflineFrom = offlineFrom(client)
+
+		// Check for changes the cached summary doesn't include yet, by the
+		// hours logged rather than the rounded ones
+		observed := map[string]float64{}
+		for _, day := range summary.Daily {
+			observed[day.Date] = day.Hours
+		}
+		summary = roundWeek(summary, round)
+		var prevHours map[time.Weekday]float64
+		if previous != nil {
+			rounded := roundWeek(*previous, round)
+			previous = &rounded
+			prevHours = hoursByWeekday(previous.Daily)
+		}
 
 		// Display results
-		fmt.Printf("\n📆 Week: %s to %s\n\n", summary.WeekStart, summary.WeekEnd)
+		printOfflineBanner(summary.OfflineFrom)
+		display.Printf("\n📆 Week %s: %s to %s\n", weekLabel(summary.WeekStart, now, firstDay), summary.WeekStart, summary.WeekEnd)
+		printSourceFilter(sources)
+		display.Println()
+
+		// Pending changes are tracked against the unfiltered totals
+		pendingHours := map[string]float64{}
+		if len(sources) == 0 {
+			pendingHours = reconcilePending(pending.ViewWeek, observed)
+		}
+
+		var billable map[string]float64
+		if weekBillableSplit {
+			billable, err = dailyBillable(client, summary, billableDefault(cmd), sources)
+			if err != nil {
+				return err
+			}
+			billable = round.Map(billable)
+		}
+
+		notes := dayNotes(client, summary.WeekStart, summary.WeekEnd)
+
+		cal, err := workCalendar(cmd)
+		if err != nil {
+			return err
+		}
+		daysOff := map[string]string{}
+		workdays := 0
+		expected := 0.0
+		for _, day := range summary.Daily {
+			if label := dayLabel(cal, day.Date); label != "" {
+				daysOff[day.Date] = label
+			} else if cal.IsWorkday(dayOf(day.Date)) {
+				workdays++
+			}
+			expected += cal.Expected(dayOf(day.Date))
+		}
 
 		// Create table for daily breakdown
-		table := display.NewTable("Day", "Date", "Hours")
+		headers := []string{"Day", "Date", "Hours"}
+		chart := weekChart && display.OutputFormat() == display.FormatTable
+		if weekCompare {
+			headers = append(headers, "Prev", "Δ")
+		}
+		if weekBillableSplit {
+			headers = append(headers, "Billable")
+		}
+		if len(daysOff) > 0 {
+			headers = append(headers, "Off")
+		}
+		if len(notes) > 0 {
+			headers = append(headers, "Note")
+		}
+		var rows [][]display.Cell
+		var totalPending float64
+		var titles []string
 		for _, day := range summary.Daily {
-			hoursStr := fmt.Sprintf("%.2f", day.Hours)
-			table.AddRow(day.DayName, day.Date, hoursStr)
+			hoursCell := display.Hours(day.Hours)
+			title := fmt.Sprintf("%s %s  %s", day.DayName, day.Date, display.FormatDuration(day.Hours))
+			if hours, ok := pendingHours[day.Date]; ok {
+				hoursCell.Text += fmt.Sprintf(" (%s pending)", display.FormatSignedHours(hours))
+				title += fmt.Sprintf(" (%s pending)", display.FormatSignedHours(hours))
+				totalPending += hours
+			}
+			row := []display.Cell{display.Text(day.DayName), display.Text(day.Date), hoursCell}
+			if weekCompare {
+				prev := prevHours[dayOf(day.Date).Weekday()]
+				delta := day.Hours - prev
+				row = append(row, display.Hours(prev), display.Cell{Text: formatDelta(delta), Value: math.Round(delta*100) / 100})
+				title += fmt.Sprintf(" (prev %s, %s)", display.FormatDuration(prev), formatDelta(delta))
+			}
+			if weekBillableSplit {
+				row = append(row, display.Hours(billable[day.Date]))
+				title += fmt.Sprintf(", %s billable", display.FormatDuration(billable[day.Date]))
+			}
+			if len(daysOff) > 0 {
+				row = append(row, display.Text(daysOff[day.Date]))
+			}
+			if len(notes) > 0 {
+				row = append(row, display.Text(notes[day.Date]))
+			}
+			for _, extra := range []string{daysOff[day.Date], notes[day.Date]} {
+				if extra != "" {
+					title += " · " + extra
+				}
+			}
+			rows = append(rows, row)
+			titles = append(titles, title)
 		}
-		table.Print()
 
-		fmt.Printf("\n⏱️  Total Hours: %.2f\n", summary.TotalHours)
-		fmt.Printf("📊 Total Entries: %d\n\n", summary.EntryCount)
+		table := display.NewTable(headers...)
+		for _, row := range rows {
+			table.AddCells(row...)
+		}
+		var chartScale string
+		if chart {
+			table, chartScale = weekChartTable(headers, rows, summary.Daily)
+		}
 
-		if len(summary.BySource) > 0 {
-			fmt.Println("Breakdown by Source:")
-			for source, hours := range summary.BySource {
-				fmt.Printf("  • %-8s %.2fh\n", source+":", hours)
+		// Older servers don't report projects; add them up from the entries
+		var entries []api.Entry
+		if weekDetailed || (showProjects && summary.ByProject == nil) {
+			entries, err = client.GetEntries(summary.WeekStart, summary.WeekEnd)
+			if err != nil {
+				return fmt.Errorf("failed to fetch entries: %w", err)
 			}
+			entries = api.FilterSources(entries, sources)
+			if summary.ByProject == nil {
+				summary.ByProject = round.Map(projectHours(entries))
+			}
+		}
+		if weekDetailed && display.OutputFormat() == display.FormatTable {
+			weekDetailTable(summary, titles, entries, observed, round).Print()
+		} else {
+			table.Print()
+		}
+		if chartScale != "" {
+			display.Printf("Scale: %s\n", chartScale)
 		}
 
-		fmt.Println()
+		display.Printf("\n⏱️  Total Hours: %s\n", display.FormatHours(summary.TotalHours))
+		if previous != nil {
+			display.Printf("    vs %s the week before (%s)\n", display.FormatHours(previous.TotalHours), formatDelta(summary.TotalHours-previous.TotalHours))
+		}
+		if totalPending != 0 {
+			display.Printf("    %s\n", formatPending(totalPending))
+		}
+		display.Printf("🎯 Expected: %s (%s)\n", display.FormatHours(expected), formatDelta(summary.TotalHours+totalPending-expected))
+		display.Printf("📊 Total Entries: %d\n", summary.EntryCount)
+		if len(daysOff) > 0 {
+			display.Printf("📅 Workdays: %d (%d off)\n", workdays, len(daysOff))
+		}
+		display.Println()
 
-		return nil
+		hasSources := len(summary.BySource) > 0 || (previous != nil && len(previous.BySource) > 0)
+		if showSources && previous != nil && hasSources {
+			printBreakdownVs("Source", summary.BySource, previous.BySource)
+		} else if showSources && hasSources {
+			printBreakdown("Source", summary.BySource, 0)
+		}
+		if showSources && showProjects && hasSources && len(summary.ByProject) > 0 {
+			display.Println()
+		}
+		if showProjects && len(summary.ByProject) > 0 {
+			printBreakdown("Project", summary.ByProject, top)
+		}
+
+		display.Println()
+		printRoundingNote(round)
+
+		result := weekResult{WeekSummaryResponse: summary, Previous: previous}
+		if weekDetailed {
+			entries = roundedEntries(entries, round)
+			result.Entries = &entries
+		}
+		return display.Result(result)
 	},
 }
 
+// weekResult is week's JSON output: the summary, with the entries for
+// --detailed and the previous week's summary for --compare
+type weekResult struct {
+	api.WeekSummaryResponse
+	Entries  *[]api.Entry             `json:"entries,omitempty"`
+	Previous *api.WeekSummaryResponse `json:"previous,omitempty"`
+}
+
+// roundWeek rounds a week's summary for display: each day, and the totals
+// as the sum of the rounded days
+func roundWeek(summary api.WeekSummaryResponse, round rounding.Display) api.WeekSummaryResponse {
+	if !round.Enabled() {
+		return summary
+	}
+
+	daily := make([]api.DailySummary, len(summary.Daily))
+	var total, billable float64
+	for i, day := range summary.Daily {
+		day.Hours = round.Hours(day.Hours)
+		total += day.Hours
+		if day.BillableHours != nil {
+			hours := round.Hours(*day.BillableHours)
+			day.BillableHours = &hours
+			billable += hours
+		}
+		daily[i] = day
+	}
+	summary.Daily = daily
+	summary.TotalHours = total
+	if summary.BillableHours != nil {
+		summary.BillableHours = &billable
+	}
+	summary.BySource = round.Map(summary.BySource)
+	summary.ByProject = round.Map(summary.ByProject)
+	return summary
+}
+
+// fetchWeekAndPrevious fetches the summaries of the week starting on start
+// (this week if zero) and of the week before it at the same time
+func fetchWeekAndPrevious(client *api.Client, start, now time.Time, firstDay time.Weekday, sources []string) (*api.WeekSummaryResponse, *api.WeekSummaryResponse, error) {
+	base := start
+	if base.IsZero() {
+		base = dates.StartOfWeek(now, firstDay)
+	}
+
+	var current, previous *api.WeekSummaryResponse
+	var currentErr, previousErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		current, currentErr = client.GetWeekSummary(start, sources)
+	}()
+	go func() {
+		defer wg.Done()
+		previous, previousErr = client.GetWeekSummary(base.AddDate(0, 0, -7), sources)
+	}()
+	wg.Wait()
+
+	if currentErr != nil {
+		return nil, nil, currentErr
+	}
+	if previousErr != nil {
+		return nil, nil, fmt.Errorf("previous week: %w", previousErr)
+	}
+	return current, previous, nil
+}
+
+// hoursByWeekday indexes a week's daily hours by weekday, to line them up
+// with another week's
+func hoursByWeekday(daily []api.DailySummary) map[time.Weekday]float64 {
+	hours := map[time.Weekday]float64{}
+	for _, day := range daily {
+		hours[dayOf(day.Date).Weekday()] += day.Hours
+	}
+	return hours
+}
+
+// weekChartTable returns the daily table with a bar column after the hours,
+// and the scale of the bars. The bars are as wide as the terminal leaves
+// room for, up to weekChartWidth.
+func weekChartTable(headers []string, rows [][]display.Cell, daily []api.DailySummary) (*display.Table, string) {
+	longest := 0.0
+	for _, day := range daily {
+		longest = math.Max(longest, day.Hours)
+	}
+
+	const column = 3 // after Day, Date and Hours
+	withBar := func(bar func(hours float64) string) *display.Table {
+		table := display.NewTable(append(append(append([]string{}, headers[:column]...), ""), headers[column:]...)...)
+		for i, row := range rows {
+			cells := append(append(append([]display.Cell{}, row[:column]...), display.Text(bar(daily[i].Hours))), row[column:]...)
+			table.AddCells(cells...)
+		}
+		return table
+	}
+
+	width := weekChartWidth
+	if terminal := display.TerminalWidth(); terminal > 0 {
+		room := terminal - withBar(func(float64) string { return "" }).Width()
+		if room < width {
+			width = room
+		}
+		if width < 1 {
+			width = 1
+		}
+	}
+
+	ascii := !display.ColorEnabled()
+	table := withBar(func(hours float64) string { return display.Bar(hours, longest, width, ascii) })
+	return table, display.BarScale(longest, width, ascii)
+}
+
+// weekDetailTable lists each day's entries in a section titled with the
+// day. Hours that the summary and the entries disagree on get a row of
+// their own, so each day's rows add up to the summary's figure; logged holds
+// the days' unrounded hours. With rounding, what the rounded entries don't
+// add up to gets a "(rounding)" row too.
+func weekDetailTable(summary api.WeekSummaryResponse, titles []string, entries []api.Entry, logged map[string]float64, round rounding.Display) *display.Table {
+	byDay := map[string][]api.Entry{}
+	for _, entry := range entries {
+		byDay[entry.Day()] = append(byDay[entry.Day()], entry)
+	}
+
+	table := display.NewTable("Project", "Description", "Hours")
+	table.FitWidth(display.TerminalWidth(), 1)
+	for i, day := range summary.Daily {
+		table.AddSection(titles[i])
+
+		var listed, shown float64
+		for _, entry := range byDay[day.Date] {
+			project := entry.Project
+			if project == "" {
+				project = "(no project)"
+			}
+			description := strings.Join(strings.Fields(entry.Description), " ")
+			hours := round.Hours(entry.Duration)
+			table.AddCells(display.Text(project), display.Text(description), display.Hours(hours))
+			listed += entry.Duration
+			shown += hours
+		}
+
+		diff := logged[day.Date] - listed
+		switch {
+		case diff >= 0.005:
+			diff = round.Hours(diff)
+			table.AddCells(display.Text("(unlisted)"), display.Text("in the summary but not among the entries"), display.Hours(diff))
+		case diff <= -0.005:
+			diff = -round.Hours(-diff)
+			table.AddCells(display.Text("(unlisted)"), display.Text("among the entries but not in the summary yet"), display.Hours(diff))
+		default:
+			diff = 0
+		}
+		if rest := day.Hours - shown - diff; round.Enabled() && math.Abs(rest) >= 0.005 {
+			table.AddCells(display.Text("(rounding)"), display.Text("the day rounds differently from its entries"), display.Hours(rest))
+		}
+	}
+	return table
+}
+
+// weekArgs accepts the N of "--last N": --last works without a value, so
+// pflag leaves a following number as an argument
+func weekArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if len(args) == 1 && cmd.Flags().Changed("last") {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid --last %q\n%s", args[0], weekSelectionHelp)
+		}
+		weekLast = n
+		return nil
+	}
+	return fmt.Errorf("unknown argument %q\n%s", args[0], weekSelectionHelp)
+}
+
+// selectedWeek returns the first day of the week beginning on firstDay that
+// was picked with --last, --date or --iso. For the current week it is zero
+// if the server's default Monday week will do.
+func selectedWeek(cmd *cobra.Command, now time.Time, firstDay time.Weekday) (time.Time, error) {
+	var picked []string
+	for _, name := range []string{"last", "date", "iso"} {
+		if cmd.Flags().Changed(name) {
+			picked = append(picked, "--"+name)
+		}
+	}
+	if len(picked) > 1 {
+		return time.Time{}, fmt.Errorf("%s can't be combined; pick one week\n%s", strings.Join(picked, " and "), weekSelectionHelp)
+	}
+
+	switch {
+	case cmd.Flags().Changed("last"):
+		if weekLast < 1 {
+			return time.Time{}, fmt.Errorf("--last must be at least 1\n%s", weekSelectionHelp)
+		}
+		return dates.StartOfWeek(now, firstDay).AddDate(0, 0, -7*weekLast), nil
+	case cmd.Flags().Changed("date"):
+		day, err := dates.Parse(weekDate, now)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%v\n%s", err, weekSelectionHelp)
+		}
+		return dates.StartOfWeek(day, firstDay), nil
+	case cmd.Flags().Changed("iso"):
+		start, err := dates.ParseWeek(weekISO, firstDay, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%v\n%s", err, weekSelectionHelp)
+		}
+		return start, nil
+	case firstDay != time.Monday:
+		return dates.StartOfWeek(now, firstDay), nil
+	}
+	return time.Time{}, nil
+}
+
+// weekLabel names the week starting on start, e.g. "2024-W11 (last week)"
+func weekLabel(start string, now time.Time, firstDay time.Weekday) string {
+	day, err := time.ParseInLocation(dates.Layout, start, now.Location())
+	if err != nil {
+		return start
+	}
+
+	label := dates.WeekName(day)
+	weeks := int(math.Round(dates.StartOfWeek(now, firstDay).Sub(dates.StartOfWeek(day, firstDay)).Hours() / (24 * 7)))
+	switch {
+	case weeks == 0:
+		return label + " (this week)"
+	case weeks == 1:
+		return label + " (last week)"
+	case weeks > 1:
+		return fmt.Sprintf("%s (%d weeks ago)", label, weeks)
+	case weeks == -1:
+		return label + " (next week)"
+	default:
+		return fmt.Sprintf("%s (in %d weeks)", label, -weeks)
+	}
+}
+
+// dailyBillable returns billable hours per day, using the summary's own
+// figures when the server reports them and the week's entries (of the given
+// sources, if any) otherwise
+func dailyBillable(client *api.Client, summary api.WeekSummaryResponse, fallback bool, sources []string) (map[string]float64, error) {
+	billable := map[string]float64{}
+
+	reported := len(summary.Daily) > 0
+	for _, day := range summary.Daily {
+		if day.BillableHours == nil {
+			reported = false
+			break
+		}
+		billable[day.Date] = *day.BillableHours
+	}
+	if reported {
+		return billable, nil
+	}
+
+	entries, err := client.GetEntries(summary.WeekStart, summary.WeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch entries: %w", err)
+	}
+
+	billable = map[string]float64{}
+	for _, entry := range api.FilterSources(entries, sources) {
+		if entry.IsBillable(fallback) {
+			billable[entry.Day()] += entry.Duration
+		}
+	}
+	return billable, nil
+}
+
 func init() {
 	rootCmd.AddCommand(weekCmd)
+
+	weekCmd.Flags().IntVar(&weekLast, "last", 1, "Show the previous week, or the week N weeks back")
+	weekCmd.Flag("last").NoOptDefVal = "1"
+	weekCmd.Flags().StringVar(&weekDate, "date", "", "Show the week containing this day (YYYY-MM-DD)")
+	weekCmd.Flags().StringVar(&weekISO, "iso", "", "Show this ISO week, e.g. 2024-W11")
+	addWeekStartFlag(weekCmd)
+	addBreakdownFlags(weekCmd, &weekBy)
+	addSourceFlag(weekCmd, &weekSources)
+	addRoundFlag(weekCmd)
+	weekCmd.Flags().BoolVar(&weekDetailed, "detailed", false, "List each day's entries")
+	weekCmd.Flags().BoolVar(&weekCompare, "compare", false, "Add the previous week's hours and the difference to each day")
+	weekCmd.Flags().BoolVar(&weekChart, "chart", false, "Add a bar chart of each day's hours to the daily breakdown")
+	weekCmd.Flags().BoolVar(&weekBillableSplit, "billable-split", false, "Add a Billable column to the daily breakdown")
 }
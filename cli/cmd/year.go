@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+var yearFlag int
+
+// yearCmd represents the year command
+var yearCmd = &cobra.Command{
+	Use:   "year",
+	Short: "Show a yearly time tracking summary",
+	Long: `Display a summary of a year's logged hours including:
+  - Monthly breakdown with a totals footer
+  - Average hours per working day (Mon-Fri, up to today for the current year)
+  - Busiest and quietest months
+  - Breakdown by project and source
+
+Examples:
+  timetracker year
+  timetracker year --year 2023`,
+	Annotations: outputFormats(display.FormatCSV, display.FormatTSV),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		year := yearFlag
+		if year == 0 {
+			year = now.Year()
+		}
+		if year > now.Year() {
+			return fmt.Errorf("--year %d is in the future", year)
+		}
+
+		client, err := newAuthenticatedClient(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		summary, err := client.GetYearSummary(year)
+		if err != nil {
+			return fmt.Errorf("failed to fetch year summary: %w", err)
+		}
+
+		display.Printf("\n📅 Year %d\n\n", summary.Year)
+
+		table := display.NewTable("Month", "Hours", "Entries")
+		for _, month := range summary.Monthly {
+			table.AddRow(monthName(month.Month), display.FormatHours(month.Hours), fmt.Sprint(month.EntryCount))
+		}
+		table.AddRow("Total", display.FormatHours(summary.TotalHours), fmt.Sprint(summary.EntryCount))
+		table.Print()
+
+		first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		last := first.AddDate(1, 0, -1)
+		if today := dates.StartOfDay(now); last.After(today) {
+			last = today
+		}
+		workingDays := countWorkingDays(first, last)
+
+		display.Printf("\n⏱️  Total Hours: %s\n", display.FormatHours(summary.TotalHours))
+		display.Printf("📊 Total Entries: %d\n", summary.EntryCount)
+		if workingDays > 0 {
+			display.Printf("📈 Average per working day: %s (%d working days)\n", display.FormatDuration(summary.TotalHours/float64(workingDays)), workingDays)
+		}
+
+		// Only months that have started count as quiet
+		elapsed := summary.Monthly
+		if year == now.Year() && int(now.Month()) < len(elapsed) {
+			elapsed = elapsed[:now.Month()]
+		}
+		if busiest, quietest, ok := monthExtremes(elapsed); ok && summary.TotalHours > 0 {
+			display.Printf("🔥 Busiest month: %s (%s)\n", monthName(busiest.Month), display.FormatDuration(busiest.Hours))
+			display.Printf("💤 Quietest month: %s (%s)\n", monthName(quietest.Month), display.FormatDuration(quietest.Hours))
+		}
+		display.Println()
+
+		printHourBreakdown("Project", summary.ByProject)
+		printHourBreakdown("Source", summary.BySource)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(yearCmd)
+
+	yearCmd.Flags().IntVar(&yearFlag, "year", 0, "Year to summarize (default: current year)")
+}
+
+// monthName turns YYYY-MM into the month's name
+func monthName(month string) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return month
+	}
+	return t.Format("January")
+}
+
+// countWorkingDays counts the weekdays from first to last, inclusive
+func countWorkingDays(first, last time.Time) int {
+	count := 0
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+// monthExtremes returns the months with the most and fewest hours
+func monthExtremes(months []api.MonthlySummary) (busiest, quietest api.MonthlySummary, ok bool) {
+	if len(months) == 0 {
+		return busiest, quietest, false
+	}
+	busiest, quietest = months[0], months[0]
+	for _, month := range months[1:] {
+		if month.Hours > busiest.Hours {
+			busiest = month
+		}
+		if month.Hours < quietest.Hours {
+			quietest = month
+		}
+	}
+	return busiest, quietest, true
+}
+
+// printHourBreakdown prints hours per key, largest first
+func printHourBreakdown(title string, hours map[string]float64) {
+	if len(hours) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(hours))
+	for key := range hours {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if hours[keys[i]] != hours[keys[j]] {
+			return hours[keys[i]] > hours[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	display.Printf("Breakdown by %s:\n", title)
+	for _, key := range keys {
+		display.Printf("  • %-20s %9s\n", key+":", display.FormatDuration(hours[key]))
+	}
+	display.Println()
+}
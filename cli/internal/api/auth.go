@@ -1,15 +1,36 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/vmiller/timetracker-cli/internal/config"
 )
 
+// ErrOTPRequired is returned by Login when the account uses two-factor
+// authentication and no one-time code was given
+var ErrOTPRequired = errors.New("one-time code required")
+
+// ErrOTPInvalid is returned by Login when the one-time code was wrong,
+// expired or an already used recovery code
+var ErrOTPInvalid = errors.New("invalid or expired one-time code")
+
 // LoginRequest represents the login request body
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	OTP      string `json:"otp,omitempty"` // TOTP or recovery code
+}
+
+// loginFailure is the body of a rejected login. OTPRequired marks the
+// two-factor challenge.
+type loginFailure struct {
+	Error       string `json:"error"`
+	OTPRequired bool   `json:"otpRequired"`
 }
 
 // LoginResponse represents the login response
@@ -31,35 +52,82 @@ type RefreshResponse struct {
 	ExpiresIn    int    `json:"expiresIn"`
 }
 
-// Login authenticates the user and stores tokens
-func (c *Client) Login(username, password string) error {
+// Login authenticates the user and stores tokens. otp is the one-time or
+// recovery code for accounts with two-factor authentication, and may be
+// empty otherwise. Returns ErrOTPRequired or ErrOTPInvalid when the server
+// asks for a (different) code.
+func (c *Client) Login(username, password, otp string) error {
 	req := LoginRequest{
 		Username: username,
 		Password: password,
+		OTP:      otp,
 	}
 
 	var resp LoginResponse
-	if err := c.Post("/api/auth/cli-login", req, &resp); err != nil {
-		return fmt.Errorf("login failed: %w", err)
+	var failure loginFailure
+	r, err := c.withTimeout(c.baseContext(), func(ctx context.Context) (*resty.Response, error) {
+		return c.resty.R().
+			SetContext(ctx).
+			SetHeader("Content-Type", "application/json").
+			SetBody(req).
+			SetResult(&resp).
+			SetError(&failure).
+			Post("/api/auth/cli-login")
+	})
+	if err != nil {
+		return fmt.Errorf("login failed: %w", requestError(err))
+	}
+
+	if r.StatusCode() == http.StatusUnauthorized && failure.OTPRequired {
+		if otp == "" {
+			return ErrOTPRequired
+		}
+		return ErrOTPInvalid
+	}
+
+	if r.IsError() {
+		return fmt.Errorf("login failed: %w", newError(r, r.Body()))
 	}
 
+	return c.saveLogin(&resp, config.AuthModePassword)
+}
+
+// saveLogin stores the tokens of a successful login
+func (c *Client) saveLogin(resp *LoginResponse, mode string) error {
 	// Update config with new tokens
 	c.config.AccessToken = resp.AccessToken
 	c.config.RefreshToken = resp.RefreshToken
+	c.config.TokenExpiry = c.tokenExpiry(resp.ExpiresIn)
+	c.config.AuthMode = mode
 
 	// Update client auth token
 	c.SetAuthToken(resp.AccessToken)
 
 	// Save config
-	if err := config.Save(c.config); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	return c.persist()
+}
+
+// LoginWithToken checks a personal access token against the server and
+// stores it in place of any previous credentials. Returns ErrTokenRejected if
+// the server doesn't accept it.
+func (c *Client) LoginWithToken(token string) error {
+	c.config.AuthMode = config.AuthModeToken
+	c.SetAuthToken(token)
+
+	if _, err := c.GetProviderStatus(); err != nil {
+		return err
 	}
 
-	return nil
+	c.config.RefreshToken = ""
+	c.config.TokenExpiry = time.Time{}
+	return c.persist()
 }
 
 // RefreshToken refreshes the access token using the refresh token
 func (c *Client) RefreshToken() error {
+	if c.config.AuthMode == config.AuthModeToken {
+		return fmt.Errorf("personal access tokens can't be refreshed")
+	}
 	if c.config.RefreshToken == "" {
 		return fmt.Errorf("no refresh token available")
 	}
@@ -69,21 +137,60 @@ func (c *Client) RefreshToken() error {
 	}
 
 	var resp RefreshResponse
-	if err := c.Post("/api/auth/cli-refresh", req, &resp); err != nil {
-		return fmt.Errorf("token refresh failed: %w", err)
+	r, err := c.withTimeout(c.baseContext(), func(ctx context.Context) (*resty.Response, error) {
+		return c.resty.R().
+			SetContext(ctx).
+			SetHeader("Content-Type", "application/json").
+			SetBody(req).
+			SetResult(&resp).
+			Post("/api/auth/cli-refresh")
+	})
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w", requestError(err))
+	}
+	switch {
+	case r.StatusCode() == http.StatusUnauthorized || r.StatusCode() == http.StatusForbidden:
+		return ErrSessionExpired
+	case r.IsError():
+		return fmt.Errorf("token refresh failed: %w", newError(r, r.Body()))
 	}
 
 	// Update config with new tokens
 	c.config.AccessToken = resp.AccessToken
 	c.config.RefreshToken = resp.RefreshToken
+	c.config.TokenExpiry = c.tokenExpiry(resp.ExpiresIn)
 
 	// Update client auth token
 	c.SetAuthToken(resp.AccessToken)
 
 	// Save config
+	return c.persist()
+}
+
+// tokenExpiry returns when an access token valid for expiresIn seconds
+// from now expires by the server's clock, or zero if the server didn't say
+func (c *Client) tokenExpiry(expiresIn int) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return c.serverNow().Add(time.Duration(expiresIn) * time.Second).Truncate(time.Second)
+}
+
+// tokenExpiring reports whether the access token expires within
+// refreshMargin, as far as its stored expiry tells
+func (c *Client) tokenExpiring() bool {
+	expiry := c.config.TokenExpiry
+	return !expiry.IsZero() && c.serverNow().Add(refreshMargin).After(expiry)
+}
+
+// persist saves the tokens to the config file, unless they belong to this
+// session only
+func (c *Client) persist() error {
+	if c.config.Session {
+		return nil
+	}
 	if err := config.Save(c.config); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-
 	return nil
 }
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestPersonalAccessTokenIsNeverRefreshed(t *testing.T) {
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/auth/cli-refresh" {
+			refreshes++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Invalid personal access token"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIURL: server.URL, RefreshToken: "stale", AuthMode: config.AuthModeToken}
+	client := NewClient(cfg)
+
+	if err := client.RefreshTokenIfNeeded(); err != nil {
+		t.Fatalf("RefreshTokenIfNeeded: %v", err)
+	}
+	if err := client.LoginWithToken("ttp_revoked"); !errors.Is(err, ErrTokenRejected) {
+		t.Errorf("LoginWithToken error = %v, want ErrTokenRejected", err)
+	}
+	if err := client.Get("/api/providers/status", nil); !errors.Is(err, ErrTokenRejected) {
+		t.Errorf("Get error = %v, want ErrTokenRejected", err)
+	}
+	if refreshes != 0 {
+		t.Errorf("refreshed %d times, want none", refreshes)
+	}
+
+	// The same 401 for a password login is an ordinary API error
+	cfg.AuthMode = config.AuthModePassword
+	if err := client.Get("/api/providers/status", nil); err == nil || errors.Is(err, ErrTokenRejected) {
+		t.Errorf("Get error = %v, want a plain 401", err)
+	}
+}
+
+func TestLoginDetectsOTPChallenge(t *testing.T) {
+	var otps []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req LoginRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		otps = append(otps, req.OTP)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.OTP {
+		case "":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"TOTP required","otpRequired":true}`))
+		case "123456":
+			w.Write([]byte(`{"accessToken":"a","refreshToken":"r","expiresIn":900}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Invalid or expired one-time code","otpRequired":true}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	if err := client.Login("admin", "secret", ""); !errors.Is(err, ErrOTPRequired) {
+		t.Errorf("Login without code = %v, want ErrOTPRequired", err)
+	}
+	if err := client.Login("admin", "secret", "000000"); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("Login with wrong code = %v, want ErrOTPInvalid", err)
+	}
+	if got := strings.Join(otps, ","); got != ",000000" {
+		t.Errorf("sent codes %q", got)
+	}
+}
+
+// expiringServer accepts only the access token "fresh", which it hands out
+// for the refresh token "valid", and counts refreshes
+func expiringServer(t *testing.T, refreshes *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/auth/cli-refresh" {
+			atomic.AddInt32(refreshes, 1)
+			var req RefreshRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.RefreshToken != "valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Invalid refresh token"}`))
+				return
+			}
+			w.Write([]byte(`{"accessToken":"fresh","refreshToken":"rotated","expiresIn":900}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Token expired"}`))
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]string{"echo": body["note"]})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExpiredAccessTokenIsRefreshedAndRetried(t *testing.T) {
+	var refreshes int32
+	server := expiringServer(t, &refreshes)
+	cfg := &config.Config{APIURL: server.URL, AccessToken: "expired", RefreshToken: "valid", AuthMode: config.AuthModePassword, Session: true}
+	client := NewClient(cfg)
+
+	// Concurrent requests that all get a 401 share one refresh
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = client.Get("/api/entries/summary/today", nil)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("Get error = %v, want success after a refresh", err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("refreshed %d times, want once", refreshes)
+	}
+	if cfg.AccessToken != "fresh" || cfg.RefreshToken != "rotated" {
+		t.Errorf("tokens after refresh: %q, %q", cfg.AccessToken, cfg.RefreshToken)
+	}
+
+	// A replayed request sends its body again
+	client.SetAuthToken("expired-again")
+	cfg.RefreshToken = "valid"
+	var result map[string]string
+	if err := client.Post("/api/notes", map[string]string{"note": "kept"}, &result); err != nil {
+		t.Fatalf("Post error = %v", err)
+	}
+	if result["echo"] != "kept" {
+		t.Errorf("replayed body = %v", result)
+	}
+}
+
+func TestDisabledRefreshReportsExpiredToken(t *testing.T) {
+	var refreshes int32
+	server := expiringServer(t, &refreshes)
+	// Expired by its stored expiry as well as by the server
+	cfg := &config.Config{APIURL: server.URL, AccessToken: "expired", RefreshToken: "valid", TokenExpiry: time.Now().Add(-time.Minute), AuthMode: config.AuthModePassword, Session: true}
+	client := NewClient(cfg)
+	client.DisableRefresh()
+
+	var apiErr *Error
+	if err := client.Get("/api/entries/summary/today", nil); !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("Get error = %v, want the 401", err)
+	}
+	if refreshes != 0 {
+		t.Errorf("refreshed %d times, want none", refreshes)
+	}
+
+	// An explicit refresh still works
+	if err := client.RefreshToken(); err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if refreshes != 1 || cfg.AccessToken != "fresh" {
+		t.Errorf("after RefreshToken: %d refreshes, access token %q", refreshes, cfg.AccessToken)
+	}
+}
+
+func TestExpiredSessionNeedsLogin(t *testing.T) {
+	var refreshes int32
+	server := expiringServer(t, &refreshes)
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "expired", RefreshToken: "expired", AuthMode: config.AuthModePassword, Session: true})
+
+	if err := client.Get("/api/entries/summary/today", nil); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Get error = %v, want ErrSessionExpired", err)
+	}
+	if refreshes != 1 {
+		t.Errorf("refreshed %d times, want once", refreshes)
+	}
+
+	// Auth endpoints never trigger a refresh
+	refreshes = 0
+	if err := client.Post("/api/auth/cli-sso/token", nil, nil); err == nil || errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Post to an auth endpoint = %v, want its own 401", err)
+	}
+	if refreshes != 0 {
+		t.Errorf("refreshed %d times for an auth endpoint", refreshes)
+	}
+}
+
+func TestExpiringAccessTokenIsRefreshedFirst(t *testing.T) {
+	serverClock := time.Now().Add(time.Hour)
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Date", serverClock.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/auth/cli-refresh" {
+			w.Write([]byte(`{"accessToken":"fresh","refreshToken":"r2","expiresIn":900}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// Valid for 30 more seconds by the local clock
+	cfg := &config.Config{APIURL: server.URL, AccessToken: "old", RefreshToken: "r1", TokenExpiry: time.Now().Add(30 * time.Second), Session: true}
+	client := NewClient(cfg)
+	if err := client.Get("/api/entries", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(paths, ","); got != "/api/auth/cli-refresh,/api/entries" {
+		t.Fatalf("requests %s, want a refresh first", got)
+	}
+
+	// The new expiry follows the server's clock, an hour ahead
+	if want := serverClock.Add(900 * time.Second); cfg.TokenExpiry.Sub(want).Abs() > 2*time.Second {
+		t.Errorf("expiry = %s, want about %s", cfg.TokenExpiry, want)
+	}
+	paths = nil
+	if err := client.Get("/api/entries", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("requests %v, want no refresh with 15 minutes left", paths)
+	}
+}
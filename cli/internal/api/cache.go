@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/vmiller/timetracker-cli/internal/httpcache"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+)
+
+// SetCache makes GET requests conditional on the responses kept in cache:
+// bodies the server tagged with an ETag or Last-Modified are stored, sent
+// back as If-None-Match or If-Modified-Since, and reused when the server
+// answers 304 Not Modified. A nil cache turns this off.
+func (c *Client) SetCache(cache *httpcache.Cache) {
+	c.cache = cache
+}
+
+// cached returns the cached response for a GET of endpoint, if any
+func (c *Client) cached(endpoint string) *httpcache.Entry {
+	if c.cache == nil {
+		return nil
+	}
+	entry, ok := c.cache.Get(c.resty.BaseURL + endpoint)
+	if !ok {
+		return nil
+	}
+	return entry
+}
+
+// setValidators makes req conditional on entry
+func setValidators(req *resty.Request, entry *httpcache.Entry) {
+	if entry == nil {
+		return
+	}
+	if entry.ETag != "" {
+		req.SetHeader("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.SetHeader("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// useCache decodes the cached body into result after a 304, or stores a
+// fresh response that carries validators. Failing to store one only costs
+// the next request its shortcut.
+func (c *Client) useCache(endpoint string, entry *httpcache.Entry, resp *resty.Response, result interface{}) error {
+	if resp.StatusCode() == http.StatusNotModified {
+		if entry == nil || result == nil {
+			return nil
+		}
+		logging.Verbosef("  reused the cached response of %s", logging.RedactURL(endpoint))
+		return json.Unmarshal([]byte(entry.Body), result)
+	}
+
+	etag, modified := resp.Header().Get("ETag"), resp.Header().Get("Last-Modified")
+	if c.cache == nil || !resp.IsSuccess() || (etag == "" && modified == "") {
+		return nil
+	}
+	err := c.cache.Put(httpcache.Entry{
+		URL:          c.resty.BaseURL + endpoint,
+		ETag:         etag,
+		LastModified: modified,
+		Body:         string(resp.Body()),
+		StoredAt:     time.Now(),
+	})
+	if err != nil {
+		logging.Verbosef("  couldn't cache %s: %v", logging.RedactURL(endpoint), err)
+	}
+	return nil
+}
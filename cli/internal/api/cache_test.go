@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/httpcache"
+)
+
+func TestGetReusesCachedResponseOn304(t *testing.T) {
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"totalHours":7.5}`))
+	}))
+	defer server.Close()
+
+	cache := httpcache.New(t.TempDir())
+	for i := 0; i < 2; i++ {
+		client := NewClient(&config.Config{APIURL: server.URL})
+		client.SetCache(cache)
+		var summary struct {
+			TotalHours float64 `json:"totalHours"`
+		}
+		if err := client.Get("/api/entries/summary/today", &summary); err != nil {
+			t.Fatalf("Get %d: %v", i, err)
+		}
+		if summary.TotalHours != 7.5 {
+			t.Errorf("Get %d decoded %+v", i, summary)
+		}
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != `"v1"` {
+		t.Errorf("If-None-Match sent: %q", conditional)
+	}
+
+	// Without a cache nothing is conditional
+	client := NewClient(&config.Config{APIURL: server.URL})
+	if err := client.Get("/api/entries/summary/today", nil); err != nil {
+		t.Fatal(err)
+	}
+	if conditional[2] != "" {
+		t.Errorf("If-None-Match %q without a cache", conditional[2])
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import "time"
+
+// DefaultChunkDays is the default longest range fetched in a single request
+const DefaultChunkDays = 92
+
+// DateRange is an inclusive range of YYYY-MM-DD dates
+type DateRange struct {
+	From string
+	To   string
+}
+
+// SplitRange splits [from, to] into consecutive ranges of at most days days.
+// Open or invalid ranges, and days <= 0, yield the range unchanged.
+func SplitRange(from, to string, days int) []DateRange {
+	whole := []DateRange{{From: from, To: to}}
+	if days <= 0 || from == "" || to == "" {
+		return whole
+	}
+
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return whole
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil || end.Before(start) {
+		return whole
+	}
+
+	var chunks []DateRange
+	for chunkStart := start; !chunkStart.After(end); chunkStart = chunkStart.AddDate(0, 0, days) {
+		chunkEnd := chunkStart.AddDate(0, 0, days-1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		chunks = append(chunks, DateRange{
+			From: chunkStart.Format("2006-01-02"),
+			To:   chunkEnd.Format("2006-01-02"),
+		})
+	}
+
+	return chunks
+}
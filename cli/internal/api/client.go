@@ -1,32 +1,324 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/httpcache"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+	"github.com/vmiller/timetracker-cli/internal/version"
 )
 
+// ErrNotFound matches the Error of a 404 Not Found response
+var ErrNotFound = errors.New("not found")
+
+// ErrTokenRejected is returned when the server refuses a personal access token
+var ErrTokenRejected = errors.New("the server rejected your personal access token; generate a new one in the dashboard and run 'timetracker login --token'")
+
+// ErrProxyAuth is returned when the proxy requests go through refuses them
+// for missing or wrong credentials
+var ErrProxyAuth = errors.New("the proxy rejected the request: 407 Proxy Authentication Required")
+
+// ErrSessionExpired is returned when the access token has expired and the
+// refresh token is rejected too
+var ErrSessionExpired = errors.New("session expired, run 'timetracker login'")
+
+// Retry policy of idempotent requests, unless the config sets another
+const (
+	DefaultAttempts   = 3
+	DefaultRetryDelay = 500 * time.Millisecond
+
+	// maxRetryDelay caps the exponential backoff
+	maxRetryDelay = 10 * time.Second
+)
+
+// Timeouts of requests. dialTimeout and tlsTimeout bound connecting to the
+// server whatever a request's own timeout.
+const (
+	DefaultTimeout = 30 * time.Second
+
+	dialTimeout = 10 * time.Second
+	tlsTimeout  = 10 * time.Second
+)
+
+// Proactive token refresh. Access tokens are refreshed refreshMargin before
+// they expire. Expiries are kept by the server's clock, which is taken from
+// its Date header once that differs from the local clock by more than
+// maxClockSkew; smaller differences are within the header's precision and
+// ordinary drift.
+const (
+	refreshMargin = 60 * time.Second
+	maxClockSkew  = 2 * time.Minute
+)
+
+// timeoutKey carries a request's own timeout in its context
+type timeoutKey struct{}
+
+// WithTimeout makes requests sent with ctx wait up to d for the server
+// instead of the client's timeout, for long operations such as a forced
+// sync. Zero leaves it to ctx's own deadline, if any.
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// TimeoutError is returned when the server doesn't respond within a
+// request's timeout
+type TimeoutError struct {
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("server did not respond within %s", e.After)
+}
+
+// authEndpoints issue tokens themselves, so a 401 from them is never
+// answered with a refresh
+var authEndpoints = []string{"/api/auth/cli-login", "/api/auth/cli-refresh", "/api/auth/cli-sso/token"}
+
 // Client wraps the HTTP client with authentication
 type Client struct {
-	resty  *resty.Client
-	config *config.Config
+	resty     *resty.Client
+	transport *http.Transport // under the gzip transport
+	config    *config.Config
+
+	// refreshMu serializes refreshes, so concurrent requests that all got a
+	// 401 refresh the tokens once
+	refreshMu sync.Mutex
+	noRefresh bool
+
+	timeout    time.Duration
+	attempts   int
+	retryDelay time.Duration
+
+	chunkDays int
+	onChunk   func(done, total int)
+
+	maxPages int
+	onPage   func(done, total int)
+
+	limiter     *rateLimiter // nil for no limit
+	onRateLimit func(wait time.Duration)
+
+	// skew is how far the server's clock is ahead of the local one, in
+	// nanoseconds, or zero if within maxClockSkew
+	skew atomic.Int64
+
+	cache *httpcache.Cache
+
+	lastKnown    *httpcache.Cache
+	offline      OfflineMode
+	offlineMu    sync.Mutex
+	offlineSince time.Time
+
+	// ctx is what requests made without one are abandoned with
+	ctx context.Context
 }
 
-// NewClient creates a new API client
+// NewClient creates a new API client. Tokens set in the environment take
+// precedence over the config file's.
 func NewClient(cfg *config.Config) *Client {
+	cfg.UseEnvCredentials()
+
 	client := resty.New()
 	client.SetBaseURL(cfg.APIURL)
+	transport, err := client.Transport()
+	if err == nil {
+		transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+		transport.TLSHandshakeTimeout = tlsTimeout
+		// Load has checked the URL; the environment's proxy applies otherwise
+		if proxy, err := url.Parse(cfg.ProxyURL); err == nil && cfg.ProxyURL != "" {
+			transport.Proxy = http.ProxyURL(proxy)
+		}
+		if cfg.CACert != "" || cfg.InsecureSkipVerify {
+			pool, err := cfg.CertPool()
+			if err != nil {
+				// Requests report the bundle's problem, not an x509 error
+				client.OnBeforeRequest(func(*resty.Client, *resty.Request) error { return err })
+			}
+			transport.TLSClientConfig = &tls.Config{
+				RootCAs:            pool,
+				InsecureSkipVerify: cfg.InsecureSkipVerify,
+			}
+		}
+		client.SetTransport(&gzipTransport{base: transport})
+	}
 
 	// Set access token if available
 	if cfg.AccessToken != "" {
 		client.SetAuthToken(cfg.AccessToken)
 	}
 
-	return &Client{
-		resty:  client,
-		config: cfg,
+	client.SetHeader("User-Agent", UserAgent())
+	client.SetHeader("X-Client-Version", version.Version)
+	logRequests(client)
+	sendRequestID(client)
+	sendTimezone(client)
+	noticeDeprecation(client)
+
+	c := &Client{
+		resty:     client,
+		transport: transport,
+		config:    cfg,
+		timeout:   cfg.Timeout,
+	}
+	c.SetRetries(cfg.Attempts, cfg.RetryDelay)
+	c.SetPaging(cfg.MaxPages, nil)
+	c.SetRateLimit(cfg.RateLimit, nil)
+	c.limitRequests()
+	c.trackClockSkew()
+	return c
+}
+
+// logRequests logs every request's method, URL, status and duration in
+// verbose runs, their headers in debug runs and their bodies at
+// logging.DebugBodies. Secrets in URLs, headers and bodies are redacted.
+func logRequests(client *resty.Client) {
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		serverID := ""
+		if id := serverRequestID(resp); id != "" {
+			serverID = " [server request id: " + id + "]"
+		}
+		logging.Verbosef("→ %s %s %s (%s)%s", resp.Request.Method, logging.RedactURL(resp.Request.URL),
+			resp.Status(), resp.Time().Round(time.Millisecond), serverID)
+		logCompression(resp.RawResponse)
+		if logging.Enabled(logging.Debug) {
+			logExchange(resp)
+		}
+		return nil
+	})
+	client.OnError(func(req *resty.Request, err error) {
+		if _, ok := err.(*resty.ResponseError); ok {
+			return // already logged with its status
+		}
+		logging.Verbosef("→ %s %s failed: %v", req.Method, logging.RedactURL(req.URL), err)
+	})
+}
+
+// logExchange logs the headers of a request and its response, and in
+// logging.DebugBodies runs their bodies, with secrets redacted
+func logExchange(resp *resty.Response) {
+	var sb strings.Builder
+	if raw := resp.Request.RawRequest; raw != nil {
+		sb.WriteString(logging.FormatHeader("  > ", raw.Header))
+	}
+	if logging.Enabled(logging.DebugBodies) {
+		if body := requestBody(resp.Request.Body); body != "" {
+			sb.WriteString("  > " + body + "\n")
+		}
+	}
+	sb.WriteString(logging.FormatHeader("  < ", resp.Header()))
+	if logging.Enabled(logging.DebugBodies) {
+		if body := logging.RedactBody(resp.Body()); body != "" {
+			sb.WriteString("  < " + body + "\n")
+		}
+	}
+	logging.Debugf("%s", sb.String())
+}
+
+// requestBody renders a request body for logging, redacted
+func requestBody(body interface{}) string {
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return logging.RedactBody(b)
+	case string:
+		return logging.RedactBody([]byte(b))
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Sprintf("(%T)", body)
+	}
+	return logging.RedactBody(encoded)
+}
+
+// UserAgent identifies the CLI, its version and platform to the server,
+// e.g. "timetracker-cli/1.4.0 (linux; amd64)"
+func UserAgent() string {
+	return fmt.Sprintf("timetracker-cli/%s (%s; %s)", strings.TrimPrefix(version.Version, "v"), runtime.GOOS, runtime.GOARCH)
+}
+
+// deprecationNotice makes sure a run prints the server's deprecation
+// warning only once, however many requests carry it
+var deprecationNotice sync.Once
+
+// noticeDeprecation prints the first Warning or X-Deprecated header the
+// server sends, such as when this CLI version will stop being supported
+func noticeDeprecation(client *resty.Client) {
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		if warning := deprecationWarning(resp.Header()); warning != "" {
+			deprecationNotice.Do(func() {
+				logging.Noticef("⚠️  %s", warning)
+			})
+		}
+		return nil
+	})
+}
+
+// deprecationWarning returns the text of an X-Deprecated header, or of a
+// Warning header such as `299 - "CLI 1.2 is deprecated"`
+func deprecationWarning(header http.Header) string {
+	if deprecated := strings.TrimSpace(header.Get("X-Deprecated")); deprecated != "" {
+		return deprecated
 	}
+	warning := strings.TrimSpace(header.Get("Warning"))
+	if start := strings.Index(warning, `"`); start >= 0 {
+		if end := strings.Index(warning[start+1:], `"`); end >= 0 {
+			return warning[start+1 : start+1+end]
+		}
+	}
+	return warning
+}
+
+// sendTimezone adds the local timezone to summary requests, so the server
+// works out "today" and week boundaries where the user is rather than in its
+// own zone. Older servers ignore the parameter.
+func sendTimezone(client *resty.Client) {
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		if zone := dates.LocalZone(); zone != "" && strings.HasPrefix(req.URL, "/api/entries/summary/") {
+			req.SetQueryParam("tz", zone)
+		}
+		return nil
+	})
+}
+
+// trackClockSkew compares the Date header of every response with the
+// local clock, so token expiries can be kept by the server's clock
+func (c *Client) trackClockSkew() {
+	c.resty.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		date, err := http.ParseTime(resp.Header().Get("Date"))
+		if err != nil {
+			return nil
+		}
+		skew := date.Sub(resp.ReceivedAt())
+		if skew > -maxClockSkew && skew < maxClockSkew {
+			skew = 0
+		} else if c.skew.Load() == 0 {
+			logging.Verbosef("🕑 The server's clock is %s off; token expiries follow it", skew.Round(time.Second))
+		}
+		c.skew.Store(int64(skew))
+		return nil
+	})
+}
+
+// serverNow returns the time by the server's clock, as far as it is known
+func (c *Client) serverNow() time.Time {
+	return time.Now().Add(time.Duration(c.skew.Load()))
 }
 
 // SetAuthToken updates the authorization token
@@ -35,69 +327,376 @@ func (c *Client) SetAuthToken(token string) {
 	c.resty.SetAuthToken(token)
 }
 
-// RefreshTokenIfNeeded checks if token refresh is needed and refreshes if so
+// SetContext makes requests that aren't given a context, like Get's, stop
+// when ctx is done, such as when the user presses Ctrl-C
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// baseContext returns the context set with SetContext, or the background one
+func (c *Client) baseContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// SetRetries sets how often idempotent requests are tried, and how long the
+// first retry waits; each further retry waits twice as long. Values below
+// one attempt or zero delay keep the defaults.
+func (c *Client) SetRetries(attempts int, delay time.Duration) {
+	if attempts < 1 {
+		attempts = DefaultAttempts
+	}
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	c.attempts, c.retryDelay = attempts, delay
+}
+
+// SetChunking makes ranged entry fetches split closed ranges longer than days
+// into sequential requests. progress, if set, is called with the number of
+// completed chunks before the first and after every chunk of a split range.
+// A days value of zero or less disables chunking.
+func (c *Client) SetChunking(days int, progress func(done, total int)) {
+	c.chunkDays = days
+	c.onChunk = progress
+}
+
+// DisableRefresh stops requests from refreshing the tokens, before they are
+// sent or after a 401, so a rejected access token is reported as it is.
+// RefreshToken still refreshes them when called.
+func (c *Client) DisableRefresh() {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.noRefresh = true
+}
+
+// RefreshTokenIfNeeded refreshes the tokens when there is no access token
+// or it expires within refreshMargin, so long-running commands don't wait
+// for a 401 to find out. Tokens without a known expiry are refreshed when
+// a request is refused instead. Personal access tokens don't expire, so
+// they are never refreshed.
 func (c *Client) RefreshTokenIfNeeded() error {
-	// If we have a refresh token but no access token, refresh
-	if c.config.RefreshToken != "" && c.config.AccessToken == "" {
-		return c.RefreshToken()
+	if c.config.AuthMode == config.AuthModeToken {
+		return nil
 	}
 
-	// Don't auto-refresh on every request - tokens last 15 minutes
-	// Only refresh if we get a 401 error (handled in the request methods)
+	// Concurrent requests refresh once; the others then see the new expiry
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	switch {
+	case c.noRefresh || c.config.RefreshToken == "":
+		return nil
+	case c.config.AccessToken == "":
+		return c.RefreshToken()
+	case c.tokenExpiring():
+		logging.Verbosef("Access token expires at %s; refreshing it first", c.config.TokenExpiry.Local().Format(time.TimeOnly))
+		return c.RefreshToken()
+	}
 	return nil
 }
 
-// Get performs a GET request with automatic token refresh
-func (c *Client) Get(endpoint string, result interface{}) error {
-	// Try to refresh token if needed
-	if err := c.RefreshTokenIfNeeded(); err != nil {
-		// If refresh fails, continue anyway (user might need to login)
+// isAuthEndpoint reports whether endpoint issues tokens
+func isAuthEndpoint(endpoint string) bool {
+	for _, auth := range authEndpoints {
+		if endpoint == auth {
+			return true
+		}
+	}
+	return false
+}
+
+// withRefresh sends a request and, if the server answers 401 because the
+// access token has expired, refreshes the tokens once and sends it again.
+// send must build a new request on every call. Returns ErrSessionExpired if
+// the refresh token is rejected as well.
+func (c *Client) withRefresh(endpoint string, send func() (*resty.Response, error)) (*resty.Response, error) {
+	c.refreshMu.Lock()
+	used := c.config.AccessToken
+	refreshable := !c.noRefresh && c.config.AuthMode != config.AuthModeToken && c.config.RefreshToken != "" && !isAuthEndpoint(endpoint)
+	c.refreshMu.Unlock()
+
+	resp, err := send()
+	if err != nil || resp.StatusCode() != http.StatusUnauthorized || !refreshable {
+		return resp, err
+	}
+	if body := resp.RawBody(); body != nil {
+		body.Close()
+	}
+
+	if err := c.refreshExpired(used); err != nil {
+		return nil, err
 	}
+	logging.Verbosef("Access token refreshed; retrying %s", endpoint)
+	return send()
+}
 
-	resp, err := c.resty.R().
-		SetResult(result).
-		Get(endpoint)
+// withRetry sends an idempotent request up to the client's attempts,
+// backing off with jitter after network errors, timeouts and 502, 503 or 504
+// responses. Other responses, 4xx included, are returned as they are, and
+// cancelling ctx stops the retries. send must build a new request on every
+// call.
+func (c *Client) withRetry(ctx context.Context, endpoint string, send func() (*resty.Response, error)) (*resty.Response, error) {
+	delay := c.retryDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		reason := retryReason(resp, err)
+		if reason == "" || attempt >= c.attempts || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil && resp.RawBody() != nil {
+			resp.RawBody().Close()
+		}
 
+		wait := delay - time.Duration(rand.Int63n(int64(delay/2)+1))
+		logging.Verbosef("↻ %s: attempt %d of %d failed (%s); retrying in %s", logging.RedactURL(endpoint), attempt, c.attempts, reason, wait.Round(10*time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-time.After(wait):
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// withTimeout sends a request with the client's timeout, or the one ctx
+// asks for with WithTimeout, and reports running out of it as a
+// TimeoutError. send must use the context it is given.
+func (c *Client) withTimeout(ctx context.Context, send func(ctx context.Context) (*resty.Response, error)) (*resty.Response, error) {
+	timeout := c.timeout
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		timeout = d
+	}
+	if timeout <= 0 {
+		return send(ctx)
+	}
+
+	timed, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := send(timed)
+	if err != nil && ctx.Err() == nil && errors.Is(timed.Err(), context.DeadlineExceeded) {
+		return resp, &TimeoutError{After: timeout}
+	}
+	return resp, err
+}
+
+// requestError describes a request that got no response. Timeouts and
+// expired sessions speak for themselves.
+func requestError(err error) error {
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) || errors.Is(err, ErrSessionExpired) {
+		return err
+	}
+	if isProxyAuthFailure(err) {
+		return ErrProxyAuth
+	}
+	var urlErr *url.Error
+	if errors.Is(err, ErrNoFixture) && errors.As(err, &urlErr) {
+		return urlErr.Err // names the request already
+	}
+	var preflight *PreflightError
+	if errors.As(err, &preflight) {
+		return err
+	}
+	if isCertificateError(err) {
+		return fmt.Errorf("the server's certificate isn't trusted (set ca_cert to your CA's PEM bundle): %w", err)
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		return fmt.Errorf("failed to reach the proxy: %w", err)
+	}
+	return fmt.Errorf("request failed: %w", err)
+}
+
+// isProxyAuthFailure reports whether err is a proxy refusing to tunnel an
+// HTTPS request for lack of credentials. The transport reports it with the
+// status text alone.
+func isProxyAuthFailure(err error) bool {
+	return err != nil && strings.HasSuffix(err.Error(), http.StatusText(http.StatusProxyAuthRequired))
+}
+
+// isCertificateError reports whether err is the server's certificate failing
+// verification, which no retry fixes
+func isCertificateError(err error) bool {
+	var verify *tls.CertificateVerificationError
+	return errors.As(err, &verify)
+}
+
+// Proxy returns the proxy requests to the API go through, or nil if they
+// connect directly
+func (c *Client) Proxy() (*url.URL, error) {
+	if c.transport == nil || c.transport.Proxy == nil {
+		return nil, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, c.resty.BaseURL, nil)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
+	return c.transport.Proxy(req)
+}
 
-	if resp.IsError() {
-		return fmt.Errorf("API error: %s - %s", resp.Status(), resp.String())
+// retryReason describes why a request may succeed when sent again, or
+// returns "" if it won't
+func retryReason(resp *resty.Response, err error) string {
+	if err != nil {
+		if IsTransient(err) {
+			return err.Error()
+		}
+		return ""
 	}
+	switch resp.StatusCode() {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return resp.Status()
+	}
+	return ""
+}
 
-	return nil
+// refreshExpired refreshes the tokens after a request sent with the access
+// token used got a 401, unless another request has refreshed them since
+func (c *Client) refreshExpired(used string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if c.config.AccessToken != used {
+		return nil
+	}
+	return c.RefreshToken()
+}
+
+// Get performs a GET request with automatic token refresh
+func (c *Client) Get(endpoint string, result interface{}) error {
+	return c.GetContext(c.baseContext(), endpoint, result)
+}
+
+// GetContext performs a GET request that is abandoned when ctx is done
+func (c *Client) GetContext(ctx context.Context, endpoint string, result interface{}) error {
+	return c.doOffline(ctx, http.MethodGet, endpoint, nil, result, true)
 }
 
 // Post performs a POST request with automatic token refresh
 func (c *Client) Post(endpoint string, body interface{}, result interface{}) error {
+	return c.PostContext(c.baseContext(), endpoint, body, result)
+}
+
+// PostContext performs a POST request that is abandoned when ctx is done
+func (c *Client) PostContext(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	return c.doOffline(ctx, http.MethodPost, endpoint, body, result, false)
+}
+
+// PostIdempotent performs a POST request that changes nothing on the
+// server, such as a dry run, so it is retried after transient failures like
+// a GET
+func (c *Client) PostIdempotent(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	return c.doOffline(ctx, http.MethodPost, endpoint, body, result, true)
+}
+
+// Put performs a PUT request with automatic token refresh
+func (c *Client) Put(endpoint string, body interface{}, result interface{}) error {
+	return c.PutContext(c.baseContext(), endpoint, body, result)
+}
+
+// PutContext performs a PUT request that is abandoned when ctx is done
+func (c *Client) PutContext(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	return c.doOffline(ctx, http.MethodPut, endpoint, body, result, false)
+}
+
+// Patch performs a PATCH request with automatic token refresh
+func (c *Client) Patch(endpoint string, body interface{}, result interface{}) error {
+	return c.PatchContext(c.baseContext(), endpoint, body, result)
+}
+
+// PatchContext performs a PATCH request that is abandoned when ctx is done
+func (c *Client) PatchContext(ctx context.Context, endpoint string, body interface{}, result interface{}) error {
+	return c.doOffline(ctx, http.MethodPatch, endpoint, body, result, false)
+}
+
+// Delete performs a DELETE request with automatic token refresh
+func (c *Client) Delete(endpoint string) error {
+	return c.DeleteContext(c.baseContext(), endpoint)
+}
+
+// DeleteContext performs a DELETE request that is abandoned when ctx is done
+func (c *Client) DeleteContext(ctx context.Context, endpoint string) error {
+	return c.doOffline(ctx, http.MethodDelete, endpoint, nil, nil, false)
+}
+
+// do sends a request with the given method, encoding body as JSON and
+// decoding a successful response into result when they aren't nil. The
+// request is sent again after a 401 once the tokens are refreshed, and, if
+// idempotent, after transient failures. Error responses become an *Error.
+func (c *Client) do(ctx context.Context, method, endpoint string, body interface{}, result interface{}, idempotent bool) error {
 	// Try to refresh token if needed (but not for auth endpoints)
-	if endpoint != "/api/auth/cli-login" && endpoint != "/api/auth/cli-refresh" {
+	if !isAuthEndpoint(endpoint) {
 		if err := c.RefreshTokenIfNeeded(); err != nil {
 			// If refresh fails, continue anyway (user might need to login)
 		}
 	}
 
-	req := c.resty.R()
+	var cached *httpcache.Entry
+	if method == http.MethodGet {
+		cached = c.cached(endpoint)
+	}
+
+	send := func() (*resty.Response, error) {
+		return c.withRefresh(endpoint, func() (*resty.Response, error) {
+			return c.withTimeout(ctx, func(ctx context.Context) (*resty.Response, error) {
+				req := c.resty.R().SetContext(ctx)
+				setValidators(req, cached)
+
+				if body != nil {
+					req.SetHeader("Content-Type", "application/json")
+					req.SetBody(body)
+				}
+
+				if result != nil {
+					req.SetResult(result)
+				}
 
-	if body != nil {
-		req.SetHeader("Content-Type", "application/json")
-		req.SetBody(body)
+				return req.Execute(method, endpoint)
+			})
+		})
+	}
+	limited := func() (*resty.Response, error) {
+		return c.withRateLimit(ctx, endpoint, send)
+	}
+	var resp *resty.Response
+	var err error
+	if idempotent {
+		resp, err = c.withRetry(ctx, endpoint, limited)
+	} else {
+		resp, err = limited()
 	}
 
-	if result != nil {
-		req.SetResult(result)
+	if err != nil {
+		return requestError(err)
 	}
 
-	resp, err := req.Post(endpoint)
+	if c.tokenRejected(resp) {
+		return ErrTokenRejected
+	}
 
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+	if resp.StatusCode() == http.StatusProxyAuthRequired {
+		return ErrProxyAuth
 	}
 
 	if resp.IsError() {
-		return fmt.Errorf("API error: %s - %s", resp.Status(), resp.String())
+		return newError(resp, resp.Body())
+	}
+
+	if method == http.MethodGet {
+		if err := c.useCache(endpoint, cached, resp, result); err != nil {
+			return fmt.Errorf("failed to decode cached response: %w", err)
+		}
 	}
 
 	return nil
 }
+
+// tokenRejected reports whether resp is a 401 for a personal access token,
+// which no refresh can fix
+func (c *Client) tokenRejected(resp *resty.Response) bool {
+	return resp.StatusCode() == http.StatusUnauthorized && c.config.AuthMode == config.AuthModeToken
+}
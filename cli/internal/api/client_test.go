@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/version"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient(&config.Config{APIURL: server.URL, Timeout: 20 * time.Millisecond, Attempts: 1})
+
+	err := client.Get("/api/entries/summary/today", nil)
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || err.Error() != "server did not respond within 20ms" {
+		t.Errorf("Get error = %v, want a TimeoutError", err)
+	}
+	if !IsTransient(err) {
+		t.Error("a timeout isn't transient")
+	}
+
+	// Long operations wait longer
+	ctx := WithTimeout(context.Background(), time.Second)
+	if err := client.GetContext(ctx, "/api/entries/summary/today", nil); err != nil {
+		t.Errorf("GetContext with a longer timeout = %v", err)
+	}
+}
+
+func TestSetContextCancelsRequests(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient(&config.Config{APIURL: server.URL, RetryDelay: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.SetContext(ctx)
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := client.Get("/api/entries/summary/today", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get returned after %s", elapsed)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("sent %d requests, want a cancelled one not retried", n)
+	}
+	if err := client.Put("/api/entries/1", map[string]string{}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Put error = %v, want context.Canceled", err)
+	}
+}
+
+func TestMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Entry not found"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]string{"method": r.Method, "body": string(body), "type": r.Header.Get("Content-Type")})
+	}))
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+	payload := map[string]int{"hours": 2}
+
+	tests := []struct {
+		method   string
+		call     func(endpoint string, result interface{}) error
+		wantBody string
+	}{
+		{http.MethodGet, func(e string, r interface{}) error { return client.Get(e, r) }, ""},
+		{http.MethodPost, func(e string, r interface{}) error { return client.Post(e, payload, r) }, `{"hours":2}`},
+		{http.MethodPut, func(e string, r interface{}) error { return client.Put(e, payload, r) }, `{"hours":2}`},
+		{http.MethodPatch, func(e string, r interface{}) error { return client.Patch(e, payload, r) }, `{"hours":2}`},
+		{http.MethodDelete, func(e string, r interface{}) error { return client.Delete(e) }, ""},
+	}
+	for _, tt := range tests {
+		var got map[string]string
+		if err := tt.call("/api/entries/1", &got); err != nil {
+			t.Errorf("%s: %v", tt.method, err)
+			continue
+		}
+		if tt.method != http.MethodDelete {
+			if got["method"] != tt.method || got["body"] != tt.wantBody {
+				t.Errorf("%s: server got %s %q", tt.method, got["method"], got["body"])
+			}
+			if tt.wantBody != "" && got["type"] != "application/json" {
+				t.Errorf("%s: Content-Type %q", tt.method, got["type"])
+			}
+		}
+
+		err := tt.call("/api/missing", nil)
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound || apiErr.Message != "Entry not found" || apiErr.Path != "/api/missing" {
+			t.Errorf("%s: error = %#v, want the 404's *Error", tt.method, err)
+		}
+	}
+}
+
+func TestClientIdentifiesItself(t *testing.T) {
+	var agent, clientVersion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent, clientVersion = r.Header.Get("User-Agent"), r.Header.Get("X-Client-Version")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	if err := NewClient(&config.Config{APIURL: server.URL}).Get("/api/providers/status", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(agent, "timetracker-cli/") || !strings.Contains(agent, runtime.GOOS) {
+		t.Errorf("User-Agent = %q", agent)
+	}
+	if clientVersion != version.Version {
+		t.Errorf("X-Client-Version = %q, want %q", clientVersion, version.Version)
+	}
+}
+
+func TestDeprecationWarning(t *testing.T) {
+	tests := []struct {
+		name, value, want string
+	}{
+		{"X-Deprecated", "CLI 1.2 is deprecated, upgrade by June", "CLI 1.2 is deprecated, upgrade by June"},
+		{"Warning", `299 api.example.com "CLI 1.2 is deprecated" "Wed, 21 Oct 2026 07:28:00 GMT"`, "CLI 1.2 is deprecated"},
+		{"Warning", "upgrade soon", "upgrade soon"},
+		{"X-Other", "ignored", ""},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set(tt.name, tt.value)
+		if got := deprecationWarning(header); got != tt.want {
+			t.Errorf("%s: %q gives %q, want %q", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// user:secret
+		if r.Header.Get("Proxy-Authorization") != "Basic dXNlcjpzZWNyZXQ=" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxied = append(proxied, r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer proxy.Close()
+	withCredentials := strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)
+
+	client := NewClient(&config.Config{APIURL: "http://api.example.test", ProxyURL: withCredentials, Attempts: 1})
+	if health, err := client.Health(); err != nil || health.Status != "healthy" {
+		t.Fatalf("Health through the proxy = %v, %v", health, err)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://api.example.test/api/health" {
+		t.Errorf("proxy saw %v", proxied)
+	}
+	if got, err := client.Proxy(); err != nil || got == nil || got.Host != strings.TrimPrefix(proxy.URL, "http://") {
+		t.Errorf("Proxy() = %v, %v", got, err)
+	}
+
+	client = NewClient(&config.Config{APIURL: "http://api.example.test", ProxyURL: proxy.URL, Attempts: 1})
+	err := client.Get("/health", nil)
+	if !errors.Is(err, ErrProxyAuth) {
+		t.Errorf("Get without proxy credentials = %v, want ErrProxyAuth", err)
+	}
+	if IsTransient(err) {
+		t.Error("a proxy authentication failure is transient")
+	}
+}
+
+func TestCACert(t *testing.T) {
+	var requests int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // the rejected handshake
+	server.StartTLS()
+	defer server.Close()
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewClient(&config.Config{APIURL: server.URL, RetryDelay: time.Millisecond}).Get("/health", nil)
+	if !isCertificateError(err) || IsTransient(err) {
+		t.Errorf("Get without the CA = %v, want a certificate error that isn't retried", err)
+	}
+	if err := NewClient(&config.Config{APIURL: server.URL, CACert: bundle}).Get("/health", nil); err != nil {
+		t.Errorf("Get with ca_cert = %v", err)
+	}
+	if err := NewClient(&config.Config{APIURL: server.URL, InsecureSkipVerify: true}).Get("/health", nil); err != nil {
+		t.Errorf("Get with insecure_skip_verify = %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	err = NewClient(&config.Config{APIURL: server.URL, CACert: missing}).Get("/health", nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to read ca_cert") {
+		t.Errorf("Get with a missing ca_cert = %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/vmiller/timetracker-cli/internal/logging"
+)
+
+// compressMinSize is the smallest request body worth compressing
+const compressMinSize = 8 << 10
+
+// gzipTransport asks for gzip-compressed responses and decodes them as they
+// are read, counting the bytes on the wire for the verbose log; Go's
+// transport would decode them too, but without telling how big they were.
+// Request bodies of compressMinSize or more are compressed once the server
+// has advertised that it accepts gzip, and sent again uncompressed if it
+// answers 415 after all.
+type gzipTransport struct {
+	base http.RoundTripper
+
+	// accepted is set when a response lists gzip in its Accept-Encoding
+	accepted atomic.Bool
+}
+
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context()) // RoundTrip must not modify req
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	raw, compressed, err := t.compress(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if compressed && resp.StatusCode == http.StatusUnsupportedMediaType {
+		resp.Body.Close()
+		t.accepted.Store(false)
+		logging.Verbosef("⇡ %s %s: the server refused a gzip body (415); sending it uncompressed", req.Method, logging.RedactURL(req.URL.String()))
+		setBody(req, raw)
+		req.Header.Del("Content-Encoding")
+		if resp, err = t.base.RoundTrip(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if acceptsGzip(resp.Header) {
+		t.accepted.Store(true)
+	}
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		resp.Body = &gzipBody{raw: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// compress replaces a large enough body with its gzip encoding if the
+// server accepts that, returning the original bytes to fall back on
+func (t *gzipTransport) compress(req *http.Request) ([]byte, bool, error) {
+	if !t.accepted.Load() || req.Body == nil || req.ContentLength < compressMinSize || req.Header.Get("Content-Encoding") != "" {
+		return nil, false, nil
+	}
+	raw, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(raw)
+	if err := zw.Close(); err != nil {
+		return nil, false, err
+	}
+	setBody(req, buf.Bytes())
+	req.Header.Set("Content-Encoding", "gzip")
+	logging.Verbosef("⇡ %s %s: %s gzip, %s uncompressed", req.Method, logging.RedactURL(req.URL.String()), byteSize(int64(buf.Len())), byteSize(int64(len(raw))))
+	return raw, true, nil
+}
+
+// setBody makes body the request's body, replayable for redirects
+func setBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+}
+
+// acceptsGzip reports whether a response advertises that the server accepts
+// gzip-compressed request bodies (RFC 7694)
+func acceptsGzip(header http.Header) bool {
+	for _, value := range header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// gzipBody decodes a gzip-compressed response body, counting the bytes read
+// before and after decoding
+type gzipBody struct {
+	raw    io.ReadCloser
+	wire   countingReader
+	reader *gzip.Reader
+
+	decoded int64
+	done    bool
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		// Created on the first read, since bodies such as a 304's are empty
+		b.wire.r = b.raw
+		reader, err := gzip.NewReader(&b.wire)
+		if err != nil {
+			return 0, err
+		}
+		b.reader = reader
+	}
+	n, err := b.reader.Read(p)
+	b.decoded += int64(n)
+	if err == io.EOF {
+		b.done = true
+	}
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	return b.raw.Close()
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// logCompression logs how big a gzip response was on the wire and decoded,
+// once its body has been read
+func logCompression(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	body, ok := resp.Body.(*gzipBody)
+	if !ok || !body.done {
+		return
+	}
+	logging.Verbosef("⇣ %s %s: %s gzip, %s uncompressed", resp.Request.Method, logging.RedactURL(resp.Request.URL.String()), byteSize(body.wire.n), byteSize(body.decoded))
+}
+
+// byteSize formats a number of bytes, e.g. "812 B" or "1.4 MB"
+func byteSize(n int64) string {
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%d B", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// gzipServer answers with body, gzip-compressed when the client accepts
+// that, and records the request bodies it receives after decoding them.
+// Unless refuse415 is set, it advertises that it accepts gzip bodies.
+type gzipServer struct {
+	body      []byte
+	refuse415 bool
+
+	mu        sync.Mutex
+	encodings []string
+	received  []string
+}
+
+func (s *gzipServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	encoding := r.Header.Get("Content-Encoding")
+	if encoding == "gzip" && s.refuse415 {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	var body io.Reader = r.Body
+	if encoding == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	received, _ := io.ReadAll(body)
+	s.mu.Lock()
+	s.encodings = append(s.encodings, encoding)
+	s.received = append(s.received, string(received))
+	s.mu.Unlock()
+
+	w.Header().Set("Accept-Encoding", "gzip")
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Write(s.body)
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	zw := gzip.NewWriter(w)
+	zw.Write(s.body)
+	zw.Close()
+}
+
+// entriesJSON returns a JSON list of n entries
+func entriesJSON(n int) []byte {
+	entries := make([]Entry, n)
+	for i := range entries {
+		entries[i] = Entry{
+			ID:          fmt.Sprintf("entry-%d", i),
+			Description: "Reviewed pull requests and answered support tickets",
+			Duration:    1.5,
+			Source:      "TEMPO",
+		}
+	}
+	body, _ := json.Marshal(entries)
+	return body
+}
+
+func TestGzipResponses(t *testing.T) {
+	server := httptest.NewServer(&gzipServer{body: entriesJSON(100)})
+	defer server.Close()
+
+	var entries []Entry
+	if err := NewClient(&config.Config{APIURL: server.URL}).Get("/api/entries", &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 100 || entries[99].ID != "entry-99" {
+		t.Errorf("decoded %d entries", len(entries))
+	}
+}
+
+func TestGzipRequests(t *testing.T) {
+	handler := &gzipServer{body: []byte(`{}`)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	large := map[string]string{"csv": strings.Repeat("2024-01-02,Project,1.5\n", 1000)}
+	small := map[string]string{"csv": "2024-01-02,Project,1.5\n"}
+	for _, body := range []interface{}{large, large, small} {
+		if err := client.Post("/api/entries/batch", body, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first response advertises gzip, so only the second large body is
+	// compressed
+	want := []string{"", "gzip", ""}
+	if fmt.Sprint(handler.encodings) != fmt.Sprint(want) {
+		t.Errorf("Content-Encoding = %q, want %q", handler.encodings, want)
+	}
+	encoded, _ := json.Marshal(large)
+	if handler.received[1] != string(encoded) {
+		t.Error("the compressed body doesn't decode to the original")
+	}
+}
+
+func TestGzipRequestsFallBackOn415(t *testing.T) {
+	handler := &gzipServer{body: []byte(`{}`), refuse415: true}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	large := map[string]string{"csv": strings.Repeat("2024-01-02,Project,1.5\n", 1000)}
+	for i := 0; i < 3; i++ {
+		if err := client.Post("/api/entries/batch", large, nil); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+
+	// The second request is refused compressed and sent again; the third
+	// isn't compressed at all
+	if len(handler.received) != 3 || strings.Join(handler.encodings, ",") != ",," {
+		t.Errorf("server received %d bodies with encodings %q", len(handler.received), handler.encodings)
+	}
+}
+
+func TestGzipBodyCounts(t *testing.T) {
+	payload := entriesJSON(1000)
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write(payload)
+	zw.Close()
+
+	body := &gzipBody{raw: io.NopCloser(bytes.NewReader(compressed.Bytes()))}
+	decoded, err := io.ReadAll(body)
+	if err != nil || !bytes.Equal(decoded, payload) {
+		t.Fatalf("decoded %d bytes, %v", len(decoded), err)
+	}
+	if !body.done || body.wire.n != int64(compressed.Len()) || body.decoded != int64(len(payload)) {
+		t.Errorf("counted %d on the wire and %d decoded, want %d and %d", body.wire.n, body.decoded, compressed.Len(), len(payload))
+	}
+
+	// An empty body, like a 304's, is just empty
+	empty := &gzipBody{raw: io.NopCloser(bytes.NewReader(nil))}
+	if n, err := empty.Read(make([]byte, 8)); n != 0 || err != io.EOF {
+		t.Errorf("empty body read %d, %v", n, err)
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	tests := map[int64]string{812: "812 B", 1536: "1.5 KB", 3 << 20: "3.0 MB"}
+	for n, want := range tests {
+		if got := byteSize(n); got != want {
+			t.Errorf("byteSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// BenchmarkDecodeEntries compares fetching a multi-MB list of entries with
+// and without gzip. Decoding streams through the gzip reader, so the
+// allocations stay within a small constant of the uncompressed path's.
+func BenchmarkDecodeEntries(b *testing.B) {
+	payload := entriesJSON(20000) // about 3 MB
+	for _, accept := range []string{"identity", "gzip"} {
+		b.Run(accept, func(b *testing.B) {
+			server := httptest.NewServer(&gzipServer{body: payload})
+			defer server.Close()
+			client := NewClient(&config.Config{APIURL: server.URL})
+			client.resty.SetHeader("Accept-Encoding", accept)
+
+			b.SetBytes(int64(len(payload)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var entries []Entry
+				if err := client.Get("/api/entries", &entries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
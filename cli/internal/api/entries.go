@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// GetEntries fetches time entries whose date falls within [from, to].
+// Dates are YYYY-MM-DD; an empty bound leaves that side of the range open.
+// Long closed ranges are fetched in chunks (see SetChunking) and stitched
+// together, so callers always receive the complete range.
+func (c *Client) GetEntries(from, to string) ([]Entry, error) {
+	return c.GetEntriesContext(c.baseContext(), from, to)
+}
+
+// GetEntriesContext fetches entries like GetEntries, abandoning the
+// requests when ctx is done
+func (c *Client) GetEntriesContext(ctx context.Context, from, to string) ([]Entry, error) {
+	entries := []Entry{}
+	err := c.EachEntriesContext(ctx, from, to, func(page []Entry) error {
+		entries = append(entries, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// EachEntries fetches the same entries as GetEntries but hands them to fn
+// a page at a time as they arrive, so long ranges needn't be held in
+// memory at once. It stops at the first error fn returns.
+func (c *Client) EachEntries(from, to string, fn func([]Entry) error) error {
+	return c.EachEntriesContext(c.baseContext(), from, to, fn)
+}
+
+// EachEntriesContext fetches entries like EachEntries, abandoning the
+// requests when ctx is done
+func (c *Client) EachEntriesContext(ctx context.Context, from, to string, fn func([]Entry) error) error {
+	chunks := SplitRange(from, to, c.chunkDays)
+	if len(chunks) <= 1 {
+		return c.fetchEntries(ctx, from, to, fn)
+	}
+
+	progress := func(done int) {
+		if c.onChunk != nil {
+			c.onChunk(done, len(chunks))
+		}
+	}
+
+	progress(0)
+	for i, chunk := range chunks {
+		// Timed out chunks are retried like every GET
+		if err := c.fetchEntries(ctx, chunk.From, chunk.To, fn); err != nil {
+			return fmt.Errorf("failed to fetch %s to %s: %w", chunk.From, chunk.To, err)
+		}
+		progress(i + 1)
+	}
+
+	return nil
+}
+
+// fetchEntries fetches a single range, following its pages
+func (c *Client) fetchEntries(ctx context.Context, from, to string, fn func([]Entry) error) error {
+	endpoint := withQuery("/api/stats", url.Values{"from": {from}, "to": {to}})
+
+	return c.GetAllPages(ctx, endpoint, 0, func(page Page) error {
+		var entries []Entry
+		if err := page.Decode(&entries); err != nil {
+			return fmt.Errorf("failed to decode entries: %w", err)
+		}
+
+		// The server may ignore the range parameters, so filter again locally
+		filtered := make([]Entry, 0, len(entries))
+		for _, entry := range entries {
+			day := entry.Day()
+			if from != "" && day < from {
+				continue
+			}
+			if to != "" && day > to {
+				continue
+			}
+			filtered = append(filtered, entry)
+		}
+		return fn(filtered)
+	})
+}
+
+// CreateEntry creates a manual time entry
+func (c *Client) CreateEntry(req CreateEntryRequest) (*Entry, error) {
+	var entry Entry
+	if err := c.Post("/api/entries", req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindEntry returns the entry with the given ID.
+// Returns ErrNotFound if no entry has that ID.
+func (c *Client) FindEntry(id string) (*Entry, error) {
+	entries, err := c.GetEntries("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// UpdateEntry replaces an entry's fields
+func (c *Client) UpdateEntry(id string, req UpdateEntryRequest) (*Entry, error) {
+	var entry Entry
+	if err := c.Put("/api/entries/"+url.PathEscape(id), req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteEntry deletes an entry
+func (c *Client) DeleteEntry(id string) error {
+	return c.Delete("/api/entries/" + url.PathEscape(id))
+}
+
+// SearchEntries runs a full-text search over entry descriptions.
+// Returns ErrNotFound if the server does not provide the search endpoint.
+func (c *Client) SearchEntries(query, from, to string, limit int) (*SearchResponse, error) {
+	params := url.Values{
+		"q":     {query},
+		"from":  {from},
+		"to":    {to},
+		"limit": {fmt.Sprint(limit)},
+	}
+
+	var resp SearchResponse
+	if err := c.Get(withQuery("/api/entries/search", params), &resp); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// withQuery appends the non-empty parameters to the endpoint
+func withQuery(endpoint string, params url.Values) string {
+	for key, values := range params {
+		if len(values) == 0 || values[0] == "" {
+			params.Del(key)
+		}
+	}
+
+	if len(params) == 0 {
+		return endpoint
+	}
+
+	return endpoint + "?" + params.Encode()
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// fakeEntriesServer serves /api/stats, honouring the from/to parameters.
+// failFirst makes the first request for each listed range return 504.
+func fakeEntriesServer(t *testing.T, entries []Entry, failFirst map[string]bool) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []string
+	failed := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+		key := from + ".." + to
+
+		mu.Lock()
+		requests = append(requests, key)
+		fail := failFirst[key] && !failed[key]
+		failed[key] = true
+		mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+
+		var result []Entry
+		for _, entry := range entries {
+			if (from == "" || entry.Date[:10] >= from) && (to == "" || entry.Date[:10] <= to) {
+				result = append(result, entry)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// threeYearsOfEntries returns one entry per day, rotating through projects
+func threeYearsOfEntries() []Entry {
+	projects := []string{"forHim", "WEKA-199", "Internal"}
+	var entries []Entry
+
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; day.Year() < 2024; i++ {
+		entries = append(entries, Entry{
+			ID:       fmt.Sprint(i),
+			Date:     day.Format("2006-01-02"),
+			Duration: float64(i%8) + 0.25,
+			Project:  projects[i%len(projects)],
+		})
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return entries
+}
+
+// projectShares groups hours by project and computes each project's share,
+// the kind of aggregation that must happen after stitching
+func projectShares(entries []Entry) map[string][2]float64 {
+	totals := map[string]float64{}
+	var sum float64
+	for _, entry := range entries {
+		totals[entry.Project] += entry.Duration
+		sum += entry.Duration
+	}
+
+	shares := map[string][2]float64{}
+	for project, hours := range totals {
+		shares[project] = [2]float64{hours, hours / sum * 100}
+	}
+	return shares
+}
+
+func TestGetEntriesChunkedMatchesSingleFetch(t *testing.T) {
+	server, requests := fakeEntriesServer(t, threeYearsOfEntries(), nil)
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "token"})
+
+	single, err := client.GetEntries("2021-01-01", "2023-12-31")
+	if err != nil {
+		t.Fatalf("single fetch: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("single fetch made %d requests", len(*requests))
+	}
+
+	*requests = nil
+	var progress []int
+	client.SetChunking(DefaultChunkDays, func(done, total int) { progress = append(progress, done) })
+
+	chunked, err := client.GetEntries("2021-01-01", "2023-12-31")
+	if err != nil {
+		t.Fatalf("chunked fetch: %v", err)
+	}
+
+	wantChunks := len(SplitRange("2021-01-01", "2023-12-31", DefaultChunkDays))
+	if len(*requests) != wantChunks || wantChunks < 2 {
+		t.Fatalf("chunked fetch made %d requests, want %d", len(*requests), wantChunks)
+	}
+	if len(progress) != wantChunks+1 || progress[len(progress)-1] != wantChunks {
+		t.Errorf("progress callbacks = %v", progress)
+	}
+
+	if !reflect.DeepEqual(single, chunked) {
+		t.Fatalf("chunked fetch returned %d entries, single fetch %d", len(chunked), len(single))
+	}
+	if !reflect.DeepEqual(projectShares(single), projectShares(chunked)) {
+		t.Fatalf("grouped totals differ between chunked and single fetch")
+	}
+}
+
+func TestGetEntriesRetriesTimedOutChunk(t *testing.T) {
+	server, requests := fakeEntriesServer(t, threeYearsOfEntries(), map[string]bool{"2021-04-03..2021-07-03": true})
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "token", RetryDelay: time.Millisecond})
+	client.SetChunking(DefaultChunkDays, nil)
+
+	entries, err := client.GetEntries("2021-01-01", "2021-12-31")
+	if err != nil {
+		t.Fatalf("GetEntries(): %v", err)
+	}
+	if len(entries) != 365 {
+		t.Fatalf("got %d entries, want 365", len(entries))
+	}
+
+	chunks := len(SplitRange("2021-01-01", "2021-12-31", DefaultChunkDays))
+	if len(*requests) != chunks+1 {
+		t.Fatalf("made %d requests, want %d (one retry)", len(*requests), chunks+1)
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct {
+		from, to string
+		days     int
+		want     []DateRange
+	}{
+		{"2024-01-01", "2024-01-10", 0, []DateRange{{"2024-01-01", "2024-01-10"}}},
+		{"", "2024-01-10", 5, []DateRange{{"", "2024-01-10"}}},
+		{"2024-01-01", "2024-01-10", 10, []DateRange{{"2024-01-01", "2024-01-10"}}},
+		{"2024-01-01", "2024-01-10", 4, []DateRange{
+			{"2024-01-01", "2024-01-04"},
+			{"2024-01-05", "2024-01-08"},
+			{"2024-01-09", "2024-01-10"},
+		}},
+		{"2023-12-30", "2024-01-02", 2, []DateRange{
+			{"2023-12-30", "2023-12-31"},
+			{"2024-01-01", "2024-01-02"},
+		}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitRange(tt.from, tt.to, tt.days); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitRange(%s, %s, %d) = %v, want %v", tt.from, tt.to, tt.days, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// maxErrorBody is how much of a body that isn't an error envelope is kept
+// as the message
+const maxErrorBody = 200
+
+// Error is an error response from the API. Use errors.As to branch on the
+// status; errors.Is(err, ErrNotFound) matches 404s.
+type Error struct {
+	Status  int    // HTTP status code
+	Code    string // the server's error code, e.g. FST_ERR_NOT_FOUND, if any
+	Message string // the server's reason, if it gave one
+	Path    string // the request's path, without its query
+
+	// RequestID is the server's ID for the request, from its X-Request-ID
+	// response header: RequestID unless the server chose another
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	status := fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	if e.Message == "" {
+		return "API error: " + status
+	}
+	return fmt.Sprintf("API error: %s - %s", status, e.Message)
+}
+
+// Is matches ErrNotFound for 404 responses
+func (e *Error) Is(target error) bool {
+	return target == ErrNotFound && e.Status == http.StatusNotFound
+}
+
+// Unauthorized reports whether the server refused the credentials
+func (e *Error) Unauthorized() bool {
+	return e.Status == http.StatusUnauthorized
+}
+
+// ServerError reports whether the server failed rather than the request
+func (e *Error) ServerError() bool {
+	return e.Status >= 500
+}
+
+// newError builds the Error for an error response with the given body
+func newError(resp *resty.Response, body []byte) *Error {
+	e := &Error{Status: resp.StatusCode(), RequestID: resp.Header().Get(RequestIDHeader)}
+	if u, err := url.Parse(resp.Request.URL); err == nil {
+		e.Path = u.Path
+	}
+	e.Code, e.Message = parseErrorBody(e.Status, body)
+	return e
+}
+
+// parseErrorBody extracts the code and reason from an error envelope of the
+// form {"error": "...", "message": "...", "code": "..."}. Envelopes whose
+// "error" only repeats the status text, like Fastify's, give "message"
+// instead. Other bodies are kept as text, shortened, unless they are JSON.
+func parseErrorBody(status int, body []byte) (code, message string) {
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		message = payload.Error
+		if message == "" || (message == http.StatusText(status) && payload.Message != "") {
+			message = payload.Message
+		}
+		return payload.Code, message
+	}
+	if json.Valid(body) {
+		return "", ""
+	}
+
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if utf8.RuneCountInString(text) > maxErrorBody {
+		text = string([]rune(text)[:maxErrorBody]) + "…"
+	}
+	return "", text
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestGetReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"statusCode":404,"code":"FST_ERR_NOT_FOUND","error":"Not Found","message":"Route GET:/api/nope not found"}`))
+	}))
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	err := client.Get("/api/nope?from=2024-03-01", nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Get error = %v (%T), want *Error", err, err)
+	}
+	want := Error{Status: http.StatusNotFound, Code: "FST_ERR_NOT_FOUND", Message: "Route GET:/api/nope not found", Path: "/api/nope"}
+	if *apiErr != want {
+		t.Errorf("error = %+v, want %+v", *apiErr, want)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("a 404 doesn't match ErrNotFound")
+	}
+	if got := err.Error(); got != "API error: 404 Not Found - Route GET:/api/nope not found" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestParseErrorBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		code, reply string
+	}{
+		{"error", 400, `{"error":"Date range is required"}`, "", "Date range is required"},
+		{"message only", 500, `{"message":"boom","code":"E1"}`, "E1", "boom"},
+		{"status text", 401, `{"error":"Unauthorized","message":"Token expired"}`, "", "Token expired"},
+		{"status text alone", 401, `{"error":"Unauthorized"}`, "", "Unauthorized"},
+		{"other JSON", 502, `{"detail":{"a":1}}`, "", ""},
+		{"html", 502, "<html>\n  <body>Bad gateway</body>\n</html>", "", "<html> <body>Bad gateway</body> </html>"},
+		{"long text", 500, strings.Repeat("x", 300), "", strings.Repeat("x", maxErrorBody) + "…"},
+	}
+	for _, tt := range tests {
+		code, message := parseErrorBody(tt.status, []byte(tt.body))
+		if code != tt.code || message != tt.reply {
+			t.Errorf("%s: got %q, %q; want %q, %q", tt.name, code, message, tt.code, tt.reply)
+		}
+	}
+}
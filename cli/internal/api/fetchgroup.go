@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultFetchConcurrency is how many of a FetchGroup's calls run at once
+// unless it is given another limit
+const DefaultFetchConcurrency = 4
+
+// FetchGroup runs independent client calls concurrently, so commands that
+// need several responses wait for the slowest rather than for all of them
+// in turn. The calls share a context that is cancelled as soon as one
+// fails.
+type FetchGroup struct {
+	group  errgroup.Group
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	calls    int
+	failures []FetchFailure
+}
+
+// NewFetchGroup returns a group that runs at most limit calls at a time
+// (DefaultFetchConcurrency if limit is zero or less), whose context is
+// derived from ctx
+func NewFetchGroup(ctx context.Context, limit int) *FetchGroup {
+	if limit <= 0 {
+		limit = DefaultFetchConcurrency
+	}
+	// Not errgroup.WithContext: its cancellation cause would reach the
+	// other calls' errors, which then no longer look cancelled
+	g := &FetchGroup{}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	g.group.SetLimit(limit)
+	return g
+}
+
+// Go runs fn with the group's context, blocking while the group is at its
+// limit. name says what fn fetches, e.g. "entries for the week of
+// 2024-01-08", and is used when reporting its failure.
+func (g *FetchGroup) Go(name string, fn func(ctx context.Context) error) {
+	g.mu.Lock()
+	index := g.calls
+	g.calls++
+	g.mu.Unlock()
+
+	g.group.Go(func() error {
+		err := fn(g.ctx)
+		if err != nil {
+			g.cancel()
+			g.mu.Lock()
+			g.failures = append(g.failures, FetchFailure{index: index, Name: name, Err: err})
+			g.mu.Unlock()
+		}
+		return err
+	})
+}
+
+// Wait waits for every call and returns a *FetchError naming the ones that
+// failed, or nil. Calls that were only cancelled because another one failed
+// aren't reported.
+func (g *FetchGroup) Wait() error {
+	err := g.group.Wait()
+	g.cancel()
+	if err == nil {
+		return nil
+	}
+
+	var failures, cancelled []FetchFailure
+	for _, failure := range g.failures {
+		if errors.Is(failure.Err, context.Canceled) {
+			cancelled = append(cancelled, failure)
+		} else {
+			failures = append(failures, failure)
+		}
+	}
+	if len(failures) == 0 {
+		failures = cancelled
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].index < failures[j].index })
+	return &FetchError{Failures: failures}
+}
+
+// FetchFailure is a call of a FetchGroup that failed
+type FetchFailure struct {
+	index int // order of the call, so reports are stable
+	Name  string
+	Err   error
+}
+
+// FetchError reports the calls of a FetchGroup that failed
+type FetchError struct {
+	Failures []FetchFailure
+}
+
+func (e *FetchError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("failed to fetch %s: %v", e.Failures[0].Name, e.Failures[0].Err)
+	}
+	parts := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		parts[i] = fmt.Sprintf("%s: %v", failure.Name, failure.Err)
+	}
+	return fmt.Sprintf("%d requests failed: %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the failures' errors, so errors.Is and errors.As see
+// through a FetchError
+func (e *FetchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
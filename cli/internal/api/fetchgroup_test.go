@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// slowServer answers every request after delay, recording the most
+// requests it was handling at once. Paths starting with /fail get a 500.
+func slowServer(delay time.Duration, overlap *int32) *httptest.Server {
+	var active int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			max := atomic.LoadInt32(overlap)
+			if n <= max || atomic.CompareAndSwapInt32(overlap, max, n) {
+				break
+			}
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/fail") {
+			http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+			return
+		}
+		select {
+		case <-time.After(delay):
+			w.Write([]byte(`{}`))
+		case <-r.Context().Done():
+		}
+	}))
+}
+
+func TestFetchGroupOverlaps(t *testing.T) {
+	var overlap int32
+	server := slowServer(100*time.Millisecond, &overlap)
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	start := time.Now()
+	group := NewFetchGroup(context.Background(), 0)
+	for _, endpoint := range []string{"/api/a", "/api/b", "/api/c"} {
+		endpoint := endpoint
+		group.Go(endpoint, func(ctx context.Context) error {
+			return client.GetContext(ctx, endpoint, nil)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("3 requests of 100ms took %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&overlap); got != 3 {
+		t.Errorf("%d requests overlapped, want 3", got)
+	}
+}
+
+func TestFetchGroupLimit(t *testing.T) {
+	var overlap int32
+	server := slowServer(20*time.Millisecond, &overlap)
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	group := NewFetchGroup(context.Background(), 2)
+	for i := 0; i < 6; i++ {
+		group.Go("entries", func(ctx context.Context) error {
+			return client.GetContext(ctx, "/api/entries", nil)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&overlap); got != 2 {
+		t.Errorf("%d requests overlapped, want at most the limit of 2", got)
+	}
+}
+
+func TestFetchGroupNamesFailures(t *testing.T) {
+	var overlap int32
+	server := slowServer(time.Second, &overlap)
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL, Attempts: 1})
+
+	start := time.Now()
+	group := NewFetchGroup(context.Background(), 0)
+	group.Go("this week", func(ctx context.Context) error {
+		return client.GetContext(ctx, "/api/slow", nil)
+	})
+	group.Go("last week", func(ctx context.Context) error {
+		return client.GetContext(ctx, "/fail", nil)
+	})
+	err := group.Wait()
+
+	// The failure cancels the slow request, which isn't reported
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Wait returned after %s", elapsed)
+	}
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || len(fetchErr.Failures) != 1 || fetchErr.Failures[0].Name != "last week" {
+		t.Fatalf("Wait = %v, want only last week's failure", err)
+	}
+	if !strings.HasPrefix(err.Error(), "failed to fetch last week: ") {
+		t.Errorf("error = %q", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusInternalServerError {
+		t.Errorf("the 500 isn't reachable through %v", err)
+	}
+}
+
+func TestFetchErrorListsEveryFailure(t *testing.T) {
+	err := &FetchError{Failures: []FetchFailure{
+		{Name: "this week", Err: errors.New("timeout")},
+		{Name: "last week", Err: ErrNotFound},
+	}}
+	if want := "2 requests failed: this week: timeout; last week: not found"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is doesn't see the failures")
+	}
+}
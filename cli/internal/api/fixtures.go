@@ -0,0 +1,334 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vmiller/timetracker-cli/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoFixture is returned for a request that none of the replayed fixtures
+// answers
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// Fixture is a request to the server and its response, as stored in a
+// fixture file. Credentials are redacted from both.
+type Fixture struct {
+	Request  FixtureRequest  `yaml:"request"`
+	Response FixtureResponse `yaml:"response"`
+}
+
+// FixtureRequest is the request of a Fixture
+type FixtureRequest struct {
+	Method string            `yaml:"method"`
+	URL    string            `yaml:"url"` // path and query, without the server
+	Header map[string]string `yaml:"header,omitempty"`
+	Body   string            `yaml:"body,omitempty"`
+}
+
+// FixtureResponse is the response of a Fixture
+type FixtureResponse struct {
+	Status int               `yaml:"status"`
+	Header map[string]string `yaml:"header,omitempty"`
+	Body   string            `yaml:"body,omitempty"`
+}
+
+// unrecordedHeaders describe a particular connection, client build or
+// server, and would only make fixtures differ between recordings
+var unrecordedHeaders = []string{
+	"Accept-Encoding", "Connection", "Content-Encoding", "Content-Length", "Date",
+	"Keep-Alive", "Server", "Transfer-Encoding", "User-Agent", "X-Client-Version",
+	RequestIDHeader,
+}
+
+// Fixtures records a client's exchanges with the server to numbered YAML
+// files in a directory, or answers its requests from such files instead of
+// the server, so commands can be tested and demonstrated without a
+// network. One Fixtures may serve several clients.
+type Fixtures struct {
+	dir    string
+	replay bool
+
+	mu       sync.Mutex
+	recorded int
+	fixtures []*replayedFixture
+}
+
+// replayedFixture is a loaded fixture and whether it has answered a request
+type replayedFixture struct {
+	Fixture
+	file string
+	used bool
+}
+
+// RecordFixtures returns Fixtures that write every exchange to dir, which
+// is created if needed and must not hold fixtures yet
+func RecordFixtures(dir string) (*Fixtures, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the fixture directory: %w", err)
+	}
+	files, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		return nil, fmt.Errorf("%s already holds fixtures; remove them to record again, or replay them", dir)
+	}
+	return &Fixtures{dir: dir}, nil
+}
+
+// ReplayFixtures returns Fixtures that answer requests from the fixtures
+// in dir. Each request gets the response of the first fixture with its
+// method, path and query that hasn't answered yet, or of the last one once
+// all have; a request without one fails with ErrNoFixture.
+func ReplayFixtures(dir string) (*Fixtures, error) {
+	files, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixtures to replay in %s", dir)
+	}
+
+	f := &Fixtures{dir: dir, replay: true}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		loaded := &replayedFixture{file: file}
+		if err := yaml.Unmarshal(data, &loaded.Fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", file, err)
+		}
+		f.fixtures = append(f.fixtures, loaded)
+	}
+	return f, nil
+}
+
+// HasFixtures reports whether dir holds fixture files
+func HasFixtures(dir string) bool {
+	files, err := fixtureFiles(dir)
+	return err == nil && len(files) > 0
+}
+
+// SetFixtures makes the client record its exchanges with f, or take its
+// responses from f
+func (c *Client) SetFixtures(f *Fixtures) {
+	base := c.resty.GetClient().Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.resty.SetTransport(&fixtureTransport{base: base, fixtures: f})
+}
+
+// fixtureFiles returns the fixture files in dir in the order they were
+// recorded
+func fixtureFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fixtureTransport records exchanges sent through base, or replays them
+// without sending anything
+type fixtureTransport struct {
+	base     http.RoundTripper
+	fixtures *Fixtures
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context()) // RoundTrip must not modify req
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		setBody(req, body)
+	}
+
+	if t.fixtures.replay {
+		return t.fixtures.answer(req)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	// Streamed responses are recorded whole, so they arrive at once
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err := t.fixtures.record(req, body, resp, respBody); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// record writes an exchange to the next fixture file
+func (f *Fixtures) record(req *http.Request, body []byte, resp *http.Response, respBody []byte) error {
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			URL:    logging.RedactURL(req.URL.RequestURI()),
+			Header: fixtureHeader(req.Header),
+			Body:   fixtureBody(body, req.Header),
+		},
+		Response: FixtureResponse{
+			Status: resp.StatusCode,
+			Header: fixtureHeader(resp.Header),
+			Body:   fixtureBody(respBody, resp.Header),
+		},
+	}
+	var data bytes.Buffer
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fixture); err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorded++
+	name := fmt.Sprintf("%03d-%s-%s.yaml", f.recorded, strings.ToLower(req.Method), fixtureSlug(req.URL.Path))
+	if err := os.WriteFile(filepath.Join(f.dir, name), data.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	logging.Verbosef("📼 Recorded %s %s to %s", req.Method, logging.RedactURL(req.URL.RequestURI()), name)
+	return nil
+}
+
+// answer returns the response of the fixture that matches req
+func (f *Fixtures) answer(req *http.Request) (*http.Response, error) {
+	key := fixtureKey(req.Method, logging.RedactURL(req.URL.RequestURI()))
+
+	f.mu.Lock()
+	var match *replayedFixture
+	for _, fixture := range f.fixtures {
+		if fixtureKey(fixture.Request.Method, fixture.Request.URL) != key {
+			continue
+		}
+		match = fixture
+		if !fixture.used {
+			break
+		}
+	}
+	if match != nil {
+		match.used = true
+	}
+	f.mu.Unlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("%w for %s %s in %s", ErrNoFixture, req.Method, logging.RedactURL(req.URL.RequestURI()), f.dir)
+	}
+	logging.Verbosef("📼 Replaying %s", filepath.Base(match.file))
+
+	header := http.Header{}
+	for name, value := range match.Response.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.Status, http.StatusText(match.Response.Status)),
+		StatusCode:    match.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(match.Response.Body)),
+		ContentLength: int64(len(match.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// fixtureKey identifies a request by its method, path and query, with the
+// query in a canonical order
+func fixtureKey(method, requestURI string) string {
+	u, err := url.Parse(requestURI)
+	if err != nil {
+		return method + " " + requestURI
+	}
+	return method + " " + u.Path + "?" + u.Query().Encode()
+}
+
+// fixtureHeader returns the headers worth recording, with credentials
+// redacted and repeated values joined
+func fixtureHeader(header http.Header) map[string]string {
+	recorded := map[string]string{}
+	for name, values := range logging.RedactHeader(header) {
+		if !isUnrecordedHeader(name) {
+			recorded[name] = strings.Join(values, ", ")
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return recorded
+}
+
+func isUnrecordedHeader(name string) bool {
+	for _, unrecorded := range unrecordedHeaders {
+		if strings.EqualFold(name, unrecorded) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixtureBody returns a body for a fixture file, redacted, with JSON
+// indented for reading and editing. Newline-delimited JSON is redacted
+// line by line.
+func fixtureBody(body []byte, header http.Header) string {
+	if strings.Contains(header.Get("Content-Type"), "ndjson") {
+		var sb strings.Builder
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(nil, len(body)+1)
+		for scanner.Scan() {
+			if line := logging.RedactFullBody(scanner.Bytes()); line != "" {
+				sb.WriteString(line + "\n")
+			}
+		}
+		return sb.String()
+	}
+
+	redacted := logging.RedactFullBody(body)
+	var indented bytes.Buffer
+	if json.Indent(&indented, []byte(redacted), "", "  ") == nil {
+		return indented.String()
+	}
+	return redacted
+}
+
+// nonSlug matches what a fixture's file name leaves out of its path
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// fixtureSlug turns a request path into part of a file name, e.g.
+// /api/entries/summary/today into entries-summary-today
+func fixtureSlug(path string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(strings.TrimPrefix(path, "/api/")), "-"), "-")
+	if slug == "" {
+		return "root"
+	}
+	return slug
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestFixturesRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/auth/cli-refresh":
+			w.Write([]byte(`{"accessToken":"new-access","refreshToken":"new-refresh"}`))
+		default:
+			w.Write([]byte(`{"path":"` + r.URL.Path + `","query":"` + r.URL.RawQuery + `"}`))
+		}
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	recorder, err := RecordFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "secret-access"})
+	client.SetFixtures(recorder)
+	var result map[string]string
+	if err := client.Get("/api/entries?to=2024-01-07&from=2024-01-01", &result); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Post("/api/auth/cli-refresh", map[string]string{"refreshToken": "secret-refresh"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if len(files) != 2 || filepath.Base(files[0]) != "001-get-entries.yaml" || filepath.Base(files[1]) != "002-post-auth-cli-refresh.yaml" {
+		t.Fatalf("recorded %v", files)
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "secret") || strings.Contains(string(data), "new-") {
+			t.Errorf("%s holds a token:\n%s", file, data)
+		}
+	}
+	if _, err := RecordFixtures(dir); err == nil {
+		t.Error("recorded over existing fixtures")
+	}
+
+	// Replayed without the server, with the query in another order
+	server.Close()
+	replayer, err := ReplayFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = NewClient(&config.Config{APIURL: "http://fixtures.invalid", Attempts: 3})
+	client.SetFixtures(replayer)
+	result = nil
+	if err := client.Get("/api/entries?from=2024-01-01&to=2024-01-07", &result); err != nil {
+		t.Fatal(err)
+	}
+	if result["path"] != "/api/entries" {
+		t.Errorf("replayed %v", result)
+	}
+
+	err = client.Get("/api/entries?from=2024-02-01", nil)
+	if !errors.Is(err, ErrNoFixture) || !strings.Contains(err.Error(), "GET /api/entries?from=2024-02-01") {
+		t.Errorf("unmatched request = %v", err)
+	}
+}
+
+func TestReplayFixturesNeedsFixtures(t *testing.T) {
+	if _, err := ReplayFixtures(t.TempDir()); err == nil {
+		t.Error("replayed an empty directory")
+	}
+}
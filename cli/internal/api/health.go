@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// healthTimeout bounds a health check, so a wrong API URL fails fast
+const healthTimeout = 2 * time.Second
+
+// healthEndpoints are where servers answer health checks, older ones only
+// at /health
+var healthEndpoints = []string{"/api/health", "/health"}
+
+// HealthError is a health check that found no server to talk to, worded to
+// say what to fix
+type HealthError struct {
+	Message string
+	Err     error
+}
+
+func (e *HealthError) Error() string {
+	return e.Message
+}
+
+func (e *HealthError) Unwrap() error {
+	return e.Err
+}
+
+// Health asks the server whether it is up and which version it runs, in
+// one request of at most healthTimeout that is neither cached nor retried.
+// A server that can't be reached, doesn't answer in time or has no health
+// endpoint gives a *HealthError; an unhealthy one answers with a 503 *Error.
+func (c *Client) Health() (*HealthResponse, error) {
+	var health *HealthResponse
+	var err error
+	for _, endpoint := range healthEndpoints {
+		if health, err = c.health(endpoint); !errors.Is(err, ErrNotFound) {
+			break
+		}
+	}
+
+	base := c.resty.BaseURL
+	var timeout *TimeoutError
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil, &HealthError{Message: fmt.Sprintf("%s has no health check, so it isn't a TimeTracker server — is the API URL correct?", base), Err: err}
+	case errors.As(err, &timeout):
+		return nil, &HealthError{Message: fmt.Sprintf("%s didn't answer within %s — is the API URL correct?", base, healthTimeout), Err: err}
+	case errors.As(err, &dnsErr):
+		return nil, &HealthError{Message: fmt.Sprintf("cannot reach %s: no such host — is the API URL correct?", base), Err: err}
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return nil, &HealthError{Message: fmt.Sprintf("cannot reach %s — is the API URL correct? (%v)", base, opErr.Err), Err: err}
+	}
+	return health, err
+}
+
+// health sends a health check to endpoint
+func (c *Client) health(endpoint string) (*HealthResponse, error) {
+	var health HealthResponse
+	ctx := WithTimeout(c.baseContext(), healthTimeout)
+	resp, err := c.withTimeout(ctx, func(ctx context.Context) (*resty.Response, error) {
+		return c.resty.R().SetContext(ctx).SetResult(&health).Get(endpoint)
+	})
+	if err != nil {
+		return nil, requestError(err)
+	}
+	if resp.IsError() {
+		return nil, newError(resp, resp.Body())
+	}
+	return &health, nil
+}
+
+// Preflight checks the server's health before a command relies on it, so a
+// wrong API URL or a server that is down is reported as such rather than
+// as whichever request fails first
+func (c *Client) Preflight() error {
+	health, err := c.Health()
+	var apiErr *Error
+	switch {
+	case errors.As(err, &apiErr) && apiErr.ServerError():
+		return fmt.Errorf("the server at %s is unhealthy: %w", c.resty.BaseURL, err)
+	case err != nil:
+		return err
+	case health.Status != "healthy":
+		return fmt.Errorf("the server at %s reports status %q", c.resty.BaseURL, health.Status)
+	}
+	return nil
+}
+
+// SetPreflight makes the client run Preflight before its first request,
+// failing every request if it fails
+func (c *Client) SetPreflight() {
+	var once sync.Once
+	var err error
+	c.resty.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		if isHealthEndpoint(req.URL) {
+			return nil
+		}
+		once.Do(func() { err = c.Preflight() })
+		if err != nil {
+			return &PreflightError{Err: err}
+		}
+		return nil
+	})
+}
+
+// PreflightError is a request that wasn't sent because the preflight
+// health check failed
+type PreflightError struct {
+	Err error
+}
+
+func (e *PreflightError) Error() string {
+	return "preflight check failed: " + e.Err.Error()
+}
+
+func (e *PreflightError) Unwrap() error {
+	return e.Err
+}
+
+// isHealthEndpoint reports whether endpoint is a health check
+func isHealthEndpoint(endpoint string) bool {
+	for _, health := range healthEndpoints {
+		if strings.HasSuffix(endpoint, health) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetProviderStatus fetches the configuration state of every provider
+func (c *Client) GetProviderStatus() ([]ProviderStatus, error) {
+	var resp ProvidersStatusResponse
+	if err := c.Get("/api/providers/status", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Providers, nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/health":
+			w.Write([]byte(`{"status":"healthy","timestamp":"2024-03-01T12:00:00Z","version":"1.2.0"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	health, err := NewClient(&config.Config{APIURL: server.URL}).Health()
+	if err != nil || health.Status != "healthy" || health.Version != "1.2.0" {
+		t.Errorf("Health = %+v, %v", health, err)
+	}
+
+	// Not the API's base URL, so there is no health check
+	_, err = NewClient(&config.Config{APIURL: server.URL + "/app"}).Health()
+	var healthErr *HealthError
+	if !errors.As(err, &healthErr) || !strings.Contains(err.Error(), "is the API URL correct?") {
+		t.Errorf("Health without an endpoint = %v", err)
+	}
+}
+
+func TestHealthFallsBackToOldEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"healthy","timestamp":"2024-03-01T12:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	health, err := NewClient(&config.Config{APIURL: server.URL}).Health()
+	if err != nil || health.Status != "healthy" || health.Version != "" {
+		t.Errorf("Health = %+v, %v", health, err)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := NewClient(&config.Config{APIURL: url, Attempts: 3})
+	var checks int32
+	client.resty.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		if isHealthEndpoint(req.URL) {
+			atomic.AddInt32(&checks, 1)
+		}
+		return nil
+	})
+	client.SetPreflight()
+
+	for i := 0; i < 2; i++ {
+		err := client.Get("/api/entries", nil)
+		var healthErr *HealthError
+		if !errors.As(err, &healthErr) || !strings.HasPrefix(err.Error(), "preflight check failed: cannot reach "+url) {
+			t.Errorf("Get = %v, want the preflight's failure", err)
+		}
+	}
+	if n := atomic.LoadInt32(&checks); n != 1 {
+		t.Errorf("sent %d health checks, want 1 for all requests and no retries", n)
+	}
+}
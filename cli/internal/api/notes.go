@@ -0,0 +1,23 @@
+package api
+
+import "net/url"
+
+// GetNotes returns the day notes between two days (YYYY-MM-DD, inclusive).
+// Returns ErrNotFound if the server does not store notes.
+func (c *Client) GetNotes(from, to string) ([]Note, error) {
+	var resp NotesResponse
+	if err := c.Get(withQuery("/api/notes", url.Values{"from": {from}, "to": {to}}), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Notes, nil
+}
+
+// SetNote creates or replaces the note for a day
+func (c *Client) SetNote(date, text string) error {
+	return c.Put("/api/notes/"+url.PathEscape(date), SetNoteRequest{Text: text}, nil)
+}
+
+// DeleteNote removes the note for a day
+func (c *Client) DeleteNote(date string) error {
+	return c.Delete("/api/notes/" + url.PathEscape(date))
+}
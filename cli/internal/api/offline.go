@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/httpcache"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+)
+
+// ErrOffline is returned for requests that can't be answered without the
+// server while the client is offline
+var ErrOffline = errors.New("not available offline")
+
+// OfflineMode says when summaries may be answered from their last-known copy
+type OfflineMode int
+
+const (
+	// OfflineFallback answers from the last-known copy when the server
+	// can't be reached
+	OfflineFallback OfflineMode = iota
+	// OfflineOnly never contacts the server
+	OfflineOnly
+	// OfflineNever reports every network error
+	OfflineNever
+)
+
+// summaryPrefix is where the endpoints whose last-known copies are kept
+// live
+const summaryPrefix = "/api/entries/summary/"
+
+// SetOffline keeps the last successful response of every summary endpoint
+// in store, and answers from it as mode allows. Responses served from it
+// are reported by OfflineSince. A nil store turns this off.
+func (c *Client) SetOffline(store *httpcache.Cache, mode OfflineMode) {
+	c.lastKnown = store
+	c.offline = mode
+}
+
+// OfflineSince reports whether any response so far came from a last-known
+// copy instead of the server, and when the oldest one was fetched
+func (c *Client) OfflineSince() (time.Time, bool) {
+	c.offlineMu.Lock()
+	defer c.offlineMu.Unlock()
+	return c.offlineSince, !c.offlineSince.IsZero()
+}
+
+// doOffline sends a request unless the client is offline, keeping or using
+// the last-known copies of summaries around it
+func (c *Client) doOffline(ctx context.Context, method, endpoint string, body interface{}, result interface{}, idempotent bool) error {
+	kept := c.lastKnown != nil && method == http.MethodGet && strings.HasPrefix(endpoint, summaryPrefix) && result != nil
+	if c.offline == OfflineOnly {
+		switch {
+		case !kept:
+			return fmt.Errorf("%s: %w", endpointPath(endpoint), ErrOffline)
+		case !c.useLastKnown(endpoint, result):
+			return fmt.Errorf("no last-known copy of %s yet; run the command once while online: %w", endpointPath(endpoint), ErrOffline)
+		}
+		return nil
+	}
+
+	err := c.do(ctx, method, endpoint, body, result, idempotent)
+	switch {
+	case !kept:
+	case err == nil:
+		c.keep(endpoint, result)
+	case c.offline == OfflineFallback && IsTransient(err) && ctx.Err() == nil:
+		if c.useLastKnown(endpoint, result) {
+			logging.Verbosef("📴 %s: %v; using the last-known copy", logging.RedactURL(endpoint), err)
+			return nil
+		}
+	}
+	return err
+}
+
+// keep stores result as the last-known copy of endpoint. Failing to store
+// it only costs the offline fallback.
+func (c *Client) keep(endpoint string, result interface{}) {
+	body, err := json.Marshal(result)
+	if err == nil {
+		err = c.lastKnown.Put(httpcache.Entry{URL: c.resty.BaseURL + endpoint, Body: string(body), StoredAt: time.Now()})
+	}
+	if err != nil {
+		logging.Verbosef("  couldn't keep an offline copy of %s: %v", logging.RedactURL(endpoint), err)
+	}
+}
+
+// useLastKnown decodes the last-known copy of endpoint into result, if
+// there is one
+func (c *Client) useLastKnown(endpoint string, result interface{}) bool {
+	entry, ok := c.lastKnown.Get(c.resty.BaseURL + endpoint)
+	if !ok || json.Unmarshal([]byte(entry.Body), result) != nil {
+		return false
+	}
+
+	c.offlineMu.Lock()
+	defer c.offlineMu.Unlock()
+	if c.offlineSince.IsZero() || entry.StoredAt.Before(c.offlineSince) {
+		c.offlineSince = entry.StoredAt
+	}
+	return true
+}
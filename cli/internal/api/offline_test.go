@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/httpcache"
+)
+
+func TestOfflineFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"date":"2024-03-04","totalHours":3.5,"bySource":{"toggl":3.5},"entryCount":2}`))
+	}))
+	store := httpcache.New(t.TempDir())
+	newOfflineClient := func(mode OfflineMode) *Client {
+		client := NewClient(&config.Config{APIURL: server.URL, Attempts: 1})
+		client.SetOffline(store, mode)
+		return client
+	}
+
+	online := newOfflineClient(OfflineFallback)
+	if _, err := online.GetTodaySummary(nil); err != nil {
+		t.Fatalf("GetTodaySummary online: %v", err)
+	}
+	if _, ok := online.OfflineSince(); ok {
+		t.Error("a response from the server counted as offline")
+	}
+	server.Close()
+
+	// The server is gone: the last-known copy is used and flagged
+	fallback := newOfflineClient(OfflineFallback)
+	summary, err := fallback.GetTodaySummary(nil)
+	if err != nil || summary.TotalHours != 3.5 {
+		t.Fatalf("GetTodaySummary offline = %+v, %v", summary, err)
+	}
+	if since, ok := fallback.OfflineSince(); !ok || time.Since(since) > time.Minute {
+		t.Errorf("OfflineSince = %v, %v", since, ok)
+	}
+
+	if _, err := newOfflineClient(OfflineNever).GetTodaySummary(nil); err == nil {
+		t.Error("OfflineNever fell back to the last-known copy")
+	}
+
+	// Offline only answers summaries, and only those fetched before
+	only := newOfflineClient(OfflineOnly)
+	if summary, err := only.GetTodaySummary(nil); err != nil || summary.EntryCount != 2 {
+		t.Errorf("GetTodaySummary with OfflineOnly = %+v, %v", summary, err)
+	}
+	if _, err := only.GetTodaySummary([]string{"tempo"}); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached summary with OfflineOnly = %v, want ErrOffline", err)
+	}
+	if _, err := only.GetEntries("2024-03-04", "2024-03-04"); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetEntries with OfflineOnly = %v, want ErrOffline", err)
+	}
+}
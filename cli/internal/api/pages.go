@@ -0,0 +1,128 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pagination of list endpoints, unless the config sets another cap
+const (
+	DefaultPageSize = 500
+	DefaultMaxPages = 200
+)
+
+// Page is one page of a list response
+type Page struct {
+	Items      json.RawMessage // the page's items, a JSON array
+	Number     int             // counted from 1
+	TotalPages int
+}
+
+// Decode decodes the page's items into v, usually a pointer to a slice
+func (p Page) Decode(v interface{}) error {
+	return json.Unmarshal(p.Items, v)
+}
+
+// pageResponse is a paginated list response. Servers put the items in
+// "data" or "items".
+type pageResponse struct {
+	Data       json.RawMessage `json:"data"`
+	Items      json.RawMessage `json:"items"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
+
+// SetPaging caps how many pages GetAllPages follows. progress, if set, is
+// called with the number of pages fetched so far and the total after every
+// page of a list that has more than one. A max of zero or less keeps the
+// default.
+func (c *Client) SetPaging(max int, progress func(done, total int)) {
+	if max < 1 {
+		max = DefaultMaxPages
+	}
+	c.maxPages = max
+	c.onPage = progress
+}
+
+// GetAllPages fetches endpoint page by page, asking for perPage items each
+// (DefaultPageSize if zero), and calls fn with every page until the last
+// one or until fn fails. Responses that are a bare array instead of a
+// paginated object are a single page, as from servers that don't paginate.
+// Lists longer than the client's page cap fail rather than being cut short.
+func (c *Client) GetAllPages(ctx context.Context, endpoint string, perPage int, fn func(Page) error) error {
+	if perPage <= 0 {
+		perPage = DefaultPageSize
+	}
+
+	for number := 1; ; number++ {
+		if number > c.maxPages {
+			return fmt.Errorf("%s has more than %d pages; narrow it down or raise api.max_pages", endpointPath(endpoint), c.maxPages)
+		}
+
+		var raw json.RawMessage
+		if err := c.GetContext(ctx, withPage(endpoint, number, perPage), &raw); err != nil {
+			return err
+		}
+		page, err := parsePage(raw, number)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if page.TotalPages > 1 && c.onPage != nil {
+			c.onPage(number, page.TotalPages)
+		}
+		if number >= page.TotalPages {
+			return nil
+		}
+	}
+}
+
+// parsePage decodes page number of a list response
+func parsePage(raw json.RawMessage, number int) (Page, error) {
+	switch trimmed := bytes.TrimSpace(raw); {
+	case len(trimmed) == 0 || string(trimmed) == "null":
+		return Page{Items: json.RawMessage("[]"), Number: number, TotalPages: number}, nil
+	case trimmed[0] == '[':
+		return Page{Items: raw, Number: number, TotalPages: number}, nil
+	}
+
+	var resp pageResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return Page{}, fmt.Errorf("failed to decode page %d: %w", number, err)
+	}
+	items := resp.Data
+	if len(items) == 0 {
+		items = resp.Items
+	}
+	if len(items) == 0 || string(items) == "null" {
+		items = json.RawMessage("[]")
+	}
+	if resp.Page != 0 && resp.Page != number {
+		return Page{}, fmt.Errorf("asked for page %d, got page %d", number, resp.Page)
+	}
+	return Page{Items: items, Number: number, TotalPages: resp.TotalPages}, nil
+}
+
+// withPage adds the page and pageSize parameters to endpoint
+func withPage(endpoint string, number, perPage int) string {
+	params := url.Values{"page": {strconv.Itoa(number)}, "pageSize": {strconv.Itoa(perPage)}}
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&" + params.Encode()
+	}
+	return endpoint + "?" + params.Encode()
+}
+
+// endpointPath returns endpoint without its query
+func endpointPath(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	return path
+}
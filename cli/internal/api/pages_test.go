@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// pagedServer serves /api/stats as totalPages pages of one entry each
+func pagedServer(t *testing.T, totalPages int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if r.URL.Query().Get("pageSize") != strconv.Itoa(DefaultPageSize) {
+			t.Errorf("pageSize = %q", r.URL.Query().Get("pageSize"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":       []Entry{{ID: strconv.Itoa(page), Date: "2024-03-0" + strconv.Itoa(page)}},
+			"page":       page,
+			"pageSize":   DefaultPageSize,
+			"totalPages": totalPages,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetEntriesFollowsPages(t *testing.T) {
+	server := pagedServer(t, 3)
+	client := NewClient(&config.Config{APIURL: server.URL})
+	var progress []string
+	client.SetPaging(0, func(done, total int) {
+		progress = append(progress, strconv.Itoa(done)+"/"+strconv.Itoa(total))
+	})
+
+	entries, err := client.GetEntries("2024-03-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("GetEntries: %v", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	if got := strings.Join(ids, ","); got != "1,2,3" {
+		t.Errorf("entries %s, want one from each page", got)
+	}
+	if got := strings.Join(progress, " "); got != "1/3 2/3 3/3" {
+		t.Errorf("progress %q", got)
+	}
+
+	// More pages than the cap fail instead of returning part of the list
+	client.SetPaging(2, nil)
+	if _, err := client.GetEntries("2024-03-01", "2024-03-31"); err == nil || !strings.Contains(err.Error(), "more than 2 pages") {
+		t.Errorf("GetEntries past the cap = %v", err)
+	}
+}
+
+func TestGetAllPagesOfBareArray(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":"a"},{"id":"b"}]`))
+	}))
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	var pages []Page
+	err := client.GetAllPages(context.Background(), "/api/stats?from=2024-03-01", 0, func(page Page) error {
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GetAllPages: %v", err)
+	}
+	if requests != 1 || len(pages) != 1 {
+		t.Fatalf("got %d pages in %d requests, want one", len(pages), requests)
+	}
+	var entries []Entry
+	if err := pages[0].Decode(&entries); err != nil || len(entries) != 2 {
+		t.Errorf("Decode = %v, %v", entries, err)
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		body       string
+		items      string
+		totalPages int
+		wantErr    bool
+	}{
+		{`{"items":[1,2],"page":2,"totalPages":4}`, `[1,2]`, 4, false},
+		{`{"data":[],"page":2,"totalPages":0}`, `[]`, 0, false},
+		{`null`, `[]`, 2, false},
+		{`{"data":[1],"page":1,"totalPages":4}`, "", 0, true},
+	}
+	for _, tt := range tests {
+		page, err := parsePage(json.RawMessage(tt.body), 2)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v", tt.body, err)
+			continue
+		}
+		if !tt.wantErr && (string(page.Items) != tt.items || page.TotalPages != tt.totalPages) {
+			t.Errorf("%s: got %s of %d pages", tt.body, page.Items, page.TotalPages)
+		}
+	}
+}
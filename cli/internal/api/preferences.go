@@ -0,0 +1,21 @@
+package api
+
+import "fmt"
+
+// GetPreferences fetches the organisation-wide display preferences.
+// Returns ErrNotFound if the server does not provide them.
+func (c *Client) GetPreferences() (map[string]string, error) {
+	var resp PreferencesResponse
+	if err := c.Get("/api/preferences", &resp); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(resp.Preferences))
+	for key, value := range resp.Preferences {
+		if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/vmiller/timetracker-cli/internal/logging"
+)
+
+const (
+	// DefaultRateLimit is how many requests per second the client sends at
+	// most, enough to go unnoticed outside batch operations
+	DefaultRateLimit = 20.0
+
+	// maxRateLimitWaits is how often a request answered with 429 is sent
+	// again before the 429 is reported
+	maxRateLimitWaits = 5
+
+	// maxRetryAfter is the longest Retry-After that is waited for; a server
+	// asking for longer gets its 429 reported instead
+	maxRetryAfter = 2 * time.Minute
+
+	// defaultRetryAfter is the first wait after a 429 without Retry-After;
+	// each further one waits twice as long
+	defaultRetryAfter = time.Second
+)
+
+// rateLimiter is a token bucket holding up to a second's worth of requests
+// and refilling at rate per second. Waiting requests reserve their token,
+// so they are sent in turn.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate, tokens: math.Max(1, rate), last: time.Now()}
+}
+
+// wait blocks until a request may be sent or ctx is done. A nil limiter
+// never waits.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(math.Max(1, l.rate), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SetRateLimit caps how many requests per second the client sends, in
+// bursts of up to a second's worth. Zero keeps DefaultRateLimit and a
+// negative rate turns the limit off. onWait, if not nil, is called with the
+// wait when a 429 response holds a request back for the server's
+// Retry-After, and with zero when it is sent again.
+func (c *Client) SetRateLimit(perSecond float64, onWait func(wait time.Duration)) {
+	switch {
+	case perSecond == 0:
+		c.limiter = newRateLimiter(DefaultRateLimit)
+	case perSecond < 0:
+		c.limiter = nil
+	default:
+		c.limiter = newRateLimiter(perSecond)
+	}
+	c.onRateLimit = onWait
+}
+
+// limitRequests makes every request, retries included, wait for the
+// client's rate limiter
+func (c *Client) limitRequests() {
+	c.resty.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		return c.limiter.wait(req.Context())
+	})
+}
+
+// withRateLimit sends a request again when the server answers 429 Too Many
+// Requests, once its Retry-After has passed, up to maxRateLimitWaits times.
+// The server hasn't acted on a request it refused that way, so any request
+// may be sent again. Cancelling ctx stops the waiting. send must build a new
+// request on every call.
+func (c *Client) withRateLimit(ctx context.Context, endpoint string, send func() (*resty.Response, error)) (*resty.Response, error) {
+	for waits := 0; ; waits++ {
+		resp, err := send()
+		if err != nil || resp.StatusCode() != http.StatusTooManyRequests || waits >= maxRateLimitWaits {
+			return resp, err
+		}
+		wait := retryAfter(resp.Header(), time.Now(), defaultRetryAfter<<waits)
+		if wait > maxRetryAfter {
+			return resp, err
+		}
+		if resp.RawBody() != nil {
+			resp.RawBody().Close()
+		}
+
+		logging.Verbosef("⏳ %s: rate limited (429); waiting %s", logging.RedactURL(endpoint), wait.Round(100*time.Millisecond))
+		if c.onRateLimit != nil {
+			c.onRateLimit(wait)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		if c.onRateLimit != nil {
+			c.onRateLimit(0)
+		}
+	}
+}
+
+// retryAfter returns how long a Retry-After header asks to wait, given in
+// seconds or as an HTTP date, or fallback if there is none
+func retryAfter(header http.Header, now time.Time, fallback time.Duration) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return fallback
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20)
+	ctx := context.Background()
+
+	// A second's worth goes out at once, the rest at the rate
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		limiter.wait(ctx)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("the burst took %s", elapsed)
+	}
+	for i := 0; i < 4; i++ {
+		limiter.wait(ctx)
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("4 requests past the burst took %s, want about 200ms", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := limiter.wait(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait with a cancelled context = %v", err)
+	}
+	if err := (*rateLimiter)(nil).wait(cancelled); err != nil {
+		t.Errorf("a nil limiter waited: %v", err)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		switch {
+		case r.URL.Path == "/api/slow":
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n <= 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL})
+	var waits []time.Duration
+	client.SetRateLimit(0, func(wait time.Duration) { waits = append(waits, wait) })
+
+	// Requests that change data are sent again too, since a 429 means the
+	// server didn't act on them
+	if err := client.Post("/api/entries/batch", map[string]string{}, nil); err != nil {
+		t.Fatalf("Post after two 429s = %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 || len(waits) != 4 {
+		t.Errorf("sent %d requests with waits %v, want 3 and a wait and its end per 429", n, waits)
+	}
+
+	// Waiting an hour isn't worth it
+	atomic.StoreInt32(&requests, 0)
+	err := client.Get("/api/slow", nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests {
+		t.Errorf("Get with Retry-After: 3600 = %v, want the 429", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"12", 12 * time.Second},
+		{"Fri, 01 Mar 2024 12:00:30 GMT", 30 * time.Second},
+		{"Fri, 01 Mar 2024 11:00:00 GMT", 0},
+		{"", time.Second},
+		{"soon", time.Second},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		if got := retryAfter(header, now, time.Second); got != tt.want {
+			t.Errorf("Retry-After %q = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RequestIDHeader carries the ID that ties requests to the run of the CLI
+// that sent them
+const RequestIDHeader = "X-Request-ID"
+
+var (
+	requestID     string
+	requestIDOnce sync.Once
+
+	// requestIDSent is set once a request has carried the ID
+	requestIDSent atomic.Bool
+)
+
+// RequestID returns this run's ID, a random UUID sent as X-Request-ID with
+// every request, so the server's logs can be searched for a failure a user
+// reports
+func RequestID() string {
+	requestIDOnce.Do(func() {
+		var b [16]byte
+		rand.Read(b[:])
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		requestID = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	})
+	return requestID
+}
+
+// RequestIDSent reports whether any request has been sent this run, so
+// there is something to find by RequestID
+func RequestIDSent() bool {
+	return requestIDSent.Load()
+}
+
+// sendRequestID sends RequestID with every request
+func sendRequestID(client *resty.Client) {
+	client.SetHeader(RequestIDHeader, RequestID())
+	client.OnBeforeRequest(func(*resty.Client, *resty.Request) error {
+		requestIDSent.Store(true)
+		return nil
+	})
+}
+
+// serverRequestID returns the ID the server gave a request, if it isn't
+// the one the client sent
+func serverRequestID(resp *resty.Response) string {
+	if resp == nil {
+		return ""
+	}
+	if id := resp.Header().Get(RequestIDHeader); id != RequestID() {
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestID(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(RequestIDHeader))
+		if r.URL.Path == "/api/replaced" {
+			w.Header().Set(RequestIDHeader, "req-42")
+		} else {
+			w.Header().Set(RequestIDHeader, r.Header.Get(RequestIDHeader))
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL, Attempts: 1})
+
+	id := RequestID()
+	if !uuidV4.MatchString(id) || RequestID() != id {
+		t.Fatalf("RequestID() = %q, want the same UUID every time", id)
+	}
+
+	var apiErr *Error
+	err := client.Get("/api/echoed", nil)
+	if !errors.As(err, &apiErr) || apiErr.RequestID != id {
+		t.Errorf("Get = %v, want the echoed ID", err)
+	}
+	err = client.Get("/api/replaced", nil)
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-42" {
+		t.Errorf("Get = %v, want the server's ID", err)
+	}
+	if len(received) != 2 || received[0] != id || received[1] != id {
+		t.Errorf("server received IDs %q, want %q twice", received, id)
+	}
+	if !RequestIDSent() {
+		t.Error("RequestIDSent() = false after requests")
+	}
+}
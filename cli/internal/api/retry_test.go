@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// flakyServer answers the first failures requests with status and the rest
+// with an empty JSON object, counting the requests
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestGetRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		status   int
+		wantErr  bool
+		want     int32
+	}{
+		{"502 then success", 2, http.StatusBadGateway, false, 3},
+		{"503 every time", 5, http.StatusServiceUnavailable, true, 3},
+		{"504 once", 1, http.StatusGatewayTimeout, false, 2},
+		{"4xx is final", 5, http.StatusBadRequest, true, 1},
+		{"500 is final", 5, http.StatusInternalServerError, true, 1},
+	}
+	for _, tt := range tests {
+		server, requests := flakyServer(t, tt.failures, tt.status)
+		client := NewClient(&config.Config{APIURL: server.URL, RetryDelay: time.Millisecond})
+
+		err := client.Get("/api/entries/summary/today", nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Get error = %v", tt.name, err)
+		}
+		if *requests != tt.want {
+			t.Errorf("%s: %d requests, want %d", tt.name, *requests, tt.want)
+		}
+	}
+}
+
+func TestPostRetriesOnlyWhenIdempotent(t *testing.T) {
+	server, requests := flakyServer(t, 1, http.StatusBadGateway)
+	client := NewClient(&config.Config{APIURL: server.URL, RetryDelay: time.Millisecond})
+
+	if err := client.Post("/api/entries", map[string]string{}, nil); err == nil {
+		t.Error("Post succeeded after a 502")
+	}
+	if err := client.PostIdempotent(context.Background(), "/api/sync?dryRun=true", nil, nil); err != nil {
+		t.Errorf("PostIdempotent error = %v", err)
+	}
+	if *requests != 2 {
+		t.Errorf("%d requests, want 2", *requests)
+	}
+}
+
+func TestGetRetriesConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := NewClient(&config.Config{APIURL: server.URL, Attempts: 2, RetryDelay: time.Millisecond})
+
+	start := time.Now()
+	if err := client.Get("/api/providers/status", nil); err == nil || !IsTransient(err) {
+		t.Errorf("Get error = %v, want a transient error after retries", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retries took %s", elapsed)
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import "net/url"
+
+// GetShareSettings reports whether the server allows share links.
+// Returns ErrNotFound if the server does not support them.
+func (c *Client) GetShareSettings() (*ShareSettings, error) {
+	var settings ShareSettings
+	if err := c.Get("/api/shares/settings", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// CreateShare asks the server for a signed, read-only link to a report
+func (c *Client) CreateShare(req CreateShareRequest) (*Share, error) {
+	var share Share
+	if err := c.Post("/api/shares", req, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// GetShares lists the share links that haven't expired or been revoked
+func (c *Client) GetShares() ([]Share, error) {
+	var resp SharesResponse
+	if err := c.Get("/api/shares", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Shares, nil
+}
+
+// RevokeShare invalidates a share link
+func (c *Client) RevokeShare(id string) error {
+	return c.Delete("/api/shares/" + url.PathEscape(id))
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+// SSOSettings tells whether the server offers browser-based login
+type SSOSettings struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SSOTokenRequest redeems a one-time login code
+type SSOTokenRequest struct {
+	Code         string `json:"code"`
+	CodeVerifier string `json:"codeVerifier"`
+	RedirectURI  string `json:"redirectUri"`
+}
+
+// GetSSOSettings fetches the server's SSO login settings.
+// Returns ErrNotFound if the server predates SSO login.
+func (c *Client) GetSSOSettings() (*SSOSettings, error) {
+	var settings SSOSettings
+	if err := c.Get("/api/auth/cli-sso", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SSOAuthorizeURL returns the page that starts a browser login. The server
+// sends the code to redirectURI once the user has logged in.
+func (c *Client) SSOAuthorizeURL(redirectURI, state, codeChallenge string) string {
+	params := url.Values{}
+	params.Set("redirect_uri", redirectURI)
+	params.Set("state", state)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+	return c.config.APIURL + "/api/auth/cli-sso/authorize?" + params.Encode()
+}
+
+// LoginWithSSOCode redeems the code of a browser login with its PKCE
+// verifier and stores the tokens
+func (c *Client) LoginWithSSOCode(ctx context.Context, code, verifier, redirectURI string) error {
+	req := SSOTokenRequest{Code: code, CodeVerifier: verifier, RedirectURI: redirectURI}
+
+	var resp LoginResponse
+	if err := c.PostContext(ctx, "/api/auth/cli-sso/token", req, &resp); err != nil {
+		return err
+	}
+
+	return c.saveLogin(&resp, config.AuthModeSSO)
+}
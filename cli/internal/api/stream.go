@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// syncStreamEvent is one event of /api/sync/stream
+type syncStreamEvent struct {
+	Type     string           `json:"type"` // progress, result or error
+	Progress *SyncJobProvider `json:"progress,omitempty"`
+	Result   *SyncResponse    `json:"result,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// SyncStream syncs like Sync, but reads the server's progress as it goes and
+// passes every provider update to progress. Cancelling ctx abandons the
+// request, but the server may still finish the sync. The stream runs as long
+// as the sync does, so the client's timeout doesn't apply; only ctx's
+// deadline does.
+// Returns ErrNotFound if the server can't stream sync progress.
+func (c *Client) SyncStream(ctx context.Context, opts SyncOptions, progress func(SyncJobProvider)) (*SyncResponse, error) {
+	if err := c.RefreshTokenIfNeeded(); err != nil {
+		// If refresh fails, continue anyway (user might need to login)
+	}
+
+	endpoint := withQuery("/api/sync/stream", syncParams(opts))
+	send := func() (*resty.Response, error) {
+		return c.withRateLimit(ctx, endpoint, func() (*resty.Response, error) {
+			return c.withRefresh(endpoint, func() (*resty.Response, error) {
+				return c.resty.R().
+					SetContext(ctx).
+					SetDoNotParseResponse(true).
+					SetHeader("Accept", "application/x-ndjson, text/event-stream").
+					Post(endpoint)
+			})
+		})
+	}
+	var resp *resty.Response
+	var err error
+	if opts.DryRun {
+		// Dry runs change nothing, so they are retried like Sync's
+		resp, err = c.withRetry(ctx, endpoint, send)
+	} else {
+		resp, err = send()
+	}
+	if err != nil {
+		return nil, requestError(err)
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if c.tokenRejected(resp) {
+		return nil, ErrTokenRejected
+	}
+	if resp.IsError() {
+		message, _ := io.ReadAll(io.LimitReader(body, 64*1024))
+		return nil, newError(resp, message)
+	}
+
+	return readSyncStream(body, progress)
+}
+
+// readSyncStream reads sync events, one JSON object per line, until the
+// result. Server-sent event framing is accepted too: the events are then
+// the data lines.
+func readSyncStream(r io.Reader, progress func(SyncJobProvider)) (*SyncResponse, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if data, ok := strings.CutPrefix(line, "data:"); ok {
+			line = strings.TrimSpace(data)
+		} else if line == "" || strings.HasPrefix(line, ":") || sseField(line) {
+			continue
+		}
+
+		var event syncStreamEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("invalid sync progress %q: %w", line, err)
+		}
+		switch event.Type {
+		case "progress":
+			if event.Progress != nil && progress != nil {
+				progress(*event.Progress)
+			}
+		case "result":
+			if event.Result == nil {
+				return nil, errors.New("sync stream sent an empty result")
+			}
+			return event.Result, nil
+		case "error":
+			return nil, errors.New(event.Error)
+		}
+		// Newer event types are skipped
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("sync progress broke off: %w", err)
+	}
+	return nil, errors.New("sync progress ended before the sync finished")
+}
+
+// sseField reports whether line is a server-sent event field other than data
+func sseField(line string) bool {
+	for _, field := range []string{"event:", "id:", "retry:"} {
+		if strings.HasPrefix(line, field) {
+			return true
+		}
+	}
+	return false
+}
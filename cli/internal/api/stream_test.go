@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestReadSyncStreamFixtures(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		progress []string // provider status fetched/imported
+		imported int
+		err      string
+	}{
+		{
+			fixture: "sync_stream.ndjson",
+			progress: []string{
+				"TOGGL pending 0/0", "TEMPO pending 0/0", "TOGGL running 0/0", "TEMPO running 0/0",
+				"TOGGL running 240/0", "TOGGL running 240/31", "TEMPO failed 0/0", "TOGGL done 240/240",
+			},
+			imported: 240,
+		},
+		{
+			fixture:  "sync_stream.sse",
+			progress: []string{"TOGGL running 0/0", "TOGGL running 12/3", "TOGGL done 12/12"},
+			imported: 12,
+		},
+		{
+			fixture:  "sync_stream_error.ndjson",
+			progress: []string{"TOGGL running 0/0"},
+			err:      "database is locked",
+		},
+		{
+			fixture:  "sync_stream_truncated.ndjson",
+			progress: []string{"TOGGL running 0/0", "TOGGL running 240/0"},
+			err:      "sync progress ended before the sync finished",
+		},
+		{
+			fixture:  "sync_stream_cut.ndjson",
+			progress: []string{"TOGGL running 0/0"},
+			err:      `invalid sync progress "{\"type\":\"progress\",\"progr"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			var progress []string
+			resp, err := readSyncStream(f, func(p SyncJobProvider) {
+				progress = append(progress, fmt.Sprintf("%s %s %d/%d", p.Provider, p.Status, p.Fetched, p.Imported))
+			})
+			if strings.Join(progress, ", ") != strings.Join(tt.progress, ", ") {
+				t.Errorf("progress = %q\nwant       %q", progress, tt.progress)
+			}
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Fatalf("readSyncStream() = %v, want error %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readSyncStream(): %v", err)
+			}
+			if resp.TotalImported != tt.imported {
+				t.Errorf("imported = %d, want %d", resp.TotalImported, tt.imported)
+			}
+		})
+	}
+}
+
+func TestSyncStreamNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	_, err := NewClient(&config.Config{APIURL: server.URL}).SyncStream(context.Background(), SyncOptions{}, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("SyncStream() = %v, want %v", err, ErrNotFound)
+	}
+}
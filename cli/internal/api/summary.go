@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GetYearSummary fetches monthly, per-project and per-source totals for a
+// calendar year. Servers without the year endpoint are handled by
+// summarizing the entries one month at a time.
+func (c *Client) GetYearSummary(year int) (*YearSummaryResponse, error) {
+	var summary YearSummaryResponse
+	err := c.Get(withQuery("/api/entries/summary/year", url.Values{"year": {fmt.Sprint(year)}}), &summary)
+	if err == nil {
+		return &summary, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	rangeSummary, err := c.SummarizeRange(from, from.AddDate(1, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	return &YearSummaryResponse{Year: year, RangeSummary: *rangeSummary}, nil
+}
+
+// GetTodaySummary fetches today's summary, limited to the given sources
+// (toggl, tempo, manual) if any. Servers that don't filter by source are
+// handled by summarizing today's entries.
+func (c *Client) GetTodaySummary(sources []string) (*TodaySummaryResponse, error) {
+	var summary TodaySummaryResponse
+	if err := c.Get(withQuery("/api/entries/summary/today", sourceQuery(sources)), &summary); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 || len(summary.Sources) > 0 {
+		return &summary, nil
+	}
+
+	entries, err := c.GetEntries(summary.Date, summary.Date)
+	if err != nil {
+		return nil, err
+	}
+	entries = FilterSources(entries, sources)
+
+	filtered := &TodaySummaryResponse{
+		Date:       summary.Date,
+		BySource:   map[string]float64{},
+		ByProject:  map[string]float64{},
+		EntryCount: len(entries),
+		Sources:    sources,
+	}
+	for _, entry := range entries {
+		project := entry.Project
+		if project == "" {
+			project = "(no project)"
+		}
+		filtered.BySource[entry.Source] += entry.Duration
+		filtered.ByProject[project] += entry.Duration
+		filtered.TotalHours += entry.Duration
+	}
+	return filtered, nil
+}
+
+// GetWeekSummary fetches the summary of the week starting on start, or of
+// the current week if start is zero, limited to the given sources if any.
+// Servers that ignore the weekStart or source parameters are handled by
+// summarizing the week's entries.
+func (c *Client) GetWeekSummary(start time.Time, sources []string) (*WeekSummaryResponse, error) {
+	params := sourceQuery(sources)
+	if !start.IsZero() {
+		params.Set("weekStart", start.Format("2006-01-02"))
+	}
+
+	var summary WeekSummaryResponse
+	if err := c.Get(withQuery("/api/entries/summary/week", params), &summary); err != nil {
+		return nil, err
+	}
+	filtered := len(sources) == 0 || len(summary.Sources) > 0
+	if filtered && (start.IsZero() || summary.WeekStart == start.Format("2006-01-02")) {
+		return &summary, nil
+	}
+	if start.IsZero() {
+		day, err := time.ParseInLocation("2006-01-02", summary.WeekStart, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected week start %q: %w", summary.WeekStart, err)
+		}
+		start = day
+	}
+	return c.SummarizeWeek(start, sources)
+}
+
+// sourceQuery returns the query parameters that limit a summary to sources
+func sourceQuery(sources []string) url.Values {
+	params := url.Values{}
+	if len(sources) > 0 {
+		params.Set("source", strings.Join(sources, ","))
+	}
+	return params
+}
+
+// FilterSources returns the entries of the given sources, or all entries if
+// none are given. Source names are matched regardless of case.
+func FilterSources(entries []Entry, sources []string) []Entry {
+	if len(sources) == 0 {
+		return entries
+	}
+	var filtered []Entry
+	for _, entry := range entries {
+		for _, source := range sources {
+			if strings.EqualFold(entry.Source, source) {
+				filtered = append(filtered, entry)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// SummarizeWeek aggregates the entries of the seven days from start into a
+// week summary like the server's, limited to the given sources if any
+func (c *Client) SummarizeWeek(start time.Time, sources []string) (*WeekSummaryResponse, error) {
+	end := start.AddDate(0, 0, 6)
+	entries, err := c.GetEntries(start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	entries = FilterSources(entries, sources)
+
+	summary := &WeekSummaryResponse{
+		WeekStart:  start.Format("2006-01-02"),
+		WeekEnd:    end.Format("2006-01-02"),
+		BySource:   map[string]float64{},
+		ByProject:  map[string]float64{},
+		EntryCount: len(entries),
+		Sources:    sources,
+	}
+	index := map[string]int{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		index[day.Format("2006-01-02")] = len(summary.Daily)
+		summary.Daily = append(summary.Daily, DailySummary{Date: day.Format("2006-01-02"), DayName: day.Format("Mon")})
+	}
+	for _, entry := range entries {
+		if i, ok := index[entry.Day()]; ok {
+			summary.Daily[i].Hours += entry.Duration
+		}
+		project := entry.Project
+		if project == "" {
+			project = "(no project)"
+		}
+		summary.BySource[entry.Source] += entry.Duration
+		summary.ByProject[project] += entry.Duration
+		summary.TotalHours += entry.Duration
+	}
+	return summary, nil
+}
+
+// SummarizeRange aggregates the entries between two days (inclusive) into
+// monthly, per-project and per-source totals. Entries are fetched and
+// discarded one month at a time so long ranges don't hold every entry in
+// memory. Every month in the range appears in Monthly, even if empty.
+func (c *Client) SummarizeRange(from, to time.Time) (*RangeSummary, error) {
+	summary := &RangeSummary{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		ByProject: map[string]float64{},
+		BySource:  map[string]float64{},
+	}
+
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	for !month.After(to) {
+		start, end := month, month.AddDate(0, 1, -1)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		entries, err := c.GetEntries(start.Format("2006-01-02"), end.Format("2006-01-02"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", month.Format("2006-01"), err)
+		}
+
+		monthly := MonthlySummary{Month: month.Format("2006-01")}
+		for _, entry := range entries {
+			monthly.Hours += entry.Duration
+			monthly.EntryCount++
+
+			project := entry.Project
+			if project == "" {
+				project = "(no project)"
+			}
+			summary.ByProject[project] += entry.Duration
+			summary.BySource[entry.Source] += entry.Duration
+		}
+
+		summary.Monthly = append(summary.Monthly, monthly)
+		summary.TotalHours += monthly.Hours
+		summary.EntryCount += monthly.EntryCount
+		month = month.AddDate(0, 1, 0)
+	}
+
+	return summary, nil
+}
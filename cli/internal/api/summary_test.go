@@ -0,0 +1,147 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestGetYearSummaryFallsBackToMonthlyAggregation(t *testing.T) {
+	entries := threeYearsOfEntries()
+	stats, requests := fakeEntriesServer(t, entries, nil)
+
+	// The year endpoint is missing; everything else goes to the entries server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/entries/summary/year") {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, stats.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+	summary, err := client.GetYearSummary(2022)
+	if err != nil {
+		t.Fatalf("GetYearSummary(): %v", err)
+	}
+
+	var want RangeSummary
+	want.ByProject = map[string]float64{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Date, "2022") {
+			want.TotalHours += entry.Duration
+			want.EntryCount++
+			want.ByProject[entry.Project] += entry.Duration
+		}
+	}
+
+	if summary.Year != 2022 || len(summary.Monthly) != 12 {
+		t.Fatalf("year = %d, %d months", summary.Year, len(summary.Monthly))
+	}
+	if summary.EntryCount != 365 || summary.EntryCount != want.EntryCount {
+		t.Errorf("entries = %d, want %d", summary.EntryCount, want.EntryCount)
+	}
+	if diff := summary.TotalHours - want.TotalHours; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("total = %v, want %v", summary.TotalHours, want.TotalHours)
+	}
+	for project, hours := range want.ByProject {
+		if diff := summary.ByProject[project] - hours; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s = %v, want %v", project, summary.ByProject[project], hours)
+		}
+	}
+	if summary.Monthly[1].Month != "2022-02" || summary.Monthly[1].EntryCount != 28 {
+		t.Errorf("February = %+v", summary.Monthly[1])
+	}
+
+	// One bounded request per month
+	if len(*requests) != 12 || (*requests)[0] != "2022-01-01..2022-01-31" {
+		t.Errorf("requests = %v", *requests)
+	}
+}
+
+func TestGetWeekSummaryFallsBackWhenServerIgnoresWeekStart(t *testing.T) {
+	entries := threeYearsOfEntries()
+	stats, _ := fakeEntriesServer(t, entries, nil)
+
+	// Like older servers, the week endpoint always returns the current week
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/entries/summary/week" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"weekStart":"2030-01-07","weekEnd":"2030-01-13","daily":[]}`))
+			return
+		}
+		http.Redirect(w, r, stats.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+	start := time.Date(2022, 3, 14, 0, 0, 0, 0, time.UTC)
+	summary, err := client.GetWeekSummary(start, nil)
+	if err != nil {
+		t.Fatalf("GetWeekSummary(): %v", err)
+	}
+
+	if summary.WeekStart != "2022-03-14" || summary.WeekEnd != "2022-03-20" {
+		t.Fatalf("week = %s to %s", summary.WeekStart, summary.WeekEnd)
+	}
+	if len(summary.Daily) != 7 || summary.Daily[0].DayName != "Mon" || summary.Daily[6].Date != "2022-03-20" {
+		t.Fatalf("daily = %+v", summary.Daily)
+	}
+	var total float64
+	for _, day := range summary.Daily {
+		total += day.Hours
+	}
+	if summary.EntryCount != 7 || total != summary.TotalHours {
+		t.Errorf("entries = %d, total = %v, days add up to %v", summary.EntryCount, summary.TotalHours, total)
+	}
+	var projects float64
+	for _, hours := range summary.ByProject {
+		projects += hours
+	}
+	if len(summary.ByProject) != 3 || projects != summary.TotalHours {
+		t.Errorf("byProject = %v, want three projects adding up to %v", summary.ByProject, summary.TotalHours)
+	}
+}
+
+func TestGetTodaySummaryFiltersSourcesWhenServerDoesNot(t *testing.T) {
+	entries := []Entry{
+		{ID: "1", Date: "2024-03-14", Duration: 2, Source: "TOGGL", Project: "forHim"},
+		{ID: "2", Date: "2024-03-14", Duration: 1.5, Source: "TEMPO", Project: "WEKA-199"},
+		{ID: "3", Date: "2024-03-14", Duration: 0.5, Source: "MANUAL"},
+	}
+	stats, _ := fakeEntriesServer(t, entries, nil)
+
+	// Like older servers, the today endpoint ignores ?source
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/entries/summary/today" {
+			query = r.URL.Query().Get("source")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"date":"2024-03-14","totalHours":4,"bySource":{"TOGGL":2,"TEMPO":1.5,"MANUAL":0.5},"entryCount":3}`))
+			return
+		}
+		http.Redirect(w, r, stats.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+	summary, err := client.GetTodaySummary([]string{"tempo", "manual"})
+	if err != nil {
+		t.Fatalf("GetTodaySummary(): %v", err)
+	}
+
+	if query != "tempo,manual" {
+		t.Errorf("source query = %q", query)
+	}
+	if summary.TotalHours != 2 || summary.EntryCount != 2 || len(summary.BySource) != 2 || summary.BySource["TOGGL"] != 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.ByProject["(no project)"] != 0.5 {
+		t.Errorf("by project = %v", summary.ByProject)
+	}
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// How often WaitForSyncJob polls: first after syncPollMin, then twice as
+// long each time up to syncPollMax
+var (
+	syncPollMin = time.Second
+	syncPollMax = 10 * time.Second
+)
+
+// Sync asks the server to fetch new entries from the providers. Cancelling
+// ctx abandons the request, but the server may still finish the sync. Dry
+// runs change nothing, so they are retried after transient failures.
+func (c *Client) Sync(ctx context.Context, opts SyncOptions) (*SyncResponse, error) {
+	var resp SyncResponse
+	post := c.PostContext
+	if opts.DryRun {
+		post = c.PostIdempotent
+	}
+	if err := post(ctx, withQuery("/api/sync", syncParams(opts)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StartSync starts a sync in the background and returns its job, whose
+// progress GetSyncJob reports. Only for servers whose SyncSettings allow
+// Async; others would sync before responding.
+func (c *Client) StartSync(ctx context.Context, opts SyncOptions) (*SyncJob, error) {
+	params := syncParams(opts)
+	params.Set("async", "true")
+
+	var job SyncJob
+	if err := c.PostContext(ctx, withQuery("/api/sync", params), nil, &job); err != nil {
+		return nil, err
+	}
+	if job.ID == "" {
+		return nil, fmt.Errorf("the server did not start a background sync")
+	}
+	return &job, nil
+}
+
+// GetSyncJob returns a background sync's progress.
+// Returns ErrNotFound if the job doesn't exist or has expired.
+func (c *Client) GetSyncJob(ctx context.Context, id string) (*SyncJob, error) {
+	var job SyncJob
+	if err := c.GetContext(ctx, "/api/sync/jobs/"+url.PathEscape(id), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitForSyncJob polls a background sync until it finishes and returns its
+// result. progress, if set, is called with the job before every wait.
+// Cancelling ctx stops waiting, but not the job.
+func (c *Client) WaitForSyncJob(ctx context.Context, job *SyncJob, progress func(*SyncJob)) (*SyncResponse, error) {
+	delay := syncPollMin
+	for {
+		if progress != nil {
+			progress(job)
+		}
+		switch job.Status {
+		case SyncJobDone:
+			if job.Result == nil {
+				return nil, fmt.Errorf("sync job %s finished without a result", job.ID)
+			}
+			return job.Result, nil
+		case SyncJobFailed:
+			return nil, errors.New(job.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > syncPollMax {
+			delay = syncPollMax
+		}
+
+		next, err := c.GetSyncJob(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		job = next
+	}
+}
+
+// IsTransient reports whether err is a network error, a request that ran
+// out of the client's timeout or a 502, 503 or 504 response, such as while
+// the server restarts, which a retry may get past. Cancelled requests and
+// those past their context's deadline are not transient.
+func IsTransient(err error) bool {
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) {
+		return true
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrProxyAuth) || errors.Is(err, ErrNoFixture) || isProxyAuthFailure(err) || isCertificateError(err) {
+		return false
+	}
+	// A local file failing, such as ca_cert, has an errno like a network
+	// error, as may a failed preflight, whose answer won't change
+	var pathErr *fs.PathError
+	var preflight *PreflightError
+	if errors.As(err, &pathErr) || errors.As(err, &preflight) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// syncParams builds the query of a sync request
+func syncParams(opts SyncOptions) url.Values {
+	params := url.Values{}
+	if opts.Force {
+		params.Set("force", "true")
+	}
+	if len(opts.Providers) > 0 {
+		params.Set("providers", strings.Join(opts.Providers, ","))
+	}
+	if opts.From != "" {
+		params.Set("from", opts.From)
+		params.Set("to", opts.To)
+	}
+	if opts.DryRun {
+		params.Set("dryRun", "true")
+	}
+	return params
+}
+
+// GetSyncSettings returns what sync requests the server supports.
+// Returns ErrNotFound if the server can't sync date ranges.
+func (c *Client) GetSyncSettings() (*SyncSettings, error) {
+	var settings SyncSettings
+	if err := c.Get("/api/sync/settings", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetSyncHistory lists the most recent sync runs, newest first, optionally
+// only those involving provider.
+// Returns ErrNotFound if the server does not record sync history.
+func (c *Client) GetSyncHistory(limit int, provider string) ([]SyncRun, error) {
+	params := url.Values{
+		"limit":    {fmt.Sprint(limit)},
+		"provider": {provider},
+	}
+
+	var resp SyncHistoryResponse
+	if err := c.Get(withQuery("/api/sync/history", params), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Runs, nil
+}
+
+// GetSyncRun returns a single sync run
+func (c *Client) GetSyncRun(id string) (*SyncRun, error) {
+	var run SyncRun
+	if err := c.Get("/api/sync/history/"+url.PathEscape(id), &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// UndoSyncRun deletes the entries a sync run imported, except those
+// modified since. With dryRun nothing is deleted and the response lists what
+// would be; such requests are retried after transient failures. Returns
+// ErrNotFound if the run doesn't exist or the server can't undo syncs.
+func (c *Client) UndoSyncRun(id string, dryRun bool) (*SyncUndoResponse, error) {
+	var resp SyncUndoResponse
+	post := c.PostContext
+	if dryRun {
+		post = c.PostIdempotent
+	}
+	if err := post(c.baseContext(), "/api/sync/history/"+url.PathEscape(id)+"/undo", SyncUndoRequest{DryRun: dryRun}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
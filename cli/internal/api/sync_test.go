@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+)
+
+func TestSyncSendsOptions(t *testing.T) {
+	tests := []struct {
+		opts SyncOptions
+		want string
+	}{
+		{SyncOptions{}, "/api/sync"},
+		{SyncOptions{Force: true}, "/api/sync?force=true"},
+		{SyncOptions{Force: true, Providers: []string{"toggl", "tempo"}}, "/api/sync?force=true&providers=toggl%2Ctempo"},
+		{SyncOptions{Providers: []string{"toggl"}, From: "2024-02-01", To: "2024-02-14"}, "/api/sync?from=2024-02-01&providers=toggl&to=2024-02-14"},
+	}
+	for _, tt := range tests {
+		var got string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.RequestURI()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(SyncResponse{Success: true, Results: []SyncResult{{Provider: "TOGGL", Success: true}}})
+		}))
+
+		resp, err := NewClient(&config.Config{APIURL: server.URL}).Sync(context.Background(), tt.opts)
+		server.Close()
+		if err != nil {
+			t.Fatalf("Sync(%+v): %v", tt.opts, err)
+		}
+		if got != tt.want {
+			t.Errorf("Sync(%+v) requested %s, want %s", tt.opts, got, tt.want)
+		}
+		if !resp.Success || len(resp.Results) != 1 {
+			t.Errorf("Sync(%+v) = %+v", tt.opts, resp)
+		}
+	}
+}
+
+func TestSyncStopsWhenCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // a hanging server
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewClient(&config.Config{APIURL: server.URL}).Sync(ctx, SyncOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Sync() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWaitForSyncJobPollsUntilDone(t *testing.T) {
+	oldMin, oldMax := syncPollMin, syncPollMax
+	t.Cleanup(func() { syncPollMin, syncPollMax = oldMin, oldMax })
+	syncPollMin, syncPollMax = time.Millisecond, 4*time.Millisecond
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/sync/jobs/j1" {
+			http.NotFound(w, r)
+			return
+		}
+		polls++
+		job := SyncJob{ID: "j1", Status: SyncJobRunning, Providers: []SyncJobProvider{{Provider: "TOGGL", Status: SyncJobRunning}}}
+		if polls == 3 {
+			job.Status = SyncJobDone
+			job.Providers[0] = SyncJobProvider{Provider: "TOGGL", Status: SyncJobDone, Imported: 4}
+			job.Result = &SyncResponse{Success: true, TotalImported: 4}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	}))
+	t.Cleanup(server.Close)
+
+	var seen []string
+	client := NewClient(&config.Config{APIURL: server.URL})
+	start := &SyncJob{ID: "j1", Status: SyncJobRunning}
+	resp, err := client.WaitForSyncJob(context.Background(), start, func(job *SyncJob) {
+		seen = append(seen, job.Status)
+	})
+	if err != nil {
+		t.Fatalf("WaitForSyncJob(): %v", err)
+	}
+	if resp.TotalImported != 4 || polls != 3 {
+		t.Errorf("result = %+v after %d polls", resp, polls)
+	}
+	if want := "running running running done"; strings.Join(seen, " ") != want {
+		t.Errorf("progress = %v, want %s", seen, want)
+	}
+}
+
+func TestWaitForSyncJobReportsFailure(t *testing.T) {
+	job := &SyncJob{ID: "j1", Status: SyncJobFailed, Error: "database is locked"}
+	_, err := NewClient(&config.Config{}).WaitForSyncJob(context.Background(), job, nil)
+	if err == nil || err.Error() != "database is locked" {
+		t.Errorf("WaitForSyncJob() = %v", err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/sync/settings":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/api/sync/jobs/gone":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	client := NewClient(&config.Config{APIURL: server.URL, Attempts: 1})
+
+	_, unavailable := client.GetSyncSettings()
+	_, badGateway := client.Sync(context.Background(), SyncOptions{})
+	_, badRequest := client.GetSyncJob(context.Background(), "gone")
+	server.Close()
+	_, refused := client.GetSyncSettings()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, cancelled := client.Sync(ctx, SyncOptions{})
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"503", unavailable, true},
+		{"502", badGateway, true},
+		{"connection refused", refused, true},
+		{"400", badRequest, false},
+		{"cancelled", cancelled, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%s: %v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
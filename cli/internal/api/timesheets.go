@@ -0,0 +1,26 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// SubmitTimesheet submits a week for approval; the server locks its entries.
+// Returns ErrNotFound if the server does not support submissions.
+func (c *Client) SubmitTimesheet(req SubmitTimesheetRequest) (*TimesheetSubmission, error) {
+	var submission TimesheetSubmission
+	if err := c.Post("/api/timesheets/submissions", req, &submission); err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// GetTimesheetSubmissions lists the most recent submissions, newest first
+func (c *Client) GetTimesheetSubmissions(limit int) ([]TimesheetSubmission, error) {
+	var resp TimesheetSubmissionsResponse
+	endpoint := withQuery("/api/timesheets/submissions", url.Values{"limit": {fmt.Sprint(limit)}})
+	if err := c.Get(endpoint, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Submissions, nil
+}
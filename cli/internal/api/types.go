@@ -1,11 +1,26 @@
 package api
 
+import "time"
+
 // TodaySummaryResponse represents the response from /api/entries/summary/today
 type TodaySummaryResponse struct {
 	Date       string             `json:"date"`
 	TotalHours float64            `json:"totalHours"`
 	BySource   map[string]float64 `json:"bySource"`
 	EntryCount int                `json:"entryCount"`
+
+	// ByProject is nil when the server doesn't report it
+	ByProject map[string]float64 `json:"byProject,omitempty"`
+
+	// BillableHours is nil when the server doesn't report billable time
+	BillableHours *float64 `json:"billableHours,omitempty"`
+
+	// Sources lists the sources the summary is limited to, if any
+	Sources []string `json:"sources,omitempty"`
+
+	// OfflineFrom is when the summary was fetched, if it is a last-known
+	// copy shown because the server couldn't be reached. The CLI sets it.
+	OfflineFrom *time.Time `json:"offlineFrom,omitempty"`
 }
 
 // WeekSummaryResponse represents the response from /api/entries/summary/week
@@ -16,6 +31,19 @@ type WeekSummaryResponse struct {
 	Daily      []DailySummary     `json:"daily"`
 	BySource   map[string]float64 `json:"bySource"`
 	EntryCount int                `json:"entryCount"`
+
+	// ByProject is nil when the server doesn't report it
+	ByProject map[string]float64 `json:"byProject,omitempty"`
+
+	// BillableHours is nil when the server doesn't report billable time
+	BillableHours *float64 `json:"billableHours,omitempty"`
+
+	// Sources lists the sources the summary is limited to, if any
+	Sources []string `json:"sources,omitempty"`
+
+	// OfflineFrom is when the summary was fetched, if it is a last-known
+	// copy shown because the server couldn't be reached. The CLI sets it.
+	OfflineFrom *time.Time `json:"offlineFrom,omitempty"`
 }
 
 // DailySummary represents a single day's summary
@@ -23,6 +51,9 @@ type DailySummary struct {
 	Date    string  `json:"date"`
 	DayName string  `json:"dayName"`
 	Hours   float64 `json:"hours"`
+
+	// BillableHours is nil when the server doesn't report billable time
+	BillableHours *float64 `json:"billableHours,omitempty"`
 }
 
 // SyncResponse represents the response from /api/sync
@@ -31,6 +62,54 @@ type SyncResponse struct {
 	TotalImported int          `json:"totalImported"`
 	TotalSkipped  int          `json:"totalSkipped"`
 	Results       []SyncResult `json:"results"`
+	From          string       `json:"from,omitempty"` // first day synced, for range syncs
+	To            string       `json:"to,omitempty"`   // last day synced
+	DryRun        bool         `json:"dryRun,omitempty"`
+}
+
+// SyncOptions selects what POST /api/sync fetches
+type SyncOptions struct {
+	Force     bool     // re-import everything instead of only what's new
+	Providers []string // provider names; all providers if empty
+	From      string   // first day to fetch, YYYY-MM-DD; needs To
+	To        string   // last day to fetch, YYYY-MM-DD
+	DryRun    bool     // only preview what would change
+}
+
+// SyncSettings represents the response from GET /api/sync/settings
+type SyncSettings struct {
+	MaxRangeDays int  `json:"maxRangeDays,omitempty"` // 0 means no limit
+	DryRun       bool `json:"dryRun"`                 // whether syncs can be previewed
+	Async        bool `json:"async"`                  // whether syncs can run in the background
+}
+
+// Statuses of a SyncJob and of its providers
+const (
+	SyncJobPending = "pending" // providers only
+	SyncJobRunning = "running"
+	SyncJobDone    = "done"
+	SyncJobFailed  = "failed"
+)
+
+// SyncJob is a sync running in the background, from /api/sync/jobs/<id>
+type SyncJob struct {
+	ID         string            `json:"id"`
+	Status     string            `json:"status"` // running, done or failed
+	StartedAt  string            `json:"startedAt"`
+	FinishedAt string            `json:"finishedAt,omitempty"`
+	Providers  []SyncJobProvider `json:"providers"`
+	Result     *SyncResponse     `json:"result,omitempty"` // once done
+	Error      string            `json:"error,omitempty"`  // why the job failed
+}
+
+// SyncJobProvider is the progress of one provider in a SyncJob or a sync
+// stream
+type SyncJobProvider struct {
+	Provider string `json:"provider"`
+	Status   string `json:"status"`            // pending, running, done or failed
+	Fetched  int    `json:"fetched,omitempty"` // entries fetched from the provider so far
+	Imported int    `json:"imported,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 // SyncResult represents the result for a single provider
@@ -40,4 +119,279 @@ type SyncResult struct {
 	Imported int    `json:"imported,omitempty"`
 	Skipped  int    `json:"skipped,omitempty"`
 	Error    string `json:"error,omitempty"`
+
+	// Set instead of Imported and Skipped when previewing a sync
+	WouldImport int                `json:"wouldImport,omitempty"`
+	WouldUpdate int                `json:"wouldUpdate,omitempty"`
+	WouldSkip   int                `json:"wouldSkip,omitempty"`
+	Sample      []SyncPreviewEntry `json:"sample,omitempty"` // some of the entries that would be imported
+}
+
+// SyncPreviewEntry is an entry a previewed sync would import
+type SyncPreviewEntry struct {
+	Date        string  `json:"date"`
+	Project     string  `json:"project"`
+	Duration    float64 `json:"duration"`
+	Description string  `json:"description"`
+}
+
+// SyncRun is one recorded sync from /api/sync/history
+type SyncRun struct {
+	ID            string       `json:"id"`
+	StartedAt     string       `json:"startedAt"`
+	FinishedAt    string       `json:"finishedAt,omitempty"`
+	DurationMs    int64        `json:"durationMs"`
+	Trigger       string       `json:"trigger"` // MANUAL, CRON or CLI
+	Force         bool         `json:"force,omitempty"`
+	RangeStart    string       `json:"rangeStart,omitempty"` // first day covered
+	RangeEnd      string       `json:"rangeEnd,omitempty"`   // last day covered
+	Success       bool         `json:"success"`
+	TotalImported int          `json:"totalImported"`
+	TotalSkipped  int          `json:"totalSkipped"`
+	Results       []SyncResult `json:"results"`
+}
+
+// SyncHistoryResponse represents the response from /api/sync/history
+type SyncHistoryResponse struct {
+	Runs []SyncRun `json:"runs"`
+}
+
+// SyncUndoRequest represents the request body for
+// POST /api/sync/history/:id/undo. A dry run reports what would be removed
+// without deleting anything.
+type SyncUndoRequest struct {
+	DryRun bool `json:"dryRun"`
+}
+
+// SyncUndoResponse represents the response from
+// POST /api/sync/history/:id/undo
+type SyncUndoResponse struct {
+	RunID string `json:"runId"`
+	// Entries are the imported entries that are (or would be) deleted
+	Entries []Entry `json:"entries"`
+	// Modified are imported entries changed since the sync; they are kept
+	Modified []Entry `json:"modified"`
+	Deleted  int     `json:"deleted"`
+}
+
+// Note is free text attached to a day, e.g. "half day - dentist"
+type Note struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Text string `json:"text"`
+}
+
+// NotesResponse represents the response from /api/notes
+type NotesResponse struct {
+	Notes []Note `json:"notes"`
+}
+
+// SetNoteRequest represents the request body for PUT /api/notes/:date
+type SetNoteRequest struct {
+	Text string `json:"text"`
+}
+
+// Entry represents a single time entry
+type Entry struct {
+	ID          string  `json:"id"`
+	Source      string  `json:"source"`
+	Date        string  `json:"date"`
+	Duration    float64 `json:"duration"`
+	Project     string  `json:"project"`
+	Description string  `json:"description"`
+	StartTime   string  `json:"startTime,omitempty"`
+	EndTime     string  `json:"endTime,omitempty"`
+
+	// Client is empty when the source doesn't group projects under clients
+	Client string `json:"client,omitempty"`
+
+	// Locked is set once the entry's week has been submitted
+	Locked bool `json:"locked,omitempty"`
+
+	// Billable is nil when the source doesn't track billability
+	Billable *bool `json:"billable,omitempty"`
+}
+
+// IsBillable reports whether the entry is billable, using fallback for
+// entries whose source doesn't say
+func (e Entry) IsBillable(fallback bool) bool {
+	if e.Billable == nil {
+		return fallback
+	}
+	return *e.Billable
+}
+
+// Day returns the entry's date as YYYY-MM-DD in the local timezone
+func (e Entry) Day() string {
+	t, err := time.Parse(time.RFC3339, e.Date)
+	if err != nil {
+		// Plain dates (or unexpected formats) keep their leading date part
+		if len(e.Date) >= 10 {
+			return e.Date[:10]
+		}
+		return e.Date
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// SearchResponse represents the response from /api/entries/search
+type SearchResponse struct {
+	Results []Entry `json:"results"`
+	Total   int     `json:"total"`
+}
+
+// CreateEntryRequest represents the request body for POST /api/entries
+type CreateEntryRequest struct {
+	Date        string `json:"date"`
+	StartTime   string `json:"startTime"`
+	EndTime     string `json:"endTime"`
+	Project     string `json:"project,omitempty"`
+	Description string `json:"description,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
+}
+
+// UpdateEntryRequest represents the request body for PUT /api/entries/:id.
+// For manual entries with start and end times the server derives the
+// duration from the times, and Date must be YYYY-MM-DD.
+type UpdateEntryRequest struct {
+	Date        string  `json:"date"`
+	Duration    float64 `json:"duration"`
+	Project     string  `json:"project"`
+	Description string  `json:"description"`
+	Source      string  `json:"source"`
+	StartTime   string  `json:"startTime,omitempty"`
+	EndTime     string  `json:"endTime,omitempty"`
+	Timezone    string  `json:"timezone,omitempty"`
+}
+
+// ImportEntry represents a single entry in an import batch
+type ImportEntry struct {
+	Source      string  `json:"source"`
+	ExternalID  string  `json:"externalId"`
+	Date        string  `json:"date"`
+	StartTime   string  `json:"startTime,omitempty"`
+	Duration    float64 `json:"duration"`
+	Project     string  `json:"project,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// ImportRequest represents the request body for POST /api/entries/batch
+type ImportRequest struct {
+	Entries []ImportEntry `json:"entries"`
+}
+
+// ImportResponse represents the response from /api/entries/batch
+type ImportResponse struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+// PreferencesResponse represents the response from /api/preferences
+type PreferencesResponse struct {
+	Preferences map[string]interface{} `json:"preferences"`
+}
+
+// HealthResponse represents the response from /api/health or /health
+type HealthResponse struct {
+	Status    string `json:"status"`
+	Timestamp string `json:"timestamp"`
+	Version   string `json:"version,omitempty"` // missing from older servers
+}
+
+// ProvidersStatusResponse represents the response from /api/providers/status
+type ProvidersStatusResponse struct {
+	Providers []ProviderStatus `json:"providers"`
+}
+
+// ProviderStatus represents the configuration state of a single provider
+type ProviderStatus struct {
+	Name       string  `json:"name"`
+	Configured bool    `json:"configured"`
+	EntryCount int     `json:"entryCount"`
+	LastSync   *string `json:"lastSync"`
+}
+
+// MonthlySummary represents a single month's totals
+type MonthlySummary struct {
+	Month      string  `json:"month"` // YYYY-MM
+	Hours      float64 `json:"hours"`
+	EntryCount int     `json:"entryCount"`
+}
+
+// RangeSummary represents totals for a span of whole or partial months
+type RangeSummary struct {
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	TotalHours float64            `json:"totalHours"`
+	EntryCount int                `json:"entryCount"`
+	Monthly    []MonthlySummary   `json:"monthly"`
+	ByProject  map[string]float64 `json:"byProject"`
+	BySource   map[string]float64 `json:"bySource"`
+}
+
+// YearSummaryResponse represents the response from /api/entries/summary/year
+type YearSummaryResponse struct {
+	Year int `json:"year"`
+	RangeSummary
+}
+
+// SubmitTimesheetRequest represents the request body for POST /api/timesheets/submissions
+type SubmitTimesheetRequest struct {
+	Week       string   `json:"week"` // ISO week, e.g. 2024-W14
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	TotalHours float64  `json:"totalHours"`
+	EntryIDs   []string `json:"entryIds"`
+}
+
+// TimesheetSubmission represents a submitted week and its approval state
+type TimesheetSubmission struct {
+	ID          string  `json:"id"`
+	Week        string  `json:"week"`
+	TotalHours  float64 `json:"totalHours"`
+	Status      string  `json:"status"` // SUBMITTED, APPROVED or REJECTED
+	SubmittedAt string  `json:"submittedAt"`
+	Comment     string  `json:"comment,omitempty"`
+}
+
+// TimesheetSubmissionsResponse represents the response from GET /api/timesheets/submissions
+type TimesheetSubmissionsResponse struct {
+	Submissions []TimesheetSubmission `json:"submissions"`
+}
+
+// ShareSettings represents the response from GET /api/shares/settings
+type ShareSettings struct {
+	Enabled       bool `json:"enabled"`
+	MaxExpiryDays int  `json:"maxExpiryDays,omitempty"` // 0 means no limit
+}
+
+// CreateShareRequest represents the request body for POST /api/shares
+type CreateShareRequest struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Label     string   `json:"label,omitempty"`
+	ExpiresAt string   `json:"expiresAt"`          // RFC 3339
+	Sources   []string `json:"sources,omitempty"`  // all sources if empty
+	Client    string   `json:"client,omitempty"`   // all clients if empty
+	Billable  *bool    `json:"billable,omitempty"` // billable or non-billable entries only, if set
+}
+
+// Share represents a signed, read-only link to a report
+type Share struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Label     string   `json:"label,omitempty"`
+	CreatedAt string   `json:"createdAt"`
+	ExpiresAt string   `json:"expiresAt"`
+	Views     int      `json:"views"`
+	Sources   []string `json:"sources,omitempty"`  // the report's sources, if limited
+	Client    string   `json:"client,omitempty"`   // the report's client, if limited
+	Billable  *bool    `json:"billable,omitempty"` // the report's billability, if limited
+}
+
+// SharesResponse represents the response from GET /api/shares
+type SharesResponse struct {
+	Shares []Share `json:"shares"`
 }
@@ -0,0 +1,151 @@
+// Package archive writes and reads entry archives: gzipped JSON Lines whose
+// first line is a manifest describing the rest. The manifest's hash covers
+// every entry line, so an archive can be verified before the archived
+// entries are deleted from the server.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/version"
+)
+
+// FormatName identifies archive files in their manifest
+const FormatName = "timetracker-archive"
+
+// FormatVersion is the current archive layout
+const FormatVersion = 1
+
+// Manifest is the first line of an archive
+type Manifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// From and To are the requested range; either may be empty for an
+	// open end. First and Last are the dates of the oldest and newest entry.
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+
+	Count  int     `json:"count"`
+	Hours  float64 `json:"hours"`
+	SHA256 string  `json:"sha256"` // of the entry lines, newlines included
+}
+
+// Write writes entries as an archive of the range from..to
+func Write(w io.Writer, entries []api.Entry, from, to string, now time.Time) (Manifest, error) {
+	manifest := Manifest{
+		Format:    FormatName,
+		Version:   FormatVersion,
+		CreatedBy: version.Version,
+		CreatedAt: now.UTC(),
+		From:      from,
+		To:        to,
+		Count:     len(entries),
+	}
+
+	// Encode the entries first: the manifest needs their hash
+	lines := make([][]byte, len(entries))
+	hash := sha256.New()
+	for i, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to encode entry %s: %w", entry.ID, err)
+		}
+		lines[i] = append(line, '\n')
+		hash.Write(lines[i])
+
+		manifest.Hours += entry.Duration
+		day := entry.Day()
+		if manifest.First == "" || day < manifest.First {
+			manifest.First = day
+		}
+		if day > manifest.Last {
+			manifest.Last = day
+		}
+	}
+	manifest.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	zw := gzip.NewWriter(w)
+	header, err := json.Marshal(manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if _, err := zw.Write(append(header, '\n')); err != nil {
+		return Manifest{}, err
+	}
+	for _, line := range lines {
+		if _, err := zw.Write(line); err != nil {
+			return Manifest{}, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// Read reads an archive and verifies its entries against the manifest's
+// count and hash
+func Read(r io.Reader) (Manifest, []api.Entry, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("not a gzipped archive: %w", err)
+	}
+	defer zr.Close()
+
+	reader := bufio.NewReader(zr)
+	header, err := reader.ReadBytes('\n')
+	if err != nil {
+		return Manifest{}, nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(header, &manifest); err != nil || manifest.Format != FormatName {
+		return Manifest{}, nil, fmt.Errorf("not a timetracker archive")
+	}
+	if manifest.Version > FormatVersion {
+		return Manifest{}, nil, fmt.Errorf("archive version %d was written by a newer CLI (%s); upgrade to read it", manifest.Version, manifest.CreatedBy)
+	}
+
+	var entries []api.Entry
+	hash := sha256.New()
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			hash.Write(line)
+			var entry api.Entry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return manifest, nil, fmt.Errorf("entry %d is corrupt: %w", len(entries)+1, err)
+			}
+			entries = append(entries, entry)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, fmt.Errorf("failed to read entries: %w", err)
+		}
+	}
+
+	if len(entries) != manifest.Count {
+		return manifest, entries, fmt.Errorf("archive has %d entries, manifest says %d", len(entries), manifest.Count)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != manifest.SHA256 {
+		return manifest, entries, fmt.Errorf("archive hash %s doesn't match manifest %s", sum, manifest.SHA256)
+	}
+
+	return manifest, entries, nil
+}
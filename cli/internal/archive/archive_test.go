@@ -0,0 +1,63 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+func TestRoundTrip(t *testing.T) {
+	entries := []api.Entry{
+		{ID: "1", Source: "TOGGL", Date: "2022-03-01", Duration: 2.5, Project: "WEKA", Description: "Review"},
+		{ID: "2", Source: "TEMPO", Date: "2022-11-30T08:00:00Z", Duration: 1, Project: "ACME"},
+	}
+
+	var buf bytes.Buffer
+	written, err := Write(&buf, entries, "", "2022-12-31", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written.Count != 2 || written.Hours != 3.5 || written.First != "2022-03-01" || written.Last != "2022-11-30" {
+		t.Errorf("manifest = %+v", written)
+	}
+
+	manifest, read, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest != written {
+		t.Errorf("read manifest %+v, wrote %+v", manifest, written)
+	}
+	if len(read) != 2 || read[0].Description != "Review" || read[1].ID != "2" {
+		t.Errorf("read entries = %+v", read)
+	}
+}
+
+func TestReadDetectsTampering(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Write(&buf, []api.Entry{{ID: "1", Date: "2022-03-01", Duration: 2}}, "", "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, _ := gzip.NewReader(&buf)
+	plain, _ := io.ReadAll(zr)
+	tampered := strings.Replace(string(plain), `"duration":2`, `"duration":9`, 1)
+
+	var out bytes.Buffer
+	zw := gzip.NewWriter(&out)
+	zw.Write([]byte(tampered))
+	zw.Close()
+
+	if _, _, err := Read(&out); err == nil || !strings.Contains(err.Error(), "hash") {
+		t.Errorf("Read(tampered) error = %v, want a hash mismatch", err)
+	}
+
+	if _, _, err := Read(strings.NewReader("not gzip")); err == nil {
+		t.Error("Read(plain text) succeeded")
+	}
+}
@@ -0,0 +1,263 @@
+// Package batch parses and validates files of entry operations for
+// `timetracker batch`. Files are YAML (a list of operations) or JSONL (one
+// operation object per line).
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// Operation kinds
+const (
+	Add    = "add"
+	Edit   = "edit"
+	Delete = "delete"
+)
+
+// Operation is one add, edit or delete. For edits, empty fields keep the
+// entry's current value.
+type Operation struct {
+	Line int `json:"-" yaml:"-"` // line in the file the operation starts on
+
+	Op          string `json:"op" yaml:"op"`
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	Date        string `json:"date,omitempty" yaml:"date,omitempty"`
+	Start       string `json:"start,omitempty" yaml:"start,omitempty"`
+	End         string `json:"end,omitempty" yaml:"end,omitempty"`
+	Duration    string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Project     string `json:"project,omitempty" yaml:"project,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// fields are the keys an operation may have
+var fields = map[string]bool{
+	"op": true, "id": true, "date": true, "start": true, "end": true,
+	"duration": true, "project": true, "description": true,
+}
+
+// LineError describes an operation that could not be parsed or validated
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ParseFile reads the operations in the file at path. .yaml and .yml files
+// are parsed as YAML, everything else as JSONL.
+func ParseFile(path string) ([]Operation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		return ParseYAML(f)
+	}
+	return ParseJSONL(f)
+}
+
+// ParseYAML reads a YAML list of operations
+func ParseYAML(r io.Reader) ([]Operation, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	list := &doc
+	if list.Kind == yaml.DocumentNode && len(list.Content) > 0 {
+		list = list.Content[0]
+	}
+	if list.Kind != yaml.SequenceNode {
+		return nil, LineError{Line: list.Line, Err: fmt.Errorf("expected a list of operations")}
+	}
+
+	ops := make([]Operation, 0, len(list.Content))
+	for _, item := range list.Content {
+		if item.Kind != yaml.MappingNode {
+			return nil, LineError{Line: item.Line, Err: fmt.Errorf("expected an operation mapping")}
+		}
+		for i := 0; i < len(item.Content); i += 2 {
+			if key := item.Content[i]; !fields[key.Value] {
+				return nil, LineError{Line: key.Line, Err: fmt.Errorf("unknown field %q", key.Value)}
+			}
+		}
+
+		var op Operation
+		if err := item.Decode(&op); err != nil {
+			return nil, LineError{Line: item.Line, Err: err}
+		}
+		op.Line = item.Line
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// ParseJSONL reads one JSON operation per line. Blank lines and lines
+// starting with # are skipped.
+func ParseJSONL(r io.Reader) ([]Operation, error) {
+	var ops []Operation
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+		decoder.DisallowUnknownFields()
+
+		var op Operation
+		if err := decoder.Decode(&op); err != nil {
+			return nil, LineError{Line: line, Err: err}
+		}
+		op.Line = line
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read operations: %w", err)
+	}
+
+	return ops, nil
+}
+
+// Validate checks every operation and normalizes dates to YYYY-MM-DD and,
+// for adds given a start and duration, fills in the end time. It returns
+// one error per invalid operation.
+func Validate(ops []Operation, now time.Time) []error {
+	var errs []error
+	for i := range ops {
+		if err := ops[i].normalize(now); err != nil {
+			errs = append(errs, LineError{Line: ops[i].Line, Err: err})
+		}
+	}
+	return errs
+}
+
+func (o *Operation) normalize(now time.Time) error {
+	o.Op = strings.ToLower(strings.TrimSpace(o.Op))
+
+	if o.Date != "" {
+		t, err := dates.Parse(o.Date, now)
+		if err != nil {
+			return err
+		}
+		o.Date = dates.Format(t)
+	}
+
+	switch o.Op {
+	case Add:
+		if o.ID != "" {
+			return fmt.Errorf("add does not take an id")
+		}
+		if o.Date == "" || o.Start == "" {
+			return fmt.Errorf("add needs date and start")
+		}
+		return o.normalizeTimes(true)
+
+	case Edit:
+		if o.ID == "" {
+			return fmt.Errorf("edit needs an id")
+		}
+		if o.Date == "" && o.Start == "" && o.End == "" && o.Duration == "" && o.Project == "" && o.Description == "" {
+			return fmt.Errorf("edit changes nothing")
+		}
+		if (o.Start == "") != (o.End == "") && o.Duration == "" {
+			return fmt.Errorf("edit needs both start and end, or start and duration")
+		}
+		return o.normalizeTimes(false)
+
+	case Delete:
+		if o.ID == "" {
+			return fmt.Errorf("delete needs an id")
+		}
+		if o.Date != "" || o.Start != "" || o.End != "" || o.Duration != "" || o.Project != "" || o.Description != "" {
+			return fmt.Errorf("delete only takes an id")
+		}
+		return nil
+
+	case "":
+		return fmt.Errorf("missing op (add, edit or delete)")
+	default:
+		return fmt.Errorf("unknown op %q (expected add, edit or delete)", o.Op)
+	}
+}
+
+// normalizeTimes checks start, end and duration and derives the end time
+// from start and duration. With required, an end or duration must be given.
+func (o *Operation) normalizeTimes(required bool) error {
+	if o.End != "" && o.Duration != "" {
+		return fmt.Errorf("give either end or duration, not both")
+	}
+
+	if o.Duration != "" {
+		hours, err := dates.ParseHours(o.Duration)
+		if err != nil {
+			return err
+		}
+		if hours <= 0 {
+			return fmt.Errorf("duration must be positive")
+		}
+		o.Duration = ""
+		if o.Start == "" {
+			// Edit of the duration only; keep it as decimal hours
+			o.Duration = fmt.Sprint(hours)
+			return nil
+		}
+		start, err := time.Parse("15:04", o.Start)
+		if err != nil {
+			return fmt.Errorf("invalid start time %q (expected HH:mm)", o.Start)
+		}
+		end := start.Add(time.Duration(math.Round(hours*60)) * time.Minute)
+		if end.Day() != start.Day() {
+			return fmt.Errorf("entry must end on the same day")
+		}
+		o.End = end.Format("15:04")
+	}
+
+	if o.Start == "" && o.End == "" {
+		if required {
+			return fmt.Errorf("needs end or duration")
+		}
+		return nil
+	}
+	if o.End == "" {
+		return fmt.Errorf("needs end or duration")
+	}
+
+	start, err := time.Parse("15:04", o.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q (expected HH:mm)", o.Start)
+	}
+	end, err := time.Parse("15:04", o.End)
+	if err != nil {
+		return fmt.Errorf("invalid end time %q (expected HH:mm)", o.End)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("end time must be after start time")
+	}
+
+	return nil
+}
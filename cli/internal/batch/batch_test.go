@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func TestParseYAMLRecordsLines(t *testing.T) {
+	input := `- op: add
+  date: 2024-03-01
+  start: "09:00"
+  duration: 8h
+  project: Retainer
+- op: delete
+  id: "42"
+`
+	ops, err := ParseYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("got %d operations, want 2", len(ops))
+	}
+	if ops[0].Line != 1 || ops[1].Line != 6 {
+		t.Errorf("lines = %d, %d; want 1, 6", ops[0].Line, ops[1].Line)
+	}
+
+	if errs := Validate(ops, now); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if ops[0].End != "17:00" || ops[0].Duration != "" {
+		t.Errorf("add end = %q, duration = %q; want 17:00 and empty", ops[0].End, ops[0].Duration)
+	}
+}
+
+func TestParseYAMLRejectsUnknownFields(t *testing.T) {
+	_, err := ParseYAML(strings.NewReader("- op: add\n  projcet: typo\n"))
+	if err == nil || !strings.Contains(err.Error(), `line 2: unknown field "projcet"`) {
+		t.Errorf("err = %v, want unknown field on line 2", err)
+	}
+}
+
+func TestParseJSONLSkipsBlankAndCommentLines(t *testing.T) {
+	input := `# retainer
+{"op":"add","date":"today","start":"09:00","end":"12:00"}
+
+{"op":"edit","id":"7","duration":"90m"}
+`
+	ops, err := ParseJSONL(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0].Line != 2 || ops[1].Line != 4 {
+		t.Fatalf("ops = %+v", ops)
+	}
+
+	if errs := Validate(ops, now); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if ops[0].Date != "2024-03-15" {
+		t.Errorf("date = %q, want 2024-03-15", ops[0].Date)
+	}
+	if ops[1].Duration != "1.5" {
+		t.Errorf("duration = %q, want 1.5", ops[1].Duration)
+	}
+}
+
+func TestValidateReportsEveryInvalidOperation(t *testing.T) {
+	ops := []Operation{
+		{Line: 1, Op: "add", Date: "2024-03-01", Start: "09:00", End: "17:00"},
+		{Line: 2, Op: "add", Date: "2024-03-01", Start: "10:00", End: "09:00"},
+		{Line: 3, Op: "edit"},
+		{Line: 4, Op: "delete", ID: "1", Project: "X"},
+		{Line: 5, Op: "move"},
+		{Line: 6, Op: "add", Date: "2024-13-01", Start: "09:00", End: "10:00"},
+	}
+
+	errs := Validate(ops, now)
+	if len(errs) != 5 {
+		t.Fatalf("got %d errors, want 5: %v", len(errs), errs)
+	}
+	for i, err := range errs {
+		if want := "line " + string(rune('2'+i)) + ":"; !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("error %d = %q, want prefix %q", i, err, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Package browser opens URLs in the user's default browser.
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ErrNoBrowser is returned when no browser can be launched, e.g. over SSH
+var ErrNoBrowser = errors.New("no browser available")
+
+// Opener opens URLs in a browser
+type Opener interface {
+	Open(url string) error
+}
+
+// System launches the platform's URL handler (xdg-open, open, rundll32)
+type System struct {
+	GOOS   string
+	Getenv func(string) string
+	Run    func(name string, args ...string) error
+}
+
+// Default returns an Opener for the current platform
+func Default() Opener {
+	return System{
+		GOOS:   runtime.GOOS,
+		Getenv: os.Getenv,
+		Run: func(name string, args ...string) error {
+			if _, err := exec.LookPath(name); err != nil {
+				return ErrNoBrowser
+			}
+			return exec.Command(name, args...).Start()
+		},
+	}
+}
+
+// Command returns the program and arguments that open url on goos
+func Command(goos, url string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	default:
+		return "xdg-open", []string{url}
+	}
+}
+
+// Open implements Opener
+func (s System) Open(url string) error {
+	// Remote sessions without a display can't show a browser
+	if s.GOOS != "darwin" && s.GOOS != "windows" &&
+		s.Getenv("DISPLAY") == "" && s.Getenv("WAYLAND_DISPLAY") == "" {
+		return ErrNoBrowser
+	}
+
+	name, args := Command(s.GOOS, url)
+	if err := s.Run(name, args...); err != nil {
+		if errors.Is(err, ErrNoBrowser) {
+			return err
+		}
+		return fmt.Errorf("failed to launch %s: %w", name, err)
+	}
+
+	return nil
+}
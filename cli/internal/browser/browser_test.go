@@ -0,0 +1,44 @@
+package browser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOpenRunsPlatformCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		env      map[string]string
+		wantName string
+		wantArgs []string
+		wantErr  error
+	}{
+		{goos: "darwin", wantName: "open", wantArgs: []string{"http://tt"}},
+		{goos: "windows", wantName: "rundll32", wantArgs: []string{"url.dll,FileProtocolHandler", "http://tt"}},
+		{goos: "linux", env: map[string]string{"DISPLAY": ":0"}, wantName: "xdg-open", wantArgs: []string{"http://tt"}},
+		{goos: "linux", env: map[string]string{"SSH_CONNECTION": "1.2.3.4"}, wantErr: ErrNoBrowser},
+	}
+
+	for _, tt := range tests {
+		var gotName string
+		var gotArgs []string
+		opener := System{
+			GOOS:   tt.goos,
+			Getenv: func(key string) string { return tt.env[key] },
+			Run: func(name string, args ...string) error {
+				gotName, gotArgs = name, args
+				return nil
+			},
+		}
+
+		err := opener.Open("http://tt")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Open() error = %v, want %v", tt.goos, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && (gotName != tt.wantName || !reflect.DeepEqual(gotArgs, tt.wantArgs)) {
+			t.Errorf("%s: ran %s %v, want %s %v", tt.goos, gotName, gotArgs, tt.wantName, tt.wantArgs)
+		}
+	}
+}
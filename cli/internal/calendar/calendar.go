@@ -0,0 +1,152 @@
+// Package calendar decides which days are workdays, days of the working
+// week that are neither public holidays nor part of a vacation week, and
+// how many hours each owes. The commands that expect hours (missing,
+// balance, week) share it so they agree on which days owe time.
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// Kind classifies a day
+type Kind int
+
+const (
+	Workday  Kind = iota
+	DayOff        // not a day of the working week, e.g. a weekend
+	Holiday       // a public holiday on a working day
+	Vacation      // a working day in a vacation week
+)
+
+// Calendar holds the working week and the days taken off it
+type Calendar struct {
+	WorkDays [7]bool           // indexed by time.Weekday
+	Hours    [7]float64        // expected hours per weekday
+	Holidays map[string]string // YYYY-MM-DD to name
+	Vacation map[string]string // ISO week ("2024-W32") to note
+}
+
+// FromSchedule returns a calendar expecting the given hours per weekday.
+// Weekdays without hours aren't workdays.
+func FromSchedule(hours [7]float64) *Calendar {
+	c := &Calendar{Hours: hours}
+	for day, h := range hours {
+		c.WorkDays[day] = h > 0
+	}
+	return c
+}
+
+// FromContract returns a calendar spreading weekly contract hours evenly
+// over the working days
+func FromContract(weekly float64, days [7]bool) *Calendar {
+	c := &Calendar{WorkDays: days}
+	count := 0
+	for _, work := range days {
+		if work {
+			count++
+		}
+	}
+	for day, work := range days {
+		if work {
+			c.Hours[day] = weekly / float64(count)
+		}
+	}
+	return c
+}
+
+// Default returns a calendar expecting 40 hours over Monday to Friday,
+// without holidays or vacation
+func Default() *Calendar {
+	var days [7]bool
+	for day := time.Monday; day <= time.Friday; day++ {
+		days[day] = true
+	}
+	return FromContract(40, days)
+}
+
+// ParseSchedule converts a schedule keyed by weekday names ("mon",
+// "Tuesday") to hours per weekday. Days not listed expect no hours.
+func ParseSchedule(schedule map[string]float64) ([7]float64, error) {
+	var hours [7]float64
+	for name, h := range schedule {
+		day, err := dates.ParseWeekday(name)
+		if err != nil {
+			return hours, err
+		}
+		if h < 0 || h > 24 {
+			return hours, fmt.Errorf("invalid hours for %s: %v", day, h)
+		}
+		hours[day] = h
+	}
+	return hours, nil
+}
+
+// Day classifies t, returning the holiday's name for holidays and the
+// vacation note for vacation days
+func (c *Calendar) Day(t time.Time) (Kind, string) {
+	if !c.WorkDays[t.Weekday()] {
+		return DayOff, ""
+	}
+	if name, ok := c.Holidays[dates.Format(t)]; ok {
+		return Holiday, name
+	}
+	if note, ok := c.Vacation[dates.ISOWeek(t)]; ok {
+		return Vacation, note
+	}
+	return Workday, ""
+}
+
+// IsWorkday reports whether hours are expected on t
+func (c *Calendar) IsWorkday(t time.Time) bool {
+	kind, _ := c.Day(t)
+	return kind == Workday
+}
+
+// IsVacationWeek reports whether the ISO week is marked as vacation
+func (c *Calendar) IsVacationWeek(isoWeek string) bool {
+	_, ok := c.Vacation[isoWeek]
+	return ok
+}
+
+// Workdays counts the workdays from from to to, inclusive
+func (c *Calendar) Workdays(from, to time.Time) int {
+	count := 0
+	for day := dates.StartOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkday(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// Expected returns the hours owed on t: its weekday's hours on workdays,
+// nothing on days off, holidays and vacation
+func (c *Calendar) Expected(t time.Time) float64 {
+	if !c.IsWorkday(t) {
+		return 0
+	}
+	return c.Hours[t.Weekday()]
+}
+
+// ExpectedBetween returns the hours owed from from to to, inclusive
+func (c *Calendar) ExpectedBetween(from, to time.Time) float64 {
+	total := 0.0
+	for day := dates.StartOfDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		total += c.Expected(day)
+	}
+	return total
+}
+
+// WeeklyHours returns the hours of a full working week without days off
+func (c *Calendar) WeeklyHours() float64 {
+	total := 0.0
+	for day, h := range c.Hours {
+		if c.WorkDays[day] {
+			total += h
+		}
+	}
+	return total
+}
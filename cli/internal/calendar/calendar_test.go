@@ -0,0 +1,154 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestDay(t *testing.T) {
+	c := Default()
+	c.Holidays = map[string]string{"2024-05-01": "Labour Day", "2024-05-04": "Saturday holiday"}
+	c.Vacation = map[string]string{"2024-W32": "Summer"}
+
+	tests := []struct {
+		date string
+		kind Kind
+		name string
+	}{
+		{"2024-04-30", Workday, ""},
+		{"2024-05-01", Holiday, "Labour Day"},
+		{"2024-05-04", DayOff, ""}, // holidays on weekends don't change anything
+		{"2024-05-05", DayOff, ""},
+		{"2024-08-07", Vacation, "Summer"},
+		{"2024-08-10", DayOff, ""},
+	}
+	for _, tt := range tests {
+		kind, name := c.Day(day(tt.date))
+		if kind != tt.kind || name != tt.name {
+			t.Errorf("Day(%s) = %v %q, want %v %q", tt.date, kind, name, tt.kind, tt.name)
+		}
+	}
+
+	// April 29 to May 5 has five weekdays, one of them a holiday
+	if got := c.Workdays(day("2024-04-29"), day("2024-05-05")); got != 4 {
+		t.Errorf("Workdays() = %d, want 4", got)
+	}
+	if got := c.Workdays(day("2024-08-05"), day("2024-08-11")); got != 0 {
+		t.Errorf("Workdays(vacation week) = %d, want 0", got)
+	}
+	if c.WeeklyHours() != 40 {
+		t.Errorf("WeeklyHours() = %v, want 40", c.WeeklyHours())
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	hours, err := ParseSchedule(map[string]float64{"mon": 8, "Tuesday": 8, "wed": 0, "thu": 8, "fri": 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := FromSchedule(hours)
+	c.Holidays = map[string]string{"2024-05-02": "Made-up Day"}
+
+	// Week of April 29: Wednesday off by schedule, Thursday a holiday
+	tests := map[string]float64{
+		"2024-04-29": 8,
+		"2024-05-01": 0,
+		"2024-05-02": 0,
+		"2024-05-03": 8,
+		"2024-05-04": 0,
+	}
+	for date, want := range tests {
+		if got := c.Expected(day(date)); got != want {
+			t.Errorf("Expected(%s) = %v, want %v", date, got, want)
+		}
+	}
+	if kind, _ := c.Day(day("2024-05-01")); kind != DayOff {
+		t.Errorf("Wednesday is %v, want DayOff", kind)
+	}
+	if got := c.ExpectedBetween(day("2024-04-29"), day("2024-05-05")); got != 24 {
+		t.Errorf("ExpectedBetween(week) = %v, want 24", got)
+	}
+	// A partial week only owes its own days
+	if got := c.ExpectedBetween(day("2024-05-01"), day("2024-05-03")); got != 8 {
+		t.Errorf("ExpectedBetween(Wed-Fri) = %v, want 8", got)
+	}
+	if c.WeeklyHours() != 32 {
+		t.Errorf("WeeklyHours() = %v, want 32", c.WeeklyHours())
+	}
+
+	if _, err := ParseSchedule(map[string]float64{"funday": 8}); err == nil {
+		t.Error("ParseSchedule accepted an unknown weekday")
+	}
+	if _, err := ParseSchedule(map[string]float64{"mon": 25}); err == nil {
+		t.Error("ParseSchedule accepted 25 hours")
+	}
+}
+
+func TestEaster(t *testing.T) {
+	for year, want := range map[int]string{
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	} {
+		if got := Easter(year).Format("2006-01-02"); got != want {
+			t.Errorf("Easter(%d) = %s, want %s", year, got, want)
+		}
+	}
+}
+
+func TestPublicHolidays(t *testing.T) {
+	tests := []struct {
+		country string
+		year    int
+		count   int
+		want    map[string]string
+	}{
+		{"DE", 2024, 9, map[string]string{
+			"2024-03-29": "Good Friday",
+			"2024-05-09": "Ascension Day",
+			"2024-05-20": "Whit Monday",
+			"2024-10-03": "German Unity Day",
+		}},
+		{"at", 2024, 13, map[string]string{"2024-05-30": "Corpus Christi"}},
+		{"GB", 2022, 8, map[string]string{
+			"2022-01-03": "New Year's Day", // Jan 1 was a Saturday
+			"2022-05-30": "Spring Bank Holiday",
+			"2022-12-26": "Boxing Day", // Christmas on a Sunday shifts Boxing Day
+			"2022-12-27": "Christmas Day",
+		}},
+		{"US", 2021, 11, map[string]string{
+			"2021-07-05": "Independence Day", // observed Monday
+			"2021-12-24": "Christmas Day",    // observed Friday
+			"2021-11-25": "Thanksgiving Day",
+			"2021-01-18": "Martin Luther King Jr. Day",
+		}},
+	}
+
+	for _, tt := range tests {
+		holidays, err := PublicHolidays(tt.country, tt.year)
+		if err != nil {
+			t.Fatalf("PublicHolidays(%s): %v", tt.country, err)
+		}
+		if len(holidays) != tt.count {
+			t.Errorf("%s %d: %d holidays, want %d: %v", tt.country, tt.year, len(holidays), tt.count, holidays)
+		}
+		for date, name := range tt.want {
+			if holidays[date] != name {
+				t.Errorf("%s %s = %q, want %q", tt.country, date, holidays[date], name)
+			}
+		}
+	}
+
+	if _, err := PublicHolidays("XX", 2024); err == nil {
+		t.Error("PublicHolidays(XX) succeeded")
+	}
+}
@@ -0,0 +1,185 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// generators compute a country's nationwide public holidays for a year.
+// Regional holidays (German states, US states, ...) are left to be added by
+// hand.
+var generators = map[string]func(year int) map[time.Time]string{
+	"AT": austria,
+	"DE": germany,
+	"GB": britain,
+	"US": unitedStates,
+}
+
+// Countries returns the country codes PublicHolidays knows, sorted
+func Countries() []string {
+	codes := make([]string, 0, len(generators))
+	for code := range generators {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// PublicHolidays returns the nationwide public holidays of a country (an
+// ISO 3166 code such as DE) in a year, as YYYY-MM-DD dates to names
+func PublicHolidays(country string, year int) (map[string]string, error) {
+	generate, ok := generators[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("no holiday calendar for %q (known: %s)", country, strings.Join(Countries(), ", "))
+	}
+
+	holidays := map[string]string{}
+	for day, name := range generate(year) {
+		holidays[day.Format("2006-01-02")] = name
+	}
+	return holidays, nil
+}
+
+// Easter returns Easter Sunday of a year in the Gregorian calendar
+// (anonymous Gregorian algorithm)
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth weekday of a month; n = -1 is the last one
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+func germany(year int) map[time.Time]string {
+	easter := Easter(year)
+	return map[time.Time]string{
+		date(year, time.January, 1):   "New Year's Day",
+		easter.AddDate(0, 0, -2):      "Good Friday",
+		easter.AddDate(0, 0, 1):       "Easter Monday",
+		date(year, time.May, 1):       "Labour Day",
+		easter.AddDate(0, 0, 39):      "Ascension Day",
+		easter.AddDate(0, 0, 50):      "Whit Monday",
+		date(year, time.October, 3):   "German Unity Day",
+		date(year, time.December, 25): "Christmas Day",
+		date(year, time.December, 26): "St. Stephen's Day",
+	}
+}
+
+func austria(year int) map[time.Time]string {
+	easter := Easter(year)
+	return map[time.Time]string{
+		date(year, time.January, 1):   "New Year's Day",
+		date(year, time.January, 6):   "Epiphany",
+		easter.AddDate(0, 0, 1):       "Easter Monday",
+		date(year, time.May, 1):       "Labour Day",
+		easter.AddDate(0, 0, 39):      "Ascension Day",
+		easter.AddDate(0, 0, 50):      "Whit Monday",
+		easter.AddDate(0, 0, 60):      "Corpus Christi",
+		date(year, time.August, 15):   "Assumption Day",
+		date(year, time.October, 26):  "National Day",
+		date(year, time.November, 1):  "All Saints' Day",
+		date(year, time.December, 8):  "Immaculate Conception",
+		date(year, time.December, 25): "Christmas Day",
+		date(year, time.December, 26): "St. Stephen's Day",
+	}
+}
+
+// britain returns the bank holidays of England and Wales. Holidays falling
+// on a weekend are moved to the next weekday, as the substitute day off.
+func britain(year int) map[time.Time]string {
+	easter := Easter(year)
+	holidays := map[time.Time]string{
+		easter.AddDate(0, 0, -2):                       "Good Friday",
+		easter.AddDate(0, 0, 1):                        "Easter Monday",
+		nthWeekday(year, time.May, time.Monday, 1):     "Early May Bank Holiday",
+		nthWeekday(year, time.May, time.Monday, -1):    "Spring Bank Holiday",
+		nthWeekday(year, time.August, time.Monday, -1): "Summer Bank Holiday",
+	}
+	fixed := []struct {
+		day  time.Time
+		name string
+	}{
+		{date(year, time.January, 1), "New Year's Day"},
+		{date(year, time.December, 25), "Christmas Day"},
+		{date(year, time.December, 26), "Boxing Day"},
+	}
+	// Substitute days go after the holidays that stay on their date, so
+	// Christmas on a Sunday moves past a Monday Boxing Day
+	for _, holiday := range fixed {
+		if !weekend(holiday.day) {
+			holidays[holiday.day] = holiday.name
+		}
+	}
+	for _, holiday := range fixed {
+		day := holiday.day
+		if !weekend(day) {
+			continue
+		}
+		for weekend(day) || holidays[day] != "" {
+			day = day.AddDate(0, 0, 1)
+		}
+		holidays[day] = holiday.name
+	}
+	return holidays
+}
+
+// unitedStates returns the federal holidays, with those falling on a
+// weekend observed on the nearest weekday
+func unitedStates(year int) map[time.Time]string {
+	holidays := map[time.Time]string{
+		nthWeekday(year, time.January, time.Monday, 3):    "Martin Luther King Jr. Day",
+		nthWeekday(year, time.February, time.Monday, 3):   "Presidents' Day",
+		nthWeekday(year, time.May, time.Monday, -1):       "Memorial Day",
+		nthWeekday(year, time.September, time.Monday, 1):  "Labor Day",
+		nthWeekday(year, time.October, time.Monday, 2):    "Columbus Day",
+		nthWeekday(year, time.November, time.Thursday, 4): "Thanksgiving Day",
+	}
+	for _, fixed := range []struct {
+		day  time.Time
+		name string
+	}{
+		{date(year, time.January, 1), "New Year's Day"},
+		{date(year, time.June, 19), "Juneteenth"},
+		{date(year, time.July, 4), "Independence Day"},
+		{date(year, time.November, 11), "Veterans Day"},
+		{date(year, time.December, 25), "Christmas Day"},
+	} {
+		day := fixed.day
+		switch day.Weekday() {
+		case time.Saturday:
+			day = day.AddDate(0, 0, -1)
+		case time.Sunday:
+			day = day.AddDate(0, 0, 1)
+		}
+		holidays[day] = fixed.name
+	}
+	return holidays
+}
+
+func weekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
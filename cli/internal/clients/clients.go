@@ -0,0 +1,52 @@
+// Package clients groups projects under clients. Entries carry their client
+// when the server knows it (e.g. from Toggl); otherwise a local mapping of
+// client names to project name patterns is used.
+package clients
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// None is the client name for entries no client claims
+const None = "(no client)"
+
+// Mapping assigns projects to clients by glob patterns such as "ACME-*".
+// Patterns are matched case-insensitively against the whole project name.
+type Mapping map[string][]string
+
+// Resolve returns the client of an entry: the server's client if given,
+// otherwise the first client (in name order) with a pattern matching the
+// project, otherwise None.
+func (m Mapping) Resolve(serverClient, project string) string {
+	if serverClient != "" {
+		return serverClient
+	}
+	if project == "" {
+		return None
+	}
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	project = strings.ToLower(project)
+	for _, name := range names {
+		for _, pattern := range m[name] {
+			if ok, err := path.Match(strings.ToLower(pattern), project); err == nil && ok {
+				return name
+			}
+		}
+	}
+
+	return None
+}
+
+// Matches reports whether client is the one asked for by a --client filter,
+// ignoring case
+func Matches(client, filter string) bool {
+	return strings.EqualFold(client, strings.TrimSpace(filter))
+}
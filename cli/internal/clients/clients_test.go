@@ -0,0 +1,28 @@
+package clients
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	mapping := Mapping{
+		"Acme":   {"ACME-*", "INTPROJ-Acme-*"},
+		"Globex": {"globex"},
+	}
+
+	tests := []struct {
+		server, project, want string
+	}{
+		{"Initech", "ACME-1", "Initech"}, // the server's client wins
+		{"", "ACME-1", "Acme"},
+		{"", "acme-42", "Acme"},
+		{"", "INTPROJ-Acme-Retainer-2024", "Acme"},
+		{"", "Globex", "Globex"},
+		{"", "GlobexPlus", None},
+		{"", "", None},
+	}
+
+	for _, tt := range tests {
+		if got := mapping.Resolve(tt.server, tt.project); got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, want %q", tt.server, tt.project, got, tt.want)
+		}
+	}
+}
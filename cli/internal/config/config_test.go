@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestUseEnvCredentials(t *testing.T) {
+	cfg := &Config{AccessToken: "file-access", RefreshToken: "file-refresh", AuthMode: AuthModePassword}
+	cfg.UseEnvCredentials()
+	if cfg.Session || cfg.AccessToken != "file-access" || cfg.CredentialSource() != "config file" {
+		t.Fatalf("without env: %+v from %s", cfg, cfg.CredentialSource())
+	}
+
+	t.Setenv(AccessTokenEnv, "env-access")
+	t.Setenv(AuthModeEnv, AuthModeToken)
+	cfg.UseEnvCredentials()
+	if !cfg.Session || cfg.AccessToken != "env-access" || cfg.RefreshToken != "" || cfg.AuthMode != AuthModeToken {
+		t.Errorf("with env: %+v", cfg)
+	}
+	if got, want := cfg.CredentialSource(), "environment ("+AccessTokenEnv+")"; got != want {
+		t.Errorf("CredentialSource() = %q, want %q", got, want)
+	}
+}
+
+func TestSaveKeepsAPIURLFromFlags(t *testing.T) {
+	path := useTempConfig(t, "api_url: https://home.example\naccess_token: old-token\nweek_start: sunday\n")
+
+	// A one-off --api-url, bound like the root command's flag
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("api-url", "http://localhost:3000", "")
+	flags.Int("chunk-days", 90, "")
+	viper.BindPFlag("api_url", flags.Lookup("api-url"))
+	viper.BindPFlag("chunk_days", flags.Lookup("chunk-days"))
+	if err := flags.Parse([]string{"--api-url", "https://staging.example", "--chunk-days", "7"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIURL != "https://staging.example" {
+		t.Fatalf("APIURL = %q, want the flag's", cfg.APIURL)
+	}
+
+	// A token refresh saves the tokens only
+	cfg.AccessToken = "new-token"
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	values, _ := ProfileValues(DefaultProfile)
+	if values["api_url"] != "https://home.example" || values["access_token"] != "new-token" || values["week_start"] != "sunday" {
+		t.Errorf("after Save: %v", values)
+	}
+	if _, ok := values["chunk_days"]; ok {
+		t.Errorf("Save wrote chunk_days from its flag: %v", values)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("config file mode = %v (%v), want 0600", info.Mode().Perm(), err)
+	}
+
+	// Logging in saves the server deliberately
+	cfg.SaveAPIURL = true
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	values, _ = ProfileValues(DefaultProfile)
+	if values["api_url"] != "https://staging.example" {
+		t.Errorf("after login Save: api_url = %v", values["api_url"])
+	}
+}
+
+func TestTokenExpiryIsSavedWithTheTokens(t *testing.T) {
+	path := useTempConfig(t, "access_token: old-token\n")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.TokenExpiry.IsZero() {
+		t.Fatalf("TokenExpiry = %s without token_expiry", cfg.TokenExpiry)
+	}
+
+	expiry := time.Date(2024, 3, 1, 12, 15, 0, 0, time.UTC)
+	cfg.AccessToken, cfg.TokenExpiry = "new-token", expiry
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = Load(); err != nil || !cfg.TokenExpiry.Equal(expiry) {
+		t.Errorf("after Save, TokenExpiry = %s (%v), want %s", cfg.TokenExpiry, err, expiry)
+	}
+
+	// Tokens from the environment have no known expiry
+	t.Setenv(AccessTokenEnv, "env-access")
+	cfg.UseEnvCredentials()
+	if !cfg.TokenExpiry.IsZero() {
+		t.Errorf("TokenExpiry = %s with tokens from the environment", cfg.TokenExpiry)
+	}
+}
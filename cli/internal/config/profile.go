@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile names the settings at the top level of the config file.
+// Other profiles live under "profiles" and are selected with "profile".
+const DefaultProfile = "default"
+
+// profileKeys are kept per profile. A profile never inherits them from the
+// default profile, so one server's tokens can't be sent to another.
+var profileKeys = []string{"api_url", "access_token", "refresh_token", "token_expiry", "auth_mode", "dashboard_url"}
+
+var profileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// active is the profile ApplyProfile selected
+var active = DefaultProfile
+
+// IsProfileKey reports whether a key is kept per profile rather than
+// inherited from the default profile
+func IsProfileKey(key string) bool {
+	for _, k := range profileKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveProfile returns the name of the profile in use
+func ActiveProfile() string {
+	return active
+}
+
+// ApplyProfile selects a profile and overlays its section on the config
+// file values, so flags and the environment still win over it. override
+// names the profile for this run only (--profile); if empty, the one named
+// by "profile" in the config file is used. A missing profile falls back to
+// the default profile with an error, which lists the profiles when the
+// override named it.
+func ApplyProfile(override string) error {
+	active = DefaultProfile
+
+	name := override
+	if name == "" {
+		if err := FileValue("profile", &name); err != nil {
+			return err
+		}
+	}
+	if name == "" || name == DefaultProfile {
+		return nil
+	}
+
+	section, err := ProfileValues(name)
+	if err != nil {
+		return err
+	}
+	if section == nil {
+		if override != "" {
+			return unknownProfile(name)
+		}
+		return fmt.Errorf("profile %q doesn't exist; using the default profile", name)
+	}
+
+	overlay := map[string]interface{}{}
+	for _, key := range profileKeys {
+		overlay[key] = ""
+	}
+	for key, value := range section {
+		overlay[key] = value
+	}
+	if err := viper.MergeConfigMap(overlay); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	active = name
+	return nil
+}
+
+// Profiles returns the names of all profiles, the default profile first
+func Profiles() ([]string, error) {
+	values, err := readFile()
+	if err != nil {
+		return nil, err
+	}
+	var profiles map[string]yaml.Node
+	if node, ok := values["profiles"]; ok {
+		if err := node.Decode(&profiles); err != nil {
+			return nil, fmt.Errorf("invalid \"profiles\" in config file: %w", err)
+		}
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// ProfileValues returns the section of a profile, or nil if it doesn't
+// exist. The default profile's values are the top level of the file.
+func ProfileValues(name string) (map[string]interface{}, error) {
+	values, err := readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	section := values
+	if name != DefaultProfile {
+		section, err = profileSection(values, name)
+		if err != nil {
+			return nil, err
+		}
+		if section == nil {
+			return nil, nil
+		}
+	}
+
+	decoded := map[string]interface{}{}
+	for key, node := range section {
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid %q in config file: %w", key, err)
+		}
+		decoded[key] = value
+	}
+	return decoded, nil
+}
+
+// CreateProfile adds a profile using the given API URL
+func CreateProfile(name, apiURL string) error {
+	if name == DefaultProfile || !profileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, - and _)", name)
+	}
+	existing, err := ProfileValues(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	return setProfileValues(name, map[string]interface{}{"api_url": apiURL})
+}
+
+// UseProfile makes a profile the active one for future commands
+func UseProfile(name string) error {
+	if name != DefaultProfile {
+		existing, err := ProfileValues(name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return unknownProfile(name)
+		}
+		return Set("profile", name)
+	}
+	return Set("profile", nil)
+}
+
+// DeleteProfile removes a profile and its tokens. Deleting the active
+// profile makes the default profile active.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile can't be deleted")
+	}
+	return update(func(values map[string]interface{}) error {
+		profiles, _ := values["profiles"].(map[string]interface{})
+		if _, ok := profiles[name]; !ok {
+			return unknownProfile(name)
+		}
+		delete(profiles, name)
+		if len(profiles) == 0 {
+			delete(values, "profiles")
+		}
+		if values["profile"] == name {
+			delete(values, "profile")
+		}
+		return nil
+	})
+}
+
+// setProfileValues writes keys into a profile's section, creating it if
+// needed
+func setProfileValues(name string, keys map[string]interface{}) error {
+	return update(func(values map[string]interface{}) error {
+		profiles, _ := values["profiles"].(map[string]interface{})
+		if profiles == nil {
+			profiles = map[string]interface{}{}
+			values["profiles"] = profiles
+		}
+		section, _ := profiles[name].(map[string]interface{})
+		if section == nil {
+			section = map[string]interface{}{}
+			profiles[name] = section
+		}
+		for key, value := range keys {
+			section[key] = value
+		}
+		return nil
+	})
+}
+
+// readFile returns the top-level nodes of the config file, or none if it
+// doesn't exist
+func readFile() (map[string]yaml.Node, error) {
+	configFile, err := File()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var values map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return values, nil
+}
+
+// profileSection returns the nodes of a profile's section, or nil if it
+// doesn't exist
+func profileSection(values map[string]yaml.Node, name string) (map[string]yaml.Node, error) {
+	if name == DefaultProfile {
+		return nil, nil
+	}
+	node, ok := values["profiles"]
+	if !ok {
+		return nil, nil
+	}
+	var profiles map[string]map[string]yaml.Node
+	if err := node.Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("invalid \"profiles\" in config file: %w", err)
+	}
+	section, ok := profiles[name]
+	if ok && section == nil {
+		section = map[string]yaml.Node{} // an empty profile still exists
+	}
+	return section, nil
+}
+
+func unknownProfile(name string) error {
+	names, _ := Profiles()
+	return fmt.Errorf("no profile %q (profiles: %s)", name, strings.Join(names, ", "))
+}
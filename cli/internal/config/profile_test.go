@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// useTempConfig points the config file at a temporary home directory
+func useTempConfig(t *testing.T, content string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	active = DefaultProfile
+
+	path := filepath.Join(home, ".timetracker", "config.yaml")
+	os.MkdirAll(filepath.Dir(path), 0700)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProfilesKeepTheirOwnTokens(t *testing.T) {
+	path := useTempConfig(t, "api_url: https://home.example\naccess_token: home-token\nrefresh_token: home-refresh\ncontract_hours: 40\n")
+
+	if err := CreateProfile("work", "https://tt.company.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateProfile("work", "https://other.example"); err == nil {
+		t.Error("created a profile twice")
+	}
+	if err := UseProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyProfile(""); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveProfile() != "work" {
+		t.Fatalf("ActiveProfile() = %q", ActiveProfile())
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIURL != "https://tt.company.com" || cfg.AccessToken != "" {
+		t.Errorf("work profile loaded %+v, want its own URL and no token", cfg)
+	}
+	// Defaults that aren't per profile are inherited
+	if viper.GetInt("contract_hours") != 40 {
+		t.Errorf("contract_hours = %d, want 40", viper.GetInt("contract_hours"))
+	}
+
+	cfg.AccessToken, cfg.RefreshToken = "work-token", "work-refresh"
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	work, _ := ProfileValues("work")
+	home, _ := ProfileValues(DefaultProfile)
+	if work["access_token"] != "work-token" || home["access_token"] != "home-token" {
+		t.Errorf("after Save: work %v, default %v", work, home)
+	}
+
+	if err := DeleteProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "work") {
+		t.Errorf("config still mentions the deleted profile:\n%s", data)
+	}
+	if names, _ := Profiles(); len(names) != 1 || names[0] != DefaultProfile {
+		t.Errorf("Profiles() = %v", names)
+	}
+}
+
+func TestFileValuePrefersProfile(t *testing.T) {
+	useTempConfig(t, "profile: client\nweek_start: monday\nprofiles:\n  client:\n    api_url: https://client.example\n    week_start: sunday\n")
+	if err := ApplyProfile(""); err != nil {
+		t.Fatal(err)
+	}
+
+	var start string
+	if err := FileValue("week_start", &start); err != nil {
+		t.Fatal(err)
+	}
+	if start != "sunday" {
+		t.Errorf("week_start = %q, want the profile's sunday", start)
+	}
+}
+
+func TestMissingProfileFallsBackToDefault(t *testing.T) {
+	useTempConfig(t, "profile: gone\napi_url: https://home.example\n")
+	if err := ApplyProfile(""); err == nil {
+		t.Error("ApplyProfile accepted a missing profile")
+	}
+	if ActiveProfile() != DefaultProfile {
+		t.Errorf("ActiveProfile() = %q, want default", ActiveProfile())
+	}
+	if err := UseProfile("nope"); err == nil || !strings.Contains(err.Error(), "default") {
+		t.Errorf("UseProfile(nope) = %v, want an error listing profiles", err)
+	}
+}
+
+func TestProfileOverride(t *testing.T) {
+	useTempConfig(t, "profile: client\napi_url: https://home.example\naccess_token: home-token\nprofiles:\n  client:\n    api_url: https://client.example\n  other:\n    api_url: https://other.example\n    access_token: other-token\n")
+
+	if err := ApplyProfile("other"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ := Load()
+	if ActiveProfile() != "other" || cfg.APIURL != "https://other.example" || cfg.AccessToken != "other-token" {
+		t.Errorf("override loaded %s: %+v", ActiveProfile(), cfg)
+	}
+
+	// Refreshed tokens go back to the overriding profile's section
+	cfg.AccessToken = "refreshed"
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := ProfileValues("other")
+	client, _ := ProfileValues("client")
+	if other["access_token"] != "refreshed" || client["access_token"] != nil {
+		t.Errorf("after Save: other %v, client %v", other, client)
+	}
+
+	err := ApplyProfile("typo")
+	if err == nil || !strings.Contains(err.Error(), "default, client, other") {
+		t.Errorf("ApplyProfile(typo) = %v, want an error listing profiles", err)
+	}
+}
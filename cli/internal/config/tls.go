@@ -0,0 +1,29 @@
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// CertPool returns the system's certificate authorities plus the ones in
+// CACert, or nil for the system's alone
+func (c *Config) CertPool() (*x509.CertPool, error) {
+	if c.CACert == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(c.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca_cert: %w", err)
+	}
+
+	// Not every platform exposes its trust store; the bundle alone works then
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("ca_cert %s contains no PEM certificates", c.CACert)
+	}
+	return pool, nil
+}
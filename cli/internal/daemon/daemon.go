@@ -0,0 +1,38 @@
+// Package daemon restarts the running program in the background, detached
+// from the terminal.
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Start runs the current executable again with args, detached from the
+// terminal, with its output appended to logPath. It returns the new
+// process's ID.
+func Start(args []string, logPath string) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the executable: %w", err)
+	}
+
+	log, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer log.Close()
+
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	cmd.SysProcAttr = detached()
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start background process: %w", err)
+	}
+
+	pid := cmd.Process.Pid
+	// The child outlives us; don't wait for it
+	cmd.Process.Release()
+	return pid, nil
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package daemon
+
+import "syscall"
+
+// detached starts the child in its own process group so Ctrl+C in the
+// console doesn't reach it
+func detached() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
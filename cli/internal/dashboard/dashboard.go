@@ -0,0 +1,121 @@
+// Package dashboard serves a read-only, single-page view of the current
+// totals. Summaries are fetched on an interval and cached, so page loads
+// never wait for the API.
+package dashboard
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//go:embed index.html
+var page []byte
+
+// Day is one bar of the chart
+type Day struct {
+	Date  string  `json:"date"`
+	Hours float64 `json:"hours"`
+}
+
+// Snapshot is what the page shows
+type Snapshot struct {
+	Today     float64            `json:"today"`
+	Week      float64            `json:"week"`
+	Month     float64            `json:"month"`
+	MonthName string             `json:"monthName"`
+	Days      []Day              `json:"days"`
+	BySource  map[string]float64 `json:"bySource"`
+	Updated   time.Time          `json:"updated"`
+}
+
+// Fetcher loads a fresh snapshot
+type Fetcher func(ctx context.Context) (*Snapshot, error)
+
+// Server caches the latest snapshot and serves it with the page
+type Server struct {
+	fetch    Fetcher
+	interval time.Duration
+
+	mu       sync.RWMutex
+	snapshot *Snapshot
+	err      error
+}
+
+// New returns a server refreshing its snapshot with fetch every interval
+func New(fetch Fetcher, interval time.Duration) *Server {
+	return &Server{fetch: fetch, interval: interval}
+}
+
+// Refresh fetches a snapshot now. A failed fetch keeps the previous
+// snapshot, which the page shows as stale along with the error.
+func (s *Server) Refresh(ctx context.Context) error {
+	snapshot, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	if err == nil {
+		s.snapshot = snapshot
+	}
+	return err
+}
+
+// Run refreshes the snapshot every interval until ctx is done. onError,
+// if set, is called with failed refreshes.
+func (s *Server) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Handler serves the page at / and the cached snapshot at /snapshot.json
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+	mux.HandleFunc("/snapshot.json", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		body := struct {
+			*Snapshot
+			Error           string `json:"error,omitempty"`
+			IntervalSeconds int    `json:"intervalSeconds"`
+		}{Snapshot: s.snapshot, IntervalSeconds: int(s.interval.Seconds())}
+		if s.err != nil {
+			body.Error = s.err.Error()
+		}
+		s.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(body)
+	})
+
+	// Read-only: anything but GET and HEAD is refused
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,74 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestServesCachedSnapshot(t *testing.T) {
+	calls := 0
+	fail := false
+	s := New(func(ctx context.Context) (*Snapshot, error) {
+		calls++
+		if fail {
+			return nil, errors.New("server down")
+		}
+		return &Snapshot{Today: 2.5, Week: 20, Days: []Day{{Date: "2024-04-08", Hours: 8}}, Updated: time.Now()}, nil
+	}, time.Minute)
+	handler := s.Handler()
+
+	// Nothing fetched yet
+	var body map[string]interface{}
+	json.Unmarshal(get(t, handler, "GET", "/snapshot.json").Body.Bytes(), &body)
+	if _, ok := body["today"]; ok {
+		t.Errorf("snapshot before the first refresh: %v", body)
+	}
+
+	if err := s.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		get(t, handler, "GET", "/snapshot.json")
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1 (requests are served from the cache)", calls)
+	}
+
+	// A failed refresh keeps the last snapshot and reports the error
+	fail = true
+	if err := s.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() succeeded")
+	}
+	body = nil
+	json.Unmarshal(get(t, handler, "GET", "/snapshot.json").Body.Bytes(), &body)
+	if body["today"] != 2.5 || body["error"] != "server down" || body["intervalSeconds"] != 60.0 {
+		t.Errorf("snapshot after failed refresh = %v", body)
+	}
+}
+
+func TestHandlerIsReadOnly(t *testing.T) {
+	handler := New(nil, time.Minute).Handler()
+
+	if rec := get(t, handler, "GET", "/"); rec.Code != 200 || !strings.Contains(rec.Body.String(), "<html") {
+		t.Errorf("GET / = %d", rec.Code)
+	}
+	if rec := get(t, handler, "POST", "/snapshot.json"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", rec.Code)
+	}
+	if rec := get(t, handler, "GET", "/other"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /other = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,247 @@
+package dates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the date format used by the API and accepted on the command line
+const Layout = "2006-01-02"
+
+// Parse parses a date argument relative to now.
+// Accepts "today", "yesterday" and YYYY-MM-DD.
+func Parse(value string, now time.Time) (time.Time, error) {
+	today := StartOfDay(now)
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	t, err := time.ParseInLocation(Layout, strings.TrimSpace(value), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, today or yesterday)", value)
+	}
+
+	return t, nil
+}
+
+// Format formats a time as YYYY-MM-DD
+func Format(t time.Time) string {
+	return t.Format(Layout)
+}
+
+// ParseHours parses a duration like 5h, 90m, 1h30m or decimal hours such
+// as 2.5 into hours
+func ParseHours(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if hours, err := strconv.ParseFloat(value, 64); err == nil {
+		return hours, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (expected e.g. 5h, 90m, 1h30m or 2.5)", value)
+	}
+	return d.Hours(), nil
+}
+
+// ParseDuration parses a span of time like time.ParseDuration, but also
+// accepts leading days or weeks, as in 3d, 2w or 1d12h
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	invalid := fmt.Errorf("invalid duration %q (expected e.g. 72h, 3d, 2w or 1d12h)", value)
+
+	var d time.Duration
+	rest := strings.TrimLeft(value, "0123456789")
+	if rest != value && (strings.HasPrefix(rest, "d") || strings.HasPrefix(rest, "w")) {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, rest))
+		if err != nil {
+			return 0, invalid
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		if rest[0] == 'w' {
+			d *= 7
+		}
+		if value = rest[1:]; value == "" {
+			return d, nil
+		}
+	}
+
+	clock, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, invalid
+	}
+	return d + clock, nil
+}
+
+// StartOfDay returns midnight of the day containing t
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight of the first day of the week containing t,
+// for weeks beginning on the given weekday
+func StartOfWeek(t time.Time, start time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(start) + 7) % 7
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+// ParseWeekday parses a weekday name such as "monday" or "Sun"
+func ParseWeekday(value string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %q", value)
+}
+
+// ParseMonth parses a month name ("april", "Apr") or number ("4")
+func ParseMonth(value string) (time.Month, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 1 || n > 12 {
+			return 0, fmt.Errorf("invalid month %q", value)
+		}
+		return time.Month(n), nil
+	}
+	for month := time.January; month <= time.December; month++ {
+		full := strings.ToLower(month.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return month, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid month %q", value)
+}
+
+// Quarter is a three-month period of a fiscal year. Fiscal years are named
+// after the calendar year they start in.
+type Quarter struct {
+	Year        int // fiscal year
+	Number      int // 1-4
+	FiscalStart time.Month
+}
+
+// QuarterOf returns the quarter containing t
+func QuarterOf(t time.Time, fiscalStart time.Month) Quarter {
+	months := (int(t.Month()) - int(fiscalStart) + 12) % 12
+	year := t.Year()
+	if t.Month() < fiscalStart {
+		year--
+	}
+	return Quarter{Year: year, Number: months/3 + 1, FiscalStart: fiscalStart}
+}
+
+// Start returns the first day of the quarter
+func (q Quarter) Start(loc *time.Location) time.Time {
+	return time.Date(q.Year, q.FiscalStart+time.Month(3*(q.Number-1)), 1, 0, 0, 0, 0, loc)
+}
+
+// End returns the last day of the quarter
+func (q Quarter) End(loc *time.Location) time.Time {
+	return q.Start(loc).AddDate(0, 3, -1)
+}
+
+// Previous returns the quarter before q
+func (q Quarter) Previous() Quarter {
+	if q.Number == 1 {
+		return Quarter{Year: q.Year - 1, Number: 4, FiscalStart: q.FiscalStart}
+	}
+	return Quarter{Year: q.Year, Number: q.Number - 1, FiscalStart: q.FiscalStart}
+}
+
+// String formats the quarter as "Q2 2024", or "Q2 FY2024" for fiscal years
+// not starting in January
+func (q Quarter) String() string {
+	if q.FiscalStart == time.January {
+		return fmt.Sprintf("Q%d %d", q.Number, q.Year)
+	}
+	return fmt.Sprintf("Q%d FY%d", q.Number, q.Year)
+}
+
+// ISOWeek formats the ISO 8601 week containing t, e.g. "2024-W32"
+func ISOWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// WeekName names the week of the seven days from start after the ISO week
+// most of them fall in, so weeks beginning on any day line up with ISO
+// weeks. For weeks beginning on Monday it is the ISO week itself.
+func WeekName(start time.Time) string {
+	return ISOWeek(start.AddDate(0, 0, 3))
+}
+
+// ParseWeek parses an ISO week such as "2024-W32" and returns the first day
+// of the week beginning on start that WeekName gives that name
+func ParseWeek(value string, start time.Weekday, loc *time.Location) (time.Time, error) {
+	monday, err := ParseISOWeek(value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return StartOfWeek(monday.AddDate(0, 0, 3), start), nil
+}
+
+// ParseISOWeek parses an ISO 8601 week such as "2024-W32" and returns the
+// Monday it starts on
+func ParseISOWeek(value string, loc *time.Location) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(strings.ToUpper(strings.TrimSpace(value)), "%d-W%d", &year, &week); err != nil || week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q (expected e.g. 2024-W32)", value)
+	}
+
+	// January 4th is always in week 1
+	monday := StartOfWeek(time.Date(year, time.January, 4, 0, 0, 0, 0, loc), time.Monday).AddDate(0, 0, 7*(week-1))
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, week)
+	}
+	return monday, nil
+}
+
+// UseZone makes name, an IANA timezone such as Europe/Berlin, the process's
+// local timezone, so "today", week boundaries and other local dates follow
+// it. An empty name keeps the system's zone.
+func UseZone(name string) error {
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("unknown timezone %q (expected an IANA name such as Europe/Berlin)", name)
+	}
+	time.Local = loc
+	return nil
+}
+
+// LocalZone returns the IANA name of the local timezone, or "" if unknown
+func LocalZone() string {
+	// Zones chosen with UseZone carry their name
+	if name := time.Local.String(); name != "Local" {
+		return name
+	}
+	if tz := os.Getenv("TZ"); tz != "" {
+		return tz
+	}
+
+	// /etc/localtime is usually a symlink into the zoneinfo database
+	target, err := filepath.EvalSymlinks("/etc/localtime")
+	if err != nil {
+		return ""
+	}
+
+	if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+		return target[i+len("zoneinfo/"):]
+	}
+
+	return ""
+}
@@ -0,0 +1,159 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuarterOf(t *testing.T) {
+	tests := []struct {
+		date        string
+		fiscalStart time.Month
+		want        string
+		start, end  string
+	}{
+		{"2024-01-01", time.January, "Q1 2024", "2024-01-01", "2024-03-31"},
+		{"2024-06-30", time.January, "Q2 2024", "2024-04-01", "2024-06-30"},
+		{"2024-12-31", time.January, "Q4 2024", "2024-10-01", "2024-12-31"},
+		{"2024-04-01", time.April, "Q1 FY2024", "2024-04-01", "2024-06-30"},
+		{"2024-03-31", time.April, "Q4 FY2023", "2024-01-01", "2024-03-31"},
+		{"2024-12-15", time.April, "Q3 FY2024", "2024-10-01", "2024-12-31"},
+		{"2025-02-01", time.October, "Q2 FY2024", "2025-01-01", "2025-03-31"},
+	}
+
+	for _, tt := range tests {
+		date, _ := time.Parse(Layout, tt.date)
+		q := QuarterOf(date, tt.fiscalStart)
+		if q.String() != tt.want {
+			t.Errorf("QuarterOf(%s, %s) = %s, want %s", tt.date, tt.fiscalStart, q, tt.want)
+		}
+		if start := Format(q.Start(time.UTC)); start != tt.start {
+			t.Errorf("%s start = %s, want %s", q, start, tt.start)
+		}
+		if end := Format(q.End(time.UTC)); end != tt.end {
+			t.Errorf("%s end = %s, want %s", q, end, tt.end)
+		}
+	}
+}
+
+func TestQuarterPrevious(t *testing.T) {
+	q := Quarter{Year: 2024, Number: 1, FiscalStart: time.April}
+	prev := q.Previous()
+	if prev.String() != "Q4 FY2023" || Format(prev.End(time.UTC)) != "2024-03-31" {
+		t.Errorf("previous of %s = %s ending %s", q, prev, Format(prev.End(time.UTC)))
+	}
+	if got := (Quarter{Year: 2024, Number: 3, FiscalStart: time.January}).Previous().String(); got != "Q2 2024" {
+		t.Errorf("previous of Q3 2024 = %s", got)
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	for input, want := range map[string]time.Month{"april": time.April, "Apr": time.April, "4": time.April, "12": time.December} {
+		if got, err := ParseMonth(input); err != nil || got != want {
+			t.Errorf("ParseMonth(%q) = %v, %v", input, got, err)
+		}
+	}
+	for _, input := range []string{"13", "0", "ap", "smarch"} {
+		if _, err := ParseMonth(input); err == nil {
+			t.Errorf("ParseMonth(%q) should fail", input)
+		}
+	}
+}
+
+func TestParseISOWeek(t *testing.T) {
+	tests := map[string]string{
+		"2024-W01": "2024-01-01",
+		"2024-W32": "2024-08-05",
+		"2025-W01": "2024-12-30", // starts in the previous calendar year
+		"2020-W53": "2020-12-28",
+		"2021-W01": "2021-01-04",
+	}
+	for input, want := range tests {
+		monday, err := ParseISOWeek(input, time.UTC)
+		if err != nil || Format(monday) != want {
+			t.Errorf("ParseISOWeek(%q) = %s, %v; want %s", input, Format(monday), err, want)
+		}
+		if ISOWeek(monday) != input {
+			t.Errorf("ISOWeek(%s) = %s, want %s", want, ISOWeek(monday), input)
+		}
+	}
+
+	for _, input := range []string{"2024-32", "2021-W53", "2024-W00", "W32"} {
+		if _, err := ParseISOWeek(input, time.UTC); err == nil {
+			t.Errorf("ParseISOWeek(%q) should fail", input)
+		}
+	}
+}
+
+func TestWeekAcrossYearBoundaries(t *testing.T) {
+	tests := []struct {
+		week  string
+		start time.Weekday
+		want  string
+	}{
+		{"2025-W01", time.Monday, "2024-12-30"},
+		{"2025-W01", time.Sunday, "2024-12-29"},
+		{"2025-W01", time.Saturday, "2024-12-28"},
+		{"2024-W01", time.Sunday, "2023-12-31"},
+		{"2020-W53", time.Sunday, "2020-12-27"},
+		{"2021-W01", time.Sunday, "2021-01-03"},
+		{"2026-W01", time.Wednesday, "2025-12-31"},
+		{"2026-W01", time.Thursday, "2026-01-01"},
+	}
+	for _, tt := range tests {
+		start, err := ParseWeek(tt.week, tt.start, time.UTC)
+		if err != nil || Format(start) != tt.want {
+			t.Errorf("ParseWeek(%q, %s) = %s, %v; want %s", tt.week, tt.start, Format(start), err, tt.want)
+		}
+		if start.Weekday() != tt.start {
+			t.Errorf("ParseWeek(%q, %s) starts on a %s", tt.week, tt.start, start.Weekday())
+		}
+		if got := WeekName(start); got != tt.week {
+			t.Errorf("WeekName(%s) = %s, want %s", tt.want, got, tt.week)
+		}
+	}
+}
+
+func TestUseZone(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+
+	if err := UseZone("Mars/Olympus"); err == nil {
+		t.Error("UseZone accepted an unknown zone")
+	}
+	if err := UseZone("Pacific/Kiritimati"); err != nil {
+		t.Fatal(err)
+	}
+	if got := LocalZone(); got != "Pacific/Kiritimati" {
+		t.Errorf("LocalZone() = %q", got)
+	}
+	// 18:00 UTC is already the next morning at UTC+14
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC).In(time.Local)
+	if got := Format(StartOfDay(now)); got != "2026-10-17" {
+		t.Errorf("today = %s, want 2026-10-17", got)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"72h", 72 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{" 90m ", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "3", "d", "3days", "1d2x"} {
+		if _, err := ParseDuration(value); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package digest renders a week's summary as an email, in plain text and
+// HTML, and sends it over SMTP.
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/dates"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// Week is the summary a digest reports on
+type Week struct {
+	Start     time.Time
+	Days      []float64 // hours per day, starting at Start
+	BySource  map[string]float64
+	ByProject map[string]float64
+	Total     float64
+}
+
+// Row is a labelled number of hours
+type Row struct {
+	Label string
+	Hours float64
+}
+
+// Title names the week, e.g. "Week 2024-W15 (Apr 8 – Apr 14)"
+func (w Week) Title() string {
+	end := w.Start.AddDate(0, 0, len(w.Days)-1)
+	return fmt.Sprintf("Week %s (%s – %s)", dates.WeekName(w.Start), w.Start.Format("Jan 2"), end.Format("Jan 2"))
+}
+
+// DayRows returns the hours of each day, labelled with its weekday and date
+func (w Week) DayRows() []Row {
+	rows := make([]Row, len(w.Days))
+	for i, hours := range w.Days {
+		rows[i] = Row{Label: w.Start.AddDate(0, 0, i).Format("Mon Jan 2"), Hours: hours}
+	}
+	return rows
+}
+
+// SourceRows returns the hours per source, largest first
+func (w Week) SourceRows() []Row { return sortedRows(w.BySource) }
+
+// ProjectRows returns the hours per project, largest first
+func (w Week) ProjectRows() []Row { return sortedRows(w.ByProject) }
+
+func sortedRows(hours map[string]float64) []Row {
+	rows := make([]Row, 0, len(hours))
+	for label, h := range hours {
+		rows = append(rows, Row{Label: label, Hours: h})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Hours != rows[j].Hours {
+			return rows[i].Hours > rows[j].Hours
+		}
+		return rows[i].Label < rows[j].Label
+	})
+	return rows
+}
+
+// Text renders the plain-text body
+func Text(w Week) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", w.Title())
+	writeRows(&b, "Daily", w.DayRows())
+	fmt.Fprintf(&b, "  %-20s %7s\n\n", "Total", display.FormatHours(w.Total))
+	writeRows(&b, "By source", w.SourceRows())
+	writeRows(&b, "By project", w.ProjectRows())
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func writeRows(b *strings.Builder, heading string, rows []Row) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", heading)
+	for _, row := range rows {
+		fmt.Fprintf(b, "  %-20s %7s\n", row.Label, display.FormatHours(row.Hours))
+	}
+	b.WriteString("\n")
+}
+
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"hours": display.FormatHours,
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+<h2>{{.Title}}</h2>
+{{define "rows"}}<table cellpadding="4" cellspacing="0" style="border-collapse: collapse; margin-bottom: 16px;">
+<tr><th align="left" style="border-bottom: 1px solid #ccc;">{{.Heading}}</th><th align="right" style="border-bottom: 1px solid #ccc;">Hours</th></tr>
+{{range .Rows}}<tr><td>{{.Label}}</td><td align="right">{{hours .Hours}}</td></tr>
+{{end}}{{if .Total}}<tr><td style="border-top: 1px solid #ccc;"><b>Total</b></td><td align="right" style="border-top: 1px solid #ccc;"><b>{{hours .Total.Hours}}</b></td></tr>
+{{end}}</table>
+{{end}}{{template "rows" .Daily}}{{if .Sources.Rows}}{{template "rows" .Sources}}{{end}}{{if .Projects.Rows}}{{template "rows" .Projects}}{{end}}</body>
+</html>
+`))
+
+type htmlTable struct {
+	Heading string
+	Rows    []Row
+	Total   *Row
+}
+
+// HTML renders the HTML body
+func HTML(w Week) (string, error) {
+	data := struct {
+		Title                    string
+		Daily, Sources, Projects htmlTable
+	}{
+		Title:    w.Title(),
+		Daily:    htmlTable{Heading: "Day", Rows: w.DayRows(), Total: &Row{Label: "Total", Hours: w.Total}},
+		Sources:  htmlTable{Heading: "Source", Rows: w.SourceRows()},
+		Projects: htmlTable{Heading: "Project", Rows: w.ProjectRows()},
+	}
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Message is an email with plain-text and HTML alternatives
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Date    time.Time
+	Text    string
+	HTML    string
+}
+
+// Bytes encodes the message as a multipart/alternative MIME message. The
+// plain-text part comes first, so clients that can render HTML prefer it.
+func (m Message) Bytes() ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		w, err := parts.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	if m.From != "" {
+		fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	}
+	if m.To != "" {
+		fmt.Fprintf(&msg, "To: %s\r\n", m.To)
+	}
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", m.Date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
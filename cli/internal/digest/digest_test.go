@@ -0,0 +1,211 @@
+package digest
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleWeek() Week {
+	return Week{
+		Start:     time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC),
+		Days:      []float64{8, 7.5, 8, 6, 4.5, 0, 0},
+		BySource:  map[string]float64{"TOGGL": 20, "MANUAL": 14},
+		ByProject: map[string]float64{"Website <redesign>": 30, "(no project)": 4},
+		Total:     34,
+	}
+}
+
+func TestText(t *testing.T) {
+	text := Text(sampleWeek())
+	for _, want := range []string{
+		"Week 2024-W15 (Apr 8 – Apr 14)",
+		"Mon Apr 8               8.00",
+		"Total                  34.00",
+		"TOGGL                  20.00",
+		"Website <redesign>     30.00",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "TOGGL") > strings.Index(text, "MANUAL") {
+		t.Error("sources should be sorted by hours")
+	}
+}
+
+func TestHTMLEscapes(t *testing.T) {
+	html, err := HTML(sampleWeek())
+	if err != nil {
+		t.Fatalf("HTML(): %v", err)
+	}
+	if !strings.Contains(html, "Website &lt;redesign&gt;") {
+		t.Errorf("project names should be escaped:\n%s", html)
+	}
+	if !strings.Contains(html, "<b>34.00</b>") {
+		t.Errorf("HTML() is missing the total:\n%s", html)
+	}
+}
+
+func TestMessageBytes(t *testing.T) {
+	msg := Message{
+		From:    "tracker@example.com",
+		To:      "me@example.com",
+		Subject: "Week 2024-W15 – 34.00h",
+		Date:    time.Date(2024, 4, 15, 8, 0, 0, 0, time.UTC),
+		Text:    "plain body\n",
+		HTML:    "<p>html body</p>\n",
+	}
+	raw, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes(): %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage(): %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q, %v; want %q", subject, err, msg.Subject)
+	}
+	if got := parsed.Header.Get("To"); got != msg.To {
+		t.Errorf("To = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", mediaType, err)
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("NextRawPart(): %v", err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		body, _ := io.ReadAll(quotedprintable.NewReader(part))
+		if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != want.body {
+			t.Errorf("part body = %q, want %q", got, want.body)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got err %v", err)
+	}
+}
+
+func TestKeyringArgs(t *testing.T) {
+	if args := keyringArgs("darwin", "me"); args[0] != "security" {
+		t.Errorf("darwin: %v", args)
+	}
+	if args := keyringArgs("linux", "me"); args[0] != "secret-tool" || args[len(args)-1] != "me" {
+		t.Errorf("linux: %v", args)
+	}
+	if args := keyringArgs("windows", "me"); args != nil {
+		t.Errorf("windows: %v", args)
+	}
+}
+
+func TestPasswordFromEnv(t *testing.T) {
+	t.Setenv(PasswordEnv, "s3cret")
+	if got, err := Password("me"); err != nil || got != "s3cret" {
+		t.Errorf("Password() = %q, %v", got, err)
+	}
+}
+
+func TestSend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 test ESMTP")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO":
+				reply("250 test")
+			case "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				received <- data.String()
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	err = Send(SMTP{Host: "127.0.0.1", Port: addr.Port}, "from@example.com", "to@example.com", []byte("Subject: hi\r\n\r\nbody\r\n"))
+	if err != nil {
+		t.Fatalf("Send(): %v", err)
+	}
+	select {
+	case data := <-received:
+		if !strings.Contains(data, "body") {
+			t.Errorf("server received %q", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server never received the message")
+	}
+}
+
+func TestSendWithoutHost(t *testing.T) {
+	if err := Send(SMTP{}, "a@example.com", "b@example.com", nil); err == nil {
+		t.Error("Send() without a host should fail")
+	}
+}
+
+func TestSender(t *testing.T) {
+	for _, tc := range []struct {
+		smtp SMTP
+		want string
+	}{
+		{SMTP{From: "x@example.com", User: "me@example.com"}, "x@example.com"},
+		{SMTP{User: "me@example.com"}, "me@example.com"},
+		{SMTP{User: "me"}, ""},
+	} {
+		if got := tc.smtp.Sender(); got != tc.want {
+			t.Errorf("%+v.Sender() = %q, want %q", tc.smtp, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package digest
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PasswordEnv is the environment variable holding the SMTP password
+const PasswordEnv = "TIMETRACKER_SMTP_PASSWORD"
+
+// KeyringService is the service name the SMTP password is stored under in
+// the system keyring
+const KeyringService = "timetracker-smtp"
+
+// DefaultPort is the SMTP submission port, used when none is configured
+const DefaultPort = 587
+
+// dialTimeout bounds connecting to the SMTP server
+const dialTimeout = 30 * time.Second
+
+// ErrNoKeyring is returned when the platform has no supported keyring tool
+var ErrNoKeyring = errors.New("no supported keyring on this system")
+
+// SMTP holds the server settings, from the "smtp" section of the config file
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	From     string `yaml:"from"`
+	Password string `yaml:"-"`
+}
+
+// Sender returns the From address: the configured one, or the user name if
+// it is an address
+func (s SMTP) Sender() string {
+	if s.From != "" {
+		return s.From
+	}
+	if strings.Contains(s.User, "@") {
+		return s.User
+	}
+	return ""
+}
+
+// Password returns the SMTP password for user from PasswordEnv or, if that
+// isn't set, from the system keyring
+func Password(user string) (string, error) {
+	if password := os.Getenv(PasswordEnv); password != "" {
+		return password, nil
+	}
+	args := keyringArgs(runtime.GOOS, user)
+	if args == nil {
+		return "", ErrNoKeyring
+	}
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("no password for %s in the keyring (%s): %w", user, args[0], err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// keyringArgs returns the command that prints the stored password
+func keyringArgs(goos, user string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"security", "find-generic-password", "-s", KeyringService, "-a", user, "-w"}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"secret-tool", "lookup", "service", KeyringService, "account", user}
+	}
+	return nil
+}
+
+// Send delivers msg to the recipient. Port 465 uses implicit TLS; other
+// ports upgrade with STARTTLS when the server offers it. Credentials are
+// only sent over TLS.
+func Send(s SMTP, from, to string, msg []byte) error {
+	if s.Host == "" {
+		return fmt.Errorf("no SMTP host configured")
+	}
+	port := s.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: s.Host}
+
+	var conn net.Conn
+	var err error
+	if port == 465 {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, dialTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to talk to %s: %w", addr, err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if s.User != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.User, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("server rejected sender %s: %w", from, err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("server rejected recipient %s: %w", to, err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("server rejected the message: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,52 @@
+package display
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// barEighths are the block characters for one to seven eighths of a cell
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// Bar renders value as a horizontal bar, where max fills width cells. Block
+// bars are drawn to an eighth of a cell; ASCII bars use "#" per whole cell.
+// Values too small to show still get the thinnest bar, and zero is drawn as
+// a dot so the row doesn't look missing.
+func Bar(value, max float64, width int, ascii bool) string {
+	if value <= 0 || max <= 0 || width < 1 {
+		if ascii {
+			return "."
+		}
+		return "·"
+	}
+	if value > max {
+		value = max
+	}
+
+	if ascii {
+		cells := int(math.Round(value / max * float64(width)))
+		if cells < 1 {
+			cells = 1
+		}
+		return strings.Repeat("#", cells)
+	}
+
+	eighths := int(math.Round(value / max * float64(width) * 8))
+	if eighths < 1 {
+		eighths = 1
+	}
+	return strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+}
+
+// BarScale explains the bars drawn by Bar, e.g. "█ = 0.50h"
+func BarScale(max float64, width int, ascii bool) string {
+	if max <= 0 || width < 1 {
+		return "no hours to chart"
+	}
+	block := "█"
+	if ascii {
+		block = "#"
+	}
+	return fmt.Sprintf("%s = %s", block, FormatDuration(max/float64(width)))
+}
@@ -0,0 +1,32 @@
+package display
+
+import "testing"
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		value, max float64
+		width      int
+		ascii      bool
+		want       string
+	}{
+		{8, 8, 4, false, "████"},
+		{3, 8, 4, false, "█▌"},
+		{0.01, 8, 4, false, "▏"},
+		{0, 8, 4, false, "·"},
+		{3, 8, 4, true, "##"},
+		{0.01, 8, 4, true, "#"},
+		{0, 0, 4, true, "."},
+		{10, 8, 4, true, "####"},
+	}
+	for _, tt := range tests {
+		if got := Bar(tt.value, tt.max, tt.width, tt.ascii); got != tt.want {
+			t.Errorf("Bar(%v, %v, %d, %v) = %q, want %q", tt.value, tt.max, tt.width, tt.ascii, got, tt.want)
+		}
+	}
+}
+
+func TestBarScale(t *testing.T) {
+	if got, want := BarScale(8, 16, true), "# = 0.50h"; got != want {
+		t.Errorf("BarScale = %q, want %q", got, want)
+	}
+}
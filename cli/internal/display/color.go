@@ -0,0 +1,54 @@
+package display
+
+import "os"
+
+const (
+	ansiBold   = "\033[1m"
+	ansiGreen  = "\033[32m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+)
+
+// DisableColor turns off styled output for the rest of the run, for
+// --no-color
+func DisableColor() {
+	style.NoColor = true
+}
+
+// ColorEnabled reports whether styled output should be written to stdout;
+// see Style
+func ColorEnabled() bool {
+	return style.Color(os.Stdout)
+}
+
+// Bold renders text in bold when color is enabled
+func Bold(text string) string {
+	if !ColorEnabled() {
+		return text
+	}
+	return ansiBold + text + ansiReset
+}
+
+// Green renders text in green when color is enabled
+func Green(text string) string {
+	if !ColorEnabled() {
+		return text
+	}
+	return ansiGreen + text + ansiReset
+}
+
+// Red renders text in red when color is enabled
+func Red(text string) string {
+	if !ColorEnabled() {
+		return text
+	}
+	return ansiRed + text + ansiReset
+}
+
+// Yellow renders text in yellow when color is enabled
+func Yellow(text string) string {
+	if !ColorEnabled() {
+		return text
+	}
+	return ansiYellow + text + ansiReset
+}
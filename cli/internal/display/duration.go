@@ -0,0 +1,77 @@
+package display
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DurationFormat is how hours are shown to people
+type DurationFormat string
+
+// Duration formats
+const (
+	DurationDecimal DurationFormat = "decimal" // 7.50
+	DurationHM      DurationFormat = "hm"      // 7:30
+)
+
+var durationFormat = DurationDecimal
+
+// ParseDurationFormat parses a duration_format setting
+func ParseDurationFormat(value string) (DurationFormat, error) {
+	switch f := DurationFormat(strings.ToLower(strings.TrimSpace(value))); f {
+	case DurationDecimal, DurationHM:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid duration format %q (use decimal or hm)", value)
+}
+
+// SetDurationFormat sets how hours are shown for the rest of the run
+func SetDurationFormat(f DurationFormat) {
+	durationFormat = f
+}
+
+// FormatHours renders hours as "7.50", or as "7:30" with the hm duration
+// format. Output other than tables keeps decimals, so scripts get numbers.
+func FormatHours(hours float64) string {
+	if durationFormat != DurationHM || format != FormatTable {
+		return strconv.FormatFloat(hours, 'f', 2, 64)
+	}
+	minutes := int64(math.Round(math.Abs(hours) * 60))
+	sign := ""
+	if hours < 0 && minutes > 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%d:%02d", sign, minutes/60, minutes%60)
+}
+
+// FormatSignedHours renders hours like FormatHours, always with a sign,
+// e.g. "+1.25" or "+1:15"
+func FormatSignedHours(hours float64) string {
+	text := FormatHours(hours)
+	if strings.HasPrefix(text, "-") {
+		return text
+	}
+	return "+" + text
+}
+
+// FormatDuration renders hours with their unit: "7.50h", or "7:30" with the
+// hm duration format
+func FormatDuration(hours float64) string {
+	return withUnit(FormatHours(hours))
+}
+
+// FormatSignedDuration renders hours like FormatDuration, always with a
+// sign, e.g. "+1.25h" or "+1:15"
+func FormatSignedDuration(hours float64) string {
+	return withUnit(FormatSignedHours(hours))
+}
+
+// withUnit appends "h" to decimal hours; "7:30" needs no unit
+func withUnit(text string) string {
+	if strings.Contains(text, ":") {
+		return text
+	}
+	return text + "h"
+}
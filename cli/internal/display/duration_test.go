@@ -0,0 +1,58 @@
+package display
+
+import "testing"
+
+func TestFormatHours(t *testing.T) {
+	defer SetDurationFormat(DurationDecimal)
+
+	tests := []struct {
+		format                  DurationFormat
+		hours                   float64
+		hoursText, signed, unit string
+	}{
+		{DurationDecimal, 7.5, "7.50", "+7.50", "7.50h"},
+		{DurationDecimal, -0.25, "-0.25", "-0.25", "-0.25h"},
+		{DurationHM, 7.5, "7:30", "+7:30", "7:30"},
+		{DurationHM, 1.2583, "1:15", "+1:15", "1:15"},
+		{DurationHM, -0.5, "-0:30", "-0:30", "-0:30"},
+		{DurationHM, 0.004, "0:00", "+0:00", "0:00"},
+		{DurationHM, 9.999, "10:00", "+10:00", "10:00"},
+	}
+	for _, tt := range tests {
+		SetDurationFormat(tt.format)
+		if got := FormatHours(tt.hours); got != tt.hoursText {
+			t.Errorf("%s: FormatHours(%v) = %q, want %q", tt.format, tt.hours, got, tt.hoursText)
+		}
+		if got := FormatSignedHours(tt.hours); got != tt.signed {
+			t.Errorf("%s: FormatSignedHours(%v) = %q, want %q", tt.format, tt.hours, got, tt.signed)
+		}
+		if got := FormatDuration(tt.hours); got != tt.unit {
+			t.Errorf("%s: FormatDuration(%v) = %q, want %q", tt.format, tt.hours, got, tt.unit)
+		}
+	}
+}
+
+func TestFormatHoursKeepsDecimalsForScripts(t *testing.T) {
+	defer SetDurationFormat(DurationDecimal)
+	defer SetFormat(FormatTable)
+
+	SetDurationFormat(DurationHM)
+	SetFormat(FormatCSV)
+	if got := FormatHours(7.5); got != "7.50" {
+		t.Errorf("FormatHours(7.5) with csv = %q, want 7.50", got)
+	}
+	if cell := Hours(7.5); cell.Text != "7.50" || cell.Value != 7.5 {
+		t.Errorf("Hours(7.5) with csv = %+v", cell)
+	}
+}
+
+func TestParseDurationFormat(t *testing.T) {
+	for _, value := range []string{"decimal", "hm", " HM "} {
+		if _, err := ParseDurationFormat(value); err != nil {
+			t.Errorf("ParseDurationFormat(%q): %v", value, err)
+		}
+	}
+	if _, err := ParseDurationFormat("minutes"); err == nil {
+		t.Error("ParseDurationFormat(\"minutes\") succeeded")
+	}
+}
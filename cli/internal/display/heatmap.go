@@ -0,0 +1,156 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// heatmapBucket maps an hour range to the cell drawn for it
+type heatmapBucket struct {
+	Max   float64 // exclusive upper bound in hours
+	Label string
+	Block string
+	ASCII string
+}
+
+// heatmapBuckets are the shades for days with logged hours, lightest first
+var heatmapBuckets = []heatmapBucket{
+	{Max: 2, Label: "<2h", Block: "░░", ASCII: "::"},
+	{Max: 4, Label: "2-4h", Block: "▒▒", ASCII: "=="},
+	{Max: 6, Label: "4-6h", Block: "▓▓", ASCII: "**"},
+	{Max: 1e9, Label: "6h+", Block: "██", ASCII: "##"},
+}
+
+const (
+	heatmapMonthsPerRow = 3
+	heatmapMonthWidth   = 7*3 - 1
+)
+
+// Heatmap renders daily hours as month calendars with shaded cells
+type Heatmap struct {
+	Hours     map[string]float64 // hours by YYYY-MM-DD
+	From, To  time.Time          // first and last day shown
+	WeekStart time.Weekday
+	ASCII     bool // use ASCII density characters instead of block shades
+}
+
+// cell returns the two-character cell for a day. Workdays without hours are
+// dotted, weekends without hours stay blank.
+func (h Heatmap) cell(day time.Time) string {
+	hours := h.Hours[day.Format("2006-01-02")]
+	if hours <= 0 {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			return "  "
+		}
+		if h.ASCII {
+			return ".."
+		}
+		return "··"
+	}
+
+	for _, bucket := range heatmapBuckets {
+		if hours < bucket.Max {
+			if h.ASCII {
+				return bucket.ASCII
+			}
+			return bucket.Block
+		}
+	}
+	return "  "
+}
+
+// month renders one month as lines of exactly heatmapMonthWidth columns
+func (h Heatmap) month(first time.Time) []string {
+	title := first.Format("January 2006")
+	pad := (heatmapMonthWidth - len(title)) / 2
+	lines := []string{fmt.Sprintf("%-*s", heatmapMonthWidth, strings.Repeat(" ", pad)+title)}
+
+	names := make([]string, 7)
+	for i := range names {
+		names[i] = time.Weekday((int(h.WeekStart) + i) % 7).String()[:2]
+	}
+	lines = append(lines, strings.Join(names, " "))
+
+	offset := (int(first.Weekday()) - int(h.WeekStart) + 7) % 7
+	cells := make([]string, offset)
+	for i := range cells {
+		cells[i] = "  "
+	}
+	for day := first; day.Month() == first.Month() && !day.After(h.To); day = day.AddDate(0, 0, 1) {
+		if day.Before(h.From) {
+			cells = append(cells, "  ")
+		} else {
+			cells = append(cells, h.cell(day))
+		}
+	}
+	for len(cells)%7 != 0 {
+		cells = append(cells, "  ")
+	}
+	for i := 0; i < len(cells); i += 7 {
+		lines = append(lines, strings.Join(cells[i:i+7], " "))
+	}
+
+	return lines
+}
+
+// Render draws every month between From and To, several months per row
+func (h Heatmap) Render() string {
+	var months [][]string
+	first := time.Date(h.From.Year(), h.From.Month(), 1, 0, 0, 0, 0, h.From.Location())
+	for !first.After(h.To) {
+		months = append(months, h.month(first))
+		first = first.AddDate(0, 1, 0)
+	}
+
+	var sb strings.Builder
+	blank := strings.Repeat(" ", heatmapMonthWidth)
+	for start := 0; start < len(months); start += heatmapMonthsPerRow {
+		end := start + heatmapMonthsPerRow
+		if end > len(months) {
+			end = len(months)
+		}
+		row := months[start:end]
+
+		height := 0
+		for _, month := range row {
+			if len(month) > height {
+				height = len(month)
+			}
+		}
+		for line := 0; line < height; line++ {
+			parts := make([]string, len(row))
+			for i, month := range row {
+				parts[i] = blank
+				if line < len(month) {
+					parts[i] = month[line]
+				}
+			}
+			sb.WriteString(strings.TrimRight(strings.Join(parts, "   "), " "))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// Legend explains the cells
+func (h Heatmap) Legend() string {
+	zero := "··"
+	if h.ASCII {
+		zero = ".."
+	}
+
+	parts := []string{zero + " no hours"}
+	for _, bucket := range heatmapBuckets {
+		shade := bucket.Block
+		if h.ASCII {
+			shade = bucket.ASCII
+		}
+		parts = append(parts, shade+" "+bucket.Label)
+	}
+	parts = append(parts, "blank: weekend or outside range")
+
+	return strings.Join(parts, "   ")
+}
@@ -0,0 +1,82 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestHeatmapCells(t *testing.T) {
+	h := Heatmap{Hours: map[string]float64{
+		"2024-04-01": 1.5, // Mon
+		"2024-04-02": 3,
+		"2024-04-03": 5,
+		"2024-04-04": 8,
+		"2024-04-06": 2, // Sat with hours
+	}}
+
+	tests := []struct {
+		day         string
+		block, text string
+	}{
+		{"2024-04-01", "░░", "::"},
+		{"2024-04-02", "▒▒", "=="},
+		{"2024-04-03", "▓▓", "**"},
+		{"2024-04-04", "██", "##"},
+		{"2024-04-05", "··", ".."}, // workday without hours
+		{"2024-04-06", "▒▒", "=="},
+		{"2024-04-07", "  ", "  "}, // weekend without hours
+	}
+
+	for _, tt := range tests {
+		h.ASCII = false
+		if got := h.cell(day(tt.day)); got != tt.block {
+			t.Errorf("cell(%s) = %q, want %q", tt.day, got, tt.block)
+		}
+		h.ASCII = true
+		if got := h.cell(day(tt.day)); got != tt.text {
+			t.Errorf("ASCII cell(%s) = %q, want %q", tt.day, got, tt.text)
+		}
+	}
+}
+
+func TestHeatmapWeekStart(t *testing.T) {
+	// April 2024 starts on a Monday
+	h := Heatmap{Hours: map[string]float64{"2024-04-01": 8}, From: day("2024-04-01"), To: day("2024-04-03"), ASCII: true}
+
+	h.WeekStart = time.Monday
+	lines := h.month(day("2024-04-01"))
+	if lines[1] != "Mo Tu We Th Fr Sa Su" || !strings.HasPrefix(lines[2], "## .. ..") {
+		t.Errorf("monday start:\n%s", strings.Join(lines, "\n"))
+	}
+
+	h.WeekStart = time.Sunday
+	lines = h.month(day("2024-04-01"))
+	if lines[1] != "Su Mo Tu We Th Fr Sa" || !strings.HasPrefix(lines[2], "   ## .. ..") {
+		t.Errorf("sunday start:\n%s", strings.Join(lines, "\n"))
+	}
+	if len(lines) != 3 {
+		t.Errorf("days after To should not add rows, got %d lines", len(lines))
+	}
+}
+
+func TestHeatmapRenderLaysOutMonths(t *testing.T) {
+	h := Heatmap{From: day("2024-01-01"), To: day("2024-04-30"), WeekStart: time.Monday, ASCII: true}
+
+	out := h.Render()
+	for _, month := range []string{"January 2024", "February 2024", "March 2024", "April 2024"} {
+		if !strings.Contains(out, month) {
+			t.Errorf("missing %s", month)
+		}
+	}
+	// Three months per row: April starts a second block
+	first := strings.SplitN(out, "\n", 2)[0]
+	if !strings.Contains(first, "March 2024") || strings.Contains(first, "April") {
+		t.Errorf("first title line = %q", first)
+	}
+}
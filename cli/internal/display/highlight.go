@@ -0,0 +1,21 @@
+package display
+
+import "regexp"
+
+const (
+	ansiHighlight = "\033[1;4m"
+	ansiReset     = "\033[0m"
+)
+
+// Highlight marks every case-insensitive occurrence of needle in text.
+// Text is returned unchanged when color is disabled.
+func Highlight(text, needle string) string {
+	if needle == "" || !ColorEnabled() {
+		return text
+	}
+
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(needle))
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		return ansiHighlight + match + ansiReset
+	})
+}
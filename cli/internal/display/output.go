@@ -0,0 +1,120 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vmiller/timetracker-cli/internal/logging"
+)
+
+// Commands write their output through this file so that one switch decides
+// whether it is meant for people or for scripts. Only the table format
+// shows the human-readable output, and not in quiet runs; json writes the
+// data passed to Result, and csv and tsv write the tables.
+
+// Format is an output format
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+	FormatTSV   Format = "tsv"
+)
+
+// Formats lists the output formats
+var Formats = []Format{FormatTable, FormatJSON, FormatCSV, FormatTSV}
+
+// ParseFormat parses an --output value
+func ParseFormat(value string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(value, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("invalid output format %q (use %s)", value, strings.Join(names, ", "))
+}
+
+var (
+	format           = FormatTable
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// SetFormat sets the output format for the rest of the run
+func SetFormat(f Format) {
+	format = f
+}
+
+// OutputFormat returns the output format
+func OutputFormat() Format {
+	return format
+}
+
+// EnableJSON switches to JSON output for the rest of the run
+func EnableJSON() {
+	format = FormatJSON
+}
+
+// JSON reports whether output is JSON
+func JSON() bool {
+	return format == FormatJSON
+}
+
+// human returns where human-readable output goes
+func human() io.Writer {
+	if format != FormatTable || logging.IsQuiet() {
+		return io.Discard
+	}
+	return stdout
+}
+
+// Printf writes human-readable output
+func Printf(format string, a ...interface{}) {
+	fmt.Fprintf(human(), format, a...)
+}
+
+// Println writes a line of human-readable output
+func Println(a ...interface{}) {
+	fmt.Fprintln(human(), a...)
+}
+
+// Print writes human-readable output
+func Print(a ...interface{}) {
+	fmt.Fprint(human(), a...)
+}
+
+// Result writes a command's data as indented JSON in JSON mode. Otherwise it
+// does nothing, since the command has printed it already.
+func Result(v interface{}) error {
+	if format != FormatJSON {
+		return nil
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// jsonError is how errors are written in JSON mode
+type jsonError struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// Error reports a failed command on stderr, as {"error": ..., "code": ...}
+// in JSON mode, where code is the process's exit code
+func Error(err error, code int) {
+	if format != FormatJSON {
+		fmt.Fprintln(stderr, "Error:", err)
+		return
+	}
+	data, _ := json.Marshal(jsonError{Error: err.Error(), Code: code})
+	fmt.Fprintln(stderr, string(data))
+}
@@ -0,0 +1,98 @@
+package display
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// captureOutput redirects stdout and stderr and restores the format
+// afterwards
+func captureOutput(t *testing.T, f Format) (*bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	oldStdout, oldStderr, oldFormat := stdout, stderr, format
+	stdout, stderr, format = &out, &errOut, f
+	t.Cleanup(func() { stdout, stderr, format = oldStdout, oldStderr, oldFormat })
+	return &out, &errOut
+}
+
+func TestHumanOutput(t *testing.T) {
+	out, errOut := captureOutput(t, FormatTable)
+
+	Printf("⏱️  Total: %.2f\n", 1.5)
+	NewTable("A").Print()
+	if err := Result(map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	Error(errors.New("boom"), 3)
+
+	if got := out.String(); !strings.HasPrefix(got, "⏱️  Total: 1.50\n┌") {
+		t.Errorf("stdout = %q", got)
+	}
+	if got := errOut.String(); got != "Error: boom\n" {
+		t.Errorf("stderr = %q", got)
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	out, errOut := captureOutput(t, FormatJSON)
+
+	Printf("⏱️  Total: %.2f\n", 1.5)
+	Println("dropped")
+	NewTable("A").Print()
+	if err := Result(map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	Error(errors.New(`bad "thing"`), 3)
+
+	if got := out.String(); got != "{\n  \"a\": 1\n}\n" {
+		t.Errorf("stdout = %q, want only the result", got)
+	}
+	if got := errOut.String(); got != `{"error":"bad \"thing\"","code":3}`+"\n" {
+		t.Errorf("stderr = %q", got)
+	}
+}
+
+func sampleTable() *Table {
+	table := NewTable("Day", "Date", "Hours", "Note")
+	table.AddCells(Text("Mon"), Text("2024-04-08"), Cell{Text: "7.30 (+1.00 pending)", Value: 7.3 + 1e-12}, Text("client \"A\", onsite"))
+	table.AddRow("Tue", "2024-04-09", Red("0.00"), "tab\there")
+	return table
+}
+
+func TestDelimitedOutput(t *testing.T) {
+	out, _ := captureOutput(t, FormatCSV)
+	Printf("📆 dropped\n")
+	sampleTable().Print()
+
+	want := "Day,Date,Hours,Note\n" +
+		"Mon,2024-04-08,7.3,\"client \"\"A\"\", onsite\"\n" +
+		"Tue,2024-04-09,0.00,tab\there\n"
+	if got := out.String(); got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+
+	out.Reset()
+	format = FormatTSV
+	sampleTable().Print()
+	if got := out.String(); !strings.Contains(got, "Tue\t2024-04-09\t0.00\t\"tab\there\"\n") {
+		t.Errorf("tsv = %q", got)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	records := sampleTable().Records()
+	if len(records) != 2 || records[0]["Hours"] != 7.3 || records[1]["Hours"] != "0.00" {
+		t.Errorf("Records() = %v", records)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("CSV"); err != nil || f != FormatCSV {
+		t.Errorf("ParseFormat(CSV) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) should fail")
+	}
+}
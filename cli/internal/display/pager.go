@@ -0,0 +1,42 @@
+package display
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Page writes text to stdout, piping it through $PAGER (default "less -R")
+// when stdout is a terminal and the text doesn't fit on one screen. If the
+// pager can't be started the text is printed directly.
+func Page(text string) error {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		_, err := fmt.Print(text)
+		return err
+	}
+
+	_, height, err := term.GetSize(fd)
+	if err != nil || strings.Count(text, "\n") < height {
+		_, err := fmt.Print(text)
+		return err
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-R"}
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		_, err := fmt.Print(text)
+		return err
+	}
+	return cmd.Wait()
+}
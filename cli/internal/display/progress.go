@@ -0,0 +1,83 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// progressWidth is the number of cells in a progress bar
+const progressWidth = 30
+
+// ProgressBar renders done out of total as a bar like "[#####-----] 5/10"
+func ProgressBar(done, total int) string {
+	filled := progressWidth
+	if total > 0 {
+		filled = done * progressWidth / total
+	}
+	return fmt.Sprintf("[%s%s] %d/%d",
+		strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), done, total)
+}
+
+// ClearLine erases a progress line left on f, such as one a command was
+// interrupted in the middle of redrawing
+func ClearLine(f *os.File) {
+	if AnimationEnabled(f) {
+		fmt.Fprint(f, "\r\033[K")
+	}
+}
+
+// StatusLines is a block of lines redrawn in place as they change, such as
+// a progress line per provider. Lines keep the order they were first set in.
+// Only draw them where AnimationEnabled(os.Stdout) holds.
+type StatusLines struct {
+	out   io.Writer
+	width int // lines are cut to fit, so they don't wrap; 0 leaves them
+	keys  []string
+	lines map[string]string
+	drawn int // number of lines on screen
+}
+
+// NewStatusLines starts an empty block of status lines
+func NewStatusLines() *StatusLines {
+	return &StatusLines{out: human(), width: TerminalWidth(), lines: map[string]string{}}
+}
+
+// Set changes the line for key, adding it below the others if it is new
+func (s *StatusLines) Set(key, line string) {
+	current, ok := s.lines[key]
+	if ok && current == line {
+		return
+	}
+	if !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.lines[key] = line
+	s.redraw()
+}
+
+// Clear erases the lines from the screen
+func (s *StatusLines) Clear() {
+	if s.drawn > 0 {
+		fmt.Fprintf(s.out, "\033[%dA\r\033[J", s.drawn)
+		s.drawn = 0
+	}
+}
+
+// redraw moves back up to the first line and draws them all again
+func (s *StatusLines) redraw() {
+	var sb strings.Builder
+	if s.drawn > 0 {
+		fmt.Fprintf(&sb, "\033[%dA", s.drawn)
+	}
+	for _, key := range s.keys {
+		line := s.lines[key]
+		if s.width > 0 {
+			line = truncate(line, s.width-1)
+		}
+		sb.WriteString("\r\033[K" + line + "\n")
+	}
+	s.drawn = len(s.keys)
+	fmt.Fprint(s.out, sb.String())
+}
@@ -0,0 +1,31 @@
+package display
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	if got, want := ProgressBar(15, 30), "[###############---------------] 15/30"; got != want {
+		t.Errorf("ProgressBar(15, 30) = %q, want %q", got, want)
+	}
+}
+
+func TestStatusLinesRedrawInPlace(t *testing.T) {
+	var out bytes.Buffer
+	lines := &StatusLines{out: &out, width: 20, lines: map[string]string{}}
+
+	lines.Set("toggl", "toggl: fetching…")
+	lines.Set("tempo", "tempo: waiting")
+	lines.Set("tempo", "tempo: waiting") // unchanged, not redrawn
+	lines.Set("toggl", "toggl: fetched 240, imported 31…")
+	lines.Clear()
+
+	want := "\r\033[Ktoggl: fetching…\n" +
+		"\033[1A\r\033[Ktoggl: fetching…\n\r\033[Ktempo: waiting\n" +
+		"\033[2A\r\033[Ktoggl: fetched 240…\n\r\033[Ktempo: waiting\n" +
+		"\033[2A\r\033[J"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q\nwant     %q", got, want)
+	}
+}
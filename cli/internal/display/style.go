@@ -0,0 +1,58 @@
+package display
+
+import (
+	"os"
+
+	"github.com/vmiller/timetracker-cli/internal/logging"
+	"golang.org/x/term"
+)
+
+// Style is the one place that decides whether output may be styled: colors,
+// and the control sequences that redraw spinners and progress lines.
+//
+// Colors need a terminal and are turned off by --no-color, a non-empty
+// NO_COLOR (see https://no-color.org) and TERM=dumb. Redrawn lines need a
+// terminal too and are turned off by --no-color and TERM=dumb, but not by
+// NO_COLOR, which is only about color.
+type Style struct {
+	NoColor    bool                            // --no-color
+	LookupEnv  func(key string) (string, bool) // os.LookupEnv
+	IsTerminal func(f *os.File) bool           // whether f is a terminal
+}
+
+// style is the style of this run
+var style = Style{
+	LookupEnv:  os.LookupEnv,
+	IsTerminal: func(f *os.File) bool { return term.IsTerminal(int(f.Fd())) },
+}
+
+// CurrentStyle returns the style of this run
+func CurrentStyle() Style {
+	return style
+}
+
+// Color reports whether colors may be written to f
+func (s Style) Color(f *os.File) bool {
+	if value, _ := s.LookupEnv("NO_COLOR"); value != "" {
+		return false
+	}
+	return s.Animate(f)
+}
+
+// Animate reports whether lines on f may be redrawn with "\r" and erased
+// with ANSI sequences
+func (s Style) Animate(f *os.File) bool {
+	if s.NoColor {
+		return false
+	}
+	if term, _ := s.LookupEnv("TERM"); term == "dumb" {
+		return false
+	}
+	return s.IsTerminal(f)
+}
+
+// AnimationEnabled reports whether progress on f may be drawn as a line
+// that is redrawn in place. Quiet runs draw no progress.
+func AnimationEnabled(f *os.File) bool {
+	return !logging.IsQuiet() && style.Animate(f)
+}
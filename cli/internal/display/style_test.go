@@ -0,0 +1,66 @@
+package display
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		noColor  bool
+		env      map[string]string
+		terminal bool
+		color    bool
+		animate  bool
+	}{
+		{name: "terminal", terminal: true, color: true, animate: true},
+		{name: "not a terminal", terminal: false},
+		{name: "--no-color", noColor: true, terminal: true},
+		{name: "NO_COLOR", env: map[string]string{"NO_COLOR": "1"}, terminal: true, animate: true},
+		{name: "empty NO_COLOR", env: map[string]string{"NO_COLOR": ""}, terminal: true, color: true, animate: true},
+		{name: "NO_COLOR off a terminal", env: map[string]string{"NO_COLOR": "1"}},
+		{name: "TERM=dumb", env: map[string]string{"TERM": "dumb"}, terminal: true},
+		{name: "TERM=xterm", env: map[string]string{"TERM": "xterm-256color"}, terminal: true, color: true, animate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Style{
+				NoColor: tt.noColor,
+				LookupEnv: func(key string) (string, bool) {
+					value, ok := tt.env[key]
+					return value, ok
+				},
+				IsTerminal: func(*os.File) bool { return tt.terminal },
+			}
+			if got := s.Color(os.Stdout); got != tt.color {
+				t.Errorf("Color() = %v, want %v", got, tt.color)
+			}
+			if got := s.Animate(os.Stdout); got != tt.animate {
+				t.Errorf("Animate() = %v, want %v", got, tt.animate)
+			}
+		})
+	}
+}
+
+func TestColorHelpersFollowStyle(t *testing.T) {
+	old := style
+	t.Cleanup(func() { style = old })
+
+	style = Style{
+		LookupEnv:  func(string) (string, bool) { return "", false },
+		IsTerminal: func(*os.File) bool { return true },
+	}
+	if got := Red("x"); got != ansiRed+"x"+ansiReset {
+		t.Errorf("Red() on a terminal = %q", got)
+	}
+
+	DisableColor()
+	if got := Red("x"); got != "x" {
+		t.Errorf("Red() after DisableColor() = %q", got)
+	}
+	if AnimationEnabled(os.Stderr) {
+		t.Error("--no-color should also stop redrawn progress lines")
+	}
+}
@@ -1,14 +1,54 @@
 package display
 
 import (
+	"encoding/csv"
 	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
 )
 
-// Table represents an ASCII table
+// ansiEscape matches the color sequences cells may contain
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// width returns the number of terminal columns a cell occupies
+func width(cell string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(cell, ""))
+}
+
+// Table represents an ASCII table. Besides the text of each cell it keeps a
+// machine-readable value, which the csv, tsv and json renderers write.
+// Section rows span the whole table and only appear in the boxed table.
 type Table struct {
 	Headers []string
 	Rows    [][]string
+	values  [][]interface{}
+
+	sections  map[int][]string // section titles by the index of the row they precede
+	maxWidth  int
+	fitColumn int
+}
+
+// Cell is a table cell whose machine-readable value differs from its text,
+// such as hours shown as "7.50 (+1.00 pending)"
+type Cell struct {
+	Text  string
+	Value interface{}
+}
+
+// Text returns a cell whose value is its text without colors
+func Text(text string) Cell {
+	return Cell{Text: text, Value: ansiEscape.ReplaceAllString(text, "")}
+}
+
+// Hours returns a cell showing hours in the duration format
+func Hours(hours float64) Cell {
+	return Cell{Text: FormatHours(hours), Value: hours}
 }
 
 // NewTable creates a new table with headers
@@ -19,9 +59,41 @@ func NewTable(headers ...string) *Table {
 	}
 }
 
-// AddRow adds a row to the table
+// AddRow adds a row to the table. The machine-readable values are the
+// cells' text without colors.
 func (t *Table) AddRow(cells ...string) {
+	values := make([]interface{}, len(cells))
+	for i, cell := range cells {
+		values[i] = ansiEscape.ReplaceAllString(cell, "")
+	}
 	t.Rows = append(t.Rows, cells)
+	t.values = append(t.values, values)
+}
+
+// AddCells adds a row of cells with machine-readable values
+func (t *Table) AddCells(cells ...Cell) {
+	texts := make([]string, len(cells))
+	values := make([]interface{}, len(cells))
+	for i, cell := range cells {
+		texts[i], values[i] = cell.Text, cell.Value
+	}
+	t.Rows = append(t.Rows, texts)
+	t.values = append(t.values, values)
+}
+
+// AddSection starts a section: a row spanning the whole table, drawn
+// between rules above the rows added after it
+func (t *Table) AddSection(title string) {
+	if t.sections == nil {
+		t.sections = map[int][]string{}
+	}
+	t.sections[len(t.Rows)] = append(t.sections[len(t.Rows)], title)
+}
+
+// FitWidth keeps the boxed table within maxWidth columns by truncating the
+// cells of column, which should be the one holding free text
+func (t *Table) FitWidth(maxWidth, column int) {
+	t.maxWidth, t.fitColumn = maxWidth, column
 }
 
 // Render renders the table as a string
@@ -33,13 +105,44 @@ func (t *Table) Render() string {
 	// Calculate column widths
 	colWidths := make([]int, len(t.Headers))
 	for i, header := range t.Headers {
-		colWidths[i] = len(header)
+		colWidths[i] = width(header)
 	}
 
 	for _, row := range t.Rows {
 		for i, cell := range row {
-			if i < len(colWidths) && len(cell) > colWidths[i] {
-				colWidths[i] = len(cell)
+			if i < len(colWidths) && width(cell) > colWidths[i] {
+				colWidths[i] = width(cell)
+			}
+		}
+	}
+
+	// Widen the last column for long section titles, then shrink the
+	// fitted column to the maximum width
+	inner := func() int {
+		total := 3 * (len(colWidths) - 1)
+		for _, w := range colWidths {
+			total += w
+		}
+		return total
+	}
+	for _, titles := range t.sections {
+		for _, title := range titles {
+			if extra := width(title) - inner(); extra > 0 {
+				colWidths[len(colWidths)-1] += extra
+			}
+		}
+	}
+	fitted := -1
+	if t.maxWidth > 0 && t.fitColumn >= 0 && t.fitColumn < len(colWidths) {
+		if excess := inner() + 4 - t.maxWidth; excess > 0 {
+			fitted = t.fitColumn
+			narrowest := width(t.Headers[fitted])
+			if narrowest < 3 {
+				narrowest = 3
+			}
+			colWidths[fitted] -= excess
+			if colWidths[fitted] < narrowest {
+				colWidths[fitted] = narrowest
 			}
 		}
 	}
@@ -47,64 +150,194 @@ func (t *Table) Render() string {
 	var sb strings.Builder
 
 	// Draw top border
-	sb.WriteString("┌")
-	for i, width := range colWidths {
-		sb.WriteString(strings.Repeat("─", width+2))
-		if i < len(colWidths)-1 {
-			sb.WriteString("┬")
-		}
-	}
-	sb.WriteString("┐\n")
+	rule(&sb, "┌", "┐", colWidths, false, true)
 
 	// Draw headers
 	sb.WriteString("│")
 	for i, header := range t.Headers {
 		sb.WriteString(" ")
 		sb.WriteString(header)
-		sb.WriteString(strings.Repeat(" ", colWidths[i]-len(header)))
+		sb.WriteString(strings.Repeat(" ", colWidths[i]-width(header)))
 		sb.WriteString(" │")
 	}
 	sb.WriteString("\n")
 
 	// Draw header separator
-	sb.WriteString("├")
-	for i, width := range colWidths {
-		sb.WriteString(strings.Repeat("─", width+2))
-		if i < len(colWidths)-1 {
-			sb.WriteString("┼")
+	rule(&sb, "├", "┤", colWidths, true, len(t.sections[0]) == 0)
+
+	// Draw rows, with sections between rules. columns tracks whether the
+	// line above is divided into columns.
+	columns, afterHeader := true, true
+	for r := 0; r <= len(t.Rows); r++ {
+		for _, title := range t.sections[r] {
+			if !afterHeader {
+				rule(&sb, "├", "┤", colWidths, columns, false)
+			}
+			title = truncate(title, inner())
+			sb.WriteString("│ ")
+			sb.WriteString(title)
+			sb.WriteString(strings.Repeat(" ", inner()-width(title)))
+			sb.WriteString(" │\n")
+			columns, afterHeader = false, false
+		}
+		if r == len(t.Rows) {
+			break
 		}
-	}
-	sb.WriteString("┤\n")
 
-	// Draw rows
-	for _, row := range t.Rows {
+		if !columns {
+			rule(&sb, "├", "┤", colWidths, false, true)
+		}
 		sb.WriteString("│")
-		for i, cell := range row {
+		for i, cell := range t.Rows[r] {
 			if i >= len(colWidths) {
 				break
 			}
+			if i == fitted {
+				cell = truncate(cell, colWidths[i])
+			}
 			sb.WriteString(" ")
 			sb.WriteString(cell)
-			sb.WriteString(strings.Repeat(" ", colWidths[i]-len(cell)))
+			sb.WriteString(strings.Repeat(" ", colWidths[i]-width(cell)))
 			sb.WriteString(" │")
 		}
 		sb.WriteString("\n")
+		columns, afterHeader = true, false
 	}
 
 	// Draw bottom border
-	sb.WriteString("└")
+	rule(&sb, "└", "┘", colWidths, columns, false)
+
+	return sb.String()
+}
+
+// Width returns the number of terminal columns the boxed table takes up
+func (t *Table) Width() int {
+	line, _, _ := strings.Cut(t.Render(), "\n")
+	return width(line)
+}
+
+// rule draws a horizontal line of the table. above and below tell whether
+// the lines it separates are divided into columns, which decides the
+// junctions.
+func rule(sb *strings.Builder, left, right string, colWidths []int, above, below bool) {
+	junction := "─"
+	switch {
+	case above && below:
+		junction = "┼"
+	case above:
+		junction = "┴"
+	case below:
+		junction = "┬"
+	}
+
+	sb.WriteString(left)
 	for i, width := range colWidths {
 		sb.WriteString(strings.Repeat("─", width+2))
 		if i < len(colWidths)-1 {
-			sb.WriteString("┴")
+			sb.WriteString(junction)
 		}
 	}
-	sb.WriteString("┘\n")
+	sb.WriteString(right + "\n")
+}
+
+// truncate cuts text to at most maxWidth columns, marking cut text with
+// "…". Cut text loses its colors.
+func truncate(text string, maxWidth int) string {
+	if width(text) <= maxWidth {
+		return text
+	}
+	runes := []rune(ansiEscape.ReplaceAllString(text, ""))
+	if maxWidth < 1 {
+		return ""
+	}
+	return string(runes[:maxWidth-1]) + "…"
+}
+
+// TerminalWidth returns the width of the terminal stdout is on, or 0 when
+// stdout isn't a terminal
+func TerminalWidth() int {
+	if !style.IsTerminal(os.Stdout) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
 
+// RenderDelimited renders the header and the machine-readable values as
+// CSV, separated by comma, quoting fields as needed
+func (t *Table) RenderDelimited(comma rune) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = comma
+	w.Write(t.Headers)
+	for _, row := range t.values {
+		record := make([]string, len(t.Headers))
+		for i := range record {
+			if i < len(row) {
+				record[i] = formatValue(row[i])
+			}
+		}
+		w.Write(record)
+	}
+	w.Flush()
 	return sb.String()
 }
 
-// Print prints the table to stdout
+// Records returns the rows as objects keyed by header, for JSON
+func (t *Table) Records() []map[string]interface{} {
+	records := make([]map[string]interface{}, 0, len(t.values))
+	for _, row := range t.values {
+		record := map[string]interface{}{}
+		for i, header := range t.Headers {
+			if i < len(row) {
+				record[header] = roundValue(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// formatValue writes a machine-readable value: numbers in plain decimal
+// notation without rounding to two places
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(roundValue(v).(float64), 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// roundValue drops the floating-point noise of summed hours, e.g.
+// 7.300000000000001, from numbers
+func roundValue(value interface{}) interface{} {
+	if v, ok := value.(float64); ok {
+		return math.Round(v*1e6) / 1e6
+	}
+	return value
+}
+
+// Print prints the table to stdout in the output format: boxed for table
+// unless the run is quiet, delimited for csv and tsv. JSON output comes from
+// Result instead.
 func (t *Table) Print() {
-	fmt.Print(t.Render())
+	switch format {
+	case FormatTable:
+		Print(t.Render())
+	case FormatCSV:
+		fmt.Fprint(stdout, t.RenderDelimited(','))
+	case FormatTSV:
+		fmt.Fprint(stdout, t.RenderDelimited('\t'))
+	}
 }
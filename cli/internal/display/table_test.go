@@ -0,0 +1,60 @@
+package display
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSections(t *testing.T) {
+	table := NewTable("Project", "Description", "Hours")
+	table.AddSection("Mon 2024-01-15 · 1.50h")
+	table.AddRow("WEKA-199", "Fix login", "1.50")
+	table.AddSection("Tue 2024-01-16 · 0.00h")
+	table.AddSection("Wed 2024-01-17 · 2.00h")
+	table.AddRow("Internal", "Planning", "2.00")
+
+	want := `┌──────────┬─────────────┬───────┐
+│ Project  │ Description │ Hours │
+├──────────┴─────────────┴───────┤
+│ Mon 2024-01-15 · 1.50h         │
+├──────────┬─────────────┬───────┤
+│ WEKA-199 │ Fix login   │ 1.50  │
+├──────────┴─────────────┴───────┤
+│ Tue 2024-01-16 · 0.00h         │
+├────────────────────────────────┤
+│ Wed 2024-01-17 · 2.00h         │
+├──────────┬─────────────┬───────┤
+│ Internal │ Planning    │ 2.00  │
+└──────────┴─────────────┴───────┘
+`
+	if got := table.Render(); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Sections are left out of machine-readable output
+	if records := table.Records(); len(records) != 2 {
+		t.Errorf("Records() = %v", records)
+	}
+}
+
+func TestRenderFitWidth(t *testing.T) {
+	table := NewTable("Project", "Description", "Hours")
+	table.AddRow("WEKA-199", "A description far too long for a narrow terminal", "1.50")
+	table.FitWidth(40, 1)
+
+	lines := strings.Split(strings.TrimSuffix(table.Render(), "\n"), "\n")
+	for _, line := range lines {
+		if width(line) != 40 {
+			t.Errorf("line %q is %d wide, want 40", line, width(line))
+		}
+	}
+	if !strings.Contains(lines[3], "│ A description fa… │ 1.50  │") {
+		t.Errorf("row = %q", lines[3])
+	}
+
+	// Tables that fit are left alone
+	table.FitWidth(200, 1)
+	if !strings.Contains(table.Render(), "narrow terminal") {
+		t.Error("FitWidth() truncated a table that fits")
+	}
+}
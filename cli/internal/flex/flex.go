@@ -0,0 +1,84 @@
+// Package flex computes an overtime (flex-time) balance from logged hours
+// and the hours a work calendar expects.
+//
+// Weeks begin on the configured day, Monday by default, and are named after
+// the ISO week most of their days fall in, so they line up with vacation
+// weeks recorded as "2024-W32". Each week owes the expected hours of its workdays
+// inside the range, so a week cut short by the start date or by today, or
+// containing holidays, owes less.
+package flex
+
+import (
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// Week is one row of the balance
+type Week struct {
+	ISOWeek  string    // see dates.WeekName
+	Start    time.Time // first day counted (the week's first day unless cut by the range)
+	End      time.Time // last day counted (the week's last day unless cut by the range)
+	Hours    float64   // logged hours
+	Expected float64   // hours owed; zero for vacation weeks
+	Vacation bool
+	Holidays int     // holidays on working days
+	Delta    float64 // Hours - Expected
+	Balance  float64 // running total of Delta
+}
+
+// Accumulate walks the weeks beginning on weekStart from from to to
+// (inclusive days) and returns each week's delta and the running balance.
+// hours maps YYYY-MM-DD to logged hours; cal says which days owe how many
+// hours, and is 40 hours over Monday to Friday without holidays or vacation
+// if nil.
+func Accumulate(from, to time.Time, hours map[string]float64, cal *calendar.Calendar, weekStart time.Weekday) []Week {
+	from, to = dates.StartOfDay(from), dates.StartOfDay(to)
+	if to.Before(from) {
+		return nil
+	}
+	if cal == nil {
+		cal = calendar.Default()
+	}
+
+	var weeks []Week
+	var balance float64
+	for first := dates.StartOfWeek(from, weekStart); !first.After(to); first = first.AddDate(0, 0, 7) {
+		week := Week{
+			ISOWeek: dates.WeekName(first),
+			Start:   maxTime(first, from),
+			End:     minTime(first.AddDate(0, 0, 6), to),
+		}
+		week.Vacation = cal.IsVacationWeek(week.ISOWeek)
+
+		for day := week.Start; !day.After(week.End); day = day.AddDate(0, 0, 1) {
+			week.Hours += hours[dates.Format(day)]
+			week.Expected += cal.Expected(day)
+			if kind, _ := cal.Day(day); kind == calendar.Holiday {
+				week.Holidays++
+			}
+		}
+		week.Delta = week.Hours - week.Expected
+		balance += week.Delta
+		week.Balance = balance
+
+		weeks = append(weeks, week)
+	}
+
+	return weeks
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
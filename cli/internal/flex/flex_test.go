@@ -0,0 +1,218 @@
+package flex
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// workweek logs the given hours on each weekday of the week starting monday
+func workweek(hours map[string]float64, monday string, perDay float64) {
+	start := date(monday)
+	for i := 0; i < 5; i++ {
+		hours[start.AddDate(0, 0, i).Format("2006-01-02")] += perDay
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestAccumulateFullWeeks(t *testing.T) {
+	hours := map[string]float64{}
+	workweek(hours, "2024-04-01", 9) // 45h
+	workweek(hours, "2024-04-08", 7) // 35h
+	workweek(hours, "2024-04-15", 8) // 40h
+
+	weeks := Accumulate(date("2024-04-01"), date("2024-04-21"), hours, nil, time.Monday)
+	if len(weeks) != 3 {
+		t.Fatalf("got %d weeks, want 3", len(weeks))
+	}
+
+	wantDelta := []float64{5, -5, 0}
+	wantBalance := []float64{5, 0, 0}
+	for i, week := range weeks {
+		if !approx(week.Delta, wantDelta[i]) || !approx(week.Balance, wantBalance[i]) {
+			t.Errorf("%s: delta %v balance %v, want %v %v", week.ISOWeek, week.Delta, week.Balance, wantDelta[i], wantBalance[i])
+		}
+	}
+	if weeks[0].ISOWeek != "2024-W14" {
+		t.Errorf("first week = %s, want 2024-W14", weeks[0].ISOWeek)
+	}
+}
+
+func TestAccumulatePartialWeeks(t *testing.T) {
+	hours := map[string]float64{
+		"2024-04-01": 8, // Monday before the start date: ignored
+		"2024-04-03": 8,
+		"2024-04-04": 8,
+		"2024-04-05": 8,
+		"2024-04-06": 2, // Saturday work counts as hours, not as owed time
+		"2024-04-08": 8,
+		"2024-04-09": 6,
+		"2024-04-10": 8, // after the end date: ignored
+	}
+
+	// Starts on a Wednesday, ends on the following Tuesday
+	weeks := Accumulate(date("2024-04-03"), date("2024-04-09"), hours, nil, time.Monday)
+	if len(weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(weeks))
+	}
+
+	first, second := weeks[0], weeks[1]
+	if !approx(first.Expected, 24) || !approx(first.Hours, 26) || !approx(first.Delta, 2) {
+		t.Errorf("first week = %+v, want 26h of 24h expected", first)
+	}
+	if first.Start.Format("2006-01-02") != "2024-04-03" || first.End.Format("2006-01-02") != "2024-04-07" {
+		t.Errorf("first week range = %s..%s", first.Start, first.End)
+	}
+	if !approx(second.Expected, 16) || !approx(second.Hours, 14) || !approx(second.Balance, 0) {
+		t.Errorf("second week = %+v, want 14h of 16h expected and balance 0", second)
+	}
+}
+
+func TestAccumulateVacationWeeks(t *testing.T) {
+	hours := map[string]float64{}
+	workweek(hours, "2024-08-05", 8)
+	hours["2024-08-14"] = 1 // answered an email on vacation
+
+	cal := calendar.Default()
+	cal.Vacation = map[string]string{"2024-W33": ""}
+	weeks := Accumulate(date("2024-08-05"), date("2024-08-18"), hours, cal, time.Monday)
+	if len(weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(weeks))
+	}
+	if weeks[1].ISOWeek != "2024-W33" || !weeks[1].Vacation || weeks[1].Expected != 0 {
+		t.Errorf("vacation week = %+v", weeks[1])
+	}
+	if !approx(weeks[1].Balance, 1) {
+		t.Errorf("balance = %v, want 1", weeks[1].Balance)
+	}
+}
+
+func TestAccumulateHolidays(t *testing.T) {
+	hours := map[string]float64{}
+	workweek(hours, "2024-04-29", 8)
+	delete(hours, "2024-05-01")
+
+	cal := calendar.Default()
+	cal.Holidays = map[string]string{"2024-05-01": "Labour Day"}
+	weeks := Accumulate(date("2024-04-29"), date("2024-05-05"), hours, cal, time.Monday)
+	if len(weeks) != 1 {
+		t.Fatalf("got %d weeks, want 1", len(weeks))
+	}
+	if week := weeks[0]; !approx(week.Expected, 32) || week.Holidays != 1 || !approx(week.Delta, 0) {
+		t.Errorf("week = %+v, want 32h expected over 4 workdays", week)
+	}
+
+	// A four-day week spreads the contract hours over four days
+	var days [7]bool
+	for day := time.Monday; day <= time.Thursday; day++ {
+		days[day] = true
+	}
+	weeks = Accumulate(date("2024-04-01"), date("2024-04-03"), map[string]float64{}, calendar.FromContract(32, days), time.Monday)
+	if !approx(weeks[0].Expected, 24) {
+		t.Errorf("expected = %v, want 24 for three of four days", weeks[0].Expected)
+	}
+}
+
+func TestAccumulateSchedule(t *testing.T) {
+	// 32h over Monday, Tuesday, Thursday and Friday with Wednesdays off,
+	// and a short Friday
+	cal := calendar.FromSchedule([7]float64{
+		time.Monday: 8, time.Tuesday: 8, time.Thursday: 10, time.Friday: 6,
+	})
+	cal.Holidays = map[string]string{"2024-05-09": "Ascension Day"}
+	if !approx(cal.WeeklyHours(), 32) {
+		t.Fatalf("WeeklyHours() = %v, want 32", cal.WeeklyHours())
+	}
+
+	hours := map[string]float64{
+		"2024-05-01": 8, // Wednesday: not a workday, counts as extra
+		"2024-05-02": 10,
+		"2024-05-03": 6,
+		"2024-05-06": 8,
+		"2024-05-07": 8,
+		"2024-05-10": 6,
+	}
+
+	// Starts on a Wednesday; the second week contains a Thursday holiday
+	weeks := Accumulate(date("2024-05-01"), date("2024-05-12"), hours, cal, time.Monday)
+	if len(weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(weeks))
+	}
+
+	first, second := weeks[0], weeks[1]
+	if !approx(first.Expected, 16) || !approx(first.Delta, 8) {
+		t.Errorf("first week = %+v, want 16h expected (Thursday and Friday) and +8", first)
+	}
+	if !approx(second.Expected, 22) || second.Holidays != 1 || !approx(second.Delta, 0) {
+		t.Errorf("second week = %+v, want 22h expected with one holiday", second)
+	}
+	if !approx(second.Balance, 8) {
+		t.Errorf("balance = %v, want 8", second.Balance)
+	}
+}
+
+func TestAccumulateAcrossYearBoundary(t *testing.T) {
+	// 2024-12-30 (Monday) belongs to 2025-W01
+	cal := calendar.Default()
+	cal.Vacation = map[string]string{"2025-W01": ""}
+	weeks := Accumulate(date("2024-12-23"), date("2025-01-05"), map[string]float64{}, cal, time.Monday)
+	if len(weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(weeks))
+	}
+	if weeks[0].ISOWeek != "2024-W52" || weeks[1].ISOWeek != "2025-W01" {
+		t.Errorf("weeks = %s, %s", weeks[0].ISOWeek, weeks[1].ISOWeek)
+	}
+	if !approx(weeks[1].Balance, -40) {
+		t.Errorf("balance = %v, want -40 (only the first week owed)", weeks[1].Balance)
+	}
+}
+
+func TestAccumulateSundayWeeksAcrossYearBoundary(t *testing.T) {
+	// Sunday 2024-12-29 to Saturday 2025-01-04 is mostly 2025-W01
+	cal := calendar.Default()
+	cal.Vacation = map[string]string{"2025-W01": ""}
+	hours := map[string]float64{"2024-12-28": 3, "2024-12-29": 2, "2025-01-04": 1}
+	weeks := Accumulate(date("2024-12-22"), date("2025-01-11"), hours, cal, time.Sunday)
+	if len(weeks) != 3 {
+		t.Fatalf("got %d weeks, want 3", len(weeks))
+	}
+
+	want := []struct {
+		name, start, end string
+		hours            float64
+	}{
+		{"2024-W52", "2024-12-22", "2024-12-28", 3},
+		{"2025-W01", "2024-12-29", "2025-01-04", 3},
+		{"2025-W02", "2025-01-05", "2025-01-11", 0},
+	}
+	for i, w := range want {
+		week := weeks[i]
+		if week.ISOWeek != w.name || dates.Format(week.Start) != w.start || dates.Format(week.End) != w.end || !approx(week.Hours, w.hours) {
+			t.Errorf("week %d = %s %s..%s %.2fh, want %s %s..%s %.2fh", i, week.ISOWeek,
+				dates.Format(week.Start), dates.Format(week.End), week.Hours, w.name, w.start, w.end, w.hours)
+		}
+	}
+	if !weeks[1].Vacation || weeks[1].Expected != 0 {
+		t.Errorf("2025-W01 should be a vacation week: %+v", weeks[1])
+	}
+}
+
+func TestAccumulateEmptyRange(t *testing.T) {
+	if weeks := Accumulate(date("2024-04-10"), date("2024-04-09"), nil, nil, time.Monday); weeks != nil {
+		t.Errorf("got %d weeks for an inverted range", len(weeks))
+	}
+}
@@ -0,0 +1,105 @@
+// Package forecast projects the end-of-week total from the hours logged so
+// far and the pace of recent weeks.
+//
+// Weeks begin on Input.WeekStart, like the flex balance. The pace is
+// the average of the hours logged on the workdays of the previous weeks, so
+// holidays, vacation and days off neither count as days nor add hours.
+// Without any hours in those weeks, each day is expected to reach its
+// scheduled hours instead. Today counts as a remaining day: it is expected
+// to reach the pace, or stay where it is if it already has.
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// Input is what a forecast is based on
+type Input struct {
+	Today     time.Time
+	WeekStart time.Weekday
+	Hours     map[string]float64 // logged hours by YYYY-MM-DD
+	Calendar  *calendar.Calendar
+	Weeks     int     // previous weeks the pace is averaged over
+	Goal      float64 // hours to reach this week; the calendar's expected hours if zero
+}
+
+// Forecast is the projection for the week containing Input.Today
+type Forecast struct {
+	Start     time.Time // first day of the week
+	Goal      float64
+	Logged    float64 // hours logged this week, including today
+	Today     float64 // hours logged today
+	Pace      float64 // average hours per workday in the previous weeks
+	PaceDays  int     // workdays the pace is averaged over
+	Scheduled bool    // nothing logged in the previous weeks; the schedule is the pace
+	Remaining int     // workdays left, including today
+	Projected float64
+	Needed    float64 // hours per remaining workday to reach the goal
+}
+
+// Short returns how many hours the projection falls short of the goal, or a
+// negative number if it exceeds it
+func (f Forecast) Short() float64 {
+	return f.Goal - f.Projected
+}
+
+// Project computes the forecast for the week containing in.Today
+func Project(in Input) Forecast {
+	today := dates.StartOfDay(in.Today)
+	cal := in.Calendar
+	if cal == nil {
+		cal = calendar.Default()
+	}
+
+	f := Forecast{Start: dates.StartOfWeek(today, in.WeekStart)}
+	end := f.Start.AddDate(0, 0, 6)
+
+	f.Goal = in.Goal
+	if f.Goal == 0 {
+		f.Goal = cal.ExpectedBetween(f.Start, end)
+	}
+
+	for day := f.Start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		f.Logged += in.Hours[dates.Format(day)]
+	}
+	f.Today = in.Hours[dates.Format(today)]
+
+	total := 0.0
+	for day := f.Start.AddDate(0, 0, -7*in.Weeks); day.Before(f.Start); day = day.AddDate(0, 0, 1) {
+		if cal.IsWorkday(day) {
+			total += in.Hours[dates.Format(day)]
+			f.PaceDays++
+		}
+	}
+	if total > 0 {
+		f.Pace = total / float64(f.PaceDays)
+	} else {
+		f.Scheduled = true
+	}
+
+	f.Projected = f.Logged
+	for day := today; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !cal.IsWorkday(day) {
+			continue
+		}
+		f.Remaining++
+
+		pace := f.Pace
+		if f.Scheduled {
+			pace = cal.Expected(day)
+		}
+		if day.Equal(today) {
+			pace = math.Max(pace-f.Today, 0)
+		}
+		f.Projected += pace
+	}
+
+	if f.Remaining > 0 {
+		f.Needed = math.Max(f.Goal-(f.Logged-f.Today), 0) / float64(f.Remaining)
+	}
+	return f
+}
@@ -0,0 +1,136 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/calendar"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+func day(value string) time.Time {
+	t, _ := time.ParseInLocation(dates.Layout, value, time.UTC)
+	return t
+}
+
+// history logs hours on every weekday of the two weeks before 2024-04-08
+func history(hours float64) map[string]float64 {
+	logged := map[string]float64{}
+	for d := day("2024-03-25"); d.Before(day("2024-04-08")); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			logged[dates.Format(d)] = hours
+		}
+	}
+	return logged
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestProjectMidWeek(t *testing.T) {
+	hours := history(7)
+	hours["2024-04-08"] = 8
+	hours["2024-04-09"] = 8
+	hours["2024-04-10"] = 3 // today, still working
+
+	f := Project(Input{Today: day("2024-04-10"), WeekStart: time.Monday, Hours: hours, Weeks: 2})
+
+	if f.Goal != 40 || f.Logged != 19 || f.Today != 3 {
+		t.Fatalf("Goal/Logged/Today = %v/%v/%v", f.Goal, f.Logged, f.Today)
+	}
+	if f.Pace != 7 || f.PaceDays != 10 || f.Scheduled {
+		t.Errorf("Pace = %v over %d days (scheduled %v)", f.Pace, f.PaceDays, f.Scheduled)
+	}
+	if f.Remaining != 3 {
+		t.Errorf("Remaining = %d, want 3 (Wed-Fri)", f.Remaining)
+	}
+	// 19 logged + 4 more today + 7 on Thu and Fri
+	if !near(f.Projected, 37) || !near(f.Short(), 3) {
+		t.Errorf("Projected = %v, Short = %v", f.Projected, f.Short())
+	}
+	// 40 - 16 logged before today, over Wed-Fri
+	if !near(f.Needed, 8) {
+		t.Errorf("Needed = %v, want 8", f.Needed)
+	}
+}
+
+func TestProjectFirstWorkday(t *testing.T) {
+	f := Project(Input{Today: day("2024-04-08"), WeekStart: time.Monday, Hours: history(8), Weeks: 2})
+
+	if f.Logged != 0 || f.Remaining != 5 {
+		t.Errorf("Logged = %v, Remaining = %d", f.Logged, f.Remaining)
+	}
+	if !near(f.Projected, 40) || !near(f.Needed, 8) {
+		t.Errorf("Projected = %v, Needed = %v", f.Projected, f.Needed)
+	}
+}
+
+func TestProjectExcludesHolidays(t *testing.T) {
+	cal := calendar.Default()
+	cal.Holidays = map[string]string{"2024-04-01": "Easter Monday", "2024-04-12": "Some holiday"}
+	hours := history(6)
+	delete(hours, "2024-04-01")
+
+	f := Project(Input{Today: day("2024-04-08"), WeekStart: time.Monday, Hours: hours, Calendar: cal, Weeks: 2})
+
+	if f.PaceDays != 9 || f.Pace != 6 {
+		t.Errorf("Pace = %v over %d days, want 6 over 9", f.Pace, f.PaceDays)
+	}
+	if f.Goal != 32 || f.Remaining != 4 {
+		t.Errorf("Goal = %v, Remaining = %d; want 32 and 4", f.Goal, f.Remaining)
+	}
+	if !near(f.Projected, 24) {
+		t.Errorf("Projected = %v, want 24", f.Projected)
+	}
+}
+
+func TestProjectWithoutHistory(t *testing.T) {
+	f := Project(Input{Today: day("2024-04-09"), WeekStart: time.Monday, Hours: map[string]float64{"2024-04-08": 5}, Weeks: 4})
+
+	if !f.Scheduled || f.Pace != 0 {
+		t.Errorf("Scheduled = %v, Pace = %v", f.Scheduled, f.Pace)
+	}
+	if !near(f.Projected, 37) {
+		t.Errorf("Projected = %v, want 5 + 4*8", f.Projected)
+	}
+}
+
+func TestProjectTodayAheadOfPace(t *testing.T) {
+	hours := history(6)
+	hours["2024-04-12"] = 10
+
+	f := Project(Input{Today: day("2024-04-12"), WeekStart: time.Monday, Hours: hours, Weeks: 2, Goal: 30})
+
+	if f.Goal != 30 || f.Remaining != 1 {
+		t.Errorf("Goal = %v, Remaining = %d", f.Goal, f.Remaining)
+	}
+	if !near(f.Projected, 10) {
+		t.Errorf("Projected = %v, today shouldn't fall back to the pace", f.Projected)
+	}
+	if !near(f.Needed, 30) {
+		t.Errorf("Needed = %v, want 30", f.Needed)
+	}
+}
+
+func TestProjectWeekend(t *testing.T) {
+	hours := history(8)
+	hours["2024-04-08"] = 40
+
+	f := Project(Input{Today: day("2024-04-13"), WeekStart: time.Monday, Hours: hours, Weeks: 2})
+
+	if f.Remaining != 0 || f.Needed != 0 || f.Projected != 40 {
+		t.Errorf("Remaining = %d, Needed = %v, Projected = %v", f.Remaining, f.Needed, f.Projected)
+	}
+}
+
+func TestProjectSundayWeeks(t *testing.T) {
+	hours := history(8)
+	hours["2024-04-07"] = 2 // Sunday, part of this week when weeks begin on Sunday
+	hours["2024-04-08"] = 8
+
+	f := Project(Input{Today: day("2024-04-08"), WeekStart: time.Sunday, Hours: hours, Weeks: 2})
+
+	if !f.Start.Equal(day("2024-04-07")) || f.Logged != 10 {
+		t.Errorf("Start = %s, Logged = %v; want the week from Sunday with 10h", dates.Format(f.Start), f.Logged)
+	}
+}
@@ -0,0 +1,133 @@
+// Package help provides the task-oriented guides shown by
+// "timetracker help <topic>".
+package help
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed topics/*.txt
+var files embed.FS
+
+// ErrUnknownTopic is returned when no topic has the requested name
+var ErrUnknownTopic = errors.New("unknown help topic")
+
+// Topic describes a guide. Its text lives in topics/<Name>.txt.
+type Topic struct {
+	Name    string
+	Summary string
+}
+
+// Topics lists every guide in display order
+var Topics = []Topic{
+	{Name: "getting-started", Summary: "Log in and learn the everyday commands"},
+	{Name: "automation", Summary: "Scheduled syncs, health checks and bulk imports in scripts"},
+	{Name: "invoicing", Summary: "Check billable hours for a billing period"},
+	{Name: "offline-use", Summary: "Pending changes, local data and synced folders"},
+	{Name: "team-setup", Summary: "Shared servers, organisation defaults and managed machines"},
+}
+
+// Data holds the user's actual values substituted into topic examples
+type Data struct {
+	APIURL     string
+	ConfigPath string
+	ConfigDir  string
+}
+
+// Lookup returns the topic with the given name
+func Lookup(name string) (Topic, bool) {
+	for _, topic := range Topics {
+		if topic.Name == name {
+			return topic, true
+		}
+	}
+	return Topic{}, false
+}
+
+// Render returns a topic's text with data substituted. Lines starting with
+// "# " are headings and are passed through heading for styling.
+func Render(name string, data Data, heading func(string) string) (string, error) {
+	if _, ok := Lookup(name); !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownTopic, name)
+	}
+
+	raw, err := files.ReadFile("topics/" + name + ".txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to read topic %s: %w", name, err)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse topic %s: %w", name, err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render topic %s: %w", name, err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "# ") {
+			lines[i] = heading(strings.TrimPrefix(line, "# "))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Suggest returns the topic names close to name: those starting with it,
+// sharing its first four letters, or within two edits of it
+func Suggest(name string) []string {
+	var matches []string
+	for _, topic := range Topics {
+		if strings.HasPrefix(topic.Name, name) || commonPrefix(name, topic.Name) >= 4 ||
+			distance(name, topic.Name) <= 2 {
+			matches = append(matches, topic.Name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// distance is the Levenshtein edit distance between a and b
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j] + 1
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+			if prev[j-1]+cost < curr[j] {
+				curr[j] = prev[j-1] + cost
+			}
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,75 @@
+package help
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEveryTopicFileIsRegistered(t *testing.T) {
+	paths, err := fs.Glob(files, "topics/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != len(Topics) {
+		t.Errorf("%d topic files but %d registered topics", len(paths), len(Topics))
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "topics/"), ".txt")
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("topic file %s is not registered", path)
+		}
+	}
+}
+
+func TestRenderSubstitutesData(t *testing.T) {
+	data := Data{APIURL: "https://tt.example.com", ConfigPath: "/home/me/.timetracker/config.yaml", ConfigDir: "/home/me/.timetracker"}
+	heading := func(s string) string { return "<" + s + ">" }
+
+	for _, topic := range Topics {
+		text, err := Render(topic.Name, data, heading)
+		if err != nil {
+			t.Errorf("%s: %v", topic.Name, err)
+			continue
+		}
+		if strings.Contains(text, "{{") {
+			t.Errorf("%s: unrendered template action", topic.Name)
+		}
+		if strings.Contains(text, "\n# ") || strings.HasPrefix(text, "# ") {
+			t.Errorf("%s: heading not styled", topic.Name)
+		}
+	}
+
+	text, _ := Render("getting-started", data, heading)
+	if !strings.Contains(text, data.APIURL) || !strings.Contains(text, data.ConfigPath) {
+		t.Errorf("getting-started does not mention the user's API URL and config path:\n%s", text)
+	}
+}
+
+func TestRenderUnknownTopic(t *testing.T) {
+	if _, err := Render("nope", Data{}, func(s string) string { return s }); !errors.Is(err, ErrUnknownTopic) {
+		t.Errorf("err = %v, want ErrUnknownTopic", err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"automaton", []string{"automation"}},
+		{"invoice", []string{"invoicing"}},
+		{"team", []string{"team-setup"}},
+		{"getting-startd", []string{"getting-started"}},
+		{"export", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Suggest(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package httpcache keeps the bodies of GET responses together with their
+// ETag and Last-Modified validators, so a later request for the same URL can
+// ask the server whether anything changed and reuse the body on a 304.
+// Every URL has a file of its own in the cache directory.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/localfile"
+)
+
+// Format identifies cached responses in their envelope
+const Format = "response-cache"
+
+func init() {
+	localfile.Register(localfile.Format{Name: Format, Version: 1})
+}
+
+// Entry is a cached response
+type Entry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Body         string    `json:"body"`
+	StoredAt     time.Time `json:"storedAt"`
+}
+
+// Cache is a directory of cached responses
+type Cache struct {
+	dir string
+}
+
+// New returns the cache kept in dir, which is created on the first Put
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the directory the cache is kept in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get returns the cached response for url. Missing and unreadable entries
+// are both a miss.
+func (c *Cache) Get(url string) (*Entry, bool) {
+	var entry Entry
+	if _, err := localfile.Read(c.path(url), Format, &entry); err != nil || entry.URL != url {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put stores entry, replacing any cached response for its URL. The file is
+// replaced atomically, so concurrent runs never see a partial one.
+func (c *Cache) Put(entry Entry) error {
+	return localfile.Write(c.path(entry.URL), Format, entry)
+}
+
+// Clear deletes every cached response and returns how many there were
+func (c *Cache) Clear() (int, error) {
+	files, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", c.dir, err)
+	}
+
+	removed := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, file.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("failed to remove %s: %w", file.Name(), err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// path names the file of url by its hash, which keeps tokens and other
+// query parameters out of file names
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,60 @@
+package httpcache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPutGetClear(t *testing.T) {
+	cache := New(t.TempDir())
+	url := "http://localhost:3000/api/entries/summary/today?date=2024-03-04"
+
+	if _, ok := cache.Get(url); ok {
+		t.Fatal("hit in an empty cache")
+	}
+	stored := Entry{URL: url, ETag: `"abc"`, Body: `{"totalHours":7.5}`, StoredAt: time.Date(2024, 3, 4, 9, 42, 0, 0, time.UTC)}
+	if err := cache.Put(stored); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, ok := cache.Get(url)
+	if !ok || *got != stored {
+		t.Errorf("Get = %+v, %v; want %+v", got, ok, stored)
+	}
+	if _, ok := cache.Get(url + "&x=1"); ok {
+		t.Error("hit for another URL")
+	}
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 1 {
+		t.Errorf("Clear = %d, %v; want 1", removed, err)
+	}
+	if _, ok := cache.Get(url); ok {
+		t.Error("hit after Clear")
+	}
+}
+
+func TestConcurrentPuts(t *testing.T) {
+	cache := New(t.TempDir())
+	url := "http://localhost:3000/api/stats"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"writer":%d}`, i)
+			if err := cache.Put(Entry{URL: url, ETag: body, Body: body}); err != nil {
+				t.Errorf("Put: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Whichever write won, the entry is whole
+	got, ok := cache.Get(url)
+	if !ok || got.ETag != got.Body {
+		t.Errorf("Get = %+v, %v; want one writer's entry", got, ok)
+	}
+}
@@ -0,0 +1,101 @@
+// Package ical writes events as an RFC 5545 iCalendar file.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest a content line may be before folding
+const maxLineOctets = 75
+
+// Event is one VEVENT. All-day events only use the dates of Start and End,
+// with End being the day after the last day.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+// Write renders the events as a VCALENDAR. stamp is used as every event's
+// DTSTAMP.
+func Write(w io.Writer, events []Event, stamp time.Time) error {
+	out := bufio.NewWriter(w)
+	line := func(name, value string) {
+		out.WriteString(fold(name + ":" + value))
+		out.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//TimeTracker//CLI//EN")
+	line("CALSCALE", "GREGORIAN")
+	for _, event := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(event.UID))
+		line("DTSTAMP", utc(stamp))
+		if event.AllDay {
+			line("DTSTART;VALUE=DATE", event.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE", event.End.Format("20060102"))
+		} else {
+			line("DTSTART", utc(event.Start))
+			line("DTEND", utc(event.End))
+		}
+		line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		// Tracked time shouldn't block the calendar
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	return out.Flush()
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes a TEXT value
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+// fold splits a content line into lines of at most 75 octets, continuing
+// with a space, without splitting UTF-8 characters
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var folded strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		// Back up to the start of a UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with the space, which counts
+		limit = maxLineOctets - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
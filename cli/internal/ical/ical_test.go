@@ -0,0 +1,75 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+	events := []Event{
+		{
+			UID:         "entry-42@timetracker",
+			Summary:     "WEKA",
+			Description: "Review, planning; notes\nline two",
+			Start:       time.Date(2024, 4, 2, 9, 0, 0, 0, berlin),
+			End:         time.Date(2024, 4, 2, 10, 30, 0, 0, berlin),
+		},
+		{
+			UID:     "entry-43@timetracker",
+			Summary: "ACME (1.50h)",
+			Start:   time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC),
+			AllDay:  true,
+		},
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, events, time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:entry-42@timetracker\r\nDTSTAMP:20240405T120000Z\r\nDTSTART:20240402T070000Z\r\nDTEND:20240402T083000Z\r\nSUMMARY:WEKA\r\n",
+		`DESCRIPTION:Review\, planning\; notes\nline two` + "\r\n",
+		"DTSTART;VALUE=DATE:20240402\r\nDTEND;VALUE=DATE:20240403\r\nSUMMARY:ACME (1.50h)\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "BEGIN:VEVENT") != 2 {
+		t.Errorf("want 2 events:\n%s", got)
+	}
+}
+
+func TestFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ä", 60)
+	folded := fold(line)
+
+	parts := strings.Split(folded, "\r\n")
+	if len(parts) < 2 {
+		t.Fatalf("fold(%d octets) didn't fold", len(line))
+	}
+	for i, part := range parts {
+		if len(part) > maxLineOctets {
+			t.Errorf("line %d is %d octets", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("continuation line %d doesn't start with a space", i)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(folded, "\r\n ", "")
+	if unfolded != line {
+		t.Errorf("unfolding gives %q, want %q", unfolded, line)
+	}
+	if short := "SUMMARY:WEKA"; fold(short) != short {
+		t.Errorf("fold(%q) changed a short line", short)
+	}
+}
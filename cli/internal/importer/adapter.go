@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Adapter maps a provider's CSV export format onto rows
+type Adapter interface {
+	// Source is the entry source the rows are imported as (e.g. "TOGGL")
+	Source() string
+	// Required lists the (lowercase) header names the format must contain
+	Required() []string
+	// Convert turns one CSV record into a row
+	Convert(record Record) (Row, error)
+}
+
+// Record gives adapters access to a CSV record's fields by header name
+type Record struct {
+	columns map[string]int
+	values  []string
+}
+
+// Get returns the trimmed value of the named column, or "" if absent
+func (r Record) Get(name string) string {
+	i, ok := r.columns[strings.ToLower(name)]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+// Has reports whether the named column exists in the file
+func (r Record) Has(name string) bool {
+	_, ok := r.columns[strings.ToLower(name)]
+	return ok
+}
+
+// RowError describes a row that could not be converted
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Parse reads a CSV export with the given adapter. Rows that cannot be
+// converted, or that have no duration, are returned as rejected instead of
+// aborting the whole file. Only an unreadable file or header is fatal.
+func Parse(r io.Reader, adapter Adapter) (rows []Row, rejected []RowError, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// Exports may start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range adapter.Required() {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("unexpected CSV format: missing %q column", required)
+		}
+	}
+
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rejected = append(rejected, RowError{Line: line, Err: err})
+			continue
+		}
+
+		row, err := adapter.Convert(Record{columns: columns, values: values})
+		if err != nil {
+			rejected = append(rejected, RowError{Line: line, Err: err})
+			continue
+		}
+		if row.Hours <= 0 {
+			rejected = append(rejected, RowError{Line: line, Err: fmt.Errorf("zero duration")})
+			continue
+		}
+
+		row.Line = line
+		rows = append(rows, row)
+	}
+
+	return rows, rejected, nil
+}
+
+// parseHours parses HH:MM:SS, HH:MM or decimal hours (with . or , separator)
+func parseHours(value string) (float64, error) {
+	if !strings.Contains(value, ":") {
+		hours, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return hours, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var hours float64
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		hours += float64(n) / []float64{1, 60, 3600}[i]
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,128 @@
+// Package importer converts provider CSV exports into time entries and
+// uploads them to the API.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+// BatchSize is the number of entries sent per upload request
+const BatchSize = 50
+
+// Row is a single parsed CSV row ready to be imported
+type Row struct {
+	Line        int
+	Date        string // YYYY-MM-DD
+	StartTime   string // HH:mm, empty if unknown
+	Hours       float64
+	Project     string
+	Description string
+}
+
+// Hash identifies a row by its content (date, project, description, duration)
+// so the same work is never imported twice, regardless of which file it came from
+func Hash(date, project, description string, hours float64) string {
+	minutes := int(math.Round(hours * 60))
+	key := strings.Join([]string{
+		date,
+		strings.TrimSpace(project),
+		strings.TrimSpace(description),
+		fmt.Sprint(minutes),
+	}, "|")
+
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Hash returns the row's content hash
+func (r Row) Hash() string {
+	return Hash(r.Date, r.Project, r.Description, r.Hours)
+}
+
+// Dedupe splits rows into those not yet on the server and those that are.
+// Duplicate rows within the file itself are also skipped.
+func Dedupe(rows []Row, existing []api.Entry) (fresh, skipped []Row) {
+	seen := make(map[string]bool, len(existing))
+	for _, entry := range existing {
+		seen[Hash(entry.Day(), entry.Project, entry.Description, entry.Duration)] = true
+	}
+
+	for _, row := range rows {
+		hash := row.Hash()
+		if seen[hash] {
+			skipped = append(skipped, row)
+			continue
+		}
+		seen[hash] = true
+		fresh = append(fresh, row)
+	}
+
+	return fresh, skipped
+}
+
+// DateRange returns the earliest and latest date among rows
+func DateRange(rows []Row) (from, to string) {
+	for _, row := range rows {
+		if from == "" || row.Date < from {
+			from = row.Date
+		}
+		if to == "" || row.Date > to {
+			to = row.Date
+		}
+	}
+	return from, to
+}
+
+// Upload sends rows to the server in batches of BatchSize, calling progress
+// after each batch. A failed batch counts all its rows as failed and the
+// upload continues with the next batch.
+func Upload(client *api.Client, source string, rows []Row, progress func(done, total int)) (*api.ImportResponse, error) {
+	result := &api.ImportResponse{}
+	var lastErr error
+
+	for start := 0; start < len(rows); start += BatchSize {
+		end := start + BatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		req := api.ImportRequest{Entries: make([]api.ImportEntry, 0, end-start)}
+		for _, row := range rows[start:end] {
+			req.Entries = append(req.Entries, api.ImportEntry{
+				Source:      source,
+				ExternalID:  row.Hash(),
+				Date:        row.Date,
+				StartTime:   row.StartTime,
+				Duration:    row.Hours,
+				Project:     row.Project,
+				Description: row.Description,
+			})
+		}
+
+		var resp api.ImportResponse
+		if err := client.Post("/api/entries/batch", req, &resp); err != nil {
+			result.Failed += end - start
+			lastErr = err
+		} else {
+			result.Imported += resp.Imported
+			result.Skipped += resp.Skipped
+			result.Failed += resp.Failed
+		}
+
+		if progress != nil {
+			progress(end, len(rows))
+		}
+	}
+
+	if result.Imported == 0 && lastErr != nil {
+		return result, lastErr
+	}
+
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+const togglCSV = "\ufeffUser,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags\n" +
+	"Viktor,v@example.com,Acme,forHim,,Code review,Yes,2024-03-05,09:00:00,2024-03-05,10:30:00,01:30:00,\n" +
+	"Viktor,v@example.com,Acme,WEKA-199,,\"Migration, part 2\",No,2024-03-06,13:15:00,2024-03-06,13:30:00,00:15:00,\n"
+
+func TestParseToggl(t *testing.T) {
+	rows, rejected, err := Parse(strings.NewReader(togglCSV), Toggl{})
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(rejected) != 0 {
+		t.Fatalf("unexpected rejected rows: %v", rejected)
+	}
+
+	want := []Row{
+		{Line: 2, Date: "2024-03-05", StartTime: "09:00", Hours: 1.5, Project: "forHim", Description: "Code review"},
+		{Line: 3, Date: "2024-03-06", StartTime: "13:15", Hours: 0.25, Project: "WEKA-199", Description: "Migration, part 2"},
+	}
+
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestParseTogglMissingColumns(t *testing.T) {
+	if _, _, err := Parse(strings.NewReader("Project,Description\nfoo,bar\n"), Toggl{}); err == nil {
+		t.Fatal("expected error for CSV without Toggl columns")
+	}
+}
+
+const tempoCSV = "Issue Key,Issue summary,Hours,Work date,Work Description\n" +
+	"WEKA-199,Migration,2.5,2024-03-05 09:30,Schema migration\n" +
+	"WEKA-200,Review,\"1,25\",05/Mar/24,Code review\n" +
+	"WEKA-201,Broken,1,not a date,Oops\n" +
+	"WEKA-202,Empty,0,2024-03-06,Nothing\n"
+
+func TestParseTempoCollectsBadRows(t *testing.T) {
+	rows, rejected, err := Parse(strings.NewReader(tempoCSV), Tempo{})
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	want := []Row{
+		{Line: 2, Date: "2024-03-05", StartTime: "09:30", Hours: 2.5, Project: "WEKA-199", Description: "Schema migration"},
+		{Line: 3, Date: "2024-03-05", Hours: 1.25, Project: "WEKA-200", Description: "Code review"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	if len(rejected) != 2 || rejected[0].Line != 4 || rejected[1].Line != 5 {
+		t.Errorf("rejected = %v, want lines 4 and 5", rejected)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	rows := []Row{
+		{Date: "2024-03-05", Hours: 1.5, Project: "forHim", Description: "Code review"},
+		{Date: "2024-03-05", Hours: 1.5, Project: "forHim", Description: "Code review"},
+		{Date: "2024-03-06", Hours: 0.25, Project: "WEKA-199", Description: "Migration"},
+	}
+	existing := []api.Entry{
+		// Server durations may differ by floating point noise
+		{Date: "2024-03-06", Duration: 0.2500001, Project: "WEKA-199", Description: "Migration"},
+	}
+
+	fresh, skipped := Dedupe(rows, existing)
+	if len(fresh) != 1 || fresh[0].Date != "2024-03-05" {
+		t.Errorf("fresh = %+v, want only the 2024-03-05 row", fresh)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("skipped %d rows, want 2", len(skipped))
+	}
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"fmt"
+	"time"
+)
+
+// tempoDateLayouts are the "Work date" formats seen in Tempo worklog exports
+var tempoDateLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06",
+	"02/Jan/2006",
+	"02.01.2006 15:04",
+	"02.01.2006",
+}
+
+// Tempo reads Tempo worklog CSV exports
+type Tempo struct{}
+
+// Source implements Adapter
+func (Tempo) Source() string { return "TEMPO" }
+
+// Required implements Adapter
+func (Tempo) Required() []string { return []string{"issue key", "hours", "work date"} }
+
+// Convert implements Adapter
+func (Tempo) Convert(record Record) (Row, error) {
+	value := record.Get("Work date")
+
+	var workDate time.Time
+	var err error
+	for _, layout := range tempoDateLayouts {
+		if workDate, err = time.Parse(layout, value); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return Row{}, fmt.Errorf("invalid work date %q", value)
+	}
+
+	hours, err := parseHours(record.Get("Hours"))
+	if err != nil {
+		return Row{}, err
+	}
+
+	// Only keep a start time when the export actually contains one
+	var startTime string
+	if workDate.Hour() != 0 || workDate.Minute() != 0 {
+		startTime = workDate.Format("15:04")
+	}
+
+	return Row{
+		Date:        workDate.Format("2006-01-02"),
+		StartTime:   startTime,
+		Hours:       hours,
+		Project:     record.Get("Issue Key"),
+		Description: record.Get("Work Description"),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package importer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Toggl reads Toggl "Detailed report" CSV exports
+type Toggl struct{}
+
+// Source implements Adapter
+func (Toggl) Source() string { return "TOGGL" }
+
+// Required implements Adapter
+func (Toggl) Required() []string { return []string{"start date", "duration"} }
+
+// Convert implements Adapter
+func (Toggl) Convert(record Record) (Row, error) {
+	date, err := time.Parse("2006-01-02", record.Get("Start date"))
+	if err != nil {
+		return Row{}, fmt.Errorf("invalid start date %q", record.Get("Start date"))
+	}
+
+	hours, err := parseHours(record.Get("Duration"))
+	if err != nil {
+		return Row{}, err
+	}
+
+	startTime := record.Get("Start time")
+	if len(startTime) >= 5 {
+		startTime = startTime[:5] // HH:mm:ss -> HH:mm
+	}
+
+	return Row{
+		Date:        date.Format("2006-01-02"),
+		StartTime:   startTime,
+		Hours:       hours,
+		Project:     record.Get("Project"),
+		Description: record.Get("Description"),
+	}, nil
+}
@@ -0,0 +1,135 @@
+// Package lint checks time entries for problems worth fixing before they
+// are invoiced. Each rule is a small function over one entry; add a rule by
+// appending it to Rules.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+// Severity says whether a finding should block (error) or only be reviewed
+// (warning)
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Rule is one check. Check returns a message for entries that break the
+// rule, or "" for entries that pass.
+type Rule struct {
+	Name        string
+	Severity    Severity
+	Description string
+	Check       func(entry api.Entry) string
+}
+
+// Finding is an entry breaking a rule
+type Finding struct {
+	Rule    Rule
+	Entry   api.Entry
+	Message string
+}
+
+const (
+	// maxHours is the longest plausible single entry
+	maxHours = 12.0
+	// minHours catches rounding leftovers and accidental start/stop clicks
+	// such as 0.01h rows
+	minHours = 0.02
+)
+
+// issueOnly matches descriptions made only of issue references, such as
+// "WEKA-123", "#42" or "WEKA-1, WEKA-2"
+var issueOnly = regexp.MustCompile(`^(#?[A-Za-z][A-Za-z0-9]*-\d+|#\d+)([\s,;/&+]+(#?[A-Za-z][A-Za-z0-9]*-\d+|#\d+))*$`)
+
+// Rules are all known rules, in the order findings are reported
+var Rules = []Rule{
+	{
+		Name:        "empty-description",
+		Severity:    Error,
+		Description: "entries without a description",
+		Check: func(entry api.Entry) string {
+			if strings.TrimSpace(entry.Description) == "" {
+				return "no description"
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "long-duration",
+		Severity:    Error,
+		Description: fmt.Sprintf("entries longer than %.0fh", maxHours),
+		Check: func(entry api.Entry) string {
+			if entry.Duration > maxHours {
+				return fmt.Sprintf("%s in one entry", display.FormatDuration(entry.Duration))
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "tiny-duration",
+		Severity:    Warning,
+		Description: fmt.Sprintf("entries shorter than %.2fh", minHours),
+		Check: func(entry api.Entry) string {
+			if entry.Duration < minHours {
+				return fmt.Sprintf("only %s", display.FormatDuration(entry.Duration))
+			}
+			return ""
+		},
+	},
+	{
+		Name:        "issue-only-description",
+		Severity:    Warning,
+		Description: "descriptions that are only issue numbers",
+		Check: func(entry api.Entry) string {
+			if issueOnly.MatchString(strings.TrimSpace(entry.Description)) {
+				return fmt.Sprintf("%q names an issue but not the work", entry.Description)
+			}
+			return ""
+		},
+	},
+}
+
+// Lookup returns the rule with the given name
+func Lookup(name string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Run checks every entry against the rules not in disabled and returns the
+// findings grouped by rule, in rule order
+func Run(entries []api.Entry, disabled map[string]bool) []Finding {
+	var findings []Finding
+	for _, rule := range Rules {
+		if disabled[rule.Name] {
+			continue
+		}
+		for _, entry := range entries {
+			if message := rule.Check(entry); message != "" {
+				findings = append(findings, Finding{Rule: rule, Entry: entry, Message: message})
+			}
+		}
+	}
+	return findings
+}
+
+// HasErrors reports whether any finding has error severity
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Rule.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		entry api.Entry
+		want  []string
+	}{
+		{api.Entry{Description: "Schema migration", Duration: 2}, nil},
+		{api.Entry{Description: "  ", Duration: 2}, []string{"empty-description"}},
+		{api.Entry{Description: "On call", Duration: 14}, []string{"long-duration"}},
+		{api.Entry{Description: "Oops", Duration: 0.01}, []string{"tiny-duration"}},
+		{api.Entry{Description: "WEKA-123", Duration: 1}, []string{"issue-only-description"}},
+		{api.Entry{Description: "#42", Duration: 1}, []string{"issue-only-description"}},
+		{api.Entry{Description: "WEKA-1, WEKA-2", Duration: 1}, []string{"issue-only-description"}},
+		{api.Entry{Description: "WEKA-123 fix login redirect", Duration: 1}, nil},
+		{api.Entry{Description: "", Duration: 0}, []string{"empty-description", "tiny-duration"}},
+	}
+
+	for _, tt := range tests {
+		findings := Run([]api.Entry{tt.entry}, nil)
+		var got []string
+		for _, finding := range findings {
+			got = append(got, finding.Rule.Name)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%+v: rules %v, want %v", tt.entry, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%+v: rules %v, want %v", tt.entry, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRunDisabledAndSeverity(t *testing.T) {
+	entries := []api.Entry{
+		{ID: "1", Description: "", Duration: 1},
+		{ID: "2", Description: "WEKA-9", Duration: 1},
+	}
+
+	findings := Run(entries, map[string]bool{"empty-description": true})
+	if len(findings) != 1 || findings[0].Entry.ID != "2" {
+		t.Fatalf("findings = %+v, want only the issue-only entry", findings)
+	}
+	if HasErrors(findings) {
+		t.Error("HasErrors() = true for warnings only")
+	}
+
+	if !HasErrors(Run(entries, nil)) {
+		t.Error("HasErrors() = false with an empty description")
+	}
+	if _, ok := Lookup("tiny-duration"); !ok {
+		t.Error("Lookup(tiny-duration) failed")
+	}
+}
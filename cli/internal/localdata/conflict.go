@@ -0,0 +1,95 @@
+package localdata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// conflictPatterns match the names file-sync tools give conflicted copies.
+// The first group is the original base name, the second its extension.
+var conflictPatterns = []*regexp.Regexp{
+	// Dropbox: "notes (Laptop's conflicted copy 2024-03-05).json"
+	regexp.MustCompile(`^(.+?) \([^)]*conflicted copy[^)]*\)(\.[^.]+)?$`),
+	// Syncthing: "notes.sync-conflict-20240305-101112-ABCDEF7.json"
+	regexp.MustCompile(`^(.+?)\.sync-conflict-\d{8}-\d{6}-[A-Z0-9]{7}(\.[^.]+)?$`),
+}
+
+// Conflict is a file together with the conflicted copies a sync tool left next to it
+type Conflict struct {
+	Original string
+	Copies   []string
+}
+
+// registered holds the base names of files that are localdata stores
+var registered = map[string]bool{}
+
+// Register marks a file name in the config directory as a localdata store,
+// making its conflicts mergeable
+func Register(name string) {
+	registered[name] = true
+}
+
+// Mergeable reports whether the conflict is in a registered store
+func (c Conflict) Mergeable() bool {
+	return registered[filepath.Base(c.Original)]
+}
+
+// OriginalName returns the name a conflicted copy was derived from, or
+// "" if name is not a conflicted copy
+func OriginalName(name string) string {
+	for _, pattern := range conflictPatterns {
+		if m := pattern.FindStringSubmatch(name); m != nil {
+			return m[1] + m[2]
+		}
+	}
+	return ""
+}
+
+// FindConflicts lists the conflicted copies in dir, grouped by original file
+func FindConflicts(dir string) ([]Conflict, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	byOriginal := map[string][]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if original := OriginalName(entry.Name()); original != "" {
+			byOriginal[original] = append(byOriginal[original], filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	conflicts := make([]Conflict, 0, len(byOriginal))
+	for original, copies := range byOriginal {
+		sort.Strings(copies)
+		conflicts = append(conflicts, Conflict{Original: filepath.Join(dir, original), Copies: copies})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Original < conflicts[j].Original
+	})
+
+	return conflicts, nil
+}
+
+// Archive moves the conflicted copies into archiveDir/<timestamp>/
+func (c Conflict) Archive(archiveDir string, now time.Time) error {
+	target := filepath.Join(archiveDir, now.Format("20060102-150405"))
+	if err := os.MkdirAll(target, 0700); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	for _, copyPath := range c.Copies {
+		if err := os.Rename(copyPath, filepath.Join(target, filepath.Base(copyPath))); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", filepath.Base(copyPath), err)
+		}
+	}
+
+	return nil
+}
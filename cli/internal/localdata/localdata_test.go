@@ -0,0 +1,169 @@
+package localdata
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/localfile"
+)
+
+func TestOriginalName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"notes (Viktor's conflicted copy 2024-03-05).json", "notes.json"},
+		{"templates (MacBook Pro's conflicted copy 2024-03-05).yaml", "templates.yaml"},
+		{"notes.sync-conflict-20240305-101112-ABCDEF7.json", "notes.json"},
+		{"notes.json", ""},
+		{"notes (copy).json", ""},
+	}
+
+	for _, tt := range tests {
+		if got := OriginalName(tt.name); got != tt.want {
+			t.Errorf("OriginalName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOpenUpgradesVersionZero(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	if err := os.WriteFile(path, []byte(`{"2024-04-03": "half day - dentist"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 4, 3, 18, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open[string](path)
+	if err != nil {
+		t.Fatalf("Open(): %v", err)
+	}
+	item := store.Items["2024-04-03"]
+	if item.Value != "half day - dentist" || !item.ModifiedAt.Equal(mtime) {
+		t.Fatalf("upgraded item = %+v", item)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+	reopened, err := Open[string](path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, _ := reopened.Get("2024-04-03"); got != "half day - dentist" {
+		t.Fatalf("round trip value = %q", got)
+	}
+	if !reopened.Items["2024-04-03"].ModifiedAt.Equal(mtime) {
+		t.Fatalf("round trip lost timestamp: %v", reopened.Items["2024-04-03"].ModifiedAt)
+	}
+}
+
+func TestOpenRejectsNewerFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.yaml")
+	if err := os.WriteFile(path, []byte("version: 99\nitems: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open[string](path); err == nil {
+		t.Fatal("expected error for newer format version")
+	}
+}
+
+func TestOpenUpgradesUnversionedItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.yaml")
+	content := "version: 1\nitems:\n  standup:\n    value: daily at 9\n    modifiedAt: 2024-04-01T09:00:00Z\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open[string](path)
+	if err != nil {
+		t.Fatalf("Open(): %v", err)
+	}
+	want := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	if item := store.Items["standup"]; item.Value != "daily at 9" || !item.ModifiedAt.Equal(want) {
+		t.Fatalf("item = %+v", item)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+	header, err := localfile.Inspect(path)
+	if err != nil {
+		t.Fatalf("Inspect(): %v", err)
+	}
+	if header.Format != FormatName || header.Version != FormatVersion {
+		t.Fatalf("saved header = %+v", header)
+	}
+	reopened, err := Open[string](path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, _ := reopened.Get("standup"); got != "daily at 9" {
+		t.Fatalf("round trip value = %q", got)
+	}
+}
+
+func TestOpenRejectsNewerEnvelope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	content := `{"format": "store", "version": 2, "createdBy": "v3.0.0", "payload": {}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var tooNew *localfile.TooNewError
+	if _, err := Open[string](path); !errors.As(err, &tooNew) {
+		t.Fatalf("err = %v, want TooNewError", err)
+	}
+}
+
+func TestMergeNewest(t *testing.T) {
+	older := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	local := &Store[any]{path: "notes.json", Items: map[string]Item[any]{
+		"a": {Value: "local edit", ModifiedAt: newer},
+		"b": {Value: "same", ModifiedAt: older},
+		"c": {Value: "removed elsewhere", ModifiedAt: older},
+	}}
+	remote := &Store[any]{path: "notes (conflicted copy).json", Items: map[string]Item[any]{
+		"a": {Value: "remote edit", ModifiedAt: older},
+		"b": {Value: "same", ModifiedAt: older},
+		"c": {ModifiedAt: newer, Deleted: true},
+		"d": {Value: "added remotely", ModifiedAt: older},
+	}}
+
+	if diffs := Diff([]*Store[any]{local, remote}); len(diffs) != 3 {
+		t.Fatalf("Diff() found %d differences, want 3 (a, c, d)", len(diffs))
+	}
+
+	if err := Merge([]*Store[any]{local, remote}, Newest); err != nil {
+		t.Fatalf("Merge(): %v", err)
+	}
+
+	if got, _ := local.Get("a"); got != "local edit" {
+		t.Errorf("a = %v, want newest local edit", got)
+	}
+	if _, ok := local.Get("c"); ok {
+		t.Errorf("c was resurrected despite newer deletion")
+	}
+	if got, _ := local.Get("d"); got != "added remotely" {
+		t.Errorf("d = %v, want remote addition", got)
+	}
+}
+
+func TestFieldChanges(t *testing.T) {
+	d := Difference{Key: "standup", Versions: []Version{
+		{Present: true, Item: Item[any]{Value: map[string]interface{}{"project": "Internal", "duration": "30m"}}},
+		{Present: true, Item: Item[any]{Value: map[string]interface{}{"project": "Internal", "duration": "45m"}}},
+	}}
+
+	changes := FieldChanges(d)
+	if len(changes) != 1 || changes[0] != `duration: "30m" | "45m"` {
+		t.Fatalf("FieldChanges() = %v", changes)
+	}
+}
@@ -0,0 +1,182 @@
+package localdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// Version is one file's copy of an item during a merge
+type Version struct {
+	File    string
+	Item    Item[any]
+	Present bool
+}
+
+// Difference is an item whose copies disagree between files
+type Difference struct {
+	Key      string
+	Versions []Version
+}
+
+// Strategy picks the index of the winning version of a difference
+type Strategy func(d Difference) (int, error)
+
+// Newest picks the most recently modified version. Missing copies never win,
+// and deletions count as modifications so removed items stay removed.
+func Newest(d Difference) (int, error) {
+	winner := -1
+	for i, version := range d.Versions {
+		if !version.Present {
+			continue
+		}
+		if winner < 0 || version.Item.ModifiedAt.After(d.Versions[winner].Item.ModifiedAt) {
+			winner = i
+		}
+	}
+	if winner < 0 {
+		return 0, fmt.Errorf("no version of %q to choose from", d.Key)
+	}
+	return winner, nil
+}
+
+// Diff compares the stores item by item and returns the items that differ
+func Diff(stores []*Store[any]) []Difference {
+	keys := map[string]bool{}
+	for _, store := range stores {
+		for key := range store.Items {
+			keys[key] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var diffs []Difference
+	for _, key := range sorted {
+		d := Difference{Key: key}
+		for _, store := range stores {
+			item, ok := store.Items[key]
+			d.Versions = append(d.Versions, Version{File: filepath.Base(store.Path()), Item: item, Present: ok})
+		}
+		if !d.agree() {
+			diffs = append(diffs, d)
+		}
+	}
+
+	return diffs
+}
+
+// agree reports whether every file holds the same content for the item
+func (d Difference) agree() bool {
+	first := d.Versions[0]
+	for _, version := range d.Versions[1:] {
+		if version.Present != first.Present || version.Item.Deleted != first.Item.Deleted {
+			return false
+		}
+		if !reflect.DeepEqual(version.Item.Value, first.Item.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge combines the stores into stores[0]. Items all files agree on are
+// kept as they are; differing items are resolved with strategy.
+func Merge(stores []*Store[any], strategy Strategy) error {
+	target := stores[0]
+
+	for _, store := range stores[1:] {
+		for key, item := range store.Items {
+			if _, ok := target.Items[key]; !ok {
+				target.Items[key] = item
+			}
+		}
+	}
+
+	for _, d := range Diff(stores) {
+		winner, err := strategy(d)
+		if err != nil {
+			return err
+		}
+
+		version := d.Versions[winner]
+		if version.Present {
+			target.Items[d.Key] = version.Item
+		} else {
+			delete(target.Items, d.Key)
+		}
+	}
+
+	return nil
+}
+
+// FieldChanges describes which fields differ between the versions of an item.
+// Values that aren't objects are compared as a whole.
+func FieldChanges(d Difference) []string {
+	fields := map[string]bool{}
+	for _, version := range d.Versions {
+		if m, ok := version.Item.Value.(map[string]interface{}); ok {
+			for field := range m {
+				fields[field] = true
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return []string{"value: " + describeVersions(d, func(v Version) interface{} { return v.Item.Value })}
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	var changes []string
+	for _, field := range names {
+		get := func(v Version) interface{} {
+			if m, ok := v.Item.Value.(map[string]interface{}); ok {
+				return m[field]
+			}
+			return nil
+		}
+
+		differs := false
+		for _, version := range d.Versions[1:] {
+			if !reflect.DeepEqual(get(version), get(d.Versions[0])) {
+				differs = true
+			}
+		}
+		if differs {
+			changes = append(changes, field+": "+describeVersions(d, get))
+		}
+	}
+
+	return changes
+}
+
+// describeVersions renders one value per version, e.g. `"a" | "b"`
+func describeVersions(d Difference, get func(Version) interface{}) string {
+	var out string
+	for i, version := range d.Versions {
+		if i > 0 {
+			out += " | "
+		}
+		switch {
+		case !version.Present:
+			out += "(missing)"
+		case version.Item.Deleted:
+			out += "(deleted)"
+		default:
+			encoded, _ := json.Marshal(get(version))
+			out += string(encoded)
+		}
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+// Package localdata implements the keyed stores the CLI keeps in its config
+// directory, and resolves conflicted copies created by file-sync tools.
+package localdata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/localfile"
+)
+
+// FormatName identifies store files in their envelope
+const FormatName = "store"
+
+// FormatVersion is the current payload layout. Version 0 files are either
+// plain key/value maps without per-item timestamps, or the {version, items}
+// layout used before stores were wrapped in an envelope.
+const FormatVersion = 1
+
+func init() {
+	localfile.Register(localfile.Format{
+		Name:     FormatName,
+		Version:  FormatVersion,
+		Upgrades: map[int]localfile.Upgrade{0: upgradeRaw},
+	})
+}
+
+// Item is a stored value together with when it last changed
+type Item[T any] struct {
+	Value      T         `json:"value" yaml:"value"`
+	ModifiedAt time.Time `json:"modifiedAt" yaml:"modifiedAt"`
+	// Deleted marks a tombstone so merges don't resurrect removed items
+	Deleted bool `json:"deleted,omitempty" yaml:"deleted,omitempty"`
+}
+
+// payload is the enveloped content of a store file
+type payload[T any] struct {
+	Items map[string]Item[T] `json:"items" yaml:"items"`
+}
+
+// Store is a keyed collection persisted as JSON or YAML (chosen by extension)
+type Store[T any] struct {
+	path  string
+	Items map[string]Item[T]
+}
+
+// Open reads the store at path. A missing file yields an empty store.
+// Older layouts are upgraded in memory and rewritten on Save.
+func Open[T any](path string) (*Store[T], error) {
+	store := &Store[T]{path: path, Items: map[string]Item[T]{}}
+
+	var content payload[T]
+	if _, err := localfile.Read(path, FormatName, &content); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if content.Items != nil {
+		store.Items = content.Items
+	}
+
+	return store, nil
+}
+
+// upgradeRaw converts a pre-envelope store to version 1. Plain maps get the
+// file's modification time as every item's timestamp.
+func upgradeRaw(raw interface{}, path string) (interface{}, error) {
+	if raw == nil {
+		return map[string]interface{}{"items": map[string]interface{}{}}, nil
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map of items")
+	}
+
+	// The {version, items} layout already carries timestamps
+	if version, ok := fields["version"]; ok {
+		if items, ok := fields["items"]; ok {
+			if n := toInt(version); n > FormatVersion {
+				return nil, &localfile.TooNewError{Path: path, Version: n}
+			}
+			return map[string]interface{}{"items": items}, nil
+		}
+	}
+
+	modifiedAt := time.Now()
+	if info, err := os.Stat(path); err == nil {
+		modifiedAt = info.ModTime()
+	}
+	items := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		items[key] = map[string]interface{}{
+			"value":      value,
+			"modifiedAt": modifiedAt.Format(time.RFC3339Nano),
+		}
+	}
+	return map[string]interface{}{"items": items}, nil
+}
+
+// toInt converts a decoded JSON or YAML number
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// Path returns the file the store is persisted to
+func (s *Store[T]) Path() string {
+	return s.path
+}
+
+// Get returns the value stored under key
+func (s *Store[T]) Get(key string) (T, bool) {
+	item, ok := s.Items[key]
+	if !ok || item.Deleted {
+		var zero T
+		return zero, false
+	}
+	return item.Value, true
+}
+
+// Set stores value under key, stamping it with the current time
+func (s *Store[T]) Set(key string, value T) {
+	s.Items[key] = Item[T]{Value: value, ModifiedAt: time.Now().UTC()}
+}
+
+// Delete removes key, leaving a tombstone for merges
+func (s *Store[T]) Delete(key string) {
+	if _, ok := s.Items[key]; !ok {
+		return
+	}
+	var zero T
+	s.Items[key] = Item[T]{Value: zero, ModifiedAt: time.Now().UTC(), Deleted: true}
+}
+
+// Keys returns the keys of all live items in sorted order
+func (s *Store[T]) Keys() []string {
+	keys := make([]string, 0, len(s.Items))
+	for key, item := range s.Items {
+		if !item.Deleted {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the store atomically with 0600 permissions
+func (s *Store[T]) Save() error {
+	return localfile.Write(s.path, FormatName, payload[T]{Items: s.Items})
+}
@@ -0,0 +1,232 @@
+// Package localfile reads and writes the files the CLI keeps in its config
+// directory. Every file is wrapped in an envelope recording its format,
+// format version and the CLI version that wrote it, so older layouts can be
+// upgraded on read and files from a newer CLI are rejected with a clear error.
+package localfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmiller/timetracker-cli/internal/version"
+)
+
+// Upgrade converts a payload of one format version to the next. path is the
+// file being read, for upgrades that need its metadata.
+type Upgrade func(payload interface{}, path string) (interface{}, error)
+
+// Unchanged is the upgrade for formats whose payload layout didn't change
+func Unchanged(payload interface{}, path string) (interface{}, error) {
+	return payload, nil
+}
+
+// Format describes a kind of local file
+type Format struct {
+	Name    string
+	Version int // current version, written by this CLI
+
+	// Upgrades[v] converts a version v payload to version v+1. Version 0 is
+	// the file's raw content from before it was wrapped in an envelope.
+	Upgrades map[int]Upgrade
+}
+
+// Header is the envelope metadata of a file
+type Header struct {
+	Format    string `json:"format" yaml:"format"`
+	Version   int    `json:"version" yaml:"version"`
+	CreatedBy string `json:"createdBy" yaml:"createdBy"`
+}
+
+// envelope is the on-disk layout of every local file
+type envelope struct {
+	Header  `yaml:",inline"`
+	Payload interface{} `json:"payload" yaml:"payload"`
+}
+
+// TooNewError is returned for files written in a format version this CLI
+// doesn't know yet
+type TooNewError struct {
+	Path      string
+	Version   int
+	CreatedBy string
+}
+
+func (e *TooNewError) Error() string {
+	if e.CreatedBy == "" {
+		return fmt.Sprintf("%s uses format version %d; upgrade the CLI to read it", filepath.Base(e.Path), e.Version)
+	}
+	return fmt.Sprintf("%s was written by a newer CLI (%s); upgrade to read it", filepath.Base(e.Path), e.CreatedBy)
+}
+
+var registry = map[string]Format{}
+
+// Register adds a format to the registry. Packages owning a local file
+// register its format from init.
+func Register(format Format) {
+	registry[format.Name] = format
+}
+
+// Lookup returns the registered format with the given name
+func Lookup(name string) (Format, bool) {
+	format, ok := registry[name]
+	return format, ok
+}
+
+// Read decodes the payload of the file at path into v, upgrading older
+// format versions. It returns the version the file was stored in; a missing
+// file yields an error matching os.ErrNotExist.
+func Read(path, name string, v interface{}) (int, error) {
+	format, ok := registry[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown local file format %q", name)
+	}
+
+	header, payload, err := decode(path)
+	if err != nil {
+		return 0, err
+	}
+	if header.Format != "" && header.Format != name {
+		return 0, fmt.Errorf("%s is a %s file, not %s", filepath.Base(path), header.Format, name)
+	}
+	if header.Version > format.Version {
+		return 0, &TooNewError{Path: path, Version: header.Version, CreatedBy: header.CreatedBy}
+	}
+
+	for version := header.Version; version < format.Version; version++ {
+		upgrade, ok := format.Upgrades[version]
+		if !ok {
+			return 0, fmt.Errorf("%s: no upgrade from %s version %d", filepath.Base(path), name, version)
+		}
+		if payload, err = upgrade(payload, path); err != nil {
+			return 0, fmt.Errorf("failed to upgrade %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	// Go through JSON so YAML and JSON files decode the same way
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	return header.Version, nil
+}
+
+// Inspect returns the header of the file at path. Files from before the
+// envelope have an empty format and version 0.
+func Inspect(path string) (Header, error) {
+	header, _, err := decode(path)
+	return header, err
+}
+
+// Write stores v as the payload of the file at path, atomically and with
+// 0600 permissions. JSON or YAML is chosen by the file's extension.
+func Write(path, name string, v interface{}) error {
+	format, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("unknown local file format %q", name)
+	}
+
+	data, err := marshal(path, envelope{
+		Header:  Header{Format: name, Version: format.Version, CreatedBy: version.Version},
+		Payload: v,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// A temporary file of its own lets concurrent runs write the same file
+	// without mixing their content; the last rename wins
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+// IsData reports whether name looks like a local file by its extension
+func IsData(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".json" || isYAML(name)
+}
+
+// decode reads the file at path and splits it into header and payload
+func decode(path string) (Header, interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Header{}, nil, err
+		}
+		return Header{}, nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var raw interface{}
+	if err := unmarshal(path, data, &raw); err != nil {
+		return Header{}, nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	fields, ok := raw.(map[string]interface{})
+	if !ok || !isEnvelope(fields) {
+		return Header{}, raw, nil
+	}
+
+	var env envelope
+	if err := unmarshal(path, data, &env); err != nil {
+		return Header{}, nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return env.Header, fields["payload"], nil
+}
+
+// isEnvelope reports whether decoded fields are an envelope rather than a
+// raw version 0 payload
+func isEnvelope(fields map[string]interface{}) bool {
+	format, ok := fields["format"].(string)
+	if !ok || format == "" {
+		return false
+	}
+	_, hasPayload := fields["payload"]
+	return hasPayload
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func unmarshal(path string, data []byte, v interface{}) error {
+	if isYAML(path) {
+		return yaml.Unmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func marshal(path string, v interface{}) ([]byte, error) {
+	if isYAML(path) {
+		return yaml.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
@@ -0,0 +1,146 @@
+package localfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmiller/timetracker-cli/internal/version"
+)
+
+type budget struct {
+	Project string  `json:"project" yaml:"project"`
+	Hours   float64 `json:"hours" yaml:"hours"`
+}
+
+func init() {
+	// Version 0 stored minutes under "mins"; version 1 renamed it to "minutes";
+	// version 2 switched to hours
+	Register(Format{
+		Name:    "test-budget",
+		Version: 2,
+		Upgrades: map[int]Upgrade{
+			0: func(payload interface{}, path string) (interface{}, error) {
+				fields := payload.(map[string]interface{})
+				fields["minutes"] = fields["mins"]
+				delete(fields, "mins")
+				return fields, nil
+			},
+			1: func(payload interface{}, path string) (interface{}, error) {
+				fields := payload.(map[string]interface{})
+				fields["hours"] = toFloat(fields["minutes"]) / 60
+				delete(fields, "minutes")
+				return fields, nil
+			},
+		},
+	})
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, name := range []string{"budget.json", "budget.yaml"} {
+		path := filepath.Join(t.TempDir(), name)
+		want := budget{Project: "WEKA", Hours: 12.5}
+
+		if err := Write(path, "test-budget", want); err != nil {
+			t.Fatalf("%s: Write(): %v", name, err)
+		}
+
+		var got budget
+		stored, err := Read(path, "test-budget", &got)
+		if err != nil {
+			t.Fatalf("%s: Read(): %v", name, err)
+		}
+		if got != want || stored != 2 {
+			t.Errorf("%s: got %+v (version %d), want %+v (version 2)", name, got, stored, want)
+		}
+
+		header, err := Inspect(path)
+		if err != nil {
+			t.Fatalf("%s: Inspect(): %v", name, err)
+		}
+		if header != (Header{Format: "test-budget", Version: 2, CreatedBy: version.Version}) {
+			t.Errorf("%s: header = %+v", name, header)
+		}
+
+		info, _ := os.Stat(path)
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s: permissions = %04o", name, info.Mode().Perm())
+		}
+	}
+}
+
+func TestReadUpgradesOldVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		stored  int
+	}{
+		{"raw JSON", "budget.json", `{"project": "WEKA", "mins": 90}`, 0},
+		{"raw YAML", "budget.yaml", "project: WEKA\nmins: 90\n", 0},
+		{"version 1", "budget.json", `{"format": "test-budget", "version": 1, "createdBy": "v0.9.0", "payload": {"project": "WEKA", "minutes": 90}}`, 1},
+	}
+
+	for _, tt := range tests {
+		var got budget
+		stored, err := Read(write(t, tt.file, tt.content), "test-budget", &got)
+		if err != nil {
+			t.Fatalf("%s: Read(): %v", tt.name, err)
+		}
+		if got != (budget{Project: "WEKA", Hours: 1.5}) || stored != tt.stored {
+			t.Errorf("%s: got %+v (version %d)", tt.name, got, stored)
+		}
+	}
+}
+
+func TestReadRejectsNewerVersion(t *testing.T) {
+	path := write(t, "budget.json", `{"format": "test-budget", "version": 3, "createdBy": "v2.1.0", "payload": {}}`)
+
+	var got budget
+	_, err := Read(path, "test-budget", &got)
+
+	var tooNew *TooNewError
+	if !errors.As(err, &tooNew) {
+		t.Fatalf("err = %v, want TooNewError", err)
+	}
+	if !strings.Contains(err.Error(), "newer CLI (v2.1.0); upgrade to read it") {
+		t.Errorf("message = %q", err.Error())
+	}
+}
+
+func TestReadRejectsOtherFormat(t *testing.T) {
+	path := write(t, "budget.json", `{"format": "notes", "version": 1, "payload": {}}`)
+
+	var got budget
+	if _, err := Read(path, "test-budget", &got); err == nil || !strings.Contains(err.Error(), "is a notes file") {
+		t.Errorf("err = %v, want format mismatch", err)
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	var got budget
+	if _, err := Read(filepath.Join(t.TempDir(), "none.json"), "test-budget", &got); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,120 @@
+// Package logging holds the verbosity of the run. Quiet runs print only
+// errors and machine output; verbose runs also log details such as API
+// requests to stderr, and debug runs their headers and bodies, redacted.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is how much the CLI prints
+type Level int
+
+const (
+	Quiet Level = iota - 1
+	Normal
+	Verbose
+	Debug       // also logs request and response headers
+	DebugBodies // also logs request and response bodies
+)
+
+var (
+	mu     sync.Mutex
+	level            = Normal
+	output io.Writer = os.Stderr
+)
+
+// SetLevel sets the level for the rest of the run
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// IsQuiet reports whether only errors and machine output are printed
+func IsQuiet() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return level <= Quiet
+}
+
+// IsVerbose reports whether details are logged
+func IsVerbose() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return level >= Verbose
+}
+
+// Enabled reports whether the run logs at least at level l
+func Enabled(l Level) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return level >= l
+}
+
+// Debugf logs a line to stderr in debug runs
+func Debugf(format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if level < Debug {
+		return
+	}
+	line := fmt.Sprintf(format, a...)
+	fmt.Fprintln(output, strings.TrimRight(line, "\n"))
+}
+
+// Verbosef logs a detail line to stderr in verbose runs
+func Verbosef(format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if level < Verbose {
+		return
+	}
+	line := fmt.Sprintf(format, a...)
+	fmt.Fprintln(output, strings.TrimRight(line, "\n"))
+}
+
+// Noticef prints a line to stderr unless the run is quiet
+func Noticef(format string, a ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if level <= Quiet {
+		return
+	}
+	line := fmt.Sprintf(format, a...)
+	fmt.Fprintln(output, strings.TrimRight(line, "\n"))
+}
+
+// secretParams are query parameters whose values are never logged
+var secretParams = []string{"token", "access_token", "refresh_token", "password", "secret", "key", "code", "signature"}
+
+// RedactURL returns rawURL with credentials and secret query parameters
+// replaced, so it can be logged
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+	query := u.Query()
+	redacted := false
+	for name := range query {
+		for _, secret := range secretParams {
+			if strings.EqualFold(name, secret) || strings.HasSuffix(strings.ToLower(name), "_"+secret) {
+				query.Set(name, "REDACTED")
+				redacted = true
+			}
+		}
+	}
+	if redacted {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
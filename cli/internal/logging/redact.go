@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces secrets in logged headers and bodies
+const Redacted = "***"
+
+// maxLoggedBody is how much of a body is logged
+const maxLoggedBody = 4096
+
+// secretHeaders are headers whose values are never logged
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// secretFields are body fields whose values are never logged, compared
+// without case, dashes or underscores. Fields containing secretFieldParts
+// are never logged either.
+var (
+	secretFields     = []string{"code", "otp", "verifier", "codeverifier", "key", "apikey", "signature"}
+	secretFieldParts = []string{"token", "password", "secret", "authorization", "cookie"}
+)
+
+// RedactHeader returns a copy of header with the values of credentials
+// replaced
+func RedactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range secretHeaders {
+		if values, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+	return redacted
+}
+
+// FormatHeader renders header as sorted "Name: value" lines, each starting
+// with prefix, with credentials redacted
+func FormatHeader(prefix string, header http.Header) string {
+	redacted := RedactHeader(header)
+	names := make([]string, 0, len(redacted))
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		for _, value := range redacted[name] {
+			fmt.Fprintf(&sb, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+	return sb.String()
+}
+
+// RedactBody returns body for logging with the values of secret fields
+// replaced: tokens, passwords, one-time and authorization codes. JSON and
+// form-encoded bodies are redacted field by field; other bodies are logged
+// as they are. Long bodies are cut short.
+func RedactBody(body []byte) string {
+	text := RedactFullBody(body)
+	if len(text) > maxLoggedBody {
+		cut := maxLoggedBody
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = fmt.Sprintf("%s… (%d more bytes)", text[:cut], len(text)-cut)
+	}
+	return text
+}
+
+// RedactFullBody redacts body like RedactBody but keeps all of it, for
+// bodies that are stored rather than logged
+func RedactFullBody(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+
+	var text string
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	switch {
+	case decoder.Decode(&value) == nil && !decoder.More():
+		encoded, err := json.Marshal(redactValue(value))
+		if err != nil {
+			return Redacted
+		}
+		text = string(encoded)
+	case isForm(trimmed):
+		form, _ := url.ParseQuery(string(trimmed))
+		for name := range form {
+			if isSecretField(name) {
+				form[name] = []string{Redacted}
+			}
+		}
+		text = form.Encode()
+	default:
+		text = string(trimmed)
+	}
+	return text
+}
+
+// redactValue replaces the values of secret fields anywhere in a decoded
+// JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if isSecretField(name) {
+				v[name] = Redacted
+			} else {
+				v[name] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// isSecretField reports whether a field named name holds a secret
+func isSecretField(name string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, secret := range secretFields {
+		if normalized == secret {
+			return true
+		}
+	}
+	for _, part := range secretFieldParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForm reports whether body looks like a form-encoded one, name=value
+// pairs joined by &
+func isForm(body []byte) bool {
+	if bytes.ContainsAny(body, " \n\t{}[]") || !bytes.Contains(body, []byte("=")) {
+		return false
+	}
+	_, err := url.ParseQuery(string(body))
+	return err == nil
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer eyJhbGciOi")
+	header.Add("Set-Cookie", "session=abc")
+	header.Add("Set-Cookie", "csrf=def")
+	header.Set("Content-Type", "application/json")
+
+	redacted := RedactHeader(header)
+	if got := redacted.Get("Authorization"); got != Redacted {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := redacted.Values("Set-Cookie"); len(got) != 2 || got[0] != Redacted || got[1] != Redacted {
+		t.Errorf("Set-Cookie = %q", got)
+	}
+	if got := redacted.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if header.Get("Authorization") != "Bearer eyJhbGciOi" {
+		t.Error("RedactHeader changed the original header")
+	}
+
+	if got := FormatHeader("> ", header); !strings.HasPrefix(got, "> Authorization: ***\n> Content-Type: application/json\n") {
+		t.Errorf("FormatHeader = %q", got)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"login", `{"username":"admin","password":"hunter2","otp":"123456"}`, `{"otp":"***","password":"***","username":"admin"}`},
+		{"tokens", `{"accessToken":"a.b.c","refresh_token":"r","expiresIn":900}`, `{"accessToken":"***","expiresIn":900,"refresh_token":"***"}`},
+		{"nested", `{"data":[{"id":1,"apiKey":"k"}],"client":{"client-secret":"s"}}`, `{"client":{"client-secret":"***"},"data":[{"apiKey":"***","id":1}]}`},
+		{"sso", `{"code":"xyz","codeVerifier":"v","redirectUri":"http://127.0.0.1:5000/cb"}`, `{"code":"***","codeVerifier":"***","redirectUri":"http://127.0.0.1:5000/cb"}`},
+		{"form", "grant_type=refresh_token&refresh_token=r1", "grant_type=refresh_token&refresh_token=%2A%2A%2A"},
+		{"large number", `{"id":12345678901234567890}`, `{"id":12345678901234567890}`},
+		{"text", "Bad gateway", "Bad gateway"},
+		{"empty", "  ", ""},
+	}
+	for _, tt := range tests {
+		if got := RedactBody([]byte(tt.body)); got != tt.want {
+			t.Errorf("%s: RedactBody = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	long := RedactBody([]byte(strings.Repeat("x", maxLoggedBody+10)))
+	if !strings.HasSuffix(long, "… (10 more bytes)") {
+		t.Errorf("long body ends %q", long[len(long)-30:])
+	}
+}
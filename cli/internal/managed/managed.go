@@ -0,0 +1,145 @@
+// Package managed loads the organisation-managed configuration overlay.
+//
+// IT departments can provision a system-wide overlay whose security-relevant
+// keys take precedence over the user's config file, environment and flags.
+// When a public key is embedded at build time or installed next to the
+// overlay, the overlay must carry a valid ed25519 signature.
+package managed
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PublicKey is a base64-encoded ed25519 key embedded at build time:
+//
+//	go build -ldflags "-X github.com/vmiller/timetracker-cli/internal/managed.PublicKey=..."
+var PublicKey string
+
+// LockedKeys are the config keys an overlay may set
+var LockedKeys = []string{"api_url", "proxy_url", "ca_cert", "insecure_skip_verify", "telemetry", "plugins_enabled", "self_update"}
+
+var (
+	// ErrUnsigned is returned when verification is required but the overlay has no signature
+	ErrUnsigned = errors.New("managed configuration is not signed")
+	// ErrTampered is returned when the overlay's signature does not match
+	ErrTampered = errors.New("managed configuration signature is invalid")
+)
+
+// DefaultPath returns the platform's overlay location
+func DefaultPath() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/Library/Application Support/TimeTracker/managed.yaml"
+	case "windows":
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		return filepath.Join(programData, "TimeTracker", "managed.yaml")
+	default:
+		return "/etc/timetracker/managed.yaml"
+	}
+}
+
+// Overlay holds the managed values of the locked keys
+type Overlay struct {
+	Path   string
+	Values map[string]string
+	Signed bool
+}
+
+// Load reads the overlay at path and verifies its signature (path + ".sig")
+// when publicKey is set or a public key file (path + ".pub") is installed.
+// A missing overlay returns nil without error.
+func Load(path, publicKey string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read managed configuration: %w", err)
+	}
+
+	if publicKey == "" {
+		if keyData, err := os.ReadFile(path + ".pub"); err == nil {
+			publicKey = strings.TrimSpace(string(keyData))
+		}
+	}
+
+	overlay := &Overlay{Path: path, Values: map[string]string{}}
+
+	if publicKey != "" {
+		if err := verify(data, path+".sig", publicKey); err != nil {
+			return nil, err
+		}
+		overlay.Signed = true
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse managed configuration: %w", err)
+	}
+
+	for _, key := range LockedKeys {
+		if value, ok := raw[key]; ok && value != nil {
+			overlay.Values[key] = fmt.Sprint(value)
+		}
+	}
+
+	return overlay, nil
+}
+
+// verify checks data against the base64 signature stored in sigPath
+func verify(data []byte, sigPath, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid managed configuration public key")
+	}
+
+	sigData, err := os.ReadFile(sigPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrUnsigned
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read managed configuration signature: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigData)))
+	if err != nil || !ed25519.Verify(key, data, signature) {
+		return ErrTampered
+	}
+
+	return nil
+}
+
+// Locked reports whether the overlay manages key
+func (o *Overlay) Locked(key string) bool {
+	if o == nil {
+		return false
+	}
+	_, ok := o.Values[key]
+	return ok
+}
+
+// Overridden returns the locked keys the user tried to set to a different
+// value, given the values they supplied through config, env or flags
+func (o *Overlay) Overridden(attempts map[string]string) []string {
+	var keys []string
+	for key, value := range attempts {
+		if managedValue, ok := o.Values[key]; ok && managedValue != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package managed
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const overlayYAML = "api_url: https://tt.corp.example\ninsecure_skip_verify: false\ntelemetry: false\nunrelated: ignored\n"
+
+func writeOverlay(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "managed.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func sign(t *testing.T, path string, key ed25519.PrivateKey) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(path+".sig", []byte(signature+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMissingOverlay(t *testing.T) {
+	overlay, err := Load(filepath.Join(t.TempDir(), "managed.yaml"), "")
+	if err != nil || overlay != nil {
+		t.Fatalf("Load() = %v, %v; want nil, nil", overlay, err)
+	}
+	if overlay.Locked("api_url") {
+		t.Fatal("nil overlay must not lock keys")
+	}
+}
+
+func TestLoadUnverifiedOverlay(t *testing.T) {
+	overlay, err := Load(writeOverlay(t, overlayYAML), "")
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+
+	want := map[string]string{"api_url": "https://tt.corp.example", "insecure_skip_verify": "false", "telemetry": "false"}
+	if !reflect.DeepEqual(overlay.Values, want) {
+		t.Errorf("Values = %v, want %v", overlay.Values, want)
+	}
+	if overlay.Signed {
+		t.Error("overlay reported as signed without verification")
+	}
+}
+
+func TestLoadVerifiesSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	t.Run("unsigned overlay with verification required", func(t *testing.T) {
+		_, err := Load(writeOverlay(t, overlayYAML), encodedKey)
+		if !errors.Is(err, ErrUnsigned) {
+			t.Fatalf("Load() error = %v, want ErrUnsigned", err)
+		}
+	})
+
+	t.Run("valid signature", func(t *testing.T) {
+		path := writeOverlay(t, overlayYAML)
+		sign(t, path, privateKey)
+
+		overlay, err := Load(path, encodedKey)
+		if err != nil {
+			t.Fatalf("Load(): %v", err)
+		}
+		if !overlay.Signed || !overlay.Locked("api_url") {
+			t.Fatalf("overlay = %+v", overlay)
+		}
+	})
+
+	t.Run("tampered after signing", func(t *testing.T) {
+		path := writeOverlay(t, overlayYAML)
+		sign(t, path, privateKey)
+		if err := os.WriteFile(path, []byte("api_url: https://attacker.example\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(path, encodedKey); !errors.Is(err, ErrTampered) {
+			t.Fatalf("Load() error = %v, want ErrTampered", err)
+		}
+	})
+
+	t.Run("public key installed next to overlay", func(t *testing.T) {
+		path := writeOverlay(t, overlayYAML)
+		if err := os.WriteFile(path+".pub", []byte(encodedKey+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(path, ""); !errors.Is(err, ErrUnsigned) {
+			t.Fatalf("Load() error = %v, want ErrUnsigned", err)
+		}
+	})
+}
+
+func TestOverridden(t *testing.T) {
+	overlay, err := Load(writeOverlay(t, overlayYAML), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := map[string]string{
+		"api_url":   "http://localhost:3000", // locked, different value
+		"telemetry": "false",                 // locked, same value
+		"timeout":   "60s",                   // not locked
+	}
+
+	if got := overlay.Overridden(attempts); !reflect.DeepEqual(got, []string{"api_url"}) {
+		t.Fatalf("Overridden() = %v, want [api_url]", got)
+	}
+}
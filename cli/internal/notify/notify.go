@@ -0,0 +1,77 @@
+// Package notify shows desktop notifications.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// ErrUnavailable is returned when notifications can't be shown, e.g. over
+// SSH or on platforms without a notification command
+var ErrUnavailable = errors.New("desktop notifications not available")
+
+// Notifier shows notifications
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// System runs the platform's notification command (notify-send, osascript)
+type System struct {
+	GOOS   string
+	Getenv func(string) string
+	Run    func(name string, args ...string) error
+}
+
+// Default returns a Notifier for the current platform
+func Default() Notifier {
+	return System{
+		GOOS:   runtime.GOOS,
+		Getenv: os.Getenv,
+		Run: func(name string, args ...string) error {
+			if _, err := exec.LookPath(name); err != nil {
+				return ErrUnavailable
+			}
+			return exec.Command(name, args...).Run()
+		},
+	}
+}
+
+// Command returns the program and arguments that show a notification on
+// goos. ok is false on platforms without a supported command.
+func Command(goos, title, message string) (name string, args []string, ok bool) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		return "osascript", []string{"-e", script}, true
+	case "windows":
+		return "", nil, false
+	default:
+		return "notify-send", []string{"--app-name=timetracker", title, message}, true
+	}
+}
+
+// Notify implements Notifier
+func (s System) Notify(title, message string) error {
+	// Remote sessions without a display have nowhere to show it
+	if s.GOOS != "darwin" && s.GOOS != "windows" &&
+		s.Getenv("DISPLAY") == "" && s.Getenv("WAYLAND_DISPLAY") == "" {
+		return ErrUnavailable
+	}
+
+	name, args, ok := Command(s.GOOS, title, message)
+	if !ok {
+		return ErrUnavailable
+	}
+	if err := s.Run(name, args...); err != nil {
+		if errors.Is(err, ErrUnavailable) {
+			return err
+		}
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+
+	return nil
+}
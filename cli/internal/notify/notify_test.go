@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNotifyRunsPlatformCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		env      map[string]string
+		wantName string
+		wantArgs []string
+		wantErr  error
+	}{
+		{goos: "darwin", wantName: "osascript", wantArgs: []string{"-e", `display notification "Nothing \"logged\"" with title "TimeTracker"`}},
+		{goos: "linux", env: map[string]string{"DISPLAY": ":0"}, wantName: "notify-send", wantArgs: []string{"--app-name=timetracker", "TimeTracker", `Nothing "logged"`}},
+		{goos: "linux", env: map[string]string{"SSH_CONNECTION": "1.2.3.4"}, wantErr: ErrUnavailable},
+		{goos: "windows", wantErr: ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		var gotName string
+		var gotArgs []string
+		notifier := System{
+			GOOS:   tt.goos,
+			Getenv: func(key string) string { return tt.env[key] },
+			Run: func(name string, args ...string) error {
+				gotName, gotArgs = name, args
+				return nil
+			},
+		}
+
+		err := notifier.Notify("TimeTracker", `Nothing "logged"`)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Notify() error = %v, want %v", tt.goos, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && (gotName != tt.wantName || !reflect.DeepEqual(gotArgs, tt.wantArgs)) {
+			t.Errorf("%s: ran %s %v, want %s %v", tt.goos, gotName, gotArgs, tt.wantName, tt.wantArgs)
+		}
+	}
+}
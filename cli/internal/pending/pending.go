@@ -0,0 +1,149 @@
+// Package pending tracks changes the CLI made that the server's cached
+// summaries may not reflect yet.
+//
+// The server caches summary responses for a short while, so a read right
+// after a mutation can under-report. Each mutation records its delta along
+// with the value every summary view showed for that date beforehand. A view
+// has caught up once it reports at least baseline + delta; until then the
+// delta is shown as a pending adjustment.
+package pending
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/config"
+	"github.com/vmiller/timetracker-cli/internal/localfile"
+)
+
+// FormatName identifies the pending store in its envelope. Version 0 is the
+// same layout without an envelope.
+const FormatName = "pending"
+
+func init() {
+	localfile.Register(localfile.Format{
+		Name:     FormatName,
+		Version:  1,
+		Upgrades: map[int]localfile.Upgrade{0: localfile.Unchanged},
+	})
+}
+
+// TTL is how long a delta is kept before it is assumed to be reflected
+const TTL = 5 * time.Minute
+
+// Tolerance absorbs the server rounding hours to two decimals
+const Tolerance = 0.011
+
+// Summary views that can report a date's total
+const (
+	ViewToday = "today"
+	ViewWeek  = "week"
+)
+
+// Delta is a change to a date's total that the server may not show yet
+type Delta struct {
+	Date  string  `json:"date"`
+	Hours float64 `json:"hours"`
+	// Baselines holds each view's total for Date before the change.
+	// A view is removed once it reflects the change.
+	Baselines map[string]float64 `json:"baselines"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// reflectedIn reports whether the observed total of a view includes the delta.
+// Views without a baseline are treated as reflected so a delta can never be
+// counted twice.
+func (d Delta) reflectedIn(view string, observed float64) bool {
+	baseline, ok := d.Baselines[view]
+	if !ok {
+		return true
+	}
+
+	expected := baseline + d.Hours
+	if d.Hours >= 0 {
+		return observed >= expected-Tolerance
+	}
+	return observed <= expected+Tolerance
+}
+
+// Store holds the pending deltas persisted between invocations
+type Store struct {
+	path   string
+	Deltas []Delta `json:"deltas"`
+}
+
+// DefaultPath returns the location of the pending store in the config directory
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pending.json"), nil
+}
+
+// Load reads the store at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	store := &Store{path: path}
+
+	if _, err := localfile.Read(path, FormatName, store); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to load pending store: %w", err)
+	}
+
+	return store, nil
+}
+
+// Add records a new delta
+func (s *Store) Add(delta Delta) {
+	if len(delta.Baselines) == 0 || math.Abs(delta.Hours) < Tolerance {
+		return // Nothing any view could ever show as pending
+	}
+	s.Deltas = append(s.Deltas, delta)
+}
+
+// Reconcile compares a view's observed total for date against the pending
+// deltas and returns the hours the view is still missing. Deltas that the
+// view reflects or that expired are dropped for that view.
+func (s *Store) Reconcile(view, date string, observed float64, now time.Time) float64 {
+	var pendingHours float64
+	kept := s.Deltas[:0]
+
+	for _, delta := range s.Deltas {
+		if now.Sub(delta.CreatedAt) > TTL {
+			continue
+		}
+
+		if delta.Date == date {
+			if delta.reflectedIn(view, observed) {
+				delete(delta.Baselines, view)
+			} else {
+				pendingHours += delta.Hours
+			}
+		}
+
+		if len(delta.Baselines) > 0 {
+			kept = append(kept, delta)
+		}
+	}
+
+	s.Deltas = kept
+	return pendingHours
+}
+
+// Save writes the store back to disk, removing the file when it is empty
+func (s *Store) Save() error {
+	if len(s.Deltas) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove pending store: %w", err)
+		}
+		return nil
+	}
+
+	return localfile.Write(s.path, FormatName, s)
+}
@@ -0,0 +1,201 @@
+package pending
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// delayedServer mimics a summary endpoint whose responses lag behind writes
+type delayedServer struct {
+	actual float64
+	cached float64
+	stale  bool
+}
+
+func (s *delayedServer) write(hours float64) {
+	s.actual += hours
+	s.stale = true
+}
+
+func (s *delayedServer) refresh() {
+	s.cached = s.actual
+	s.stale = false
+}
+
+func (s *delayedServer) read() float64 {
+	return s.cached
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestReconcileDelayedConsistency(t *testing.T) {
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+	server := &delayedServer{actual: 4, cached: 4}
+	store := &Store{}
+
+	record := func(hours float64) {
+		store.Add(Delta{
+			Date:      "2024-04-03",
+			Hours:     hours,
+			Baselines: map[string]float64{ViewToday: server.read()},
+			CreatedAt: now,
+		})
+		server.write(hours)
+	}
+
+	record(0.75)
+
+	// Server still serves the cached total: the delta is pending
+	if got := store.Reconcile(ViewToday, "2024-04-03", server.read(), now); !approxEqual(got, 0.75) {
+		t.Fatalf("pending before refresh = %v, want 0.75", got)
+	}
+
+	// A second change while the first is still stale stacks up
+	record(1.5)
+	if got := store.Reconcile(ViewToday, "2024-04-03", server.read(), now); !approxEqual(got, 2.25) {
+		t.Fatalf("pending with two stale deltas = %v, want 2.25", got)
+	}
+
+	// Once the server catches up nothing is pending and the store empties
+	server.refresh()
+	if got := store.Reconcile(ViewToday, "2024-04-03", server.read(), now); got != 0 {
+		t.Fatalf("pending after refresh = %v, want 0", got)
+	}
+	if len(store.Deltas) != 0 {
+		t.Fatalf("store kept %d reflected deltas", len(store.Deltas))
+	}
+
+	// Reading again never double-counts
+	if got := store.Reconcile(ViewToday, "2024-04-03", server.read(), now); got != 0 {
+		t.Fatalf("pending on repeat read = %v, want 0", got)
+	}
+}
+
+func TestReconcilePartialRefresh(t *testing.T) {
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+	store := &Store{}
+
+	// First delta recorded against 3h, second against a stale 3h as well
+	store.Add(Delta{Date: "2024-04-03", Hours: 1, Baselines: map[string]float64{ViewToday: 3}, CreatedAt: now})
+	store.Add(Delta{Date: "2024-04-03", Hours: 2, Baselines: map[string]float64{ViewToday: 3}, CreatedAt: now})
+
+	// Server now reflects only the first change (4h of an eventual 6h)
+	if got := store.Reconcile(ViewToday, "2024-04-03", 4, now); !approxEqual(got, 2) {
+		t.Fatalf("pending after partial refresh = %v, want 2", got)
+	}
+	if len(store.Deltas) != 1 {
+		t.Fatalf("store has %d deltas, want 1", len(store.Deltas))
+	}
+}
+
+func TestReconcileTolerance(t *testing.T) {
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		hours    float64
+		observed float64
+		want     float64
+	}{
+		{"rounded down by server", 1.0 / 3, 2.33, 0},
+		{"rounded up by server", 2.0 / 3, 2.67, 0},
+		{"one hundredth short is rounding", 0.75, 2.74, 0},
+		{"clearly stale", 0.75, 2.0, 0.75},
+		{"deletion reflected", -1.5, 0.5, 0},
+		{"deletion stale", -1.5, 2.0, -1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &Store{}
+			store.Add(Delta{Date: "2024-04-03", Hours: tt.hours, Baselines: map[string]float64{ViewToday: 2}, CreatedAt: now})
+
+			if got := store.Reconcile(ViewToday, "2024-04-03", tt.observed, now); !approxEqual(got, tt.want) {
+				t.Errorf("Reconcile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileViewsAreIndependent(t *testing.T) {
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+	store := &Store{}
+	store.Add(Delta{
+		Date:      "2024-04-03",
+		Hours:     1,
+		Baselines: map[string]float64{ViewToday: 2, ViewWeek: 2},
+		CreatedAt: now,
+	})
+
+	if got := store.Reconcile(ViewToday, "2024-04-03", 3, now); got != 0 {
+		t.Fatalf("today pending = %v, want 0", got)
+	}
+
+	// The week summary is cached separately and may still be stale
+	if got := store.Reconcile(ViewWeek, "2024-04-03", 2, now); got != 1 {
+		t.Fatalf("week pending = %v, want 1", got)
+	}
+
+	// Views without a baseline never show the delta
+	if got := store.Reconcile("month", "2024-04-03", 2, now); got != 0 {
+		t.Fatalf("unknown view pending = %v, want 0", got)
+	}
+}
+
+func TestReconcileExpiresAndIgnoresOtherDates(t *testing.T) {
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+	store := &Store{}
+	store.Add(Delta{Date: "2024-04-02", Hours: 1, Baselines: map[string]float64{ViewWeek: 0}, CreatedAt: now})
+	store.Add(Delta{Date: "2024-04-03", Hours: 1, Baselines: map[string]float64{ViewWeek: 0}, CreatedAt: now.Add(-TTL - time.Second)})
+
+	if got := store.Reconcile(ViewWeek, "2024-04-03", 0, now); got != 0 {
+		t.Fatalf("expired delta still pending: %v", got)
+	}
+	if len(store.Deltas) != 1 || store.Deltas[0].Date != "2024-04-02" {
+		t.Fatalf("unexpected deltas after reconcile: %+v", store.Deltas)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.json")
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() on missing file: %v", err)
+	}
+	store.Add(Delta{Date: "2024-04-03", Hours: 0.5, Baselines: map[string]float64{ViewToday: 1}, CreatedAt: now})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+	if got := loaded.Reconcile(ViewToday, "2024-04-03", 1, now); got != 0.5 {
+		t.Fatalf("pending after reload = %v, want 0.5", got)
+	}
+}
+
+func TestLoadUnversionedStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.json")
+	content := `{"deltas": [{"date": "2024-04-03", "hours": 0.5, "baselines": {"today": 1}, "createdAt": "2024-04-03T10:00:00Z"}]}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+	now := time.Date(2024, 4, 3, 10, 1, 0, 0, time.UTC)
+	if got := store.Reconcile(ViewToday, "2024-04-03", 1, now); got != 0.5 {
+		t.Fatalf("pending = %v, want 0.5", got)
+	}
+}
@@ -0,0 +1,142 @@
+// Package remind decides when to remind the user that no time has been
+// logged for a while. It only sees today's total at each check: time counts
+// as logged when the total changes.
+package remind
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+const (
+	// minBackoff is the wait after the first failed check
+	minBackoff = 30 * time.Second
+
+	// maxBackoff caps the wait between failing checks
+	maxBackoff = 30 * time.Minute
+)
+
+// Schedule is the working time during which reminders are sent
+type Schedule struct {
+	Start time.Duration // since midnight
+	End   time.Duration // since midnight
+	Days  [7]bool       // indexed by time.Weekday
+}
+
+// ParseSchedule parses working hours like "09:00-17:00" and working days
+// like "mon-fri" or "mon,tue,thu"
+func ParseSchedule(hours, days string) (Schedule, error) {
+	var schedule Schedule
+
+	start, end, ok := strings.Cut(hours, "-")
+	if !ok {
+		return schedule, fmt.Errorf("invalid working hours %q (expected e.g. 09:00-17:00)", hours)
+	}
+	var err error
+	if schedule.Start, err = parseClock(start); err != nil {
+		return schedule, fmt.Errorf("invalid working hours %q: %w", hours, err)
+	}
+	if schedule.End, err = parseClock(end); err != nil {
+		return schedule, fmt.Errorf("invalid working hours %q: %w", hours, err)
+	}
+	if schedule.End <= schedule.Start {
+		return schedule, fmt.Errorf("invalid working hours %q: the end must be after the start", hours)
+	}
+
+	for _, part := range strings.Split(days, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := dates.ParseWeekday(first)
+		if err != nil {
+			return schedule, fmt.Errorf("invalid working days %q: %w", days, err)
+		}
+		to := from
+		if isRange {
+			if to, err = dates.ParseWeekday(last); err != nil {
+				return schedule, fmt.Errorf("invalid working days %q: %w", days, err)
+			}
+		}
+		// Ranges may wrap around the weekend, e.g. sun-thu or fri-mon
+		for day := from; ; day = (day + 1) % 7 {
+			schedule.Days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+
+	return schedule, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", strings.TrimSpace(value))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls within working time
+func (s Schedule) Contains(t time.Time) bool {
+	if !s.Days[t.Weekday()] {
+		return false
+	}
+	clock := t.Sub(dates.StartOfDay(t))
+	return clock >= s.Start && clock < s.End
+}
+
+// Watcher tracks today's total across checks and decides when to remind
+type Watcher struct {
+	Threshold time.Duration
+	Schedule  Schedule
+
+	day        string
+	total      float64
+	lastChange time.Time
+	reminded   time.Time
+}
+
+// Observe records today's total at now. It reports whether to remind and
+// for how long nothing has been logged during working time. Reminders repeat
+// every threshold for as long as nothing is logged.
+func (w *Watcher) Observe(now time.Time, total float64) (remind bool, idle time.Duration) {
+	// The first check, a new day or a changed total restarts the clock
+	if day := dates.Format(now); w.lastChange.IsZero() || day != w.day || total != w.total {
+		w.day, w.total, w.lastChange, w.reminded = day, total, now, time.Time{}
+	}
+
+	if !w.Schedule.Contains(now) {
+		return false, 0
+	}
+
+	// Idle time only counts from the start of the working day
+	since := w.lastChange
+	if start := dates.StartOfDay(now).Add(w.Schedule.Start); since.Before(start) {
+		since = start
+	}
+	idle = now.Sub(since)
+
+	if idle < w.Threshold {
+		return false, idle
+	}
+	if !w.reminded.IsZero() && now.Sub(w.reminded) < w.Threshold {
+		return false, idle
+	}
+	w.reminded = now
+	return true, idle
+}
+
+// Backoff returns how long to wait after the given number of consecutive
+// failed checks: 30s, doubling up to 30m
+func Backoff(failures int) time.Duration {
+	wait := minBackoff
+	for i := 1; i < failures && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		return maxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,101 @@
+package remind
+
+import (
+	"testing"
+	"time"
+)
+
+func at(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule("08:30-17:00", "mon-fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Start != 8*time.Hour+30*time.Minute || schedule.End != 17*time.Hour {
+		t.Errorf("hours = %s-%s", schedule.Start, schedule.End)
+	}
+	want := [7]bool{false, true, true, true, true, true, false}
+	if schedule.Days != want {
+		t.Errorf("Days = %v, want %v", schedule.Days, want)
+	}
+
+	// 2024-03-04 is a Monday
+	for value, inside := range map[string]bool{
+		"2024-03-04 08:29": false,
+		"2024-03-04 08:30": true,
+		"2024-03-04 16:59": true,
+		"2024-03-04 17:00": false,
+		"2024-03-09 10:00": false,
+	} {
+		if got := schedule.Contains(at(value)); got != inside {
+			t.Errorf("Contains(%s) = %v, want %v", value, got, inside)
+		}
+	}
+}
+
+func TestParseScheduleDayLists(t *testing.T) {
+	schedule, err := ParseSchedule("09:00-17:00", "sun-tue,Thursday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [7]bool{true, true, true, false, true, false, false}
+	if schedule.Days != want {
+		t.Errorf("Days = %v, want %v", schedule.Days, want)
+	}
+
+	for _, tt := range [][2]string{{"17:00-09:00", "mon-fri"}, {"9-17", "mon-fri"}, {"09:00-17:00", "mon-fry"}} {
+		if _, err := ParseSchedule(tt[0], tt[1]); err == nil {
+			t.Errorf("ParseSchedule(%q, %q) succeeded, want an error", tt[0], tt[1])
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	schedule, _ := ParseSchedule("09:00-17:00", "mon-fri")
+	w := &Watcher{Threshold: 2 * time.Hour, Schedule: schedule}
+
+	steps := []struct {
+		at     string
+		total  float64
+		remind bool
+	}{
+		{"2024-03-04 07:00", 0, false}, // before work
+		{"2024-03-04 10:59", 0, false}, // idle since 09:00, under the threshold
+		{"2024-03-04 11:00", 0, true},
+		{"2024-03-04 12:00", 0, false}, // already reminded
+		{"2024-03-04 13:00", 0, true},  // still idle a threshold later
+		{"2024-03-04 13:15", 1, false}, // time was logged
+		{"2024-03-04 15:14", 1, false},
+		{"2024-03-04 15:15", 1, true},
+		{"2024-03-04 18:00", 1, false}, // after work
+		{"2024-03-05 11:30", 1, false}, // a new day restarts the clock
+	}
+
+	for _, step := range steps {
+		if remind, idle := w.Observe(at(step.at), step.total); remind != step.remind {
+			t.Errorf("Observe(%s, %v) = %v (idle %s), want %v", step.at, step.total, remind, idle, step.remind)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		4:  4 * time.Minute,
+		7:  30 * time.Minute,
+		50: 30 * time.Minute,
+	}
+	for failures, want := range tests {
+		if got := Backoff(failures); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package rounding
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Display rounds durations as they are shown, for reports in billing
+// increments. Unlike the round command it never changes entries; totals are
+// meant to be added up from the rounded values.
+type Display struct {
+	Granularity time.Duration // zero shows durations unrounded
+	Mode        Mode
+}
+
+// ParseDisplay parses "none", a granularity such as "15m" (rounded to the
+// nearest increment) or a granularity and mode such as "15m:up"
+func ParseDisplay(value string) (Display, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "none" {
+		return Display{}, nil
+	}
+
+	granularity, modeName, _ := strings.Cut(value, ":")
+	d := Display{Mode: Nearest}
+	if modeName != "" {
+		mode, err := ParseMode(modeName)
+		if err != nil {
+			return Display{}, err
+		}
+		d.Mode = mode
+	}
+
+	step, err := time.ParseDuration(granularity)
+	if err != nil || step < time.Minute || step%time.Minute != 0 {
+		return Display{}, fmt.Errorf("invalid rounding %q (expected none, or whole minutes such as 15m or 15m:up)", value)
+	}
+	d.Granularity = step
+	return d, nil
+}
+
+// Enabled reports whether durations are rounded
+func (d Display) Enabled() bool {
+	return d.Granularity > 0
+}
+
+// Hours rounds hours for display
+func (d Display) Hours(hours float64) float64 {
+	return Hours(hours, d.Granularity, d.Mode)
+}
+
+// Map rounds each value of hours for display. Nil stays nil.
+func (d Display) Map(hours map[string]float64) map[string]float64 {
+	if hours == nil || !d.Enabled() {
+		return hours
+	}
+	rounded := make(map[string]float64, len(hours))
+	for key, value := range hours {
+		rounded[key] = d.Hours(value)
+	}
+	return rounded
+}
+
+// String describes the rounding, e.g. "to the nearest 15 minutes"
+func (d Display) String() string {
+	if !d.Enabled() {
+		return "none"
+	}
+	minutes := int(d.Granularity.Minutes())
+	switch d.Mode {
+	case Up:
+		return fmt.Sprintf("up to %d minutes", minutes)
+	case Down:
+		return fmt.Sprintf("down to %d minutes", minutes)
+	}
+	return fmt.Sprintf("to the nearest %d minutes", minutes)
+}
@@ -0,0 +1,54 @@
+// Package rounding rounds durations to billing increments: Hours for the
+// round command, which changes entries, and Display for views that only show
+// rounded durations.
+package rounding
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Mode selects the rounding direction
+type Mode string
+
+const (
+	Up      Mode = "up"
+	Nearest Mode = "nearest"
+	Down    Mode = "down"
+)
+
+// ParseMode parses up, nearest or down
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case Up, Nearest, Down:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid rounding mode %q (expected up, nearest or down)", value)
+}
+
+// Hours rounds a duration in hours to a multiple of granularity. Durations
+// are compared in whole seconds first, so hours that are already a multiple
+// (up to float noise) are left alone. A granularity of zero or less returns
+// hours unchanged.
+func Hours(hours float64, granularity time.Duration, mode Mode) float64 {
+	step := granularity.Seconds()
+	if step <= 0 {
+		return hours
+	}
+
+	seconds := math.Round(hours * 3600)
+	units := seconds / step
+
+	switch mode {
+	case Up:
+		units = math.Ceil(units)
+	case Down:
+		units = math.Floor(units)
+	default:
+		units = math.Round(units)
+	}
+
+	return units * step / 3600
+}
@@ -0,0 +1,76 @@
+package rounding
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHours(t *testing.T) {
+	tests := []struct {
+		hours float64
+		mode  Mode
+		want  float64
+	}{
+		{1.1, Up, 1.25},
+		{1.1, Nearest, 1.0},
+		{1.13, Nearest, 1.25},
+		{1.2, Down, 1.0},
+		{1.25, Up, 1.25},
+		{1.25, Down, 1.25},
+		{0.01, Up, 0.25},
+		{0.01, Down, 0},
+		{2.0 / 3, Up, 0.75}, // float noise must not push exact values up
+	}
+
+	for _, tt := range tests {
+		got := Hours(tt.hours, 15*time.Minute, tt.mode)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Hours(%v, 15m, %s) = %v, want %v", tt.hours, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestHoursExactMultipleUnchanged(t *testing.T) {
+	// 0.1h is 6 minutes; not representable exactly as a float
+	for _, mode := range []Mode{Up, Nearest, Down} {
+		if got := Hours(0.1, 6*time.Minute, mode); math.Abs(got-0.1) > 1e-9 {
+			t.Errorf("Hours(0.1, 6m, %s) = %v, want 0.1", mode, got)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if mode, err := ParseMode("Nearest"); err != nil || mode != Nearest {
+		t.Errorf("ParseMode(Nearest) = %q, %v", mode, err)
+	}
+	if _, err := ParseMode("ceil"); err == nil {
+		t.Error("ParseMode(ceil) should fail")
+	}
+}
+
+func TestParseDisplay(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Display
+		text  string
+	}{
+		{"none", Display{}, "none"},
+		{"", Display{}, "none"},
+		{"15m", Display{Granularity: 15 * time.Minute, Mode: Nearest}, "to the nearest 15 minutes"},
+		{"6m:UP", Display{Granularity: 6 * time.Minute, Mode: Up}, "up to 6 minutes"},
+		{"1h:down", Display{Granularity: time.Hour, Mode: Down}, "down to 60 minutes"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDisplay(tt.value)
+		if err != nil || got != tt.want || got.String() != tt.text {
+			t.Errorf("ParseDisplay(%q) = %+v (%s), %v; want %+v (%s)", tt.value, got, got, err, tt.want, tt.text)
+		}
+	}
+
+	for _, value := range []string{"15", "30s", "15m:sideways", "quarter"} {
+		if _, err := ParseDisplay(value); err == nil {
+			t.Errorf("ParseDisplay(%q) succeeded", value)
+		}
+	}
+}
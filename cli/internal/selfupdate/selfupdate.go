@@ -0,0 +1,249 @@
+// Package selfupdate finds newer releases of the CLI on GitHub and replaces
+// the running executable with the matching, checksum-verified binary.
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRepo is the GitHub repository releases are published to
+	DefaultRepo = "viktormiller/time-tracker"
+
+	// ChecksumsAsset lists the SHA-256 of every binary of a release, in
+	// sha256sum format
+	ChecksumsAsset = "checksums.txt"
+)
+
+// ErrNoAsset is returned when a release has no binary for the platform
+var ErrNoAsset = errors.New("no binary for this platform")
+
+// Release is a published GitHub release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Find returns the asset with the given name
+func (r *Release) Find(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Updater talks to the GitHub releases API
+type Updater struct {
+	APIURL string // defaults to https://api.github.com
+	Repo   string // defaults to DefaultRepo
+	HTTP   *http.Client
+}
+
+func (u *Updater) client() *http.Client {
+	if u.HTTP != nil {
+		return u.HTTP
+	}
+	return &http.Client{Timeout: 2 * time.Minute}
+}
+
+// Latest returns the newest published release
+func (u *Updater) Latest() (*Release, error) {
+	apiURL, repo := u.APIURL, u.Repo
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	if repo == "" {
+		repo = DefaultRepo
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(apiURL, "/")+"/repos/"+repo+"/releases/latest", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for updates: GitHub returned %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to read release: %w", err)
+	}
+	return &release, nil
+}
+
+// Download fetches an asset, verifies it against the release's checksums
+// and returns its contents
+func (u *Updater) Download(release *Release, name string) ([]byte, error) {
+	asset, ok := release.Find(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no %s", ErrNoAsset, release.TagName, name)
+	}
+	sums, ok := release.Find(ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("%s publishes no %s; refusing to install an unverified binary", release.TagName, ChecksumsAsset)
+	}
+
+	checksums, err := u.fetch(sums.URL)
+	if err != nil {
+		return nil, err
+	}
+	want, err := Checksum(checksums, name)
+	if err != nil {
+		return nil, err
+	}
+
+	binary, err := u.fetch(asset.URL)
+	if err != nil {
+		return nil, err
+	}
+	got := sha256.Sum256(binary)
+	if hex.EncodeToString(got[:]) != want {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %x, want %s", name, got, want)
+	}
+	return binary, nil
+}
+
+func (u *Updater) fetch(url string) ([]byte, error) {
+	resp, err := u.client().Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// AssetName returns the binary name for a platform, as built by
+// "make build-all"
+func AssetName(goos, goarch string) string {
+	name := "timetracker-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Checksum returns the SHA-256 listed for name in a sha256sum-format file
+func Checksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s doesn't list %s", ChecksumsAsset, name)
+}
+
+// Newer reports whether version latest is newer than current. Both are
+// "v1.2.3"-style versions; anything after a "-" is ignored. Versions that
+// don't parse, such as "dev", are never newer or older.
+func Newer(current, latest string) bool {
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// Replace atomically replaces the executable at path with binary, keeping
+// its permissions. The new binary is written next to it first, so a
+// failure leaves the old one in place.
+func Replace(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to find the executable: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new executable (is %s writable?): %w", filepath.Dir(path), err)
+	}
+	_, err = tmp.Write(binary)
+	if err == nil {
+		err = tmp.Chmod(info.Mode().Perm())
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write the new executable: %w", err)
+	}
+
+	if err := swap(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace the executable: %w", err)
+	}
+	return nil
+}
+
+// oldSuffix marks an executable swapped out on Windows, where a running
+// program's file can be renamed but not deleted
+const oldSuffix = ".old"
+
+// Cleanup removes an executable left behind by a previous update
+func Cleanup(path string) {
+	os.Remove(path + oldSuffix)
+}
@@ -0,0 +1,107 @@
+package selfupdate
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.2.4", true},
+		{"v1.2.3", "v1.10.0", true},
+		{"v1.2.3", "v2", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.3.0", "v1.2.9", false},
+		{"v1.2.3-4-gabc123-dirty", "v1.2.3", false},
+		{"dev", "v9.9.9", false},
+		{"v1.0.0", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+// fakeGitHub serves a release with one binary and its checksums
+func fakeGitHub(t *testing.T, binary []byte, checksum string) *httptest.Server {
+	t.Helper()
+	name := AssetName("linux", "amd64")
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/" + DefaultRepo + "/releases/latest":
+			json.NewEncoder(w).Encode(Release{TagName: "v1.4.0", Assets: []Asset{
+				{Name: name, URL: server.URL + "/dl/" + name},
+				{Name: ChecksumsAsset, URL: server.URL + "/dl/" + ChecksumsAsset},
+			}})
+		case "/dl/" + name:
+			w.Write(binary)
+		case "/dl/" + ChecksumsAsset:
+			fmt.Fprintf(w, "%s  timetracker-darwin-arm64\n%s  %s\n", strings.Repeat("0", 64), checksum, name)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadVerifiesChecksum(t *testing.T) {
+	binary := []byte("new binary")
+	sum := fmt.Sprintf("%x", sha256.Sum256(binary))
+
+	server := fakeGitHub(t, binary, sum)
+	updater := &Updater{APIURL: server.URL}
+	release, err := updater.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.TagName != "v1.4.0" {
+		t.Errorf("TagName = %q", release.TagName)
+	}
+	got, err := updater.Download(release, AssetName("linux", "amd64"))
+	if err != nil || string(got) != "new binary" {
+		t.Errorf("Download() = %q, %v", got, err)
+	}
+	if _, err := updater.Download(release, AssetName("plan9", "386")); err == nil {
+		t.Error("Download() found a binary for plan9")
+	}
+
+	tampered := fakeGitHub(t, []byte("tampered"), sum)
+	updater = &Updater{APIURL: tampered.URL}
+	release, _ = updater.Latest()
+	if _, err := updater.Download(release, AssetName("linux", "amd64")); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Download(tampered) = %v, want a checksum mismatch", err)
+	}
+}
+
+func TestReplaceKeepsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timetracker")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	info, _ := os.Stat(path)
+	if string(data) != "new" || info.Mode().Perm() != 0755 {
+		t.Errorf("after Replace: %q mode %v", data, info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Replace left %d files behind", len(entries)-1)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package selfupdate
+
+import "os"
+
+// swap moves the new executable over the old one; the running process
+// keeps its open file
+func swap(newPath, path string) error {
+	return os.Rename(newPath, path)
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package selfupdate
+
+import "os"
+
+// swap moves the running executable aside, since Windows doesn't allow
+// replacing it in place, and puts the new one in its place. Cleanup deletes
+// the old one on the next update.
+func swap(newPath, path string) error {
+	os.Remove(path + oldSuffix)
+	if err := os.Rename(path, path+oldSuffix); err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, path); err != nil {
+		// Put the old executable back so the CLI still starts
+		os.Rename(path+oldSuffix, path)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package settings
+
+import (
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/localfile"
+)
+
+// CacheFormat identifies the preferences cache in its envelope. Version 0 is
+// the same layout without an envelope.
+const CacheFormat = "preferences"
+
+func init() {
+	localfile.Register(localfile.Format{
+		Name:     CacheFormat,
+		Version:  1,
+		Upgrades: map[int]localfile.Upgrade{0: localfile.Unchanged},
+	})
+}
+
+// PreferenceTTL is how long fetched server preferences are reused
+const PreferenceTTL = 24 * time.Hour
+
+// PreferenceRetryAfter is how long a failed fetch is not retried, so an
+// unreachable server doesn't slow down every command
+const PreferenceRetryAfter = time.Hour
+
+// PreferenceCache stores the server's preferences between invocations
+type PreferenceCache struct {
+	path      string
+	FetchedAt time.Time         `json:"fetchedAt"`
+	Supported bool              `json:"supported"` // false if the server has no preferences endpoint
+	Values    map[string]string `json:"values"`
+	FailedAt  time.Time         `json:"failedAt,omitempty"` // last failed fetch since FetchedAt
+}
+
+// LoadPreferenceCache reads the cache at path. A missing, unreadable or
+// newer-format cache is returned empty, which makes it stale.
+func LoadPreferenceCache(path string) *PreferenceCache {
+	cache := &PreferenceCache{path: path}
+	if _, err := localfile.Read(path, CacheFormat, cache); err != nil {
+		return &PreferenceCache{path: path}
+	}
+	return cache
+}
+
+// Stale reports whether the preferences should be fetched again
+func (c *PreferenceCache) Stale(now time.Time) bool {
+	if !c.FailedAt.IsZero() && now.Sub(c.FailedAt) < PreferenceRetryAfter {
+		return false
+	}
+	return c.FetchedAt.IsZero() || now.Sub(c.FetchedAt) > PreferenceTTL
+}
+
+// Update replaces the cached preferences
+func (c *PreferenceCache) Update(values map[string]string, supported bool, now time.Time) {
+	c.Values = values
+	c.Supported = supported
+	c.FetchedAt = now
+	c.FailedAt = time.Time{}
+}
+
+// Fail records a failed fetch, keeping the previous preferences
+func (c *PreferenceCache) Fail(now time.Time) {
+	c.FailedAt = now
+}
+
+// Save writes the cache to disk
+func (c *PreferenceCache) Save() error {
+	return localfile.Write(c.path, CacheFormat, c)
+}
@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPreferenceCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preferences.json")
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+
+	cache := LoadPreferenceCache(path)
+	if !cache.Stale(now) {
+		t.Fatal("missing cache should be stale")
+	}
+	cache.Update(map[string]string{"week_start": "sunday"}, true, now)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+
+	loaded := LoadPreferenceCache(path)
+	if loaded.Stale(now) || !loaded.Supported || loaded.Values["week_start"] != "sunday" {
+		t.Fatalf("loaded cache = %+v", loaded)
+	}
+}
+
+func TestPreferenceCacheReadsUnversionedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preferences.json")
+	content := `{"fetchedAt": "2024-04-03T10:00:00Z", "supported": true, "values": {"rounding": "15m"}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := LoadPreferenceCache(path)
+	if cache.Values["rounding"] != "15m" || cache.Stale(time.Date(2024, 4, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("cache = %+v", cache)
+	}
+}
+
+func TestPreferenceCacheIgnoresNewerFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preferences.json")
+	content := `{"format": "preferences", "version": 9, "createdBy": "v9.0.0", "payload": {"values": {"rounding": "15m"}}}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache := LoadPreferenceCache(path); cache.Values != nil || !cache.Stale(time.Now()) {
+		t.Fatalf("newer cache should be treated as empty, got %+v", cache)
+	}
+}
+
+func TestPreferenceCacheBacksOffAfterFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preferences.json")
+	now := time.Date(2024, 4, 3, 10, 0, 0, 0, time.UTC)
+
+	cache := LoadPreferenceCache(path)
+	cache.Fail(now)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+
+	loaded := LoadPreferenceCache(path)
+	if loaded.Stale(now.Add(time.Minute)) {
+		t.Error("cache should not be stale right after a failed fetch")
+	}
+	if !loaded.Stale(now.Add(PreferenceRetryAfter + time.Minute)) {
+		t.Error("cache should be stale once the retry delay has passed")
+	}
+
+	loaded.Update(map[string]string{"rounding": "15m"}, true, now)
+	if !loaded.FailedAt.IsZero() {
+		t.Errorf("Update() kept FailedAt = %v", loaded.FailedAt)
+	}
+}
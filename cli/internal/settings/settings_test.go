@@ -0,0 +1,82 @@
+package settings
+
+import "testing"
+
+// layers builds Sources from plain maps; a missing map means the layer is unset
+func layers(managed, server, config, env, flag map[string]string) Sources {
+	lookup := func(m map[string]string) func(string) (string, bool) {
+		return func(key string) (string, bool) {
+			value, ok := m[key]
+			return value, ok
+		}
+	}
+	return Sources{Managed: managed, Server: server, Config: lookup(config), Env: lookup(env), Flag: lookup(flag)}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		key       string
+		managed   map[string]string
+		server    map[string]string
+		config    map[string]string
+		env       map[string]string
+		flag      map[string]string
+		wantValue string
+		wantLayer Layer
+	}{
+		// api_url: never taken from the server
+		{key: "api_url", wantValue: "http://localhost:3000", wantLayer: LayerDefault},
+		{key: "api_url", server: map[string]string{"api_url": "https://evil.example"}, wantValue: "http://localhost:3000", wantLayer: LayerDefault},
+		{key: "api_url", config: map[string]string{"api_url": "https://tt.example"}, wantValue: "https://tt.example", wantLayer: LayerConfig},
+		{key: "api_url", config: map[string]string{"api_url": "https://tt.example"}, env: map[string]string{"API_URL": "https://env.example"}, wantValue: "https://env.example", wantLayer: LayerEnv},
+		{key: "api_url", env: map[string]string{"API_URL": "https://env.example"}, flag: map[string]string{"api-url": "https://flag.example"}, wantValue: "https://flag.example", wantLayer: LayerFlag},
+		{key: "api_url", managed: map[string]string{"api_url": "https://corp.example"}, config: map[string]string{"api_url": "https://tt.example"}, env: map[string]string{"API_URL": "https://env.example"}, flag: map[string]string{"api-url": "https://flag.example"}, wantValue: "https://corp.example", wantLayer: LayerManaged},
+
+		// week_start
+		{key: "week_start", wantValue: "monday", wantLayer: LayerDefault},
+		{key: "week_start", server: map[string]string{"week_start": "sunday"}, wantValue: "sunday", wantLayer: LayerServer},
+		{key: "week_start", server: map[string]string{"week_start": "sunday"}, config: map[string]string{"week_start": "monday"}, wantValue: "monday", wantLayer: LayerConfig},
+		{key: "week_start", server: map[string]string{"week_start": "sunday"}, env: map[string]string{"TIMETRACKER_WEEK_START": "saturday"}, wantValue: "saturday", wantLayer: LayerEnv},
+
+		// duration_format
+		{key: "duration_format", wantValue: "decimal", wantLayer: LayerDefault},
+		{key: "duration_format", server: map[string]string{"duration_format": "hm"}, wantValue: "hm", wantLayer: LayerServer},
+		{key: "duration_format", server: map[string]string{"duration_format": ""}, wantValue: "decimal", wantLayer: LayerDefault},
+		{key: "duration_format", server: map[string]string{"duration_format": "hm"}, config: map[string]string{"duration_format": "decimal"}, wantValue: "decimal", wantLayer: LayerConfig},
+
+		// rounding
+		{key: "rounding", wantValue: "none", wantLayer: LayerDefault},
+		{key: "rounding", server: map[string]string{"rounding": "15m"}, wantValue: "15m", wantLayer: LayerServer},
+		{key: "rounding", server: map[string]string{"rounding": "15m"}, env: map[string]string{"TIMETRACKER_ROUNDING": "none"}, wantValue: "none", wantLayer: LayerEnv},
+
+		// billable_default
+		{key: "billable_default", wantValue: "true", wantLayer: LayerDefault},
+		{key: "billable_default", server: map[string]string{"billable_default": "false"}, wantValue: "false", wantLayer: LayerServer},
+		{key: "billable_default", server: map[string]string{"billable_default": "false"}, config: map[string]string{"billable_default": "true"}, wantValue: "true", wantLayer: LayerConfig},
+	}
+
+	for _, tt := range tests {
+		setting, ok := Lookup(tt.key)
+		if !ok {
+			t.Fatalf("setting %q not registered", tt.key)
+		}
+
+		got := Resolve(setting, layers(tt.managed, tt.server, tt.config, tt.env, tt.flag))
+		if got.Value != tt.wantValue || got.Layer != tt.wantLayer {
+			t.Errorf("Resolve(%s) with server=%v config=%v env=%v flag=%v = %q from %s, want %q from %s",
+				tt.key, tt.server, tt.config, tt.env, tt.flag, got.Value, got.Layer, tt.wantValue, tt.wantLayer)
+		}
+	}
+}
+
+func TestResolveAllCoversRegistry(t *testing.T) {
+	all := ResolveAll(Sources{})
+	if len(all) != len(Registry) {
+		t.Fatalf("ResolveAll() returned %d settings, want %d", len(all), len(Registry))
+	}
+	for _, resolved := range all {
+		if resolved.Layer != LayerDefault || resolved.Value != resolved.Default {
+			t.Errorf("%s resolved to %q from %s without any sources", resolved.Key, resolved.Value, resolved.Layer)
+		}
+	}
+}
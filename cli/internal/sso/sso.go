@@ -0,0 +1,127 @@
+// Package sso implements the client side of browser-based logins: PKCE
+// secrets and a loopback listener that receives the authorization code.
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// OOBRedirectURI asks the server to show the code for pasting instead of
+// redirecting to a local listener
+const OOBRedirectURI = "urn:ietf:wg:oauth:2.0:oob"
+
+// ErrStateMismatch is returned when a callback doesn't belong to this login
+var ErrStateMismatch = errors.New("login response doesn't match this login attempt")
+
+// PKCE holds a code verifier and its S256 challenge (RFC 7636)
+type PKCE struct {
+	Verifier  string
+	Challenge string
+}
+
+// NewPKCE generates a random verifier and its challenge
+func NewPKCE() (PKCE, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return PKCE{}, err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return PKCE{Verifier: verifier, Challenge: base64.RawURLEncoding.EncodeToString(sum[:])}, nil
+}
+
+// NewState returns a random value tying the callback to this login
+func NewState() (string, error) {
+	return randomString(16)
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// result is what the browser delivered to the listener
+type result struct {
+	code string
+	err  error
+}
+
+// Listener receives the authorization code on a random localhost port
+type Listener struct {
+	RedirectURI string
+
+	state   string
+	server  *http.Server
+	results chan result
+}
+
+// Listen starts a listener on 127.0.0.1 that accepts one callback for state
+func Listen(state string) (*Listener, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start login listener: %w", err)
+	}
+
+	l := &Listener{
+		RedirectURI: fmt.Sprintf("http://%s/callback", ln.Addr()),
+		state:       state,
+		results:     make(chan result, 1),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", l.handle)
+	l.server = &http.Server{Handler: mux}
+	go l.server.Serve(ln)
+
+	return l, nil
+}
+
+func (l *Listener) handle(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var res result
+	switch {
+	case query.Get("state") != l.state:
+		res.err = ErrStateMismatch
+	case query.Get("error") != "":
+		res.err = fmt.Errorf("login was refused: %s", query.Get("error"))
+	case query.Get("code") == "":
+		res.err = errors.New("login response has no code")
+	default:
+		res.code = query.Get("code")
+	}
+
+	if res.err != nil {
+		http.Error(w, "Login failed: "+res.err.Error()+". You can close this tab.", http.StatusBadRequest)
+	} else {
+		fmt.Fprintln(w, "Logged in to TimeTracker. You can close this tab and return to the terminal.")
+	}
+
+	select {
+	case l.results <- res:
+	default: // a result is already waiting; ignore repeats
+	}
+}
+
+// Wait returns the code from the first callback, or ctx's error if it is done
+// first
+func (l *Listener) Wait(ctx context.Context) (string, error) {
+	select {
+	case res := <-l.results:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Close stops the listener
+func (l *Listener) Close() error {
+	return l.server.Close()
+}
@@ -0,0 +1,72 @@
+package sso
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewPKCE(t *testing.T) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkce.Verifier) < 43 {
+		t.Errorf("verifier %q is shorter than RFC 7636 allows", pkce.Verifier)
+	}
+	sum := sha256.Sum256([]byte(pkce.Verifier))
+	if want := base64.RawURLEncoding.EncodeToString(sum[:]); pkce.Challenge != want {
+		t.Errorf("challenge = %q, want %q", pkce.Challenge, want)
+	}
+}
+
+func TestListenerReceivesCode(t *testing.T) {
+	l, err := Listen("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if !strings.HasPrefix(l.RedirectURI, "http://127.0.0.1:") {
+		t.Fatalf("RedirectURI = %q", l.RedirectURI)
+	}
+
+	// A callback for another login is reported, not taken as the code
+	resp, err := http.Get(l.RedirectURI + "?code=stolen&state=other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := l.Wait(ctx); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("Wait error = %v, want ErrStateMismatch", err)
+	}
+
+	resp, err = http.Get(l.RedirectURI + "?code=xyz&state=abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if code, err := l.Wait(ctx); err != nil || code != "xyz" {
+		t.Errorf("Wait = %q, %v, want xyz", code, err)
+	}
+}
+
+func TestListenerTimesOut(t *testing.T) {
+	l, err := Listen("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait error = %v, want DeadlineExceeded", err)
+	}
+}
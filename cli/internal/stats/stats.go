@@ -0,0 +1,194 @@
+// Package stats computes working-pattern statistics from entries: when the
+// day usually starts, how the hours spread over the weekdays, streaks of
+// tracked days and the most-worked project.
+//
+// Entries without a start time (duration-only imports) count towards hours
+// and streaks but are left out of the time-of-day figures. A day's start is
+// its earliest timed entry, so a day with both kinds of entries still has a
+// start time. Weekends without entries don't break a streak, since most
+// people don't track them; tracked weekend days extend it.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// Weekday is the hours logged on one day of the week
+type Weekday struct {
+	Day         time.Weekday
+	Hours       float64
+	Occurrences int     // how often the weekday falls in the window
+	TrackedDays int     // how many of those have entries
+	Average     float64 // Hours / Occurrences
+}
+
+// Streak is a run of tracked days
+type Streak struct {
+	Start time.Time
+	End   time.Time
+	Days  int // tracked days in the run, not counting skipped weekends
+}
+
+// Project is one project's hours
+type Project struct {
+	Name  string
+	Hours float64
+}
+
+// Report holds the statistics for a window of days
+type Report struct {
+	From time.Time
+	To   time.Time
+
+	TotalHours  float64
+	Entries     int
+	TrackedDays int
+
+	// TimedDays is the number of days with at least one start time. The
+	// average start and end are only meaningful when it is positive.
+	TimedDays      int
+	UntimedEntries int
+	AverageStart   time.Duration // since midnight
+	AverageEnd     time.Duration // since midnight
+
+	Weekdays [7]Weekday // Monday first
+
+	LongestStreak Streak
+	CurrentStreak Streak // the run still going at the end of the window
+
+	// Projects are this month's projects, most hours first
+	Projects []Project
+}
+
+// Compute builds the report for the days from..to (inclusive). Entries
+// outside the window only count towards the month's projects, so callers
+// can fetch from the start of the month when the window starts later.
+func Compute(entries []api.Entry, from, to, now time.Time) Report {
+	from, to = dates.StartOfDay(from), dates.StartOfDay(to)
+	report := Report{From: from, To: to}
+
+	hours := map[string]float64{}
+	starts := map[string]time.Duration{}
+	ends := map[string]time.Duration{}
+	projects := map[string]float64{}
+	month := now.Format("2006-01")
+
+	fromKey, toKey := dates.Format(from), dates.Format(to)
+	for _, entry := range entries {
+		day := entry.Day()
+		if len(day) >= 7 && day[:7] == month {
+			projects[entry.Project] += entry.Duration
+		}
+		if day < fromKey || day > toKey {
+			continue
+		}
+
+		report.Entries++
+		report.TotalHours += entry.Duration
+		hours[day] += entry.Duration
+
+		start, ok := clock(entry.StartTime)
+		if !ok {
+			report.UntimedEntries++
+			continue
+		}
+		end := start + time.Duration(entry.Duration*float64(time.Hour))
+		if current, seen := starts[day]; !seen || start < current {
+			starts[day] = start
+		}
+		if end > ends[day] {
+			ends[day] = end
+		}
+	}
+
+	report.TimedDays = len(starts)
+	if report.TimedDays > 0 {
+		var startSum, endSum time.Duration
+		for day, start := range starts {
+			startSum += start
+			endSum += ends[day]
+		}
+		report.AverageStart = (startSum / time.Duration(report.TimedDays)).Round(time.Minute)
+		report.AverageEnd = (endSum / time.Duration(report.TimedDays)).Round(time.Minute)
+	}
+
+	for i := range report.Weekdays {
+		report.Weekdays[i].Day = time.Weekday((i + 1) % 7)
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		weekday := &report.Weekdays[(int(day.Weekday())+6)%7]
+		weekday.Occurrences++
+		if h, ok := hours[dates.Format(day)]; ok {
+			weekday.Hours += h
+			weekday.TrackedDays++
+			report.TrackedDays++
+		}
+	}
+	for i := range report.Weekdays {
+		if report.Weekdays[i].Occurrences > 0 {
+			report.Weekdays[i].Average = report.Weekdays[i].Hours / float64(report.Weekdays[i].Occurrences)
+		}
+	}
+
+	report.LongestStreak, report.CurrentStreak = streaks(hours, from, to)
+
+	for name, h := range projects {
+		report.Projects = append(report.Projects, Project{Name: name, Hours: h})
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		if report.Projects[i].Hours != report.Projects[j].Hours {
+			return report.Projects[i].Hours > report.Projects[j].Hours
+		}
+		return report.Projects[i].Name < report.Projects[j].Name
+	})
+
+	return report
+}
+
+// streaks returns the longest run of tracked days and the run still going
+// at the end of the window
+func streaks(hours map[string]float64, from, to time.Time) (longest, current Streak) {
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if _, ok := hours[dates.Format(day)]; ok {
+			if current.Days == 0 {
+				current.Start = day
+			}
+			current.End = day
+			current.Days++
+			if current.Days > longest.Days {
+				longest = current
+			}
+			continue
+		}
+		// The window's last day may not be over yet, so it doesn't end a run
+		if !isWeekend(day) && day.Before(to) {
+			current = Streak{}
+		}
+	}
+	return longest, current
+}
+
+func isWeekend(day time.Time) bool {
+	return day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+}
+
+// clock parses a start time given as HH:mm or RFC 3339 into the time since
+// midnight, local time
+func clock(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, false
+		}
+		t = t.Local()
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+func day(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestComputeTimeOfDayIgnoresUntimedEntries(t *testing.T) {
+	entries := []api.Entry{
+		{Date: "2024-03-04", StartTime: "08:00", Duration: 4, Project: "A"},
+		{Date: "2024-03-04", StartTime: "13:00", Duration: 4, Project: "A"},
+		{Date: "2024-03-05", StartTime: "10:00", Duration: 6, Project: "B"},
+		// Duration-only import: counts as hours, not as a 00:00 start
+		{Date: "2024-03-05", Duration: 2, Project: "B"},
+		{Date: "2024-03-06", Duration: 8, Project: "C"},
+	}
+
+	report := Compute(entries, day("2024-03-04"), day("2024-03-10"), day("2024-03-10"))
+
+	if report.TotalHours != 24 || report.Entries != 5 || report.TrackedDays != 3 {
+		t.Errorf("totals = %.2fh, %d entries, %d days; want 24h, 5, 3", report.TotalHours, report.Entries, report.TrackedDays)
+	}
+	if report.TimedDays != 2 || report.UntimedEntries != 2 {
+		t.Errorf("timed days %d, untimed entries %d; want 2, 2", report.TimedDays, report.UntimedEntries)
+	}
+	if want := 9 * time.Hour; report.AverageStart != want {
+		t.Errorf("AverageStart = %s, want %s", report.AverageStart, want)
+	}
+	if want := 16*time.Hour + 30*time.Minute; report.AverageEnd != want {
+		t.Errorf("AverageEnd = %s, want %s", report.AverageEnd, want)
+	}
+}
+
+func TestComputeWeekdayAverages(t *testing.T) {
+	entries := []api.Entry{
+		{Date: "2024-03-04", Duration: 8}, // Monday
+		{Date: "2024-03-11", Duration: 6}, // Monday
+		{Date: "2024-03-05", Duration: 3}, // Tuesday
+	}
+
+	report := Compute(entries, day("2024-03-04"), day("2024-03-17"), day("2024-03-17"))
+
+	monday := report.Weekdays[0]
+	if monday.Day != time.Monday || monday.Occurrences != 2 || monday.TrackedDays != 2 || monday.Average != 7 {
+		t.Errorf("Monday = %+v, want 2 occurrences averaging 7h", monday)
+	}
+	tuesday := report.Weekdays[1]
+	if tuesday.Occurrences != 2 || tuesday.TrackedDays != 1 || math.Abs(tuesday.Average-1.5) > 1e-9 {
+		t.Errorf("Tuesday = %+v, want 1 of 2 tracked averaging 1.5h", tuesday)
+	}
+	if sunday := report.Weekdays[6]; sunday.Day != time.Sunday || sunday.Average != 0 {
+		t.Errorf("Sunday = %+v, want no hours", sunday)
+	}
+}
+
+func TestComputeStreaks(t *testing.T) {
+	var entries []api.Entry
+	// Thu 2024-03-07 to Tue 2024-03-12 across a weekend, then a gap on Wed
+	for _, d := range []string{"2024-03-07", "2024-03-08", "2024-03-11", "2024-03-12"} {
+		entries = append(entries, api.Entry{Date: d, Duration: 1})
+	}
+	// Thursday; the window ends on Friday before anything is logged
+	entries = append(entries, api.Entry{Date: "2024-03-14", Duration: 1})
+
+	report := Compute(entries, day("2024-03-01"), day("2024-03-15"), day("2024-03-15"))
+
+	longest := report.LongestStreak
+	if longest.Days != 4 || !longest.Start.Equal(day("2024-03-07")) || !longest.End.Equal(day("2024-03-12")) {
+		t.Errorf("LongestStreak = %d days %s..%s, want 4 days 2024-03-07..2024-03-12",
+			longest.Days, longest.Start.Format("2006-01-02"), longest.End.Format("2006-01-02"))
+	}
+	if report.CurrentStreak.Days != 1 || !report.CurrentStreak.Start.Equal(day("2024-03-14")) {
+		t.Errorf("CurrentStreak = %+v, want 1 day from 2024-03-14", report.CurrentStreak)
+	}
+}
+
+func TestComputeProjectsThisMonth(t *testing.T) {
+	entries := []api.Entry{
+		{Date: "2024-02-28", Duration: 20, Project: "OLD"},
+		{Date: "2024-03-01", Duration: 2, Project: "A"},
+		{Date: "2024-03-02", Duration: 5, Project: "B"},
+		{Date: "2024-03-03", Duration: 1, Project: "A"},
+	}
+
+	// The window starts after the month does; March still counts in full
+	report := Compute(entries, day("2024-03-03"), day("2024-03-03"), day("2024-03-03"))
+
+	if len(report.Projects) != 2 || report.Projects[0] != (Project{"B", 5}) || report.Projects[1] != (Project{"A", 3}) {
+		t.Errorf("Projects = %v, want [B 5h, A 3h]", report.Projects)
+	}
+	if report.TotalHours != 1 {
+		t.Errorf("TotalHours = %v, want only the window's 1h", report.TotalHours)
+	}
+}
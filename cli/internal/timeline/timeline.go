@@ -0,0 +1,165 @@
+// Package timeline finds overlaps and gaps among a day's time intervals.
+// Times are minutes since midnight; intervals are half-open, so one ending
+// at 10:00 and another starting at 10:00 neither overlap nor leave a gap.
+package timeline
+
+import "sort"
+
+// Interval is a span of time belonging to one entry
+type Interval struct {
+	ID    string
+	Start int
+	End   int
+}
+
+// Overlap is a span during which two or more intervals are active
+type Overlap struct {
+	Start int
+	End   int
+	IDs   []string // the active intervals, in input order
+}
+
+// Minutes returns the overlap's length
+func (o Overlap) Minutes() int {
+	return o.End - o.Start
+}
+
+// DoubleCounted returns the minutes counted more than once during the
+// overlap: each interval beyond the first counts its full length again
+func (o Overlap) DoubleCounted() int {
+	return (len(o.IDs) - 1) * o.Minutes()
+}
+
+// Gap is a span with no interval active
+type Gap struct {
+	Start int
+	End   int
+}
+
+// Minutes returns the gap's length
+func (g Gap) Minutes() int {
+	return g.End - g.Start
+}
+
+// boundary is an interval starting or ending, for the sweep
+type boundary struct {
+	at    int
+	index int
+	start bool
+}
+
+// Overlaps sweeps the intervals and returns the spans where at least two
+// are active. Adjacent spans with the same active intervals are merged.
+// Empty or inverted intervals are ignored.
+func Overlaps(intervals []Interval) []Overlap {
+	var boundaries []boundary
+	for i, interval := range intervals {
+		if interval.End <= interval.Start {
+			continue
+		}
+		boundaries = append(boundaries, boundary{interval.Start, i, true}, boundary{interval.End, i, false})
+	}
+	// At equal times ends go first, so touching intervals don't overlap
+	sort.Slice(boundaries, func(i, j int) bool {
+		if boundaries[i].at != boundaries[j].at {
+			return boundaries[i].at < boundaries[j].at
+		}
+		return !boundaries[i].start && boundaries[j].start
+	})
+
+	var overlaps []Overlap
+	active := map[int]bool{}
+	for i, b := range boundaries {
+		if b.start {
+			active[b.index] = true
+		} else {
+			delete(active, b.index)
+		}
+
+		// The span up to the next boundary has the current active set
+		if i+1 == len(boundaries) || len(active) < 2 {
+			continue
+		}
+		next := boundaries[i+1].at
+		if next == b.at {
+			continue
+		}
+
+		indexes := make([]int, 0, len(active))
+		for index := range active {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		ids := make([]string, len(indexes))
+		for k, index := range indexes {
+			ids[k] = intervals[index].ID
+		}
+
+		if n := len(overlaps); n > 0 && overlaps[n-1].End == b.at && sameIDs(overlaps[n-1].IDs, ids) {
+			overlaps[n-1].End = next
+			continue
+		}
+		overlaps = append(overlaps, Overlap{Start: b.at, End: next, IDs: ids})
+	}
+
+	return overlaps
+}
+
+// DoubleCounted returns the total minutes counted more than once
+func DoubleCounted(overlaps []Overlap) int {
+	total := 0
+	for _, overlap := range overlaps {
+		total += overlap.DoubleCounted()
+	}
+	return total
+}
+
+// Gaps returns the spans between from and to not covered by any interval
+// that last at least min minutes
+func Gaps(intervals []Interval, from, to, min int) []Gap {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		if interval.End > interval.Start {
+			sorted = append(sorted, interval)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var gaps []Gap
+	add := func(start, end int) {
+		if start < from {
+			start = from
+		}
+		if end > to {
+			end = to
+		}
+		if end-start >= min && end > start {
+			gaps = append(gaps, Gap{Start: start, End: end})
+		}
+	}
+
+	covered := from
+	for _, interval := range sorted {
+		if interval.Start > covered {
+			add(covered, interval.Start)
+		}
+		if interval.End > covered {
+			covered = interval.End
+		}
+	}
+	add(covered, to)
+
+	return gaps
+}
+
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
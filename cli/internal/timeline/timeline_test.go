@@ -0,0 +1,98 @@
+package timeline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      []Overlap
+		double    int
+	}{
+		{
+			name:      "disjoint",
+			intervals: []Interval{{"a", 540, 600}, {"b", 660, 720}},
+			want:      nil,
+		},
+		{
+			name:      "touching intervals don't overlap",
+			intervals: []Interval{{"a", 540, 600}, {"b", 600, 660}},
+			want:      nil,
+		},
+		{
+			name:      "partial",
+			intervals: []Interval{{"a", 540, 630}, {"b", 600, 660}},
+			want:      []Overlap{{600, 630, []string{"a", "b"}}},
+			double:    30,
+		},
+		{
+			name:      "identical intervals from two providers",
+			intervals: []Interval{{"toggl", 600, 630}, {"tempo", 600, 630}},
+			want:      []Overlap{{600, 630, []string{"toggl", "tempo"}}},
+			double:    30,
+		},
+		{
+			name:      "contained",
+			intervals: []Interval{{"day", 540, 1020}, {"meeting", 600, 660}},
+			want:      []Overlap{{600, 660, []string{"day", "meeting"}}},
+			double:    60,
+		},
+		{
+			name:      "three-way overlap is split by active set",
+			intervals: []Interval{{"a", 540, 660}, {"b", 570, 630}, {"c", 600, 690}},
+			want: []Overlap{
+				{570, 600, []string{"a", "b"}},
+				{600, 630, []string{"a", "b", "c"}},
+				{630, 660, []string{"a", "c"}},
+			},
+			double: 30 + 2*30 + 30,
+		},
+		{
+			name:      "input order doesn't matter",
+			intervals: []Interval{{"c", 600, 690}, {"a", 540, 660}},
+			want:      []Overlap{{600, 660, []string{"c", "a"}}},
+			double:    60,
+		},
+		{
+			name:      "empty and inverted intervals are ignored",
+			intervals: []Interval{{"a", 540, 600}, {"empty", 550, 550}, {"inverted", 590, 560}},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		got := Overlaps(tt.intervals)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Overlaps() = %v, want %v", tt.name, got, tt.want)
+		}
+		if double := DoubleCounted(got); double != tt.double {
+			t.Errorf("%s: DoubleCounted() = %d, want %d", tt.name, double, tt.double)
+		}
+	}
+}
+
+func TestGaps(t *testing.T) {
+	intervals := []Interval{
+		{"a", 500, 560},   // starts before the window
+		{"b", 600, 700},   // 40 minute gap before
+		{"c", 650, 710},   // overlaps b; no gap
+		{"d", 720, 780},   // 10 minute gap before
+		{"e", 1000, 1100}, // runs past the window
+	}
+
+	got := Gaps(intervals, 540, 1020, 15)
+	want := []Gap{{560, 600}, {780, 1000}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Gaps() = %v, want %v", got, want)
+	}
+
+	if got := Gaps(nil, 540, 1020, 15); !reflect.DeepEqual(got, []Gap{{540, 1020}}) {
+		t.Errorf("Gaps(nil) = %v, want the whole window", got)
+	}
+	if got := Gaps([]Interval{{"all", 0, 1440}}, 540, 1020, 1); got != nil {
+		t.Errorf("Gaps(covered) = %v, want none", got)
+	}
+}
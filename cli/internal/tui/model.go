@@ -0,0 +1,343 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/display"
+)
+
+const (
+	// todayPaneWidth is the width of the today pane, the week pane gets the rest
+	todayPaneWidth = 34
+
+	// topPaneHeight fits the week table: title, header, 7 days, total and
+	// borders
+	topPaneHeight = 12
+
+	minWidth  = 64
+	minHeight = 20
+)
+
+// Panes, for per-pane fetch errors
+const (
+	paneToday   = "today"
+	paneWeek    = "week"
+	paneEntries = "entries"
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+var (
+	paneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder())
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// model is the TUI's bubbletea model
+type model struct {
+	client *api.Client
+	width  int
+	height int
+
+	today   *api.TodaySummaryResponse
+	week    *api.WeekSummaryResponse
+	entries []api.Entry      // newest first
+	errs    map[string]error // fetch errors by pane
+
+	filter    string
+	filtering bool
+	selected  int // index into the filtered entries
+	offset    int // first filtered entry shown
+
+	syncing   bool
+	syncStart time.Time
+	status    string
+	frame     int
+
+	highlight bool // use reverse video for the selected row
+}
+
+// setEntries replaces the entry list, newest first
+func (m *model) setEntries(entries []api.Entry) {
+	sorted := append([]api.Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Day() != sorted[j].Day() {
+			return sorted[i].Day() > sorted[j].Day()
+		}
+		return sorted[i].StartTime > sorted[j].StartTime
+	})
+	m.entries = sorted
+	m.clampSelection(0)
+}
+
+// visible returns the entries matching the filter
+func (m *model) visible() []api.Entry {
+	if m.filter == "" {
+		return m.entries
+	}
+
+	filter := strings.ToLower(m.filter)
+	var matches []api.Entry
+	for _, entry := range m.entries {
+		text := strings.ToLower(strings.Join([]string{entry.Day(), entry.Source, entry.Project, entry.Description}, " "))
+		if strings.Contains(text, filter) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// handleKey applies a key press and returns the command it asks for: quit,
+// sync or refresh
+func (m *model) handleKey(key tea.KeyMsg) tea.Cmd {
+	if key.Type == tea.KeyCtrlC {
+		return tea.Quit
+	}
+
+	if m.filtering {
+		switch key.Type {
+		case tea.KeyEnter:
+			m.filtering = false
+		case tea.KeyEsc:
+			m.filtering = false
+			m.filter = ""
+		case tea.KeyBackspace:
+			if m.filter != "" {
+				_, size := utf8.DecodeLastRuneInString(m.filter)
+				m.filter = m.filter[:len(m.filter)-size]
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.filter += string(key.Runes)
+		}
+		m.selected, m.offset = 0, 0
+		return nil
+	}
+
+	rows := listHeight(m.height)
+	switch key.String() {
+	case "up", "k":
+		m.move(-1, rows)
+	case "down", "j":
+		m.move(1, rows)
+	case "pgup":
+		m.move(-rows, rows)
+	case "pgdown":
+		m.move(rows, rows)
+	case "home":
+		m.move(-len(m.entries), rows)
+	case "end":
+		m.move(len(m.entries), rows)
+	case "esc":
+		m.filter = ""
+		m.selected, m.offset = 0, 0
+	case "q":
+		return tea.Quit
+	case "/":
+		m.filtering = true
+	case "s":
+		if !m.syncing {
+			return m.sync()
+		}
+	case "r":
+		m.status = "refreshing..."
+		return m.load()
+	}
+	return nil
+}
+
+// move shifts the selection and scrolls to keep it in view
+func (m *model) move(delta, listHeight int) {
+	m.selected += delta
+	m.clampSelection(listHeight)
+}
+
+// clampSelection keeps the selection within the filtered entries and the
+// scroll offset such that the selection is visible
+func (m *model) clampSelection(listHeight int) {
+	count := len(m.visible())
+	if m.selected >= count {
+		m.selected = count - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if listHeight > 0 && m.selected >= m.offset+listHeight {
+		m.offset = m.selected - listHeight + 1
+	}
+}
+
+// listHeight returns the number of entry rows that fit the screen
+func listHeight(height int) int {
+	// title, top panes, list border, title and header, status line
+	return height - 1 - topPaneHeight - 4 - 1
+}
+
+// render draws the whole screen as exactly height lines of width columns
+func (m *model) render(width, height int) string {
+	if width == 0 || height == 0 {
+		return "" // the size isn't known yet
+	}
+	if width < minWidth || height < minHeight {
+		lines := []string{fit(fmt.Sprintf("Terminal too small (need %dx%d). Press q to quit.", minWidth, minHeight), width)}
+		for len(lines) < height {
+			lines = append(lines, strings.Repeat(" ", width))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	header := fit(" timetracker  "+time.Now().Format("Mon 2006-01-02 15:04"), width)
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		box("Today", m.todayLines(), todayPaneWidth, topPaneHeight),
+		" ",
+		box("Week", m.weekLines(), width-todayPaneWidth-1, topPaneHeight),
+	)
+
+	rows := listHeight(height)
+	m.clampSelection(rows)
+	title := fmt.Sprintf("Entries (%d)", len(m.visible()))
+	if m.filter != "" {
+		title = fmt.Sprintf("Entries matching %q (%d of %d)", m.filter, len(m.visible()), len(m.entries))
+	}
+	list := box(title, m.entryLines(width-2, rows), width, rows+4)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, panes, list, m.statusLine(width))
+}
+
+func (m *model) todayLines() []string {
+	if m.today == nil {
+		return []string{m.placeholder(paneToday)}
+	}
+
+	lines := []string{
+		m.today.Date,
+		"",
+		fmt.Sprintf("Total:   %7s", display.FormatDuration(m.today.TotalHours)),
+		fmt.Sprintf("Entries: %6d", m.today.EntryCount),
+		"",
+	}
+	sources := make([]string, 0, len(m.today.BySource))
+	for source := range m.today.BySource {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		lines = append(lines, fmt.Sprintf("%-8s %7s", source, display.FormatDuration(m.today.BySource[source])))
+	}
+	return lines
+}
+
+func (m *model) weekLines() []string {
+	if m.week == nil {
+		return []string{m.placeholder(paneWeek)}
+	}
+
+	lines := []string{fmt.Sprintf("%-4s %-10s  %6s", "Day", "Date", "Hours")}
+	for _, day := range m.week.Daily {
+		name := day.DayName
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		lines = append(lines, fmt.Sprintf("%-4s %-10s  %6s", name, day.Date, display.FormatHours(day.Hours)))
+	}
+	lines = append(lines, fmt.Sprintf("%-4s %-10s  %6s", "Sum", "", display.FormatHours(m.week.TotalHours)))
+	return lines
+}
+
+func (m *model) entryLines(width, rows int) []string {
+	if m.entries == nil {
+		return []string{m.placeholder(paneEntries)}
+	}
+
+	descWidth := width - (2 + 10 + 1 + 6 + 1 + 6 + 1 + 20 + 1)
+	lines := []string{fit(fmt.Sprintf("  %-10s %-6s %6s %-20s %s", "Date", "Source", "Hours", "Project", "Description"), width)}
+
+	visible := m.visible()
+	for i := m.offset; i < len(visible) && i < m.offset+rows; i++ {
+		entry := visible[i]
+		marker := "  "
+		if i == m.selected {
+			marker = "> "
+		}
+		line := fit(fmt.Sprintf("%s%-10s %-6s %6s %-20s %s",
+			marker, entry.Day(), fit(entry.Source, 6), display.FormatHours(entry.Duration), fit(entry.Project, 20), fit(entry.Description, descWidth)), width)
+		if i == m.selected && m.highlight {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (m *model) statusLine(width int) string {
+	if m.filtering {
+		return fit(" /"+m.filter+"_   (enter to keep, esc to clear)", width)
+	}
+
+	help := " q quit  / filter  j/k scroll  s sync  r refresh"
+	status := m.status
+	if m.syncing {
+		status = fmt.Sprintf("%s syncing... %ds", spinnerFrames[m.frame%len(spinnerFrames)], int(time.Since(m.syncStart).Seconds()))
+	}
+	if status == "" {
+		return fit(help, width)
+	}
+	return fit(help+"  |  "+status, width)
+}
+
+// placeholder is shown in panes whose data hasn't arrived
+func (m *model) placeholder(pane string) string {
+	if err := m.errs[pane]; err != nil {
+		return "Error: " + err.Error()
+	}
+	return "Loading..."
+}
+
+// box draws a titled pane of the given size around lines. Lines are cut or
+// padded to fit; styled lines must fit already.
+func box(title string, lines []string, width, height int) string {
+	inner := width - 2
+	content := []string{titleStyle.Render(fit(title, inner))}
+	for i := 0; i < height-3; i++ {
+		line := ""
+		if i < len(lines) {
+			line = lines[i]
+		}
+		if lipgloss.Width(line) != inner {
+			line = fit(line, inner)
+		}
+		content = append(content, line)
+	}
+	return paneStyle.Render(strings.Join(content, "\n"))
+}
+
+// fit cuts text to width columns and pads it with spaces to exactly width
+func fit(text string, width int) string {
+	return runewidth.FillRight(truncate(text, width), width)
+}
+
+// truncate cuts text to at most width columns, marking cut text with "…".
+// Control characters become spaces so they can't break the layout.
+func truncate(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	text = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, text)
+	return runewidth.Truncate(text, width, "…")
+}
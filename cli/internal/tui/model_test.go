@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+)
+
+// runes is the key message for typing text
+func runes(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+// isQuit reports whether cmd quits the program
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func testModel() *model {
+	m := &model{width: 80, height: 24}
+	m.setEntries([]api.Entry{
+		{ID: "1", Date: "2024-03-11", Source: "TOGGL", Project: "ACME-1", Description: "Standup", Duration: 0.25},
+		{ID: "2", Date: "2024-03-12", Source: "TEMPO", Project: "ACME-2", Description: "Review", Duration: 1.5},
+		{ID: "3", Date: "2024-03-13", Source: "MANUAL", Project: "INTERNAL", Description: "Planning", Duration: 2},
+	})
+	return m
+}
+
+func TestSetEntriesNewestFirst(t *testing.T) {
+	m := testModel()
+	if m.entries[0].ID != "3" || m.entries[2].ID != "1" {
+		t.Errorf("entries = %s, %s, %s; want newest first", m.entries[0].ID, m.entries[1].ID, m.entries[2].ID)
+	}
+}
+
+func TestFilterByTyping(t *testing.T) {
+	m := testModel()
+	m.handleKey(runes("j"))
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.selected != 2 {
+		t.Fatalf("selected = %d after two moves, want 2", m.selected)
+	}
+
+	m.handleKey(runes("/"))
+	for _, r := range "acme" {
+		m.handleKey(runes(string(r)))
+	}
+	if isQuit(m.handleKey(runes("q"))) {
+		t.Fatal("q quit while typing a filter")
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.filter != "acme" || m.filtering {
+		t.Fatalf("filter = %q (filtering %v), want \"acme\" kept", m.filter, m.filtering)
+	}
+	if got := len(m.visible()); got != 2 {
+		t.Errorf("%d entries match \"acme\", want 2", got)
+	}
+	if m.selected != 0 {
+		t.Errorf("selected = %d after filtering, want 0", m.selected)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filter != "" || len(m.visible()) != 3 {
+		t.Errorf("esc left filter %q with %d entries", m.filter, len(m.visible()))
+	}
+	if !isQuit(m.handleKey(runes("q"))) {
+		t.Error("q did not quit")
+	}
+}
+
+func TestScrollKeepsSelectionVisible(t *testing.T) {
+	m := testModel()
+	m.height = minHeight // room for two entries
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnd})
+	if m.selected != 2 || m.offset != 1 {
+		t.Errorf("after end: selected %d, offset %d; want 2, 1", m.selected, m.offset)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.selected != 2 {
+		t.Errorf("selected = %d past the end, want 2", m.selected)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyHome})
+	if m.selected != 0 || m.offset != 0 {
+		t.Errorf("after home: selected %d, offset %d; want 0, 0", m.selected, m.offset)
+	}
+}
+
+func TestSyncKeyIgnoredWhileSyncing(t *testing.T) {
+	m := testModel()
+	if cmd := m.handleKey(runes("s")); cmd == nil || !m.syncing {
+		t.Error("s did not start a sync")
+	}
+	if cmd := m.handleKey(runes("s")); cmd != nil {
+		t.Error("s started a second sync while one is running")
+	}
+	m.Update(syncedMsg{resp: &api.SyncResponse{TotalImported: 3}})
+	if m.syncing || !strings.HasPrefix(m.status, "synced: 3 imported") {
+		t.Errorf("after the sync: syncing %v, status %q", m.syncing, m.status)
+	}
+}
+
+func TestRenderFillsScreen(t *testing.T) {
+	m := testModel()
+	m.filter = "a very long filter that will not fit in the entries title at all"
+	for _, size := range [][2]int{{80, 24}, {120, 40}, {40, 10}} {
+		width, height := size[0], size[1]
+		lines := strings.Split(m.render(width, height), "\n")
+		if len(lines) != height {
+			t.Errorf("%dx%d: rendered %d lines", width, height, len(lines))
+		}
+		for i, line := range lines {
+			if n := lipgloss.Width(line); n != width {
+				t.Errorf("%dx%d: line %d is %d columns: %q", width, height, i, n, line)
+			}
+		}
+	}
+}
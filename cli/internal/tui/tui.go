@@ -0,0 +1,191 @@
+// Package tui is the full-screen interactive mode, built on bubbletea. It
+// draws today's summary, the week and a scrollable, filterable entry list,
+// and can trigger a sync. All data comes through api.Client, like every
+// other command.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+
+	"github.com/vmiller/timetracker-cli/internal/api"
+	"github.com/vmiller/timetracker-cli/internal/dates"
+)
+
+// entryDays is how many days of entries the list shows
+const entryDays = 30
+
+// ErrNotTerminal is returned when stdin or stdout isn't a terminal
+var ErrNotTerminal = errors.New("the TUI needs an interactive terminal; use 'today', 'week' or 'search' in scripts and pipes")
+
+// Options configure the TUI
+type Options struct {
+	Highlight bool // reverse video for the selected entry
+}
+
+// loadedMsg carries the result of a background fetch
+type loadedMsg struct {
+	pane    string
+	today   *api.TodaySummaryResponse
+	week    *api.WeekSummaryResponse
+	entries []api.Entry
+	err     error
+}
+
+// syncedMsg carries the result of a sync
+type syncedMsg struct {
+	resp *api.SyncResponse
+	err  error
+}
+
+// tickMsg advances the sync spinner
+type tickMsg time.Time
+
+// Run starts the TUI and blocks until the user quits
+func Run(client *api.Client, opts Options) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return ErrNotTerminal
+	}
+
+	m := &model{client: client, highlight: opts.Highlight}
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("the TUI failed: %w", err)
+	}
+	return nil
+}
+
+// Init starts loading the panes and the spinner's ticks
+func (m *model) Init() tea.Cmd {
+	return tea.Batch(m.load(), tick())
+}
+
+// Update applies a message to the model
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampSelection(listHeight(m.height))
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+
+	case loadedMsg:
+		m.apply(msg)
+
+	case syncedMsg:
+		m.syncing = false
+		m.status = syncStatus(msg)
+		if msg.err == nil {
+			return m, m.load()
+		}
+
+	case tickMsg:
+		m.frame++
+		return m, tick()
+	}
+	return m, nil
+}
+
+// View draws the screen
+func (m *model) View() string {
+	return m.render(m.width, m.height)
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(250*time.Millisecond, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// load fetches the three panes' data in the background
+func (m *model) load() tea.Cmd {
+	client := m.client
+	return tea.Batch(
+		func() tea.Msg {
+			var today api.TodaySummaryResponse
+			if err := client.Get("/api/entries/summary/today", &today); err != nil {
+				return loadedMsg{pane: paneToday, err: err}
+			}
+			return loadedMsg{pane: paneToday, today: &today}
+		},
+		func() tea.Msg {
+			var week api.WeekSummaryResponse
+			if err := client.Get("/api/entries/summary/week", &week); err != nil {
+				return loadedMsg{pane: paneWeek, err: err}
+			}
+			return loadedMsg{pane: paneWeek, week: &week}
+		},
+		func() tea.Msg {
+			now := time.Now()
+			from := dates.Format(now.AddDate(0, 0, -entryDays))
+			entries, err := client.GetEntries(from, dates.Format(now))
+			if err != nil {
+				return loadedMsg{pane: paneEntries, err: err}
+			}
+			if entries == nil {
+				entries = []api.Entry{}
+			}
+			return loadedMsg{pane: paneEntries, entries: entries}
+		},
+	)
+}
+
+// sync runs a sync of all providers in the background
+func (m *model) sync() tea.Cmd {
+	client := m.client
+	m.syncing, m.syncStart, m.status = true, time.Now(), ""
+	return func() tea.Msg {
+		resp, err := client.Sync(context.Background(), api.SyncOptions{})
+		return syncedMsg{resp: resp, err: err}
+	}
+}
+
+// apply stores a fetch result in the model
+func (m *model) apply(result loadedMsg) {
+	if m.errs == nil {
+		m.errs = map[string]error{}
+	}
+	m.errs[result.pane] = result.err
+	if result.err != nil {
+		m.status = "failed to load " + result.pane
+		return
+	}
+	if result.today != nil {
+		m.today = result.today
+	}
+	if result.week != nil {
+		m.week = result.week
+	}
+	if result.entries != nil {
+		m.setEntries(result.entries)
+	}
+	if strings.HasPrefix(m.status, "refreshing") {
+		m.status = "refreshed " + time.Now().Format("15:04:05")
+	}
+}
+
+// syncStatus describes a finished sync for the status line
+func syncStatus(result syncedMsg) string {
+	if result.err != nil {
+		return "sync failed: " + result.err.Error()
+	}
+
+	var failed []string
+	for _, provider := range result.resp.Results {
+		if !provider.Success {
+			failed = append(failed, provider.Provider)
+		}
+	}
+	status := fmt.Sprintf("synced: %d imported, %d skipped", result.resp.TotalImported, result.resp.TotalSkipped)
+	if len(failed) > 0 {
+		status += " (failed: " + strings.Join(failed, ", ") + ")"
+	}
+	return status
+}
@@ -0,0 +1,7 @@
+// Package version holds the CLI's version, set at build time with
+//
+//	go build -ldflags "-X github.com/vmiller/timetracker-cli/internal/version.Version=v1.2.3"
+package version
+
+// Version is the CLI version; "dev" for local builds
+var Version = "dev"
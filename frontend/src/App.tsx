@@ -86,6 +86,25 @@ const getPresetRange = (preset: DatePreset): { start: Date, end: Date } | null =
     }
 };
 
+// Ansicht und Zeitraum aus der URL, z.B. von `timetracker open week`:
+// ?view=estimates oder ?view=dashboard&from=2024-03-04&to=2024-03-10
+type View = 'dashboard' | 'add-entry' | 'settings' | 'estimates' | 'utilities';
+const URL_VIEWS: View[] = ['dashboard', 'settings', 'estimates', 'utilities'];
+const URL_DATE = /^\d{4}-\d{2}-\d{2}$/;
+
+const getUrlView = (): View => {
+    const view = new URLSearchParams(window.location.search).get('view');
+    return URL_VIEWS.find(v => v === view) ?? 'dashboard';
+};
+
+const getUrlRange = (): { start: Date, end: Date } | null => {
+    const params = new URLSearchParams(window.location.search);
+    const from = params.get('from');
+    const to = params.get('to') ?? from;
+    if (!from || !to || !URL_DATE.test(from) || !URL_DATE.test(to)) return null;
+    return { start: parseISO(from), end: endOfDay(parseISO(to)) };
+};
+
 function App() {
   return (
     <ThemeProvider>
@@ -115,7 +134,7 @@ function AuthenticatedApp({ logout }: { logout: () => void }) {
   const { toast } = useToast();
 
   // --- VIEW STATE ---
-  const [currentView, setCurrentView] = useState<'dashboard' | 'add-entry' | 'settings' | 'estimates' | 'utilities'>('dashboard');
+  const [currentView, setCurrentView] = useState<View>(getUrlView);
   const [sidebarOpen, setSidebarOpen] = useState(() => localStorage.getItem('sidebarOpen') !== 'false');
 
   const toggleSidebar = () => {
@@ -167,9 +186,9 @@ function AuthenticatedApp({ logout }: { logout: () => void }) {
   }, []);
 
   // DATE STATE
-  const [datePreset, setDatePreset] = useState<DatePreset>('MONTH');
+  const [datePreset, setDatePreset] = useState<DatePreset>(() => getUrlRange() ? 'CUSTOM' : 'MONTH');
   const [dateRange, setDateRange] = useState<{ start: Date; end: Date }>(() => {
-     return getPresetRange('MONTH')!;
+     return getUrlRange() ?? getPresetRange('MONTH')!;
   });
 
   const fileInputRef = useRef<HTMLInputElement>(null);